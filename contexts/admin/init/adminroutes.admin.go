@@ -2,46 +2,62 @@ package init
 
 import (
 	"net/http"
-	"sort"
 
 	"github.com/labstack/echo/v4"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/views/pages"
+	sharedweb "github.com/go-arrower/skeleton/shared/infrastructure/web"
 )
 
 func registerAdminRoutes(di *AdminContext) {
 	di.globalContainer.AdminRouter.GET("", func(c echo.Context) error {
-		return c.Render(http.StatusOK, "admin.home", nil)
+		return sharedweb.Render(c, http.StatusOK, pages.ViewAdminHome, pages.HomePage{})
 	})
 
 	di.globalContainer.AdminRouter.GET("/", func(c echo.Context) error {
-		return c.Render(http.StatusOK, "admin.home", nil)
+		return sharedweb.Render(c, http.StatusOK, pages.ViewAdminHome, pages.HomePage{})
 	})
 
 	di.globalContainer.AdminRouter.GET("/routes", func(c echo.Context) error {
-		routes := di.globalContainer.WebRouter.Routes()
+		return sharedweb.Render(c, http.StatusOK, pages.ViewRoutes, pages.PresentRoutes(di.globalContainer.WebRouter.Routes()))
+	})
 
-		// sort routes by path and then by method
-		sort.Slice(routes, func(i, j int) bool {
-			if routes[i].Path < routes[j].Path {
-				return true
-			}
+	di.globalContainer.AdminRouter.GET("/components", func(c echo.Context) error {
+		return sharedweb.Render(c, http.StatusOK, pages.ViewComponents, pages.PresentComponents(c.QueryParams()))
+	})
+	di.globalContainer.AdminRouter.POST("/components", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent) // the confirm dialog of the gallery has nothing to do
+	})
 
-			if routes[i].Path == routes[j].Path {
-				return routes[i].Method < routes[j].Method
-			}
+	// the topics are published by the triggers of the migration 000005_events
+	di.globalContainer.AdminRouter.GET("/events", di.globalContainer.Events.Stream(
+		"jobs.queues", "jobs.finished", "jobs.workers", "logs",
+	))
 
-			return false
-		})
+	di.settingsController.List()
 
-		return c.Render(http.StatusOK, "admin.routes", echo.Map{
-			"Flashes": nil,
-			"Routes":  routes,
-		})
-	})
+	di.maintenanceController.ShowMaintenance()
+	di.maintenanceController.SaveMaintenance()
 
-	di.settingsController.List()
+	{
+		flags := di.globalContainer.AdminRouter.Group("/flags")
+		flags.GET("", di.flagsController.ListFlags()).Name = "admin.flags"
+		flags.POST("", di.flagsController.CreateFlag())
+		flags.GET("/:key", di.flagsController.ShowFlag()).Name = "admin.flag"
+		flags.POST("/:key", di.flagsController.SaveFlag())
+		flags.POST("/:key/toggle", di.flagsController.ToggleFlag())
+		flags.POST("/:key/delete", di.flagsController.DeleteFlag())
+	}
 
 	di.logsController.ShowLogs()
+	di.logsController.ExportLogs()
+	di.logsController.SaveSearch()
+	di.logsController.DeleteSearch()
 	di.logsController.SettingLogs()
+	di.logsController.ShowMaintenance()
+	di.logsController.SaveRetention()
+	di.logsController.PruneLogs()
+	di.logsController.VacuumLogs()
 
 	{
 		jobs := di.globalContainer.AdminRouter.Group("/jobs")
@@ -50,6 +66,7 @@ func registerAdminRoutes(di *AdminContext) {
 		jobs.GET("/data/pending", di.jobsController.PendingJobsPieChartData())                // todo better htmx fruednly data URL
 		jobs.GET("/data/processed/:interval", di.jobsController.ProcessedJobsLineChartData()) // todo better htmx fruednly data URL
 		jobs.GET("/:queue", di.jobsController.ShowQueue()).Name = "admin.jobs.queue"          // todo move route(s) to /queue/:queue_name (or similar)
+		jobs.GET("/:queue/metrics", di.jobsController.ShowQueueMetrics()).Name = "admin.jobs.queue.metrics"
 		jobs.GET("/:queue/delete/:job_id", di.jobsController.DeleteJob())
 		jobs.GET("/:queue/reschedule/:job_id", di.jobsController.RescheduleJob())
 		jobs.GET("/schedule", di.jobsController.CreateJobs()).Name = "admin.jobs.schedule"
@@ -57,6 +74,9 @@ func registerAdminRoutes(di *AdminContext) {
 		jobs.GET("/jobTypes", di.jobsController.ShowJobTypes())
 		jobs.GET("/payloads", di.jobsController.PayloadExamples())
 		jobs.GET("/workers", di.jobsController.ListWorkers())
+		jobs.POST("/workers/:queue/pause", di.jobsController.PauseQueue())
+		jobs.POST("/workers/:queue/resume", di.jobsController.ResumeQueue())
+		jobs.POST("/workers/:queue/scale", di.jobsController.ScaleQueue())
 		jobs.GET("/maintenance", di.jobsController.ShowMaintenance()).Name = "admin.jobs.maintenance"
 		jobs.POST("/vacuum/:table", di.jobsController.VacuumJobTables())
 		jobs.POST("/history", di.jobsController.DeleteHistory())
@@ -66,5 +86,18 @@ func registerAdminRoutes(di *AdminContext) {
 		jobs.GET("/finished", di.jobsController.FinishedJobs()).Name = "admin.jobs.finished"
 		jobs.GET("/finished/total", di.jobsController.FinishedJobsTotal()).Name = "admin.jobs.finished_total"
 		jobs.GET("/job/:job_id", di.jobsController.ShowJob()).Name = "admin.jobs.job"
+		jobs.POST("/job/:job_id", di.jobsController.UpdateJob())
+		jobs.GET("/retries", di.jobsController.ListRetryPolicies()).Name = "admin.jobs.retries"
+		jobs.POST("/retries", di.jobsController.SaveRetryPolicy())
+		jobs.POST("/retries/delete", di.jobsController.DeleteRetryPolicy())
+		jobs.GET("/alerts", di.jobsController.ListAlerts()).Name = "admin.jobs.alerts"
+		jobs.POST("/alerts", di.jobsController.SaveAlertRule())
+		jobs.POST("/alerts/delete", di.jobsController.DeleteAlertRule())
+		jobs.GET("/cron", di.cronController.ListSchedules()).Name = "admin.jobs.cron"
+		jobs.POST("/cron", di.cronController.CreateSchedule())
+		jobs.POST("/cron/:name/pause", di.cronController.PauseSchedule())
+		jobs.POST("/cron/:name/resume", di.cronController.ResumeSchedule())
+		jobs.POST("/cron/:name/trigger", di.cronController.TriggerSchedule())
+		jobs.POST("/cron/:name/delete", di.cronController.DeleteSchedule())
 	}
 }
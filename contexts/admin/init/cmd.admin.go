@@ -0,0 +1,127 @@
+package init
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/application"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/logs"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/interfaces/repository"
+	"github.com/go-arrower/skeleton/shared/infrastructure"
+)
+
+// RegisterCommands adds the commands of the admin context to the root command.
+// They use the Container returned by setup, without starting the background services.
+func RegisterCommands(root *cobra.Command, setup infrastructure.Setup) {
+	jobs := &cobra.Command{Use: "jobs", Short: "Manage the job queues"}
+	jobs.AddCommand(
+		enqueueCommand(setup),
+		pruneHistoryCommand(setup),
+	)
+
+	root.AddCommand(jobs)
+}
+
+func enqueueCommand(setup infrastructure.Setup) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "enqueue <job-type> [payload]",
+		Short: "Enqueue jobs with a JSON payload",
+		Args:  cobra.RangeArgs(1, 2), //nolint:gomnd // the payload is optional
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			payload := "{}"
+			if len(args) == 2 { //nolint:gomnd // the payload is optional
+				payload = args[1]
+			}
+
+			appDI, err := commandApplication(ctx, setup)
+			if err != nil {
+				return err
+			}
+
+			queue, _ := cmd.Flags().GetString("queue")
+			if queue == "Default" {
+				queue = ""
+			}
+
+			priority, _ := cmd.Flags().GetInt16("priority")
+			count, _ := cmd.Flags().GetInt("count")
+
+			err = appDI.ScheduleJobs.H(ctx, application.ScheduleJobsCommand{
+				Queue:    queue,
+				JobType:  args[0],
+				Payload:  payload,
+				Priority: -1 * priority,
+				Count:    count,
+				RunAt:    time.Now(),
+			})
+			if err != nil {
+				return fmt.Errorf("could not enqueue jobs: %w", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "enqueued %d jobs\n", count)
+
+			return nil
+		},
+	}
+	cmd.Flags().String("queue", "Default", "queue to enqueue the jobs to")
+	cmd.Flags().Int("count", 1, "number of jobs to enqueue")
+	cmd.Flags().Int16("priority", 0, "higher priorities are worked on first")
+
+	return cmd
+}
+
+func pruneHistoryCommand(setup infrastructure.Setup) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune-history",
+		Short: "Delete the history of the jobs older than the given days",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			appDI, err := commandApplication(ctx, setup)
+			if err != nil {
+				return err
+			}
+
+			days, _ := cmd.Flags().GetInt("days")
+
+			res, err := appDI.PruneJobHistory.H(ctx, application.PruneJobHistoryRequest{Days: days})
+			if err != nil {
+				return fmt.Errorf("could not prune job history: %w", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "size of jobs: %s, size of history: %s\n", res.Jobs, res.History)
+
+			return nil
+		},
+	}
+	cmd.Flags().Int("days", 30, "keep the history of the last days") //nolint:gomnd
+
+	return cmd
+}
+
+// commandApplication returns the use cases of the admin context, without registering its routes, jobs, and views,
+// as commands run once and do not serve any requests.
+func commandApplication(ctx context.Context, setup infrastructure.Setup) (application.App, error) {
+	di, err := setup(ctx, infrastructure.WithoutBackgroundServices())
+	if err != nil {
+		return application.App{}, err //nolint:wrapcheck // the error is shown as is
+	}
+
+	var logsArchive logs.Archive
+	if di.Config.Logs.ArchiveDir != "" {
+		logsArchive = repository.NewFileLogsArchive(di.Config.Logs.ArchiveDir)
+	}
+
+	return setupApplication(
+		di,
+		repository.NewTracedJobsRepository(repository.NewPostgresJobsRepository(di.PGx)),
+		repository.NewPostgresLogsRepository(di.PGx),
+		logsArchive,
+	), nil
+}
@@ -10,18 +10,26 @@ import (
 	"fmt"
 	"io/fs"
 	"log/slog"
+	"net/http"
 	"os"
+	"time"
 
-	alogmodels "github.com/go-arrower/arrower/alog/models"
 	"github.com/go-arrower/arrower/app"
 
 	"github.com/go-arrower/skeleton/contexts/admin/internal/application"
 	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/logs"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/interfaces/metrics"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/interfaces/notify"
 	"github.com/go-arrower/skeleton/contexts/admin/internal/interfaces/repository"
 	"github.com/go-arrower/skeleton/contexts/admin/internal/interfaces/repository/models"
 	"github.com/go-arrower/skeleton/contexts/admin/internal/interfaces/web"
 	"github.com/go-arrower/skeleton/contexts/admin/internal/views"
 	"github.com/go-arrower/skeleton/shared/infrastructure"
+	"github.com/go-arrower/skeleton/shared/infrastructure/cron"
+	"github.com/go-arrower/skeleton/shared/infrastructure/feature"
+	"github.com/go-arrower/skeleton/shared/infrastructure/maintenance"
+	sharedweb "github.com/go-arrower/skeleton/shared/infrastructure/web"
 )
 
 const contextName = "admin"
@@ -32,11 +40,12 @@ func NewAdminContext(ctx context.Context, di *infrastructure.Container) (*AdminC
 		return nil, fmt.Errorf("missing dependencies to initialise context admin: %w", err)
 	}
 
-	admin, err := setupAdminContext(di)
+	admin, err := setupAdminContext(ctx, di)
 	if err != nil {
 		return nil, fmt.Errorf("could not initialise context admin: %w", err)
 	}
 
+	di.Lifecycle.OnShutdown("admin context", admin.Shutdown)
 	di.Logger.DebugContext(ctx, "context admin initialised")
 
 	return admin, nil
@@ -46,14 +55,20 @@ type AdminContext struct {
 	globalContainer *infrastructure.Container
 
 	jobRepository jobs.Repository
+	jobMetrics    *metrics.JobMetrics
 
 	settingsController *web.SettingsController
 	jobsController     *web.JobsController
+	cronController     *web.CronController
 	logsController     *web.LogsController
+
+	maintenanceController *web.MaintenanceController
+	flagsController       *web.FlagsController
 }
 
-func (c *AdminContext) Shutdown(_ context.Context) error {
-	return nil
+// Shutdown is called by the Lifecycle of the Container, before the shared dependencies are shut down.
+func (c *AdminContext) Shutdown(ctx context.Context) error {
+	return c.jobMetrics.Shutdown(ctx) //nolint:wrapcheck // wrapped by the Lifecycle
 }
 
 func ensureRequiredDependencies(di *infrastructure.Container) error {
@@ -81,20 +96,109 @@ func ensureRequiredDependencies(di *infrastructure.Container) error {
 		return fmt.Errorf("%w: settings", infrastructure.ErrMissingDependency)
 	}
 
+	if di.ArrowerQueue == nil {
+		return fmt.Errorf("%w: arrower queue", infrastructure.ErrMissingDependency)
+	}
+
+	if di.Scheduler == nil {
+		return fmt.Errorf("%w: scheduler", infrastructure.ErrMissingDependency)
+	}
+
+	if di.Maintenance == nil {
+		return fmt.Errorf("%w: maintenance", infrastructure.ErrMissingDependency)
+	}
+
+	if di.Features == nil {
+		return fmt.Errorf("%w: feature flags", infrastructure.ErrMissingDependency)
+	}
+
 	return nil
 }
 
-func setupAdminContext(di *infrastructure.Container) (*AdminContext, error) {
+func setupAdminContext(ctx context.Context, di *infrastructure.Container) (*AdminContext, error) {
 	logger := di.Logger.With(slog.String("context", contextName))
 
 	jobRepository := repository.NewTracedJobsRepository(repository.NewPostgresJobsRepository(di.PGx))
+	logsRepository := repository.NewPostgresLogsRepository(di.PGx)
+
+	var logsArchive logs.Archive
+	if di.Config.Logs.ArchiveDir != "" {
+		logsArchive = repository.NewFileLogsArchive(di.Config.Logs.ArchiveDir)
+	}
+
+	appDI := setupApplication(di, jobRepository, logsRepository, logsArchive)
+
+	meter := di.MeterProvider.Meter(fmt.Sprintf("%s/%s", di.Config.ApplicationName, contextName))
+
+	jobMetrics := metrics.NewJobMetrics(logger, jobRepository)
 
-	appDI := setupApplication(di, jobRepository)
+	err := jobMetrics.Register(meter)
+	if err != nil {
+		return nil, fmt.Errorf("could not register job metrics: %w", err)
+	}
+
+	jobMetrics.Start(ctx)
+
+	{ // evaluate the alert rules every minute, run by the leading instance only.
+		err = di.ArrowerQueue.RegisterJobFunc(
+			app.NewInstrumentedJob(di.TraceProvider, di.MeterProvider, di.Logger,
+				application.NewEvaluateAlertsJobHandler(logger, jobRepository, notify.NewChannels(logger, di.Mailer, nil)),
+			).H,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not register alert job: %w", err)
+		}
+
+		err = di.Scheduler.Register(ctx, "admin.evaluate-alerts", "* * * * *", "Arrower", application.EvaluateAlertsJob{})
+		if err != nil {
+			return nil, fmt.Errorf("could not schedule alert job: %w", err)
+		}
+	}
+
+	{ // prune the logs by their retention policy, run by the leading instance only.
+		err = di.ArrowerQueue.RegisterJobFunc(
+			app.NewInstrumentedJob(di.TraceProvider, di.MeterProvider, di.Logger,
+				application.NewPruneLogsJobHandler(logger, di.Settings, logsRepository, logsArchive),
+			).H,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("could not register prune logs job: %w", err)
+		}
+
+		err = di.Scheduler.Register(ctx, "admin.prune-logs", "@hourly", "Arrower", application.PruneLogsJob{})
+		if err != nil {
+			return nil, fmt.Errorf("could not schedule prune logs job: %w", err)
+		}
+	}
+
+	if di.Config.Role.RunsJobs() { // an instance, that only serves the web, has no worker pools
+		di.Health.Register("job workers", func(ctx context.Context) error {
+			pools, err := jobRepository.WorkerPools(ctx)
+			if err != nil {
+				return fmt.Errorf("could not get worker pools: %w", err)
+			}
+
+			return jobs.CheckHeartbeat(pools, di.Config.InstanceName, time.Now()) //nolint:wrapcheck // domain error
+		}, infrastructure.Optional())
+	}
+
+	// release the jobs parked during the maintenance. Each instance does it, as releasing them twice is harmless.
+	di.Maintenance.OnChange(func(ctx context.Context, old maintenance.Mode, mode maintenance.Mode) {
+		if !old.PausesJobs() || mode.PausesJobs() {
+			return
+		}
+
+		err := appDI.ResumeAfterMaintenance.H(ctx, application.ResumeAfterMaintenanceCommand{})
+		if err != nil {
+			logger.LogAttrs(ctx, slog.LevelError, "could not resume jobs after maintenance", slog.String("err", err.Error()))
+		}
+	})
 
 	admin := &AdminContext{
 		globalContainer: di,
 
 		jobRepository: jobRepository,
+		jobMetrics:    jobMetrics,
 
 		settingsController: web.NewSettingsController(di.AdminRouter),
 		jobsController: web.NewJobsController(
@@ -107,12 +211,16 @@ func setupAdminContext(di *infrastructure.Container) (*AdminContext, error) {
 			),
 			appDI,
 		),
+		cronController: web.NewCronController(logger, di.Scheduler, jobRepository),
 		logsController: web.NewLogsController(
 			logger,
 			di.Settings,
-			alogmodels.New(di.PGx),
+			logsRepository,
+			appDI,
 			di.AdminRouter.Group("/logs"),
 		),
+		maintenanceController: web.NewMaintenanceController(appDI, di.AdminRouter),
+		flagsController:       web.NewFlagsController(logger, di.Features),
 	}
 
 	{ // add context-specific web views.
@@ -121,7 +229,7 @@ func setupAdminContext(di *infrastructure.Container) (*AdminContext, error) {
 			views = os.DirFS("contexts/admin/internal/views")
 		}
 
-		err := di.WebRenderer.AddContext(contextName, views)
+		err = di.WebRenderer.AddContext(contextName, views)
 		if err != nil {
 			return nil, fmt.Errorf("could not add context views: %w", err)
 		}
@@ -136,12 +244,42 @@ func setupAdminContext(di *infrastructure.Container) (*AdminContext, error) {
 		}
 	}
 
+	registerErrors(di.ErrorHandler)
 	registerAdminRoutes(admin)
 
 	return admin, nil
 }
 
-func setupApplication(di *infrastructure.Container, jobRepository *repository.TracedJobsRepository) application.App {
+// registerErrors answers requests failing with an error of the admin context with a fitting status code.
+func registerErrors(errorHandler *sharedweb.ErrorHandler) {
+	if errorHandler == nil {
+		return
+	}
+
+	errorHandler.Register(http.StatusNotFound, jobs.ErrJobNotFound, cron.ErrScheduleNotFound, feature.ErrFlagNotFound)
+	errorHandler.Register(http.StatusConflict, jobs.ErrJobLockedAlready, cron.ErrScheduleExists, feature.ErrFlagExists)
+	errorHandler.Register(http.StatusBadRequest,
+		jobs.ErrInvalidPayload,
+		jobs.ErrInvalidAlertRule,
+		jobs.ErrInvalidRetryPolicy,
+		jobs.ErrInvalidWindow,
+		logs.ErrInvalidQuery,
+		logs.ErrInvalidSavedSearch,
+		logs.ErrInvalidRetention,
+		application.ErrInvalidWorkers,
+		cron.ErrInvalidExpression,
+		cron.ErrUnknownQueue,
+		maintenance.ErrInvalidMode,
+		feature.ErrInvalidFlag,
+	)
+}
+
+func setupApplication(
+	di *infrastructure.Container,
+	jobRepository *repository.TracedJobsRepository,
+	logsRepository logs.Repository,
+	logsArchive logs.Archive,
+) application.App {
 	return application.App{
 		PruneJobHistory: app.NewInstrumentedRequest(di.TraceProvider, di.MeterProvider, di.Logger,
 			application.NewPruneJobHistoryRequestHandler(models.New(di.PGx)),
@@ -167,5 +305,47 @@ func setupApplication(di *infrastructure.Container, jobRepository *repository.Tr
 		ScheduleJobs: app.NewInstrumentedCommand(di.TraceProvider, di.MeterProvider, di.Logger,
 			application.NewScheduleJobsCommandHandler(models.New(di.PGx)),
 		),
+		UpdateJob: app.NewInstrumentedCommand(di.TraceProvider, di.MeterProvider, di.Logger,
+			application.NewUpdateJobCommandHandler(jobRepository),
+		),
+		SaveRetryPolicy: app.NewInstrumentedCommand(di.TraceProvider, di.MeterProvider, di.Logger,
+			application.NewSaveRetryPolicyCommandHandler(jobRepository),
+		),
+		GetQueueControls: app.NewInstrumentedQuery(di.TraceProvider, di.MeterProvider, di.Logger,
+			application.NewGetQueueControlsQueryHandler(di.Settings, jobRepository),
+		),
+		PauseQueue: app.NewInstrumentedCommand(di.TraceProvider, di.MeterProvider, di.Logger,
+			application.NewPauseQueueCommandHandler(di.Settings),
+		),
+		ResumeQueue: app.NewInstrumentedCommand(di.TraceProvider, di.MeterProvider, di.Logger,
+			application.NewResumeQueueCommandHandler(di.Settings, jobRepository),
+		),
+		ScaleQueue: app.NewInstrumentedCommand(di.TraceProvider, di.MeterProvider, di.Logger,
+			application.NewScaleQueueCommandHandler(di.Settings),
+		),
+		GetJobMetrics: app.NewInstrumentedQuery(di.TraceProvider, di.MeterProvider, di.Logger,
+			application.NewGetJobMetricsQueryHandler(jobRepository),
+		),
+		SaveAlertRule: app.NewInstrumentedCommand(di.TraceProvider, di.MeterProvider, di.Logger,
+			application.NewSaveAlertRuleCommandHandler(jobRepository),
+		),
+		GetLogRetention: app.NewInstrumentedQuery(di.TraceProvider, di.MeterProvider, di.Logger,
+			application.NewGetLogRetentionQueryHandler(di.Settings, logsRepository, logsArchive),
+		),
+		SaveLogRetention: app.NewInstrumentedCommand(di.TraceProvider, di.MeterProvider, di.Logger,
+			application.NewSaveLogRetentionCommandHandler(di.Settings),
+		),
+		PruneLogs: app.NewInstrumentedRequest(di.TraceProvider, di.MeterProvider, di.Logger,
+			application.NewPruneLogsRequestHandler(di.Settings, logsRepository, logsArchive),
+		),
+		GetMaintenance: app.NewInstrumentedQuery(di.TraceProvider, di.MeterProvider, di.Logger,
+			application.NewGetMaintenanceQueryHandler(di.Settings),
+		),
+		SaveMaintenance: app.NewInstrumentedCommand(di.TraceProvider, di.MeterProvider, di.Logger,
+			application.NewSaveMaintenanceCommandHandler(di.Settings),
+		),
+		ResumeAfterMaintenance: app.NewInstrumentedCommand(di.TraceProvider, di.MeterProvider, di.Logger,
+			application.NewResumeAfterMaintenanceCommandHandler(di.Settings, jobRepository),
+		),
 	}
 }
@@ -16,4 +16,19 @@ type App struct {
 	JobTypesForQueue app.Query[JobTypesForQueueQuery, []jobs.JobType]
 	ListAllQueues    app.Query[ListAllQueuesQuery, ListAllQueuesResponse]
 	ScheduleJobs     app.Command[ScheduleJobsCommand]
+	UpdateJob        app.Command[UpdateJobCommand]
+	SaveRetryPolicy  app.Command[SaveRetryPolicyCommand]
+	GetQueueControls app.Query[GetQueueControlsQuery, GetQueueControlsResponse]
+	PauseQueue       app.Command[PauseQueueCommand]
+	ResumeQueue      app.Command[ResumeQueueCommand]
+	ScaleQueue       app.Command[ScaleQueueCommand]
+	GetJobMetrics    app.Query[GetJobMetricsQuery, GetJobMetricsResponse]
+	SaveAlertRule    app.Command[SaveAlertRuleCommand]
+	GetLogRetention  app.Query[GetLogRetentionQuery, GetLogRetentionResponse]
+	SaveLogRetention app.Command[SaveLogRetentionCommand]
+	PruneLogs        app.Request[PruneLogsRequest, PruneLogsResponse]
+	GetMaintenance   app.Query[GetMaintenanceQuery, GetMaintenanceResponse]
+	SaveMaintenance  app.Command[SaveMaintenanceCommand]
+	// ResumeAfterMaintenance is not called by a controller, but when a maintenance ends.
+	ResumeAfterMaintenance app.Command[ResumeAfterMaintenanceCommand]
 }
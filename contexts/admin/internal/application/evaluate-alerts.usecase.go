@@ -0,0 +1,164 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/go-arrower/arrower/app"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+)
+
+var ErrEvaluateAlertsFailed = errors.New("evaluate alerts failed")
+
+// defaultAlertChannel is used for rules without any channel, so no alert goes unnoticed.
+const defaultAlertChannel = "log"
+
+func NewEvaluateAlertsJobHandler(logger alog.Logger, repo jobs.Repository, notifier jobs.Notifier) app.Job[EvaluateAlertsJob] {
+	return &evaluateAlertsJobHandler{
+		logger:   logger,
+		repo:     repo,
+		notifier: notifier,
+	}
+}
+
+type evaluateAlertsJobHandler struct {
+	logger   alog.Logger
+	repo     jobs.Repository
+	notifier jobs.Notifier
+}
+
+// EvaluateAlertsJob checks all alert rules and notifies their channels about fired and resolved alerts.
+type EvaluateAlertsJob struct{}
+
+func (j EvaluateAlertsJob) JobType() string { return "admin.evaluate-alerts" }
+
+func (h *evaluateAlertsJobHandler) H(ctx context.Context, _ EvaluateAlertsJob) error {
+	now := time.Now()
+
+	rules, err := h.repo.AlertRules(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrEvaluateAlertsFailed, err)
+	}
+
+	alerts, err := h.repo.Alerts(ctx, now)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrEvaluateAlertsFailed, err)
+	}
+
+	open := map[string]*jobs.Alert{}
+
+	for i := range alerts {
+		if !alerts[i].IsResolved() {
+			open[alerts[i].Rule] = &alerts[i]
+		}
+	}
+
+	var errs []error
+
+	// a failing rule does not prevent the other rules from being evaluated
+	for _, rule := range rules {
+		if err := h.evaluate(ctx, rule, open[rule.Name], now); err != nil {
+			errs = append(errs, fmt.Errorf("rule %s: %w", rule.Name, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrEvaluateAlertsFailed, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// evaluate updates the alert of the rule and notifies about its transition.
+func (h *evaluateAlertsJobHandler) evaluate(ctx context.Context, rule jobs.AlertRule, open *jobs.Alert, now time.Time) error {
+	value, err := h.observe(ctx, rule, now)
+	if err != nil {
+		return err
+	}
+
+	alert, transition := rule.Evaluate(open, value, now)
+
+	if alert == nil {
+		if open != nil {
+			if err := h.repo.DeleteAlert(ctx, open.ID); err != nil {
+				return fmt.Errorf("could not delete alert: %w", err)
+			}
+		}
+
+		return nil
+	}
+
+	if err := h.repo.SaveAlert(ctx, *alert); err != nil {
+		return fmt.Errorf("could not save alert: %w", err)
+	}
+
+	if transition != jobs.TransitionNone {
+		h.notify(ctx, rule, *alert, transition)
+	}
+
+	return nil
+}
+
+// observe returns the current value of the metric the rule watches.
+func (h *evaluateAlertsJobHandler) observe(ctx context.Context, rule jobs.AlertRule, now time.Time) (float64, error) {
+	switch rule.Condition {
+	case jobs.ConditionPendingAbove, jobs.ConditionNoWorkers:
+		kpis, err := h.repo.QueueKPIs(ctx, rule.Queue)
+		if err != nil {
+			return 0, fmt.Errorf("could not get queue kpis: %w", err)
+		}
+
+		if rule.Condition == jobs.ConditionNoWorkers {
+			return float64(kpis.AvailableWorkers), nil
+		}
+
+		if rule.JobType != "" {
+			return float64(kpis.PendingJobsPerType[string(rule.JobType)]), nil
+		}
+
+		return float64(kpis.PendingJobs), nil
+	case jobs.ConditionFailureRateAbove:
+		metrics, err := h.repo.JobMetrics(ctx, rule.Queue, now.Add(-rule.FailureRateWindow()))
+		if err != nil {
+			return 0, fmt.Errorf("could not get job metrics: %w", err)
+		}
+
+		var total jobs.JobTypeMetrics
+
+		for _, m := range metrics {
+			if rule.JobType == "" || m.JobType == rule.JobType {
+				total.Processed += m.Processed
+				total.Failed += m.Failed
+			}
+		}
+
+		return total.FailureRate(), nil
+	default:
+		return 0, fmt.Errorf("%w: unknown condition: %s", jobs.ErrInvalidAlertRule, rule.Condition)
+	}
+}
+
+// notify sends the alert to all channels of the rule.
+// A failing channel is logged, so it does not prevent the other channels from being notified.
+func (h *evaluateAlertsJobHandler) notify(ctx context.Context, rule jobs.AlertRule, alert jobs.Alert, transition jobs.Transition) {
+	channels := rule.Channels
+	if len(channels) == 0 {
+		channels = []string{defaultAlertChannel}
+	}
+
+	for _, channel := range channels {
+		err := h.notifier.Notify(ctx, channel, alert, transition)
+		if err != nil {
+			h.logger.LogAttrs(ctx, slog.LevelError, "could not notify about alert",
+				slog.String("rule", rule.Name),
+				slog.String("channel", channel),
+				slog.String("err", err.Error()),
+			)
+		}
+	}
+}
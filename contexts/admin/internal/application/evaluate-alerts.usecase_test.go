@@ -0,0 +1,111 @@
+//go:build integration
+
+package application_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/application"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/interfaces/repository"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/interfaces/repository/models"
+)
+
+func TestEvaluateAlertsJobHandler_H(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fire and resolve", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		repo := repository.NewPostgresJobsRepository(pg)
+		notifier := &fakeNotifier{}
+		handler := application.NewEvaluateAlertsJobHandler(alog.NewNoopLogger(), repo, notifier)
+
+		_ = application.NewSaveAlertRuleCommandHandler(repo).H(ctx, application.SaveAlertRuleCommand{
+			Rule: jobs.AlertRule{Name: "too-many-pending", Condition: jobs.ConditionPendingAbove, Threshold: 1},
+		})
+		_ = application.NewScheduleJobsCommandHandler(models.New(pg)).H(ctx, application.ScheduleJobsCommand{
+			JobType: "SomeJob",
+			Payload: `{}`,
+			Count:   2,
+			RunAt:   time.Now().Add(time.Hour),
+		})
+
+		err := handler.H(ctx, application.EvaluateAlertsJob{})
+		assert.NoError(t, err)
+		assert.Equal(t, []jobs.Transition{jobs.TransitionFired}, notifier.transitions)
+		assert.Equal(t, []string{"log"}, notifier.channels, "rules without channels are logged")
+
+		err = handler.H(ctx, application.EvaluateAlertsJob{})
+		assert.NoError(t, err)
+		assert.Len(t, notifier.transitions, 1, "a firing alert is notified once")
+
+		_, _ = pg.Exec(ctx, `DELETE FROM arrower.gue_jobs`)
+
+		err = handler.H(ctx, application.EvaluateAlertsJob{})
+		assert.NoError(t, err)
+		assert.Equal(t, []jobs.Transition{jobs.TransitionFired, jobs.TransitionResolved}, notifier.transitions)
+
+		alerts, _ := repo.Alerts(ctx, time.Time{})
+		assert.Len(t, alerts, 1)
+		assert.True(t, alerts[0].IsResolved())
+	})
+
+	t.Run("pending alert", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		repo := repository.NewPostgresJobsRepository(pg)
+		notifier := &fakeNotifier{}
+		handler := application.NewEvaluateAlertsJobHandler(alog.NewNoopLogger(), repo, notifier)
+
+		_ = application.NewSaveAlertRuleCommandHandler(repo).H(ctx, application.SaveAlertRuleCommand{
+			Rule: jobs.AlertRule{Name: "no-workers", Condition: jobs.ConditionNoWorkers, For: time.Hour},
+		})
+
+		err := handler.H(ctx, application.EvaluateAlertsJob{})
+		assert.NoError(t, err)
+		assert.Empty(t, notifier.transitions, "alert is pending until the rule is met for its duration")
+
+		alerts, _ := repo.Alerts(ctx, time.Now())
+		assert.Len(t, alerts, 1)
+		assert.False(t, alerts[0].IsFiring())
+	})
+
+	t.Run("failing rule", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		repo := repository.NewPostgresJobsRepository(pg)
+		notifier := &fakeNotifier{}
+		handler := application.NewEvaluateAlertsJobHandler(alog.NewNoopLogger(), repo, notifier)
+
+		_, _ = pg.Exec(ctx, `INSERT INTO arrower.job_alert_rules (name, condition) VALUES ('a-broken-rule', 'unknown')`)
+		_ = application.NewSaveAlertRuleCommandHandler(repo).H(ctx, application.SaveAlertRuleCommand{
+			Rule: jobs.AlertRule{Name: "no-workers", Condition: jobs.ConditionNoWorkers},
+		})
+
+		err := handler.H(ctx, application.EvaluateAlertsJob{})
+		assert.ErrorIs(t, err, application.ErrEvaluateAlertsFailed)
+		assert.ErrorIs(t, err, jobs.ErrInvalidAlertRule)
+		assert.Equal(t, []jobs.Transition{jobs.TransitionFired}, notifier.transitions, "the other rules are evaluated")
+	})
+}
+
+type fakeNotifier struct {
+	channels    []string
+	transitions []jobs.Transition
+}
+
+func (n *fakeNotifier) Notify(_ context.Context, channel string, _ jobs.Alert, transition jobs.Transition) error {
+	n.channels = append(n.channels, channel)
+	n.transitions = append(n.transitions, transition)
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-arrower/arrower/app"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+)
+
+var ErrGetJobMetricsFailed = errors.New("get job metrics failed")
+
+func NewGetJobMetricsQueryHandler(repo jobs.Repository) app.Query[GetJobMetricsQuery, GetJobMetricsResponse] {
+	return &getJobMetricsQueryHandler{repo: repo}
+}
+
+type getJobMetricsQueryHandler struct {
+	repo jobs.Repository
+}
+
+type (
+	GetJobMetricsQuery struct {
+		Queue jobs.QueueName
+		// Window is the name of a jobs.MetricsWindow, empty is the shortest window.
+		Window string
+	}
+	GetJobMetricsResponse struct {
+		Window   jobs.MetricsWindow
+		JobTypes []jobs.JobTypeMetrics
+		Series   []jobs.MetricsBucket
+	}
+)
+
+func (h *getJobMetricsQueryHandler) H(ctx context.Context, query GetJobMetricsQuery) (GetJobMetricsResponse, error) {
+	window, err := jobs.WindowByName(query.Window)
+	if err != nil {
+		return GetJobMetricsResponse{}, fmt.Errorf("%w: %w", ErrGetJobMetricsFailed, err)
+	}
+
+	since := time.Now().Add(-window.Range)
+
+	metrics, err := h.repo.JobMetrics(ctx, query.Queue, since)
+	if err != nil {
+		return GetJobMetricsResponse{}, fmt.Errorf("%w: %w", ErrGetJobMetricsFailed, err)
+	}
+
+	series, err := h.repo.JobMetricsTimeSeries(ctx, query.Queue, since, window.Bin)
+	if err != nil {
+		return GetJobMetricsResponse{}, fmt.Errorf("%w: %w", ErrGetJobMetricsFailed, err)
+	}
+
+	return GetJobMetricsResponse{
+		Window:   window,
+		JobTypes: metrics,
+		Series:   series,
+	}, nil
+}
@@ -0,0 +1,78 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-arrower/arrower/app"
+	"github.com/go-arrower/arrower/setting"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/logs"
+)
+
+var ErrGetLogRetentionFailed = errors.New("get log retention failed")
+
+var (
+	SettingLogRetentionDays    = setting.NewKey("admin", "logs", "retention_days")
+	SettingLogRetentionRows    = setting.NewKey("admin", "logs", "retention_rows")
+	SettingLogRetentionArchive = setting.NewKey("admin", "logs", "retention_archive")
+)
+
+func NewGetLogRetentionQueryHandler(
+	settings setting.Settings,
+	repo logs.Repository,
+	archive logs.Archive,
+) app.Query[GetLogRetentionQuery, GetLogRetentionResponse] {
+	return &getLogRetentionQueryHandler{settings: settings, repo: repo, archive: archive}
+}
+
+type getLogRetentionQueryHandler struct {
+	settings setting.Settings
+	repo     logs.Repository
+	archive  logs.Archive
+}
+
+type (
+	GetLogRetentionQuery    struct{}
+	GetLogRetentionResponse struct {
+		Policy logs.RetentionPolicy
+		Size   logs.TableSize
+		// CanArchive is false, if no archive is configured.
+		CanArchive bool
+	}
+)
+
+func (h *getLogRetentionQueryHandler) H(ctx context.Context, _ GetLogRetentionQuery) (GetLogRetentionResponse, error) {
+	size, err := h.repo.TableSize(ctx)
+	if err != nil {
+		return GetLogRetentionResponse{}, fmt.Errorf("%w: %w", ErrGetLogRetentionFailed, err)
+	}
+
+	return GetLogRetentionResponse{
+		Policy:     retentionPolicy(ctx, h.settings),
+		Size:       size,
+		CanArchive: h.archive != nil,
+	}, nil
+}
+
+// retentionPolicy returns the policy as saved by the admin. Without any settings, all logs are kept.
+// Invalid settings are ignored, so they keep the logs as well.
+func retentionPolicy(ctx context.Context, settings setting.Settings) logs.RetentionPolicy {
+	var policy logs.RetentionPolicy
+
+	if val, err := settings.Setting(ctx, SettingLogRetentionDays); err == nil {
+		policy.MaxAgeDays, _ = strconv.Atoi(val.String())
+	}
+
+	if val, err := settings.Setting(ctx, SettingLogRetentionRows); err == nil {
+		policy.MaxRows, _ = strconv.Atoi(val.String())
+	}
+
+	if val, err := settings.Setting(ctx, SettingLogRetentionArchive); err == nil {
+		policy.Archive, _ = strconv.ParseBool(val.String())
+	}
+
+	return policy
+}
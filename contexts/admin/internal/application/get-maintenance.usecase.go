@@ -0,0 +1,29 @@
+package application
+
+import (
+	"context"
+
+	"github.com/go-arrower/arrower/app"
+	"github.com/go-arrower/arrower/setting"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/maintenance"
+)
+
+func NewGetMaintenanceQueryHandler(settings setting.Settings) app.Query[GetMaintenanceQuery, GetMaintenanceResponse] {
+	return &getMaintenanceQueryHandler{settings: settings}
+}
+
+type getMaintenanceQueryHandler struct {
+	settings setting.Settings
+}
+
+type (
+	GetMaintenanceQuery    struct{}
+	GetMaintenanceResponse struct {
+		Mode maintenance.Mode
+	}
+)
+
+func (h *getMaintenanceQueryHandler) H(ctx context.Context, _ GetMaintenanceQuery) (GetMaintenanceResponse, error) {
+	return GetMaintenanceResponse{Mode: maintenance.Load(ctx, h.settings)}, nil
+}
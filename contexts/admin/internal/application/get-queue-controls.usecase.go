@@ -0,0 +1,97 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/go-arrower/arrower/app"
+	"github.com/go-arrower/arrower/setting"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+	"github.com/go-arrower/skeleton/shared/infrastructure/jobqueue"
+)
+
+var ErrGetQueueControlsFailed = errors.New("get queue controls failed")
+
+func NewGetQueueControlsQueryHandler(settings setting.Settings, repo jobs.Repository) app.Query[GetQueueControlsQuery, GetQueueControlsResponse] {
+	return &getQueueControlsQueryHandler{settings: settings, repo: repo}
+}
+
+type getQueueControlsQueryHandler struct {
+	settings setting.Settings
+	repo     jobs.Repository
+}
+
+type (
+	GetQueueControlsQuery    struct{}
+	GetQueueControlsResponse struct {
+		Controls []jobs.QueueControls
+	}
+)
+
+// H returns the controls of all queues, that have a worker pool running.
+func (h *getQueueControlsQueryHandler) H(ctx context.Context, _ GetQueueControlsQuery) (GetQueueControlsResponse, error) {
+	pools, err := h.repo.WorkerPools(ctx)
+	if err != nil {
+		return GetQueueControlsResponse{}, fmt.Errorf("%w: %w", ErrGetQueueControlsFailed, err)
+	}
+
+	jobTypes := map[jobs.QueueName]map[jobs.JobType]bool{}
+
+	for _, pool := range pools {
+		queue := jobs.QueueName(pool.Queue)
+		if queue == "" {
+			queue = jobs.DefaultQueueName
+		}
+
+		if jobTypes[queue] == nil {
+			jobTypes[queue] = map[jobs.JobType]bool{}
+		}
+
+		for _, jt := range pool.JobTypes {
+			jobTypes[queue][jobs.JobType(jt)] = true
+		}
+	}
+
+	controls := make([]jobs.QueueControls, 0, len(jobTypes))
+
+	for queue, types := range jobTypes {
+		c := jobs.QueueControls{
+			Queue:          queue,
+			Paused:         isPaused(ctx, h.settings, jobqueue.SettingPaused(string(queue))),
+			Workers:        workers(ctx, h.settings, queue),
+			JobTypes:       make([]jobs.JobType, 0, len(types)),
+			PausedJobTypes: map[jobs.JobType]bool{},
+		}
+
+		for jt := range types {
+			c.JobTypes = append(c.JobTypes, jt)
+
+			if isPaused(ctx, h.settings, jobqueue.SettingJobTypePaused(string(queue), string(jt))) {
+				c.PausedJobTypes[jt] = true
+			}
+		}
+
+		sort.Slice(c.JobTypes, func(i, j int) bool { return c.JobTypes[i] < c.JobTypes[j] })
+
+		controls = append(controls, c)
+	}
+
+	sort.Slice(controls, func(i, j int) bool { return controls[i].Queue < controls[j].Queue })
+
+	return GetQueueControlsResponse{Controls: controls}, nil
+}
+
+func workers(ctx context.Context, settings setting.Settings, queue jobs.QueueName) int {
+	val, err := settings.Setting(ctx, jobqueue.SettingWorkers(string(queue)))
+	if err != nil {
+		return 0
+	}
+
+	n, _ := strconv.Atoi(val.String())
+
+	return n
+}
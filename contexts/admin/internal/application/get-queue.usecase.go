@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/go-arrower/arrower/app"
 
@@ -27,6 +28,9 @@ type (
 	GetQueueResponse struct {
 		Jobs []jobs.PendingJob
 		Kpis jobs.QueueKPIs
+		// EstimateUntilEmpty is based on the throughput of the last hour.
+		// It is zero, if no job got processed within the last hour.
+		EstimateUntilEmpty time.Duration
 	}
 )
 
@@ -41,8 +45,18 @@ func (h *getQueueQueryHandler) H(ctx context.Context, query GetQueueQuery) (GetQ
 		return GetQueueResponse{}, fmt.Errorf("%w: could not get pending jobs: %w", ErrGetQueueFailed, err)
 	}
 
+	metrics, err := h.repo.JobMetrics(ctx, query.QueueName, time.Now().Add(-time.Hour))
+	if err != nil {
+		return GetQueueResponse{}, fmt.Errorf("%w: could not get job metrics: %w", ErrGetQueueFailed, err)
+	}
+
 	return GetQueueResponse{
-		Jobs: jobs,
-		Kpis: kpis,
+		Jobs:               jobs,
+		Kpis:               kpis,
+		EstimateUntilEmpty: estimateUntilEmpty(kpis.PendingJobs, metrics),
 	}, nil
 }
+
+func estimateUntilEmpty(pending int, metrics []jobs.JobTypeMetrics) time.Duration {
+	return jobs.EstimateUntilEmpty(pending, metrics, time.Hour)
+}
@@ -0,0 +1,46 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-arrower/arrower/app"
+	"github.com/go-arrower/arrower/setting"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+	"github.com/go-arrower/skeleton/shared/infrastructure/jobqueue"
+)
+
+var ErrPauseQueueFailed = errors.New("pause queue failed")
+
+func NewPauseQueueCommandHandler(settings setting.Settings) app.Command[PauseQueueCommand] {
+	return &pauseQueueCommandHandler{settings: settings}
+}
+
+type pauseQueueCommandHandler struct {
+	settings setting.Settings
+}
+
+// PauseQueueCommand pauses all jobs of the Queue or, if given, only the jobs of the JobType.
+type PauseQueueCommand struct {
+	Queue   jobs.QueueName
+	JobType jobs.JobType
+}
+
+func (h *pauseQueueCommandHandler) H(ctx context.Context, cmd PauseQueueCommand) error {
+	err := h.settings.Save(ctx, pausedSetting(cmd.Queue, cmd.JobType), setting.NewValue(true))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrPauseQueueFailed, err)
+	}
+
+	return nil
+}
+
+func pausedSetting(queue jobs.QueueName, jobType jobs.JobType) setting.Key {
+	if jobType == "" {
+		return jobqueue.SettingPaused(string(queue))
+	}
+
+	return jobqueue.SettingJobTypePaused(string(queue), string(jobType))
+}
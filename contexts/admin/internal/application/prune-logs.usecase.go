@@ -0,0 +1,170 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/go-arrower/arrower/app"
+	"github.com/go-arrower/arrower/setting"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/logs"
+)
+
+var ErrPruneLogsFailed = errors.New("prune logs failed")
+
+// NewPruneLogsJobHandler prunes the logs by the retention policy saved by the admin.
+func NewPruneLogsJobHandler(
+	logger alog.Logger,
+	settings setting.Settings,
+	repo logs.Repository,
+	archive logs.Archive,
+) app.Job[PruneLogsJob] {
+	return &pruneLogsJobHandler{
+		logger:   logger,
+		settings: settings,
+		repo:     repo,
+		archive:  archive,
+	}
+}
+
+type pruneLogsJobHandler struct {
+	logger   alog.Logger
+	settings setting.Settings
+	repo     logs.Repository
+	archive  logs.Archive
+}
+
+// PruneLogsJob applies the retention policy to the logs written into postgres.
+type PruneLogsJob struct{}
+
+func (j PruneLogsJob) JobType() string { return "admin.prune-logs" }
+
+func (h *pruneLogsJobHandler) H(ctx context.Context, _ PruneLogsJob) error {
+	policy := retentionPolicy(ctx, h.settings)
+	if !policy.IsEnabled() {
+		return nil
+	}
+
+	var (
+		beyondMaxRows time.Time
+		err           error
+	)
+
+	if policy.MaxRows > 0 {
+		beyondMaxRows, err = h.repo.NewestBeyond(ctx, policy.MaxRows)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrPruneLogsFailed, err)
+		}
+	}
+
+	until := policy.Until(time.Now(), beyondMaxRows)
+	if until.IsZero() {
+		return nil
+	}
+
+	deleted, path, err := pruneLogs(ctx, h.repo, h.archive, until, policy.Archive)
+	if err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		h.logger.LogAttrs(ctx, slog.LevelInfo, "logs pruned",
+			slog.Int64("deleted", deleted),
+			slog.Time("until", until),
+			slog.String("archive", path),
+		)
+	}
+
+	return nil
+}
+
+func NewPruneLogsRequestHandler(
+	settings setting.Settings,
+	repo logs.Repository,
+	archive logs.Archive,
+) app.Request[PruneLogsRequest, PruneLogsResponse] {
+	return &pruneLogsRequestHandler{settings: settings, repo: repo, archive: archive}
+}
+
+type pruneLogsRequestHandler struct {
+	settings setting.Settings
+	repo     logs.Repository
+	archive  logs.Archive
+}
+
+type (
+	// PruneLogsRequest deletes the logs older than Days. Zero deletes all logs.
+	// The logs are archived, if the retention policy says so.
+	PruneLogsRequest struct {
+		Days int
+	}
+
+	PruneLogsResponse struct {
+		Size    logs.TableSize
+		Deleted int64
+		// Archive is where the deleted logs are archived, if they are.
+		Archive string
+	}
+)
+
+func (h *pruneLogsRequestHandler) H(ctx context.Context, req PruneLogsRequest) (PruneLogsResponse, error) {
+	if req.Days < 0 {
+		return PruneLogsResponse{}, fmt.Errorf("%w: %w: days can not be negative", ErrPruneLogsFailed, logs.ErrInvalidRetention)
+	}
+
+	until := time.Now().AddDate(0, 0, -req.Days)
+
+	deleted, path, err := pruneLogs(ctx, h.repo, h.archive, until, retentionPolicy(ctx, h.settings).Archive)
+	if err != nil {
+		return PruneLogsResponse{}, err
+	}
+
+	size, err := h.repo.TableSize(ctx)
+	if err != nil {
+		return PruneLogsResponse{}, fmt.Errorf("%w: could not get new log table size: %w", ErrPruneLogsFailed, err)
+	}
+
+	return PruneLogsResponse{
+		Size:    size,
+		Deleted: deleted,
+		Archive: path,
+	}, nil
+}
+
+// pruneLogs deletes all logs up to until. If the logs are to be archived, they are only deleted
+// once the archive is saved, so no log is lost if archiving fails.
+func pruneLogs(
+	ctx context.Context,
+	repo logs.Repository,
+	archive logs.Archive,
+	until time.Time,
+	archiveLogs bool,
+) (int64, string, error) {
+	var path string
+
+	if archiveLogs {
+		if archive == nil {
+			return 0, "", fmt.Errorf("%w: the policy archives logs, but no archive is configured", ErrPruneLogsFailed)
+		}
+
+		var err error
+
+		path, err = archive.Save(ctx, "logs-until-"+until.UTC().Format("20060102T150405Z"), func(fn func(logs.Log) error) error {
+			return repo.Export(ctx, logs.Search{To: until}, fn) //nolint:exhaustruct // all logs up to until
+		})
+		if err != nil {
+			return 0, "", fmt.Errorf("%w: %w", ErrPruneLogsFailed, err)
+		}
+	}
+
+	deleted, err := repo.Delete(ctx, until)
+	if err != nil {
+		return 0, "", fmt.Errorf("%w: %w", ErrPruneLogsFailed, err)
+	}
+
+	return deleted, path, nil
+}
@@ -0,0 +1,125 @@
+//go:build integration
+
+package application_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/go-arrower/arrower/setting"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/application"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/logs"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/interfaces/repository"
+)
+
+func TestPruneLogsJobHandler_H(t *testing.T) {
+	t.Parallel()
+
+	t.Run("keep all logs without policy", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		insertLogs(t, pg, 3)
+
+		handler := application.NewPruneLogsJobHandler(alog.NewNoopLogger(), setting.NewInMemorySettings(),
+			repository.NewPostgresLogsRepository(pg), nil)
+
+		err := handler.H(ctx, application.PruneLogsJob{})
+		assert.NoError(t, err)
+		assertTableNumberOfRows(t, pg, "arrower.log", 3)
+	})
+
+	t.Run("by age", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		insertLogs(t, pg, 3)
+
+		settings := setting.NewInMemorySettings()
+		_ = application.NewSaveLogRetentionCommandHandler(settings).H(ctx, application.SaveLogRetentionCommand{
+			Policy: logs.RetentionPolicy{MaxAgeDays: 2},
+		})
+
+		handler := application.NewPruneLogsJobHandler(alog.NewNoopLogger(), settings,
+			repository.NewPostgresLogsRepository(pg), nil)
+
+		err := handler.H(ctx, application.PruneLogsJob{})
+		assert.NoError(t, err)
+		assertTableNumberOfRows(t, pg, "arrower.log", 2)
+	})
+
+	t.Run("by rows with archive", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		insertLogs(t, pg, 3)
+
+		settings := setting.NewInMemorySettings()
+		_ = application.NewSaveLogRetentionCommandHandler(settings).H(ctx, application.SaveLogRetentionCommand{
+			Policy: logs.RetentionPolicy{MaxRows: 1, Archive: true},
+		})
+
+		dir := t.TempDir()
+		handler := application.NewPruneLogsJobHandler(alog.NewNoopLogger(), settings,
+			repository.NewPostgresLogsRepository(pg), repository.NewFileLogsArchive(dir))
+
+		err := handler.H(ctx, application.PruneLogsJob{})
+		assert.NoError(t, err)
+		assertTableNumberOfRows(t, pg, "arrower.log", 1)
+
+		files, _ := os.ReadDir(dir)
+		assert.Len(t, files, 1)
+	})
+
+	t.Run("archive not configured", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		insertLogs(t, pg, 3)
+
+		settings := setting.NewInMemorySettings()
+		_ = application.NewSaveLogRetentionCommandHandler(settings).H(ctx, application.SaveLogRetentionCommand{
+			Policy: logs.RetentionPolicy{MaxRows: 1, Archive: true},
+		})
+
+		handler := application.NewPruneLogsJobHandler(alog.NewNoopLogger(), settings,
+			repository.NewPostgresLogsRepository(pg), nil)
+
+		err := handler.H(ctx, application.PruneLogsJob{})
+		assert.ErrorIs(t, err, application.ErrPruneLogsFailed)
+		assertTableNumberOfRows(t, pg, "arrower.log", 3) // logs are not deleted without being archived
+	})
+}
+
+func TestPruneLogsRequestHandler_H(t *testing.T) {
+	t.Parallel()
+
+	pg := pgHandler.NewTestDatabase()
+	insertLogs(t, pg, 3)
+
+	handler := application.NewPruneLogsRequestHandler(setting.NewInMemorySettings(),
+		repository.NewPostgresLogsRepository(pg), nil)
+
+	res, err := handler.H(ctx, application.PruneLogsRequest{Days: 0})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), res.Deleted)
+	assert.Equal(t, int64(0), res.Size.Logs)
+	assert.NotEmpty(t, res.Size.Size)
+}
+
+// insertLogs inserts n logs, one per day, starting today.
+func insertLogs(t *testing.T, pg *pgxpool.Pool, n int) {
+	t.Helper()
+
+	for i := range n {
+		_, err := pg.Exec(ctx, `INSERT INTO arrower.log (time, log) VALUES ($1, '{"level":"INFO","msg":"log"}')`,
+			time.Now().Add(-time.Minute).AddDate(0, 0, -i),
+		)
+		assert.NoError(t, err)
+	}
+}
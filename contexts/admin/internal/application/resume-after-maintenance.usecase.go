@@ -0,0 +1,49 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-arrower/arrower/app"
+	"github.com/go-arrower/arrower/setting"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+)
+
+var ErrResumeAfterMaintenanceFailed = errors.New("resume after maintenance failed")
+
+func NewResumeAfterMaintenanceCommandHandler(
+	settings setting.Settings,
+	repo jobs.Repository,
+) app.Command[ResumeAfterMaintenanceCommand] {
+	return &resumeAfterMaintenanceCommandHandler{settings: settings, repo: repo}
+}
+
+type resumeAfterMaintenanceCommandHandler struct {
+	settings setting.Settings
+	repo     jobs.Repository
+}
+
+// ResumeAfterMaintenanceCommand releases the jobs parked during a maintenance, that paused the jobs.
+// The jobs of queues paused by an admin stay parked, until the queue is resumed.
+type ResumeAfterMaintenanceCommand struct{}
+
+func (h *resumeAfterMaintenanceCommandHandler) H(ctx context.Context, _ ResumeAfterMaintenanceCommand) error {
+	queues, err := h.repo.Queues(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrResumeAfterMaintenanceFailed, err)
+	}
+
+	for _, queue := range queues {
+		if isPaused(ctx, h.settings, pausedSetting(queue, "")) {
+			continue
+		}
+
+		if err := h.repo.ResumePausedJobs(ctx, queue, ""); err != nil {
+			return fmt.Errorf("%w: %w", ErrResumeAfterMaintenanceFailed, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+//go:build integration
+
+package application_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-arrower/arrower/setting"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/application"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/interfaces/repository"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/interfaces/repository/models"
+	"github.com/go-arrower/skeleton/shared/infrastructure/jobqueue"
+)
+
+func TestResumeAfterMaintenanceCommandHandler_H(t *testing.T) {
+	t.Parallel()
+
+	t.Run("release parked jobs", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		repo := repository.NewPostgresJobsRepository(pg)
+
+		_ = application.NewScheduleJobsCommandHandler(models.New(pg)).H(ctx, application.ScheduleJobsCommand{
+			JobType: "SomeJob",
+			Payload: `{}`,
+			Count:   1,
+			RunAt:   time.Now(),
+		})
+		_, _ = pg.Exec(ctx, `UPDATE arrower.gue_jobs SET run_at = 'infinity', last_error = $1, paused = true`, jobqueue.ErrPaused.Error())
+
+		err := application.NewResumeAfterMaintenanceCommandHandler(setting.NewInMemorySettings(), repo).
+			H(ctx, application.ResumeAfterMaintenanceCommand{})
+		assert.NoError(t, err)
+
+		pending, _ := repo.PendingJobs(ctx, jobs.DefaultQueueName)
+		assert.False(t, pending[0].RunAt.IsZero(), "parked job is released")
+	})
+
+	t.Run("queue paused by admin", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		repo := repository.NewPostgresJobsRepository(pg)
+		settings := setting.NewInMemorySettings()
+
+		_ = application.NewScheduleJobsCommandHandler(models.New(pg)).H(ctx, application.ScheduleJobsCommand{
+			JobType: "SomeJob",
+			Payload: `{}`,
+			Count:   1,
+			RunAt:   time.Now(),
+		})
+		_, _ = pg.Exec(ctx, `UPDATE arrower.gue_jobs SET run_at = 'infinity', last_error = $1, paused = true`, jobqueue.ErrPaused.Error())
+
+		_ = application.NewPauseQueueCommandHandler(settings).H(ctx, application.PauseQueueCommand{Queue: jobs.DefaultQueueName})
+
+		err := application.NewResumeAfterMaintenanceCommandHandler(settings, repo).
+			H(ctx, application.ResumeAfterMaintenanceCommand{})
+		assert.NoError(t, err)
+
+		pending, _ := repo.PendingJobs(ctx, jobs.DefaultQueueName)
+		assert.True(t, pending[0].RunAt.IsZero(), "job stays parked, while the queue is paused")
+	})
+}
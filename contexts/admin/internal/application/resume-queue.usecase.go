@@ -0,0 +1,61 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-arrower/arrower/app"
+	"github.com/go-arrower/arrower/setting"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+)
+
+var ErrResumeQueueFailed = errors.New("resume queue failed")
+
+func NewResumeQueueCommandHandler(settings setting.Settings, repo jobs.Repository) app.Command[ResumeQueueCommand] {
+	return &resumeQueueCommandHandler{settings: settings, repo: repo}
+}
+
+type resumeQueueCommandHandler struct {
+	settings setting.Settings
+	repo     jobs.Repository
+}
+
+// ResumeQueueCommand resumes the Queue or, if given, only the JobType.
+// The jobs parked while paused are released, unless they are still paused otherwise.
+type ResumeQueueCommand struct {
+	Queue   jobs.QueueName
+	JobType jobs.JobType
+}
+
+func (h *resumeQueueCommandHandler) H(ctx context.Context, cmd ResumeQueueCommand) error {
+	err := h.settings.Save(ctx, pausedSetting(cmd.Queue, cmd.JobType), setting.NewValue(false))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrResumeQueueFailed, err)
+	}
+
+	if cmd.JobType != "" && isPaused(ctx, h.settings, pausedSetting(cmd.Queue, "")) {
+		// the whole queue is still paused, the jobs get released when it is resumed
+		return nil
+	}
+
+	err = h.repo.ResumePausedJobs(ctx, cmd.Queue, cmd.JobType)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrResumeQueueFailed, err)
+	}
+
+	return nil
+}
+
+func isPaused(ctx context.Context, settings setting.Settings, key setting.Key) bool {
+	val, err := settings.Setting(ctx, key)
+	if err != nil {
+		return false
+	}
+
+	paused, _ := strconv.ParseBool(val.String())
+
+	return paused
+}
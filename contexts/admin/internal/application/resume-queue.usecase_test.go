@@ -0,0 +1,72 @@
+//go:build integration
+
+package application_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-arrower/arrower/setting"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/application"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/interfaces/repository"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/interfaces/repository/models"
+	"github.com/go-arrower/skeleton/shared/infrastructure/jobqueue"
+)
+
+func TestResumeQueueCommandHandler_H(t *testing.T) {
+	t.Parallel()
+
+	t.Run("resume queue", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		repo := repository.NewPostgresJobsRepository(pg)
+		settings := setting.NewInMemorySettings()
+
+		_ = application.NewScheduleJobsCommandHandler(models.New(pg)).H(ctx, application.ScheduleJobsCommand{
+			JobType: "SomeJob",
+			Payload: `{}`,
+			Count:   1,
+			RunAt:   time.Now(),
+		})
+		_, _ = pg.Exec(ctx, `UPDATE arrower.gue_jobs SET run_at = 'infinity', last_error = $1, paused = true`, jobqueue.ErrPaused.Error())
+
+		_ = application.NewPauseQueueCommandHandler(settings).H(ctx, application.PauseQueueCommand{Queue: "Default"})
+
+		err := application.NewResumeQueueCommandHandler(settings, repo).H(ctx, application.ResumeQueueCommand{Queue: "Default"})
+		assert.NoError(t, err)
+
+		val, _ := settings.Setting(ctx, jobqueue.SettingPaused("Default"))
+		assert.False(t, val.MustBool())
+
+		pending, _ := repo.PendingJobs(ctx, "")
+		assert.False(t, pending[0].RunAt.IsZero(), "paused job is released")
+	})
+
+	t.Run("resume job type of paused queue", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		repo := repository.NewPostgresJobsRepository(pg)
+		settings := setting.NewInMemorySettings()
+
+		_ = application.NewScheduleJobsCommandHandler(models.New(pg)).H(ctx, application.ScheduleJobsCommand{
+			JobType: "SomeJob",
+			Payload: `{}`,
+			Count:   1,
+			RunAt:   time.Now(),
+		})
+		_, _ = pg.Exec(ctx, `UPDATE arrower.gue_jobs SET run_at = 'infinity', last_error = $1, paused = true`, jobqueue.ErrPaused.Error())
+
+		_ = application.NewPauseQueueCommandHandler(settings).H(ctx, application.PauseQueueCommand{Queue: "Default"})
+		_ = application.NewPauseQueueCommandHandler(settings).H(ctx, application.PauseQueueCommand{Queue: "Default", JobType: "SomeJob"})
+
+		err := application.NewResumeQueueCommandHandler(settings, repo).H(ctx, application.ResumeQueueCommand{Queue: "Default", JobType: "SomeJob"})
+		assert.NoError(t, err)
+
+		pending, _ := repo.PendingJobs(ctx, "")
+		assert.True(t, pending[0].RunAt.IsZero(), "job stays parked, while the queue is paused")
+	})
+}
@@ -0,0 +1,43 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-arrower/arrower/app"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+)
+
+var ErrSaveAlertRuleFailed = errors.New("save alert rule failed")
+
+func NewSaveAlertRuleCommandHandler(repo jobs.Repository) app.Command[SaveAlertRuleCommand] {
+	return &saveAlertRuleCommandHandler{repo: repo}
+}
+
+type saveAlertRuleCommandHandler struct {
+	repo jobs.Repository
+}
+
+type SaveAlertRuleCommand struct {
+	Rule jobs.AlertRule
+}
+
+func (h *saveAlertRuleCommandHandler) H(ctx context.Context, cmd SaveAlertRuleCommand) error {
+	if cmd.Rule.Queue == "" {
+		cmd.Rule.Queue = jobs.DefaultQueueName
+	}
+
+	err := cmd.Rule.Validate()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSaveAlertRuleFailed, err)
+	}
+
+	err = h.repo.SaveAlertRule(ctx, cmd.Rule)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSaveAlertRuleFailed, err)
+	}
+
+	return nil
+}
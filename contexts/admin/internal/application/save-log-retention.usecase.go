@@ -0,0 +1,45 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-arrower/arrower/app"
+	"github.com/go-arrower/arrower/setting"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/logs"
+)
+
+var ErrSaveLogRetentionFailed = errors.New("save log retention failed")
+
+func NewSaveLogRetentionCommandHandler(settings setting.Settings) app.Command[SaveLogRetentionCommand] {
+	return &saveLogRetentionCommandHandler{settings: settings}
+}
+
+type saveLogRetentionCommandHandler struct {
+	settings setting.Settings
+}
+
+// SaveLogRetentionCommand sets the policy the maintenance job prunes the logs with.
+type SaveLogRetentionCommand struct {
+	Policy logs.RetentionPolicy
+}
+
+func (h *saveLogRetentionCommandHandler) H(ctx context.Context, cmd SaveLogRetentionCommand) error {
+	if err := cmd.Policy.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrSaveLogRetentionFailed, err)
+	}
+
+	for key, val := range map[setting.Key]setting.Value{
+		SettingLogRetentionDays:    setting.NewValue(cmd.Policy.MaxAgeDays),
+		SettingLogRetentionRows:    setting.NewValue(cmd.Policy.MaxRows),
+		SettingLogRetentionArchive: setting.NewValue(cmd.Policy.Archive),
+	} {
+		if err := h.settings.Save(ctx, key, val); err != nil {
+			return fmt.Errorf("%w: %w", ErrSaveLogRetentionFailed, err)
+		}
+	}
+
+	return nil
+}
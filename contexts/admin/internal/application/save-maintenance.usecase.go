@@ -0,0 +1,35 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-arrower/arrower/app"
+	"github.com/go-arrower/arrower/setting"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/maintenance"
+)
+
+var ErrSaveMaintenanceFailed = errors.New("save maintenance failed")
+
+func NewSaveMaintenanceCommandHandler(settings setting.Settings) app.Command[SaveMaintenanceCommand] {
+	return &saveMaintenanceCommandHandler{settings: settings}
+}
+
+type saveMaintenanceCommandHandler struct {
+	settings setting.Settings
+}
+
+// SaveMaintenanceCommand turns the maintenance of all instances on or off.
+type SaveMaintenanceCommand struct {
+	Mode maintenance.Mode
+}
+
+func (h *saveMaintenanceCommandHandler) H(ctx context.Context, cmd SaveMaintenanceCommand) error {
+	if err := maintenance.Save(ctx, h.settings, cmd.Mode); err != nil {
+		return fmt.Errorf("%w: %w", ErrSaveMaintenanceFailed, err)
+	}
+
+	return nil
+}
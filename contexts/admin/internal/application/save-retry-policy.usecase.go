@@ -0,0 +1,39 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-arrower/arrower/app"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+)
+
+var ErrSaveRetryPolicyFailed = errors.New("save retry policy failed")
+
+func NewSaveRetryPolicyCommandHandler(repo jobs.Repository) app.Command[SaveRetryPolicyCommand] {
+	return &saveRetryPolicyCommandHandler{repo: repo}
+}
+
+type saveRetryPolicyCommandHandler struct {
+	repo jobs.Repository
+}
+
+type SaveRetryPolicyCommand struct {
+	Policy jobs.RetryPolicy
+}
+
+func (h *saveRetryPolicyCommandHandler) H(ctx context.Context, cmd SaveRetryPolicyCommand) error {
+	err := cmd.Policy.Validate()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSaveRetryPolicyFailed, err)
+	}
+
+	err = h.repo.SaveRetryPolicy(ctx, cmd.Policy)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSaveRetryPolicyFailed, err)
+	}
+
+	return nil
+}
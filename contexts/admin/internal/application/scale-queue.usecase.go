@@ -0,0 +1,46 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-arrower/arrower/app"
+	"github.com/go-arrower/arrower/setting"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+	"github.com/go-arrower/skeleton/shared/infrastructure/jobqueue"
+)
+
+var (
+	ErrScaleQueueFailed = errors.New("scale queue failed")
+	ErrInvalidWorkers   = errors.New("invalid number of workers")
+)
+
+func NewScaleQueueCommandHandler(settings setting.Settings) app.Command[ScaleQueueCommand] {
+	return &scaleQueueCommandHandler{settings: settings}
+}
+
+type scaleQueueCommandHandler struct {
+	settings setting.Settings
+}
+
+// ScaleQueueCommand sets the number of Workers each instance runs for the Queue.
+// Zero resets it to the default.
+type ScaleQueueCommand struct {
+	Queue   jobs.QueueName
+	Workers int
+}
+
+func (h *scaleQueueCommandHandler) H(ctx context.Context, cmd ScaleQueueCommand) error {
+	if cmd.Workers < 0 {
+		return fmt.Errorf("%w: %w: %d", ErrScaleQueueFailed, ErrInvalidWorkers, cmd.Workers)
+	}
+
+	err := h.settings.Save(ctx, jobqueue.SettingWorkers(string(cmd.Queue)), setting.NewValue(cmd.Workers))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrScaleQueueFailed, err)
+	}
+
+	return nil
+}
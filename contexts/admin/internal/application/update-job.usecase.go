@@ -0,0 +1,102 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-arrower/arrower/app"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+)
+
+var ErrUpdateJobFailed = errors.New("update job failed")
+
+func NewUpdateJobCommandHandler(repo jobs.Repository) app.Command[UpdateJobCommand] {
+	return &updateJobCommandHandler{repo: repo}
+}
+
+type updateJobCommandHandler struct {
+	repo jobs.Repository
+}
+
+// UpdateJobCommand changes a pending job.
+// Payload is the job data only, the tracing information of the job is kept as is.
+type UpdateJobCommand struct {
+	JobID           string
+	Queue           jobs.QueueName
+	Payload         string
+	Priority        int16
+	ResetErrorCount bool
+	RetryNow        bool
+}
+
+func (h *updateJobCommandHandler) H(ctx context.Context, cmd UpdateJobCommand) error {
+	job, err := h.repo.Job(ctx, cmd.JobID)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUpdateJobFailed, err)
+	}
+
+	examples, err := h.repo.PayloadExamples(ctx, jobs.QueueName(job.Queue), jobs.JobType(job.Type))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUpdateJobFailed, err)
+	}
+
+	payload := []byte(strings.TrimSpace(cmd.Payload))
+
+	err = jobs.ValidatePayload(payload, jobDataOf(examples))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUpdateJobFailed, err)
+	}
+
+	var envelope JobPayload
+
+	_ = json.Unmarshal([]byte(job.Payload), &envelope)
+	envelope.JobData = json.RawMessage(payload)
+
+	args, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("%w: could not marshal job: %v", ErrUpdateJobFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	job.Payload = string(args)
+	job.Queue = string(cmd.Queue)
+	job.Priority = cmd.Priority
+
+	if cmd.ResetErrorCount {
+		job.ErrorCount = 0
+	}
+
+	if cmd.RetryNow {
+		job.RunAt = time.Now()
+	}
+
+	err = h.repo.UpdateJob(ctx, job)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUpdateJobFailed, err)
+	}
+
+	return nil
+}
+
+// jobDataOf unwraps the job data from the complete payloads as stored by the workers.
+func jobDataOf(payloads [][]byte) [][]byte {
+	data := make([][]byte, 0, len(payloads))
+
+	for _, p := range payloads {
+		var envelope struct {
+			JobData json.RawMessage `json:"jobData"`
+		}
+
+		if err := json.Unmarshal(p, &envelope); err != nil || len(envelope.JobData) == 0 {
+			continue
+		}
+
+		data = append(data, envelope.JobData)
+	}
+
+	return data
+}
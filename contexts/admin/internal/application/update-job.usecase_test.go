@@ -0,0 +1,88 @@
+//go:build integration
+
+package application_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/application"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/interfaces/repository"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/interfaces/repository/models"
+)
+
+func TestUpdateJobCommandHandler_H(t *testing.T) {
+	t.Parallel()
+
+	t.Run("update and retry now", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		repo := repository.NewPostgresJobsRepository(pg)
+
+		_ = application.NewScheduleJobsCommandHandler(models.New(pg)).H(ctx, application.ScheduleJobsCommand{
+			JobType: "SomeJob",
+			Payload: `{"name":"arrower"}`,
+			Count:   1,
+			RunAt:   time.Now().Add(time.Hour),
+		})
+		pending, _ := repo.PendingJobs(ctx, "")
+
+		handler := application.NewUpdateJobCommandHandler(repo)
+		err := handler.H(ctx, application.UpdateJobCommand{
+			JobID:           pending[0].ID,
+			Queue:           "Other",
+			Payload:         `{"name":"edited"}`,
+			Priority:        3,
+			ResetErrorCount: true,
+			RetryNow:        true,
+		})
+		assert.NoError(t, err)
+
+		job, _ := repo.Job(ctx, pending[0].ID)
+		assert.Equal(t, "Other", job.Queue)
+		assert.Equal(t, int16(3), job.Priority)
+		assert.True(t, job.RunAt.Before(time.Now()))
+
+		var payload application.JobPayload
+		_ = json.Unmarshal([]byte(job.Payload), &payload)
+		assert.Equal(t, map[string]any{"name": "edited"}, payload.JobData)
+	})
+
+	t.Run("invalid payload", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		repo := repository.NewPostgresJobsRepository(pg)
+
+		_ = application.NewScheduleJobsCommandHandler(models.New(pg)).H(ctx, application.ScheduleJobsCommand{
+			JobType: "SomeJob",
+			Payload: `{}`,
+			Count:   1,
+			RunAt:   time.Now().Add(time.Hour),
+		})
+		pending, _ := repo.PendingJobs(ctx, "")
+
+		handler := application.NewUpdateJobCommandHandler(repo)
+		err := handler.H(ctx, application.UpdateJobCommand{
+			JobID:   pending[0].ID,
+			Payload: `{no json`,
+		})
+		assert.ErrorIs(t, err, application.ErrUpdateJobFailed)
+		assert.ErrorIs(t, err, jobs.ErrInvalidPayload)
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+
+		handler := application.NewUpdateJobCommandHandler(repository.NewPostgresJobsRepository(pg))
+		err := handler.H(ctx, application.UpdateJobCommand{JobID: "non-existing-id", Payload: `{}`})
+		assert.ErrorIs(t, err, jobs.ErrJobNotFound)
+	})
+}
@@ -0,0 +1,175 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+var ErrInvalidAlertRule = errors.New("invalid alert rule")
+
+// AlertCondition is what an AlertRule watches.
+type AlertCondition string
+
+const (
+	// ConditionPendingAbove is met, if more jobs than the threshold are pending.
+	ConditionPendingAbove AlertCondition = "pending_above"
+	// ConditionFailureRateAbove is met, if the percentage of failed attempts within the
+	// duration of the rule is above the threshold.
+	ConditionFailureRateAbove AlertCondition = "failure_rate_above"
+	// ConditionNoWorkers is met, if no worker of the queue has been seen for a minute.
+	ConditionNoWorkers AlertCondition = "no_workers"
+)
+
+// minFailureRateWindow is the smallest window the failure rate is calculated over,
+// so a rule firing immediately does not depend on a single attempt.
+const minFailureRateWindow = 5 * time.Minute
+
+// AlertRule fires an Alert, once its Condition is met for the duration For.
+type AlertRule struct {
+	Name      string
+	Queue     QueueName
+	JobType   JobType // optional, empty watches all job types of the queue
+	Condition AlertCondition
+	Channels  []string
+	Threshold float64
+	For       time.Duration
+}
+
+func (r AlertRule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidAlertRule)
+	}
+
+	switch r.Condition {
+	case ConditionPendingAbove, ConditionFailureRateAbove:
+		if r.Threshold < 0 {
+			return fmt.Errorf("%w: threshold can not be negative", ErrInvalidAlertRule)
+		}
+	case ConditionNoWorkers:
+		if r.JobType != "" {
+			return fmt.Errorf("%w: workers are watched per queue, not per job type", ErrInvalidAlertRule)
+		}
+	default:
+		return fmt.Errorf("%w: unknown condition: %s", ErrInvalidAlertRule, r.Condition)
+	}
+
+	if r.For < 0 {
+		return fmt.Errorf("%w: duration can not be negative", ErrInvalidAlertRule)
+	}
+
+	return nil
+}
+
+// FailureRateWindow is the time range the failure rate of the rule is calculated over.
+func (r AlertRule) FailureRateWindow() time.Duration {
+	return max(r.For, minFailureRateWindow)
+}
+
+// IsMet returns if the condition is met for the observed value.
+// The value is the number of pending jobs, the failure rate, or the number of workers, depending on the Condition.
+func (r AlertRule) IsMet(value float64) bool {
+	switch r.Condition {
+	case ConditionPendingAbove, ConditionFailureRateAbove:
+		return value > r.Threshold
+	case ConditionNoWorkers:
+		return value == 0
+	default:
+		return false
+	}
+}
+
+func (r AlertRule) message(value float64) string {
+	subject := string(r.Queue)
+	if r.JobType != "" {
+		subject += "/" + string(r.JobType)
+	}
+
+	switch r.Condition {
+	case ConditionPendingAbove:
+		return fmt.Sprintf("%s: %.0f jobs pending, more than %.0f", subject, value, r.Threshold)
+	case ConditionFailureRateAbove:
+		return fmt.Sprintf("%s: %.1f%% of the attempts failed, more than %.1f%%", subject, value, r.Threshold)
+	case ConditionNoWorkers:
+		return subject + ": no worker seen"
+	default:
+		return subject
+	}
+}
+
+// Transition is the change of an Alert, that operators get notified about.
+type Transition string
+
+const (
+	TransitionNone     Transition = ""
+	TransitionFired    Transition = "fired"
+	TransitionResolved Transition = "resolved"
+)
+
+// Alert is an AlertRule, that got met.
+// It is pending until the rule is met for its duration, then it fires, until it is resolved.
+type Alert struct {
+	PendingSince time.Time
+	FiredAt      time.Time
+	ResolvedAt   time.Time
+	ID           string
+	Rule         string
+	Queue        QueueName
+	JobType      JobType
+	Message      string
+	Value        float64
+}
+
+func (a Alert) IsFiring() bool {
+	return !a.FiredAt.IsZero() && a.ResolvedAt.IsZero()
+}
+
+func (a Alert) IsResolved() bool {
+	return !a.ResolvedAt.IsZero()
+}
+
+// Evaluate returns the alert of the rule after observing the value at the time now.
+// The open alert is the currently pending or firing alert of the rule, or nil.
+// If the returned alert is nil, the rule is not met and a pending alert is cleared.
+func (r AlertRule) Evaluate(open *Alert, value float64, now time.Time) (*Alert, Transition) {
+	if !r.IsMet(value) {
+		if open == nil || !open.IsFiring() {
+			return nil, TransitionNone
+		}
+
+		resolved := *open
+		resolved.ResolvedAt = now
+
+		return &resolved, TransitionResolved
+	}
+
+	alert := Alert{ //nolint:exhaustruct // FiredAt and ResolvedAt are set below
+		ID:           ulid.Make().String(),
+		Rule:         r.Name,
+		Queue:        r.Queue,
+		JobType:      r.JobType,
+		PendingSince: now,
+	}
+	if open != nil {
+		alert = *open
+	}
+
+	alert.Value = value
+	alert.Message = r.message(value)
+
+	if alert.FiredAt.IsZero() && now.Sub(alert.PendingSince) >= r.For {
+		alert.FiredAt = now
+
+		return &alert, TransitionFired
+	}
+
+	return &alert, TransitionNone
+}
+
+// Notifier sends alerts to a channel, e.g. a log, an email address, or a webhook.
+type Notifier interface {
+	Notify(ctx context.Context, channel string, alert Alert, transition Transition) error
+}
@@ -0,0 +1,133 @@
+package jobs_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+)
+
+func TestAlertRule_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName string
+		rule     jobs.AlertRule
+		valid    bool
+	}{
+		{
+			"valid",
+			jobs.AlertRule{Name: "n", Condition: jobs.ConditionPendingAbove, Threshold: 100},
+			true,
+		},
+		{
+			"missing name",
+			jobs.AlertRule{Condition: jobs.ConditionPendingAbove},
+			false,
+		},
+		{
+			"unknown condition",
+			jobs.AlertRule{Name: "n", Condition: "unknown"},
+			false,
+		},
+		{
+			"negative threshold",
+			jobs.AlertRule{Name: "n", Condition: jobs.ConditionFailureRateAbove, Threshold: -1},
+			false,
+		},
+		{
+			"workers per job type",
+			jobs.AlertRule{Name: "n", Condition: jobs.ConditionNoWorkers, JobType: "t"},
+			false,
+		},
+		{
+			"negative duration",
+			jobs.AlertRule{Name: "n", Condition: jobs.ConditionNoWorkers, For: -time.Minute},
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.rule.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, jobs.ErrInvalidAlertRule)
+			}
+		})
+	}
+}
+
+func TestAlertRule_IsMet(t *testing.T) {
+	t.Parallel()
+
+	pending := jobs.AlertRule{Condition: jobs.ConditionPendingAbove, Threshold: 10}
+	assert.False(t, pending.IsMet(10))
+	assert.True(t, pending.IsMet(11))
+
+	workers := jobs.AlertRule{Condition: jobs.ConditionNoWorkers}
+	assert.True(t, workers.IsMet(0))
+	assert.False(t, workers.IsMet(1))
+}
+
+func TestAlertRule_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	rule := jobs.AlertRule{Name: "n", Queue: "Default", Condition: jobs.ConditionPendingAbove, Threshold: 10, For: 5 * time.Minute}
+
+	t.Run("not met", func(t *testing.T) {
+		t.Parallel()
+
+		alert, transition := rule.Evaluate(nil, 1, now)
+		assert.Nil(t, alert)
+		assert.Equal(t, jobs.TransitionNone, transition)
+	})
+
+	t.Run("pending until met for the duration", func(t *testing.T) {
+		t.Parallel()
+
+		alert, transition := rule.Evaluate(nil, 20, now)
+		assert.Equal(t, jobs.TransitionNone, transition)
+		assert.False(t, alert.IsFiring())
+		assert.Equal(t, "Default: 20 jobs pending, more than 10", alert.Message)
+
+		alert, transition = rule.Evaluate(alert, 30, now.Add(5*time.Minute))
+		assert.Equal(t, jobs.TransitionFired, transition)
+		assert.True(t, alert.IsFiring())
+		assert.Equal(t, 30.0, alert.Value)
+
+		alert, transition = rule.Evaluate(alert, 30, now.Add(6*time.Minute))
+		assert.Equal(t, jobs.TransitionNone, transition, "fires only once")
+		assert.True(t, alert.IsFiring())
+
+		alert, transition = rule.Evaluate(alert, 0, now.Add(7*time.Minute))
+		assert.Equal(t, jobs.TransitionResolved, transition)
+		assert.True(t, alert.IsResolved())
+	})
+
+	t.Run("pending alert is cleared", func(t *testing.T) {
+		t.Parallel()
+
+		alert, _ := rule.Evaluate(nil, 20, now)
+
+		alert, transition := rule.Evaluate(alert, 0, now.Add(time.Minute))
+		assert.Nil(t, alert)
+		assert.Equal(t, jobs.TransitionNone, transition)
+	})
+
+	t.Run("fire immediately", func(t *testing.T) {
+		t.Parallel()
+
+		rule := jobs.AlertRule{Name: "n", Condition: jobs.ConditionNoWorkers}
+
+		alert, transition := rule.Evaluate(nil, 0, now)
+		assert.Equal(t, jobs.TransitionFired, transition)
+		assert.True(t, alert.IsFiring())
+	})
+}
@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrStaleWorkers = errors.New("stale workers")
+
+// WorkerStaleAfter is how long a worker pool can go without a heartbeat, before it is considered gone.
+const WorkerStaleAfter = time.Minute
+
+// CheckHeartbeat returns an error, if the instance has no worker pool
+// or if one of its pools has not sent a heartbeat within WorkerStaleAfter.
+func CheckHeartbeat(pools []WorkerPool, instance string, now time.Time) error {
+	found := false
+
+	for _, pool := range pools {
+		if pool.ID != instance {
+			continue
+		}
+
+		found = true
+
+		if seen := now.Sub(pool.LastSeen); seen > WorkerStaleAfter {
+			return fmt.Errorf("%w: queue %s last seen %s ago", ErrStaleWorkers, pool.Queue, seen.Round(time.Second))
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("%w: no worker pool of instance %s", ErrStaleWorkers, instance)
+	}
+
+	return nil
+}
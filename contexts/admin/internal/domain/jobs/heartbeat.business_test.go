@@ -0,0 +1,63 @@
+package jobs_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+)
+
+func TestCheckHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	tests := []struct {
+		testName string
+		pools    []jobs.WorkerPool
+		healthy  bool
+	}{
+		{
+			"no pools",
+			nil,
+			false,
+		},
+		{
+			"pools of other instances only",
+			[]jobs.WorkerPool{{ID: "other", LastSeen: now}},
+			false,
+		},
+		{
+			"fresh",
+			[]jobs.WorkerPool{
+				{ID: "instance", Queue: "", LastSeen: now.Add(-10 * time.Second)},
+				{ID: "instance", Queue: "Arrower", LastSeen: now},
+				{ID: "other", LastSeen: now.Add(-time.Hour)},
+			},
+			true,
+		},
+		{
+			"stale",
+			[]jobs.WorkerPool{
+				{ID: "instance", Queue: "", LastSeen: now},
+				{ID: "instance", Queue: "Arrower", LastSeen: now.Add(-2 * time.Minute)},
+			},
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			err := jobs.CheckHeartbeat(tt.pools, "instance", now)
+			if tt.healthy {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, jobs.ErrStaleWorkers)
+			}
+		})
+	}
+}
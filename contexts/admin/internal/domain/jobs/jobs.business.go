@@ -7,7 +7,10 @@ import (
 	"github.com/go-arrower/arrower/postgres"
 )
 
-var ErrJobLockedAlready = fmt.Errorf("%w: job might be processing already", postgres.ErrQueryFailed)
+var (
+	ErrJobLockedAlready = fmt.Errorf("%w: job might be processing already", postgres.ErrQueryFailed)
+	ErrJobNotFound      = fmt.Errorf("%w: job not found", postgres.ErrQueryFailed)
+)
 
 type (
 	JobType string
@@ -38,6 +41,8 @@ type (
 		LastError  string
 		ErrorCount int32
 		Priority   int16
+		// Paused is true, if the job is parked, because its queue or job type is paused.
+		Paused bool
 	}
 
 	QueueKPIs struct {
@@ -53,6 +58,8 @@ type (
 		LastSeen time.Time
 		ID       string
 		Queue    string // todo change type
+		// Role is the role of the instance running the pool, e.g. worker. It is empty for instances started before roles existed.
+		Role     string
 		Workers  int
 		Version  string
 		JobTypes []string
@@ -15,6 +15,29 @@ type Repository interface {
 	WorkerPools(ctx context.Context) ([]WorkerPool, error)
 	FinishedJobs(ctx context.Context, f Filter) ([]PendingJob, error)
 	FinishedJobsTotal(ctx context.Context, f Filter) (int64, error)
+
+	Job(ctx context.Context, jobID string) (PendingJob, error)
+	UpdateJob(ctx context.Context, job PendingJob) error
+	PayloadExamples(ctx context.Context, queue QueueName, jobType JobType) ([][]byte, error)
+
+	RetryPolicies(ctx context.Context) ([]RetryPolicy, error)
+	SaveRetryPolicy(ctx context.Context, policy RetryPolicy) error
+	DeleteRetryPolicy(ctx context.Context, queue QueueName, jobType JobType) error
+
+	// ResumePausedJobs releases the jobs, that got parked while their queue or job type was paused.
+	// An empty jobType releases the jobs of all job types in the queue.
+	ResumePausedJobs(ctx context.Context, queue QueueName, jobType JobType) error
+
+	JobMetrics(ctx context.Context, queue QueueName, since time.Time) ([]JobTypeMetrics, error)
+	JobMetricsTimeSeries(ctx context.Context, queue QueueName, since time.Time, bin time.Duration) ([]MetricsBucket, error)
+
+	AlertRules(ctx context.Context) ([]AlertRule, error)
+	SaveAlertRule(ctx context.Context, rule AlertRule) error
+	DeleteAlertRule(ctx context.Context, name string) error
+	// Alerts returns all open alerts and the ones resolved after the given time.
+	Alerts(ctx context.Context, resolvedSince time.Time) ([]Alert, error)
+	SaveAlert(ctx context.Context, alert Alert) error
+	DeleteAlert(ctx context.Context, id string) error
 }
 
 type Filter struct {
@@ -0,0 +1,100 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidWindow = errors.New("invalid metrics window")
+
+// MetricsWindow is the time range the job metrics are calculated over.
+// The time series of a window is divided into bins of the size Bin.
+type MetricsWindow struct {
+	Name  string
+	Range time.Duration
+	Bin   time.Duration
+}
+
+//nolint:gochecknoglobals // used like a constant
+var MetricsWindows = []MetricsWindow{
+	{Name: "hour", Range: time.Hour, Bin: 5 * time.Minute},
+	{Name: "day", Range: 24 * time.Hour, Bin: time.Hour},
+	{Name: "week", Range: 7 * 24 * time.Hour, Bin: 24 * time.Hour},
+}
+
+// WindowByName returns the MetricsWindow with the given name.
+// An empty name returns the shortest window.
+func WindowByName(name string) (MetricsWindow, error) {
+	if name == "" {
+		return MetricsWindows[0], nil
+	}
+
+	for _, w := range MetricsWindows {
+		if w.Name == name {
+			return w, nil
+		}
+	}
+
+	return MetricsWindow{}, fmt.Errorf("%w: %s", ErrInvalidWindow, name)
+}
+
+// Percentiles of a duration.
+type Percentiles struct {
+	P50 time.Duration
+	P95 time.Duration
+	P99 time.Duration
+}
+
+// JobTypeMetrics are the metrics of all attempts of one JobType, that finished within a MetricsWindow.
+type JobTypeMetrics struct {
+	JobType JobType
+	// Latency is the time a worker spent processing the job.
+	Latency Percentiles
+	// WaitTime is the time between the job was scheduled to run and a worker started it.
+	WaitTime  Percentiles
+	Processed int
+	Failed    int
+}
+
+// FailureRate returns the percentage of failed attempts.
+func (m JobTypeMetrics) FailureRate() float64 {
+	if m.Processed == 0 {
+		return 0
+	}
+
+	return float64(m.Failed) * 100 / float64(m.Processed)
+}
+
+// Throughput returns the number of processed jobs per minute.
+func (m JobTypeMetrics) Throughput(window time.Duration) float64 {
+	if window < time.Minute {
+		return 0
+	}
+
+	return float64(m.Processed) / window.Minutes()
+}
+
+// MetricsBucket are the metrics of all attempts of a queue, that finished within one bin of a time series.
+type MetricsBucket struct {
+	Time       time.Time
+	LatencyP95 time.Duration
+	WaitP95    time.Duration
+	Processed  int
+	Failed     int
+}
+
+// EstimateUntilEmpty estimates how long it takes to process all pending jobs,
+// based on the throughput within the window. It is zero, if no job got processed.
+func EstimateUntilEmpty(pending int, metrics []JobTypeMetrics, window time.Duration) time.Duration {
+	var throughput float64
+	for _, m := range metrics {
+		throughput += m.Throughput(window)
+	}
+
+	if throughput == 0 {
+		return 0
+	}
+
+	return time.Duration(float64(pending) / throughput * float64(time.Minute))
+}
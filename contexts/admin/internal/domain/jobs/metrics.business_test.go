@@ -0,0 +1,59 @@
+package jobs_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+)
+
+func TestWindowByName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName string
+		name     string
+		window   time.Duration
+		err      error
+	}{
+		{"default", "", time.Hour, nil},
+		{"hour", "hour", time.Hour, nil},
+		{"week", "week", 7 * 24 * time.Hour, nil},
+		{"unknown", "year", 0, jobs.ErrInvalidWindow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			w, err := jobs.WindowByName(tt.name)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.window, w.Range)
+		})
+	}
+}
+
+func TestJobTypeMetrics_FailureRate(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0.0, jobs.JobTypeMetrics{}.FailureRate())
+	assert.Equal(t, 25.0, jobs.JobTypeMetrics{Processed: 4, Failed: 1}.FailureRate())
+}
+
+func TestJobTypeMetrics_Throughput(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0.0, jobs.JobTypeMetrics{Processed: 4}.Throughput(time.Second))
+	assert.Equal(t, 2.0, jobs.JobTypeMetrics{Processed: 120}.Throughput(time.Hour))
+}
+
+func TestEstimateUntilEmpty(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, time.Duration(0), jobs.EstimateUntilEmpty(10, nil, time.Hour))
+
+	metrics := []jobs.JobTypeMetrics{{Processed: 60}, {Processed: 60}}
+	assert.Equal(t, 5*time.Minute, jobs.EstimateUntilEmpty(10, metrics, time.Hour))
+}
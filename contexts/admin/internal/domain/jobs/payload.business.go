@@ -0,0 +1,80 @@
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var ErrInvalidPayload = errors.New("invalid payload")
+
+// ValidatePayload ensures that an edited job payload can still be processed by the workers.
+// The payload has to be valid JSON and, if examples of previous payloads of the same job type exist,
+// it has to have the same structure as at least one of them:
+// the same keys with values of the same JSON type.
+func ValidatePayload(payload []byte, examples [][]byte) error {
+	var p any
+
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("%w: not valid json: %v", ErrInvalidPayload, err) //nolint:errorlint // prevent err in api
+	}
+
+	if len(examples) == 0 {
+		return nil
+	}
+
+	for _, example := range examples {
+		var e any
+
+		if err := json.Unmarshal(example, &e); err != nil {
+			continue
+		}
+
+		if sameStructure(p, e) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: structure does not match the previous payloads of this job type", ErrInvalidPayload)
+}
+
+func sameStructure(value, example any) bool {
+	if value == nil || example == nil {
+		return true
+	}
+
+	switch e := example.(type) {
+	case map[string]any:
+		v, ok := value.(map[string]any)
+		if !ok || len(v) != len(e) {
+			return false
+		}
+
+		for key, ev := range e {
+			vv, ok := v[key]
+			if !ok || !sameStructure(vv, ev) {
+				return false
+			}
+		}
+
+		return true
+	case []any:
+		_, ok := value.([]any)
+
+		return ok
+	case string:
+		_, ok := value.(string)
+
+		return ok
+	case float64:
+		_, ok := value.(float64)
+
+		return ok
+	case bool:
+		_, ok := value.(bool)
+
+		return ok
+	}
+
+	return false
+}
@@ -0,0 +1,49 @@
+package jobs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+)
+
+func TestValidatePayload(t *testing.T) {
+	t.Parallel()
+
+	examples := [][]byte{
+		[]byte(`{"name":"arrower","count":1,"tags":["a"]}`),
+		[]byte(`{"name":"arrower","nested":{"ok":true}}`),
+	}
+
+	tests := []struct {
+		testName string
+		payload  string
+		examples [][]byte
+		valid    bool
+	}{
+		{"no json", `{`, nil, false},
+		{"no examples", `{"any":"thing"}`, nil, true},
+		{"matches first example", `{"name":"other","count":2,"tags":[]}`, examples, true},
+		{"matches second example", `{"name":"other","nested":{"ok":false}}`, examples, true},
+		{"null is allowed", `{"name":null,"count":2,"tags":null}`, examples, true},
+		{"missing key", `{"name":"other"}`, examples, false},
+		{"additional key", `{"name":"other","count":2,"tags":[],"new":1}`, examples, false},
+		{"wrong type", `{"name":"other","count":"2","tags":[]}`, examples, false},
+		{"wrong nested type", `{"name":"other","nested":{"ok":"yes"}}`, examples, false},
+		{"ignores broken examples", `{"a":1}`, [][]byte{[]byte(`{`), []byte(`{"a":0}`)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			err := jobs.ValidatePayload([]byte(tt.payload), tt.examples)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, jobs.ErrInvalidPayload)
+			}
+		})
+	}
+}
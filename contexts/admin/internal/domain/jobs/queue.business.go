@@ -6,3 +6,13 @@ type (
 	QueueName  string
 	QueueNames []QueueName
 )
+
+// QueueControls are the runtime controls of a queue, shared by all instances.
+type QueueControls struct {
+	PausedJobTypes map[JobType]bool
+	Queue          QueueName
+	JobTypes       []JobType
+	// Workers is the number of workers per instance. Zero means the default of the queue.
+	Workers int
+	Paused  bool
+}
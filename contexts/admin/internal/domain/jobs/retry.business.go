@@ -0,0 +1,98 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidRetryPolicy = errors.New("invalid retry policy")
+
+// DefaultMaxDelay caps the delay of a RetryPolicy without a MaxDelay, so the delay of a job,
+// that failed many times, does not overflow. It has to be kept in sync with arrower.job_retry_delay.
+const DefaultMaxDelay = 7 * 24 * time.Hour
+
+// Backoff is the strategy used to calculate the delay between two attempts of a failed job.
+type Backoff string
+
+const (
+	BackoffFixed       Backoff = "fixed"
+	BackoffLinear      Backoff = "linear"
+	BackoffExponential Backoff = "exponential"
+)
+
+// RetryPolicy controls how often and when a failed job of the given JobType is retried.
+// The policy is applied by the database, whenever a worker reschedules a failed job,
+// so all instances follow the same policy without restarting.
+type RetryPolicy struct {
+	Queue   QueueName
+	JobType JobType
+	Backoff Backoff
+	// MaxAttempts is the number of failed attempts, after which the job is not retried anymore.
+	// Zero means the job is retried forever.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	// MaxDelay caps the delay between two attempts. Zero means the delay is capped by DefaultMaxDelay.
+	MaxDelay time.Duration
+}
+
+func (p RetryPolicy) Validate() error {
+	if p.JobType == "" {
+		return fmt.Errorf("%w: job type is required", ErrInvalidRetryPolicy)
+	}
+
+	switch p.Backoff {
+	case BackoffFixed, BackoffLinear, BackoffExponential:
+	default:
+		return fmt.Errorf("%w: unknown backoff: %s", ErrInvalidRetryPolicy, p.Backoff)
+	}
+
+	if p.MaxAttempts < 0 {
+		return fmt.Errorf("%w: max attempts can not be negative", ErrInvalidRetryPolicy)
+	}
+
+	if p.BaseDelay < time.Second {
+		return fmt.Errorf("%w: base delay has to be at least one second", ErrInvalidRetryPolicy)
+	}
+
+	if p.MaxDelay != 0 && p.MaxDelay < p.BaseDelay {
+		return fmt.Errorf("%w: max delay is smaller than the base delay", ErrInvalidRetryPolicy)
+	}
+
+	return nil
+}
+
+// Delay returns the time to wait before the next attempt, after the job failed for the given number of times.
+func (p RetryPolicy) Delay(errorCount int) time.Duration {
+	if errorCount < 1 {
+		errorCount = 1
+	}
+
+	maxDelay := p.MaxDelay
+	if maxDelay == 0 {
+		maxDelay = DefaultMaxDelay
+	}
+
+	delay := p.BaseDelay
+
+	switch p.Backoff {
+	case BackoffFixed:
+	case BackoffLinear:
+		if errorCount > int(maxDelay/p.BaseDelay) { // prevent an overflow
+			return maxDelay
+		}
+
+		delay = p.BaseDelay * time.Duration(errorCount)
+	case BackoffExponential:
+		for i := 1; i < errorCount && delay < maxDelay; i++ {
+			delay *= 2
+		}
+	}
+
+	return min(delay, maxDelay)
+}
+
+// Exhausted reports if a job that failed errorCount times is not retried anymore.
+func (p RetryPolicy) Exhausted(errorCount int) bool {
+	return p.MaxAttempts > 0 && errorCount >= p.MaxAttempts
+}
@@ -0,0 +1,135 @@
+package jobs_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+)
+
+func TestRetryPolicy_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName string
+		policy   jobs.RetryPolicy
+		valid    bool
+	}{
+		{
+			"valid",
+			jobs.RetryPolicy{JobType: "t", Backoff: jobs.BackoffFixed, BaseDelay: time.Second},
+			true,
+		},
+		{
+			"missing job type",
+			jobs.RetryPolicy{Backoff: jobs.BackoffFixed, BaseDelay: time.Second},
+			false,
+		},
+		{
+			"unknown backoff",
+			jobs.RetryPolicy{JobType: "t", Backoff: "random", BaseDelay: time.Second},
+			false,
+		},
+		{
+			"negative attempts",
+			jobs.RetryPolicy{JobType: "t", Backoff: jobs.BackoffFixed, BaseDelay: time.Second, MaxAttempts: -1},
+			false,
+		},
+		{
+			"delay too small",
+			jobs.RetryPolicy{JobType: "t", Backoff: jobs.BackoffFixed, BaseDelay: time.Millisecond},
+			false,
+		},
+		{
+			"max delay smaller than base",
+			jobs.RetryPolicy{JobType: "t", Backoff: jobs.BackoffFixed, BaseDelay: time.Minute, MaxDelay: time.Second},
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.policy.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, jobs.ErrInvalidRetryPolicy)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName   string
+		policy     jobs.RetryPolicy
+		errorCount int
+		expected   time.Duration
+	}{
+		{
+			"fixed",
+			jobs.RetryPolicy{Backoff: jobs.BackoffFixed, BaseDelay: time.Minute},
+			5,
+			time.Minute,
+		},
+		{
+			"linear",
+			jobs.RetryPolicy{Backoff: jobs.BackoffLinear, BaseDelay: time.Minute},
+			3,
+			3 * time.Minute,
+		},
+		{
+			"exponential first attempt",
+			jobs.RetryPolicy{Backoff: jobs.BackoffExponential, BaseDelay: time.Minute},
+			1,
+			time.Minute,
+		},
+		{
+			"exponential",
+			jobs.RetryPolicy{Backoff: jobs.BackoffExponential, BaseDelay: time.Minute},
+			4,
+			8 * time.Minute,
+		},
+		{
+			"capped by max delay",
+			jobs.RetryPolicy{Backoff: jobs.BackoffExponential, BaseDelay: time.Minute, MaxDelay: time.Hour},
+			100,
+			time.Hour,
+		},
+		{
+			"exponential capped by default",
+			jobs.RetryPolicy{Backoff: jobs.BackoffExponential, BaseDelay: time.Minute},
+			1000,
+			jobs.DefaultMaxDelay,
+		},
+		{
+			"linear capped by default",
+			jobs.RetryPolicy{Backoff: jobs.BackoffLinear, BaseDelay: time.Hour},
+			math.MaxInt32,
+			jobs.DefaultMaxDelay,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, tt.policy.Delay(tt.errorCount))
+		})
+	}
+}
+
+func TestRetryPolicy_Exhausted(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, jobs.RetryPolicy{MaxAttempts: 0}.Exhausted(100), "zero attempts retries forever")
+	assert.False(t, jobs.RetryPolicy{MaxAttempts: 3}.Exhausted(2))
+	assert.True(t, jobs.RetryPolicy{MaxAttempts: 3}.Exhausted(3))
+}
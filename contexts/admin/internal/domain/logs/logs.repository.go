@@ -0,0 +1,128 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidSavedSearch = errors.New("invalid saved search")
+
+const (
+	DefaultLimit = 1000
+	// MaxLimit is the most logs shown at once, exports are not limited by it.
+	MaxLimit = 10000
+)
+
+// Repository manages the data access to the logs written by alog.
+type Repository interface {
+	// Search returns the newest logs matching the search, ordered by their time.
+	// If After is set, it returns the oldest logs after the cursor instead, so the page can tail the logs.
+	Search(ctx context.Context, search Search) ([]Log, error)
+	// Export calls fn for every log matching the search, ordered by their time.
+	Export(ctx context.Context, search Search, fn func(Log) error) error
+
+	// TableSize returns the number of logs and the size of the table they are stored in.
+	TableSize(ctx context.Context) (TableSize, error)
+	// NewestBeyond returns the time of the newest log, that is not among the n newest logs.
+	// It returns the zero time, if there are not more than n logs.
+	NewestBeyond(ctx context.Context, n int) (time.Time, error)
+	// Delete deletes all logs up to and including until and returns the number of deleted logs.
+	Delete(ctx context.Context, until time.Time) (int64, error)
+	// Vacuum gives the space of deleted logs back to the operating system.
+	Vacuum(ctx context.Context) error
+
+	SavedSearches(ctx context.Context, userID string) ([]SavedSearch, error)
+	SaveSearch(ctx context.Context, search SavedSearch) error
+	DeleteSearch(ctx context.Context, userID string, name string) error
+}
+
+type Search struct {
+	Query Query
+	From  time.Time
+	// To is the end of the time range, if zero there is no end.
+	To    time.Time
+	After Cursor
+	Limit int
+}
+
+// Cursor is the position of a log. Logs of the same time are ordered by the hash of their content,
+// so tailing the logs does not skip or repeat any of them.
+// Only logs with the same time and the same content can not be told apart.
+type Cursor struct {
+	Time time.Time
+	Hash string
+}
+
+func (c Cursor) IsZero() bool {
+	return c.Time.IsZero() && c.Hash == ""
+}
+
+// Log is a single log line as written by alog.
+type Log struct {
+	// Hash is the hash of the content of the log, as arrower.log has no id.
+	Hash   string
+	Time   time.Time
+	UserID string
+	// Attrs are all attributes of the log, including level, msg and the trace id.
+	Attrs map[string]any
+	// Raw is the log as stored, e.g. to export it unchanged.
+	Raw []byte
+}
+
+// Cursor returns the position of the log, to search the logs after it.
+func (l Log) Cursor() Cursor {
+	return Cursor{Time: l.Time, Hash: l.Hash}
+}
+
+func (l Log) Level() string {
+	return l.attr("level")
+}
+
+func (l Log) Msg() string {
+	return l.attr("msg")
+}
+
+// TraceID is the id of the trace the log was written in, if any.
+func (l Log) TraceID() string {
+	return l.attr("traceID")
+}
+
+func (l Log) attr(key string) string {
+	v, ok := l.Attrs[key]
+	if !ok || v == nil {
+		return ""
+	}
+
+	return fmt.Sprint(v)
+}
+
+// SavedSearch is a search an admin saved under a name, to run it again later.
+type SavedSearch struct {
+	UserID string
+	Name   string
+	Query  string
+	// Range is the number of minutes before now, the search looks at.
+	Range int
+}
+
+func (s SavedSearch) Validate() error {
+	if s.UserID == "" {
+		return fmt.Errorf("%w: user is required", ErrInvalidSavedSearch)
+	}
+
+	if s.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidSavedSearch)
+	}
+
+	if s.Range <= 0 {
+		return fmt.Errorf("%w: range has to be positive", ErrInvalidSavedSearch)
+	}
+
+	if _, err := ParseQuery(s.Query); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSavedSearch, err)
+	}
+
+	return nil
+}
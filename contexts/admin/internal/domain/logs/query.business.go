@@ -0,0 +1,246 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidQuery = errors.New("invalid log query")
+
+// Keys with a special meaning in a Query. All other keys are attributes of the log,
+// nested attributes are separated by a dot, e.g. request.method.
+const (
+	KeyLevel   = "level"
+	KeyMsg     = "msg"
+	KeyUserID  = "user_id"
+	KeyTraceID = "trace_id"
+)
+
+type Operator string
+
+const (
+	OpEqual          Operator = "="
+	OpNotEqual       Operator = "!="
+	OpContains       Operator = "~"
+	OpNotContains    Operator = "!~"
+	OpGreater        Operator = ">"
+	OpGreaterOrEqual Operator = ">="
+	OpLess           Operator = "<"
+	OpLessOrEqual    Operator = "<="
+)
+
+// operators ordered, so the longest operator matches first.
+var operators = []Operator{ //nolint:gochecknoglobals // read only
+	OpNotEqual, OpNotContains, OpGreaterOrEqual, OpLessOrEqual,
+	OpEqual, OpContains, OpGreater, OpLess,
+}
+
+var (
+	keyPattern    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_\-]*(\.[A-Za-z0-9_\-]+)*$`)
+	numberPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+)
+
+// Condition is a single filter of a Query, e.g. level>=warn.
+type Condition struct {
+	Key   string
+	Op    Operator
+	Value string
+}
+
+func (c Condition) IsOrdering() bool {
+	return c.Op == OpGreater || c.Op == OpGreaterOrEqual || c.Op == OpLess || c.Op == OpLessOrEqual
+}
+
+func (c Condition) String() string {
+	value := c.Value
+	if value == "" || strings.ContainsAny(value, " \"\\") {
+		value = strconv.Quote(value)
+	}
+
+	return c.Key + string(c.Op) + value
+}
+
+// Query filters logs. A log has to match all its conditions.
+type Query []Condition
+
+// ParseQuery parses a query like:
+//
+//	level>=warn user_id=0c6b... msg~"timeout" request.method=GET
+//
+// A term without an operator searches the msg, so `timeout` is short for `msg~timeout`.
+// Values with spaces are quoted. Levels are compared by their severity: debug, info, warn and error.
+// Other values can only be compared with <, <=, > and >=, if they are numbers.
+func ParseQuery(s string) (Query, error) {
+	var query Query
+
+	rest := strings.TrimSpace(s)
+	for rest != "" {
+		condition, remaining, err := parseCondition(rest)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := condition.validate(); err != nil {
+			return nil, err
+		}
+
+		query = append(query, condition)
+		rest = strings.TrimSpace(remaining)
+	}
+
+	return query, nil
+}
+
+func (q Query) String() string {
+	conditions := make([]string, 0, len(q))
+	for _, c := range q {
+		conditions = append(conditions, c.String())
+	}
+
+	return strings.Join(conditions, " ")
+}
+
+func parseCondition(s string) (Condition, string, error) {
+	if strings.HasPrefix(s, `"`) {
+		term, rest, err := parseQuoted(s)
+		if err != nil {
+			return Condition{}, "", err
+		}
+
+		return Condition{Key: KeyMsg, Op: OpContains, Value: term}, rest, nil
+	}
+
+	end := strings.IndexAny(s, "=!~<> ")
+	if end == -1 || s[end] == ' ' {
+		word, rest := cutWord(s)
+
+		return Condition{Key: KeyMsg, Op: OpContains, Value: word}, rest, nil
+	}
+
+	key := s[:end]
+	if key == "" {
+		return Condition{}, "", fmt.Errorf("%w: missing key before %s", ErrInvalidQuery, s)
+	}
+
+	rest := s[end:]
+
+	var op Operator
+
+	for _, o := range operators {
+		if strings.HasPrefix(rest, string(o)) {
+			op = o
+			rest = rest[len(o):]
+
+			break
+		}
+	}
+
+	if op == "" {
+		return Condition{}, "", fmt.Errorf("%w: unknown operator in %s", ErrInvalidQuery, s)
+	}
+
+	if strings.HasPrefix(rest, `"`) {
+		value, remaining, err := parseQuoted(rest)
+		if err != nil {
+			return Condition{}, "", err
+		}
+
+		return Condition{Key: key, Op: op, Value: value}, remaining, nil
+	}
+
+	// an operator followed by another one, e.g. == or <>, is not a known operator
+	if strings.IndexAny(rest, "=!~<>") == 0 {
+		return Condition{}, "", fmt.Errorf("%w: unknown operator in %s", ErrInvalidQuery, s)
+	}
+
+	value, remaining := cutWord(rest)
+
+	return Condition{Key: key, Op: op, Value: value}, remaining, nil
+}
+
+// parseQuoted returns the unquoted string at the beginning of s and the rest of s.
+// A quote inside the string is escaped with a backslash.
+func parseQuoted(s string) (string, string, error) {
+	var b strings.Builder
+
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+			}
+		case '"':
+			return b.String(), s[i+1:], nil
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return "", "", fmt.Errorf("%w: missing closing quote in %s", ErrInvalidQuery, s)
+}
+
+func cutWord(s string) (string, string) {
+	word, rest, _ := strings.Cut(s, " ")
+
+	return word, rest
+}
+
+func (c Condition) validate() error {
+	if !keyPattern.MatchString(c.Key) {
+		return fmt.Errorf("%w: invalid key: %s", ErrInvalidQuery, c.Key)
+	}
+
+	switch c.Key {
+	case KeyLevel:
+		if _, err := ParseLevel(c.Value); err != nil {
+			return err
+		}
+
+		if c.Op == OpContains || c.Op == OpNotContains {
+			return fmt.Errorf("%w: %s can not be used with %s", ErrInvalidQuery, c.Op, c.Key)
+		}
+	case KeyUserID, KeyTraceID:
+		if c.Op != OpEqual && c.Op != OpNotEqual {
+			return fmt.Errorf("%w: %s can not be used with %s", ErrInvalidQuery, c.Op, c.Key)
+		}
+	case KeyMsg:
+		if c.IsOrdering() {
+			return fmt.Errorf("%w: %s can not be used with %s", ErrInvalidQuery, c.Op, c.Key)
+		}
+	default:
+		if c.IsOrdering() && !numberPattern.MatchString(c.Value) {
+			return fmt.Errorf("%w: %s needs a number: %s", ErrInvalidQuery, c.Op, c.String())
+		}
+	}
+
+	return nil
+}
+
+// Level is the severity of a log, with the values of slog.
+type Level int
+
+const (
+	LevelDebug Level = -4
+	LevelInfo  Level = 0
+	LevelWarn  Level = 4
+	LevelError Level = 8
+)
+
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return 0, fmt.Errorf("%w: unknown level: %s", ErrInvalidQuery, s)
+	}
+}
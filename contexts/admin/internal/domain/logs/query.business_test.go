@@ -0,0 +1,106 @@
+package logs_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/logs"
+)
+
+func TestParseQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		query    string
+		expected logs.Query
+	}{
+		"empty": {
+			"  ",
+			nil,
+		},
+		"level": {
+			"level>=warn",
+			logs.Query{{Key: logs.KeyLevel, Op: logs.OpGreaterOrEqual, Value: "warn"}},
+		},
+		"multiple conditions": {
+			`level>=warn user_id=0c6b msg~"connection timeout"`,
+			logs.Query{
+				{Key: logs.KeyLevel, Op: logs.OpGreaterOrEqual, Value: "warn"},
+				{Key: logs.KeyUserID, Op: logs.OpEqual, Value: "0c6b"},
+				{Key: logs.KeyMsg, Op: logs.OpContains, Value: "connection timeout"},
+			},
+		},
+		"free text searches the msg": {
+			`timeout "user not found"`,
+			logs.Query{
+				{Key: logs.KeyMsg, Op: logs.OpContains, Value: "timeout"},
+				{Key: logs.KeyMsg, Op: logs.OpContains, Value: "user not found"},
+			},
+		},
+		"nested attribute": {
+			"request.method!=GET",
+			logs.Query{{Key: "request.method", Op: logs.OpNotEqual, Value: "GET"}},
+		},
+		"escaped quote": {
+			`err!~"say \"hi\""`,
+			logs.Query{{Key: "err", Op: logs.OpNotContains, Value: `say "hi"`}},
+		},
+		"number": {
+			"duration_ms>250.5",
+			logs.Query{{Key: "duration_ms", Op: logs.OpGreater, Value: "250.5"}},
+		},
+		"sql is just a value": {
+			`msg="'; DROP TABLE arrower.log; --"`,
+			logs.Query{{Key: logs.KeyMsg, Op: logs.OpEqual, Value: "'; DROP TABLE arrower.log; --"}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := logs.ParseQuery(tt.query)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, query)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		for _, q := range []string{
+			`msg="missing quote`,
+			"level>=fatal",
+			"level~warn",
+			"trace_id~abc",
+			"duration>long",
+			"=value",
+			"key!value",
+			"ke'y=1",
+			"msg>5",
+			"msg<=5",
+			"user_id>1",
+			"duration==5",
+			"duration<>5",
+			"duration=>5",
+		} {
+			_, err := logs.ParseQuery(q)
+			assert.ErrorIs(t, err, logs.ErrInvalidQuery, q)
+		}
+	})
+}
+
+func TestQuery_String(t *testing.T) {
+	t.Parallel()
+
+	query := `level>=warn msg~"connection timeout" user_id=""`
+
+	parsed, err := logs.ParseQuery(query)
+	assert.NoError(t, err)
+	assert.Equal(t, query, parsed.String())
+
+	reparsed, err := logs.ParseQuery(parsed.String())
+	assert.NoError(t, err)
+	assert.Equal(t, parsed, reparsed)
+}
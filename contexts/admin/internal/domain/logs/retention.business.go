@@ -0,0 +1,68 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidRetention = errors.New("invalid retention policy")
+
+// RetentionPolicy decides which logs are pruned by the maintenance job.
+// A limit of zero is not enforced, so the zero policy keeps all logs.
+type RetentionPolicy struct {
+	// MaxAgeDays is the number of days logs are kept.
+	MaxAgeDays int
+	// MaxRows is the number of the newest logs kept.
+	MaxRows int
+	// Archive writes the logs into an Archive before they are deleted.
+	Archive bool
+}
+
+func (p RetentionPolicy) Validate() error {
+	if p.MaxAgeDays < 0 {
+		return fmt.Errorf("%w: max age can not be negative", ErrInvalidRetention)
+	}
+
+	if p.MaxRows < 0 {
+		return fmt.Errorf("%w: max rows can not be negative", ErrInvalidRetention)
+	}
+
+	return nil
+}
+
+func (p RetentionPolicy) IsEnabled() bool {
+	return p.MaxAgeDays > 0 || p.MaxRows > 0
+}
+
+// Until returns the time up to which logs are pruned, or the zero time if no log is.
+// beyondMaxRows is the time of the newest log, that is not among the MaxRows newest logs,
+// see Repository.NewestBeyond.
+func (p RetentionPolicy) Until(now time.Time, beyondMaxRows time.Time) time.Time {
+	var until time.Time
+
+	if p.MaxAgeDays > 0 {
+		until = now.AddDate(0, 0, -p.MaxAgeDays)
+	}
+
+	if p.MaxRows > 0 && beyondMaxRows.After(until) {
+		until = beyondMaxRows
+	}
+
+	return until
+}
+
+// TableSize is the size of the table the logs are stored in.
+type TableSize struct {
+	Logs int64
+	// Size is human-readable, e.g. 12 MB.
+	Size string
+}
+
+// Archive keeps logs before they are pruned.
+type Archive interface {
+	// Save stores all logs passed to the callback of export under the name.
+	// It returns where the logs are stored, e.g. the path of a file, or nothing if there were no logs.
+	Save(ctx context.Context, name string, export func(fn func(Log) error) error) (string, error)
+}
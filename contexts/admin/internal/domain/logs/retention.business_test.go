@@ -0,0 +1,71 @@
+package logs_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/logs"
+)
+
+func TestRetentionPolicy_Until(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	weekAgo := now.AddDate(0, 0, -7)
+
+	tests := map[string]struct {
+		policy        logs.RetentionPolicy
+		beyondMaxRows time.Time
+		expected      time.Time
+	}{
+		"disabled": {
+			logs.RetentionPolicy{},
+			now.Add(-time.Hour),
+			time.Time{},
+		},
+		"by age": {
+			logs.RetentionPolicy{MaxAgeDays: 7},
+			time.Time{},
+			weekAgo,
+		},
+		"by rows": {
+			logs.RetentionPolicy{MaxRows: 100},
+			now.Add(-time.Hour),
+			now.Add(-time.Hour),
+		},
+		"fewer rows than max": {
+			logs.RetentionPolicy{MaxRows: 100},
+			time.Time{},
+			time.Time{},
+		},
+		"rows prune more than age": {
+			logs.RetentionPolicy{MaxAgeDays: 7, MaxRows: 100},
+			now.Add(-time.Hour),
+			now.Add(-time.Hour),
+		},
+		"age prunes more than rows": {
+			logs.RetentionPolicy{MaxAgeDays: 7, MaxRows: 100},
+			weekAgo.Add(-time.Hour),
+			weekAgo,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, tt.policy.Until(now, tt.beyondMaxRows))
+		})
+	}
+}
+
+func TestRetentionPolicy_Validate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, logs.RetentionPolicy{}.Validate())
+	assert.NoError(t, logs.RetentionPolicy{MaxAgeDays: 30, MaxRows: 1000, Archive: true}.Validate())
+	assert.ErrorIs(t, logs.RetentionPolicy{MaxAgeDays: -1}.Validate(), logs.ErrInvalidRetention)
+	assert.ErrorIs(t, logs.RetentionPolicy{MaxRows: -1}.Validate(), logs.ErrInvalidRetention)
+}
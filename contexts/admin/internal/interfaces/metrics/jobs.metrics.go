@@ -0,0 +1,183 @@
+// Package metrics exports the job metrics of the admin context as OpenTelemetry metrics.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/go-arrower/arrower/alog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+)
+
+const (
+	// window is the time range the exported metrics are calculated over.
+	// It is short, so the metrics follow changes quickly.
+	window = 5 * time.Minute
+
+	// refreshInterval is how often the metrics are calculated from the job history.
+	// Collecting the metrics reads the last snapshot, so the history is not queried on every collection.
+	refreshInterval = time.Minute
+)
+
+// NewJobMetrics returns JobMetrics without a snapshot. Call Refresh or Start to calculate them.
+func NewJobMetrics(logger alog.Logger, repo jobs.Repository) *JobMetrics {
+	return &JobMetrics{
+		logger:   logger,
+		repo:     repo,
+		done:     make(chan struct{}),
+		snapshot: map[jobs.QueueName][]jobs.JobTypeMetrics{},
+	}
+}
+
+// JobMetrics exports the job metrics of all queues as observable gauges.
+type JobMetrics struct {
+	logger alog.Logger
+	repo   jobs.Repository
+
+	done chan struct{}
+
+	snapshot map[jobs.QueueName][]jobs.JobTypeMetrics
+
+	mu sync.RWMutex
+	wg sync.WaitGroup
+}
+
+// Register registers the gauges for the job metrics with the meter.
+// They report the last snapshot taken by Refresh.
+func (m *JobMetrics) Register(meter metric.Meter) error {
+	latency, err := meter.Float64ObservableGauge("arrower.jobs.latency",
+		metric.WithDescription("processing time of a job, by percentile"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return fmt.Errorf("could not create latency gauge: %w", err)
+	}
+
+	waitTime, err := meter.Float64ObservableGauge("arrower.jobs.wait_time",
+		metric.WithDescription("time between a job is scheduled to run and a worker starts it, by percentile"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return fmt.Errorf("could not create wait time gauge: %w", err)
+	}
+
+	throughput, err := meter.Float64ObservableGauge("arrower.jobs.throughput",
+		metric.WithDescription("processed jobs per minute"),
+		metric.WithUnit("{job}/min"),
+	)
+	if err != nil {
+		return fmt.Errorf("could not create throughput gauge: %w", err)
+	}
+
+	failureRate, err := meter.Float64ObservableGauge("arrower.jobs.failure_rate",
+		metric.WithDescription("percentage of failed attempts"),
+		metric.WithUnit("%"),
+	)
+	if err != nil {
+		return fmt.Errorf("could not create failure rate gauge: %w", err)
+	}
+
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+
+		for queue, metrics := range m.snapshot {
+			for _, jm := range metrics {
+				attrs := []attribute.KeyValue{
+					attribute.String("queue", string(queue)),
+					attribute.String("job_type", string(jm.JobType)),
+				}
+
+				observePercentiles(o, latency, jm.Latency, attrs)
+				observePercentiles(o, waitTime, jm.WaitTime, attrs)
+				o.ObserveFloat64(throughput, jm.Throughput(window), metric.WithAttributes(attrs...))
+				o.ObserveFloat64(failureRate, jm.FailureRate(), metric.WithAttributes(attrs...))
+			}
+		}
+
+		return nil
+	}, latency, waitTime, throughput, failureRate)
+	if err != nil {
+		return fmt.Errorf("could not register job metrics: %w", err)
+	}
+
+	return nil
+}
+
+// Refresh calculates the metrics of all queues from the job history and replaces the snapshot.
+// If it fails, the last snapshot is kept.
+func (m *JobMetrics) Refresh(ctx context.Context) error {
+	queues, err := m.repo.Queues(ctx)
+	if err != nil {
+		return fmt.Errorf("could not get queues: %w", err)
+	}
+
+	snapshot := make(map[jobs.QueueName][]jobs.JobTypeMetrics, len(queues))
+
+	for _, queue := range queues {
+		metrics, err := m.repo.JobMetrics(ctx, queue, time.Now().Add(-window))
+		if err != nil {
+			return fmt.Errorf("could not get job metrics: %w", err)
+		}
+
+		snapshot[queue] = metrics
+	}
+
+	m.mu.Lock()
+	m.snapshot = snapshot
+	m.mu.Unlock()
+
+	return nil
+}
+
+// Start refreshes the metrics periodically in the background, until Shutdown is called or the ctx is cancelled.
+func (m *JobMetrics) Start(ctx context.Context) {
+	m.wg.Add(1)
+
+	go func() {
+		defer m.wg.Done()
+
+		ticker := time.NewTicker(refreshInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := m.Refresh(ctx); err != nil {
+				m.logger.LogAttrs(ctx, slog.LevelWarn, "could not refresh job metrics", slog.String("err", err.Error()))
+			}
+
+			select {
+			case <-ticker.C:
+			case <-m.done:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (m *JobMetrics) Shutdown(_ context.Context) error {
+	select {
+	case <-m.done:
+	default:
+		close(m.done)
+	}
+
+	m.wg.Wait()
+
+	return nil
+}
+
+func observePercentiles(o metric.Observer, gauge metric.Float64Observable, p jobs.Percentiles, attrs []attribute.KeyValue) {
+	for quantile, d := range map[string]time.Duration{"0.5": p.P50, "0.95": p.P95, "0.99": p.P99} {
+		o.ObserveFloat64(gauge, d.Seconds(),
+			metric.WithAttributes(append(attrs, attribute.String("quantile", quantile))...),
+		)
+	}
+}
@@ -0,0 +1,120 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/stretchr/testify/assert"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/interfaces/metrics"
+)
+
+var ctx = context.Background()
+
+func TestJobMetrics_Register(t *testing.T) {
+	t.Parallel()
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	repo := &fakeRepository{}
+
+	m := metrics.NewJobMetrics(alog.NewNoopLogger(), repo)
+
+	err := m.Register(provider.Meter("test"))
+	assert.NoError(t, err)
+
+	assert.Empty(t, collect(t, reader), "nothing is reported before the first refresh")
+
+	err = m.Refresh(ctx)
+	assert.NoError(t, err)
+
+	want := map[string]int{
+		"arrower.jobs.latency":      3, // one per percentile
+		"arrower.jobs.wait_time":    3,
+		"arrower.jobs.throughput":   1,
+		"arrower.jobs.failure_rate": 1,
+	}
+	assert.Equal(t, want, collect(t, reader))
+	assert.Equal(t, want, collect(t, reader))
+	assert.Equal(t, int64(1), repo.calls.Load(), "collecting reads the snapshot and does not query the repository")
+
+	repo.fail.Store(true)
+
+	err = m.Refresh(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, want, collect(t, reader), "the last snapshot is kept")
+}
+
+func TestJobMetrics_Start(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeRepository{}
+	m := metrics.NewJobMetrics(alog.NewNoopLogger(), repo)
+
+	m.Start(ctx)
+
+	assert.Eventually(t, func() bool {
+		return repo.calls.Load() == 1
+	}, time.Second, time.Millisecond, "the metrics are refreshed at start")
+
+	err := m.Shutdown(ctx)
+	assert.NoError(t, err)
+}
+
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]int {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	err := reader.Collect(ctx, &rm)
+	assert.NoError(t, err)
+
+	got := map[string]int{}
+
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			gauge, ok := m.Data.(metricdata.Gauge[float64])
+			assert.True(t, ok)
+
+			if len(gauge.DataPoints) > 0 {
+				got[m.Name] = len(gauge.DataPoints)
+			}
+		}
+	}
+
+	return got
+}
+
+var errRepository = errors.New("repository failed")
+
+type fakeRepository struct {
+	jobs.Repository
+
+	calls atomic.Int64
+	fail  atomic.Bool
+}
+
+func (r *fakeRepository) Queues(_ context.Context) (jobs.QueueNames, error) {
+	if r.fail.Load() {
+		return nil, errRepository
+	}
+
+	return jobs.QueueNames{jobs.DefaultQueueName}, nil
+}
+
+func (r *fakeRepository) JobMetrics(_ context.Context, _ jobs.QueueName, _ time.Time) ([]jobs.JobTypeMetrics, error) {
+	r.calls.Add(1)
+
+	return []jobs.JobTypeMetrics{{
+		JobType:   "SomeJob",
+		Latency:   jobs.Percentiles{P50: time.Second, P95: 2 * time.Second, P99: 3 * time.Second},
+		Processed: 10,
+		Failed:    1,
+	}}, nil
+}
@@ -0,0 +1,169 @@
+// Package notify sends job alerts to the channels of an alert rule.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-arrower/arrower/alog"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+	"github.com/go-arrower/skeleton/shared/infrastructure/mail"
+)
+
+var (
+	ErrNotifyFailed   = errors.New("notify failed")
+	ErrUnknownChannel = fmt.Errorf("%w: unknown channel", ErrNotifyFailed)
+)
+
+const (
+	channelLog     = "log"
+	channelEmail   = "email"
+	channelWebhook = "webhook"
+
+	webhookTimeout = 10 * time.Second
+)
+
+// NewChannels returns a jobs.Notifier, that sends alerts depending on the channel:
+//
+//	log                         logs the alert
+//	email:ops@example.com,...   sends an email, if a mailer is given
+//	webhook:https://example.com posts the alert as JSON
+func NewChannels(logger alog.Logger, mailer mail.Mailer, client *http.Client) *Channels {
+	if client == nil {
+		client = &http.Client{Timeout: webhookTimeout} //nolint:exhaustruct // use the defaults
+	}
+
+	return &Channels{
+		logger: logger,
+		mailer: mailer,
+		client: client,
+	}
+}
+
+type Channels struct {
+	logger alog.Logger
+	mailer mail.Mailer
+	client *http.Client
+}
+
+var _ jobs.Notifier = (*Channels)(nil)
+
+func (c *Channels) Notify(ctx context.Context, channel string, alert jobs.Alert, transition jobs.Transition) error {
+	kind, target, _ := strings.Cut(channel, ":")
+
+	switch kind {
+	case channelLog:
+		c.log(ctx, alert, transition)
+
+		return nil
+	case channelEmail:
+		return c.email(ctx, target, alert, transition)
+	case channelWebhook:
+		return c.webhook(ctx, target, alert, transition)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownChannel, channel)
+	}
+}
+
+func (c *Channels) log(ctx context.Context, alert jobs.Alert, transition jobs.Transition) {
+	level := slog.LevelWarn
+	if transition == jobs.TransitionResolved {
+		level = slog.LevelInfo
+	}
+
+	c.logger.LogAttrs(ctx, level, "job alert "+string(transition),
+		slog.String("rule", alert.Rule),
+		slog.String("queue", string(alert.Queue)),
+		slog.String("job_type", string(alert.JobType)),
+		slog.String("message", alert.Message),
+		slog.Float64("value", alert.Value),
+	)
+}
+
+func (c *Channels) email(ctx context.Context, to string, alert jobs.Alert, transition jobs.Transition) error {
+	if c.mailer == nil {
+		return fmt.Errorf("%w: no mailer configured", ErrNotifyFailed)
+	}
+
+	recipients := strings.Split(to, ",")
+	for i := range recipients {
+		recipients[i] = strings.TrimSpace(recipients[i])
+	}
+
+	subject := fmt.Sprintf("[%s] %s", strings.ToUpper(string(transition)), alert.Rule)
+
+	err := c.mailer.Send(ctx, recipients, subject, alert.Message)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrNotifyFailed, err)
+	}
+
+	return nil
+}
+
+type webhookPayload struct {
+	PendingSince time.Time  `json:"pendingSince"`
+	FiredAt      *time.Time `json:"firedAt,omitempty"`
+	ResolvedAt   *time.Time `json:"resolvedAt,omitempty"`
+	Transition   string     `json:"transition"`
+	ID           string     `json:"id"`
+	Rule         string     `json:"rule"`
+	Queue        string     `json:"queue"`
+	JobType      string     `json:"jobType,omitempty"`
+	Message      string     `json:"message"`
+	Value        float64    `json:"value"`
+}
+
+func (c *Channels) webhook(ctx context.Context, url string, alert jobs.Alert, transition jobs.Transition) error {
+	payload := webhookPayload{
+		PendingSince: alert.PendingSince,
+		FiredAt:      optionalTime(alert.FiredAt),
+		ResolvedAt:   optionalTime(alert.ResolvedAt),
+		Transition:   string(transition),
+		ID:           alert.ID,
+		Rule:         alert.Rule,
+		Queue:        string(alert.Queue),
+		JobType:      string(alert.JobType),
+		Message:      alert.Message,
+		Value:        alert.Value,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNotifyFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: invalid webhook: %v", ErrNotifyFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNotifyFailed, err) //nolint:errorlint // prevent err in api
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%w: webhook responded with status: %d", ErrNotifyFailed, res.StatusCode)
+	}
+
+	return nil
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
@@ -0,0 +1,108 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/interfaces/notify"
+)
+
+var (
+	ctx   = context.Background()
+	alert = jobs.Alert{ID: "1", Rule: "too-many-pending", Queue: "Default", Message: "Default: 20 jobs pending, more than 10", Value: 20}
+)
+
+func TestChannels_Notify(t *testing.T) {
+	t.Parallel()
+
+	t.Run("log", func(t *testing.T) {
+		t.Parallel()
+
+		c := notify.NewChannels(alog.NewNoopLogger(), nil, nil)
+
+		err := c.Notify(ctx, "log", alert, jobs.TransitionFired)
+		assert.NoError(t, err)
+	})
+
+	t.Run("email", func(t *testing.T) {
+		t.Parallel()
+
+		mailer := &fakeMailer{}
+		c := notify.NewChannels(alog.NewNoopLogger(), mailer, nil)
+
+		err := c.Notify(ctx, "email:ops@example.com, dev@example.com", alert, jobs.TransitionFired)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ops@example.com", "dev@example.com"}, mailer.to)
+		assert.Equal(t, "[FIRED] too-many-pending", mailer.subject)
+	})
+
+	t.Run("email without mailer", func(t *testing.T) {
+		t.Parallel()
+
+		c := notify.NewChannels(alog.NewNoopLogger(), nil, nil)
+
+		err := c.Notify(ctx, "email:ops@example.com", alert, jobs.TransitionFired)
+		assert.ErrorIs(t, err, notify.ErrNotifyFailed)
+	})
+
+	t.Run("webhook", func(t *testing.T) {
+		t.Parallel()
+
+		var got map[string]any
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&got)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		c := notify.NewChannels(alog.NewNoopLogger(), nil, server.Client())
+
+		err := c.Notify(ctx, "webhook:"+server.URL, alert, jobs.TransitionResolved)
+		assert.NoError(t, err)
+		assert.Equal(t, "resolved", got["transition"])
+		assert.Equal(t, "too-many-pending", got["rule"])
+	})
+
+	t.Run("webhook fails", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		c := notify.NewChannels(alog.NewNoopLogger(), nil, server.Client())
+
+		err := c.Notify(ctx, "webhook:"+server.URL, alert, jobs.TransitionFired)
+		assert.ErrorIs(t, err, notify.ErrNotifyFailed)
+	})
+
+	t.Run("unknown channel", func(t *testing.T) {
+		t.Parallel()
+
+		c := notify.NewChannels(alog.NewNoopLogger(), nil, nil)
+
+		err := c.Notify(ctx, "pager:123", alert, jobs.TransitionFired)
+		assert.ErrorIs(t, err, notify.ErrUnknownChannel)
+	})
+}
+
+type fakeMailer struct {
+	to      []string
+	subject string
+}
+
+func (m *fakeMailer) Send(_ context.Context, to []string, subject string, _ string) error {
+	m.to = to
+	m.subject = subject
+
+	return nil
+}
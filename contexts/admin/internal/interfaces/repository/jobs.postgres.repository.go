@@ -8,6 +8,8 @@ import (
 
 	"github.com/go-arrower/arrower/postgres"
 	"github.com/jackc/pgx/v4"
+	pgxv5 "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"golang.org/x/sync/errgroup"
@@ -57,21 +59,21 @@ func (repo *PostgresJobsRepository) PendingJobs(ctx context.Context, queue jobs.
 	return jobsToDomain(jobs), nil
 }
 
-func jobsToDomain(j []models.ArrowerGueJob) []jobs.PendingJob {
+func jobsToDomain(j []models.GetPendingJobsRow) []jobs.PendingJob {
 	jobs := make([]jobs.PendingJob, len(j))
 
 	for i := 0; i < len(j); i++ {
-		jobs[i] = jobToDomain(j[i])
+		jobs[i] = jobToDomain(models.GetJobRow(j[i]))
 	}
 
 	return jobs
 }
 
-func jobToDomain(job models.ArrowerGueJob) jobs.PendingJob {
+func jobToDomain(job models.GetJobRow) jobs.PendingJob {
 	return jobs.PendingJob{
 		ID:         job.JobID,
 		Priority:   job.Priority,
-		RunAt:      job.RunAt.Time,
+		RunAt:      job.RunAt.Time, // zero, if the job is parked with run_at = 'infinity'
 		Type:       job.JobType,
 		Payload:    string(job.Args),
 		ErrorCount: job.ErrorCount,
@@ -79,6 +81,7 @@ func jobToDomain(job models.ArrowerGueJob) jobs.PendingJob {
 		Queue:      job.Queue,
 		CreatedAt:  job.CreatedAt.Time,
 		UpdatedAt:  job.UpdatedAt.Time,
+		Paused:     job.Paused,
 	}
 }
 
@@ -217,7 +220,7 @@ func (repo *PostgresJobsRepository) WorkerPools(ctx context.Context) ([]jobs.Wor
 	return workersToDomain(w), nil
 }
 
-func workersToDomain(w []models.ArrowerGueJobsWorkerPool) []jobs.WorkerPool {
+func workersToDomain(w []models.GetWorkerPoolsRow) []jobs.WorkerPool {
 	workers := make([]jobs.WorkerPool, len(w))
 
 	for i, w := range w {
@@ -226,6 +229,7 @@ func workersToDomain(w []models.ArrowerGueJobsWorkerPool) []jobs.WorkerPool {
 			Queue:    string(queueNameToDomain(w.Queue)), // todo change struct type
 			Version:  w.GitHash,
 			JobTypes: w.JobTypes,
+			Role:     w.Role,
 			Workers:  int(w.Workers),
 			LastSeen: w.UpdatedAt.Time,
 		}
@@ -320,6 +324,7 @@ func historyJobToDomain(job models.ArrowerGueJobsHistory) jobs.PendingJob {
 		Queue:      string(queueNameToDomain(job.Queue)), // todo change type of struct
 		CreatedAt:  job.CreatedAt.Time,
 		UpdatedAt:  job.UpdatedAt.Time,
+		Paused:     false,
 	}
 }
 
@@ -338,3 +343,330 @@ func queueNameToDomain(name string) jobs.QueueName {
 
 	return jobs.QueueName(name)
 }
+
+func (repo *PostgresJobsRepository) Job(ctx context.Context, jobID string) (jobs.PendingJob, error) {
+	job, err := repo.Conn().GetJob(ctx, jobID)
+	if errors.Is(err, pgxv5.ErrNoRows) {
+		return jobs.PendingJob{}, jobs.ErrJobNotFound
+	}
+
+	if err != nil {
+		return jobs.PendingJob{}, fmt.Errorf("%w: could not get job: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	return jobToDomain(job), nil
+}
+
+// UpdateJob overwrites the job with the given values.
+// A zero RunAt parks the job, so no worker will pick it up.
+//
+// UpdateJob will time out after one second, assuming that if the database needs longer to execute the query,
+// it means the row is locked by an active worker processing the job.
+func (repo *PostgresJobsRepository) UpdateJob(ctx context.Context, job jobs.PendingJob) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	runAt := pgtype.Timestamptz{Time: job.RunAt, Valid: true, InfinityModifier: pgtype.Finite}
+	if job.RunAt.IsZero() {
+		runAt = pgtype.Timestamptz{Valid: true, InfinityModifier: pgtype.Infinity} //nolint:exhaustruct // no time for infinity
+	}
+
+	err := repo.ConnOrTX(ctx).UpdateJob(ctx, models.UpdateJobParams{
+		JobID:      job.ID,
+		Queue:      queueNameFromDomain(jobs.QueueName(job.Queue)),
+		Priority:   job.Priority,
+		Args:       []byte(job.Payload),
+		ErrorCount: job.ErrorCount,
+		RunAt:      runAt,
+	})
+	if isLocked(err) {
+		return fmt.Errorf("%w: could not update: %v", jobs.ErrJobLockedAlready, err) //nolint:errorlint // prevent err in api
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: could not update: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	return nil
+}
+
+// The codes of the postgres errors, that are returned while a worker holds the lock on a job.
+const (
+	pgQueryCanceled    = "57014"
+	pgLockNotAvailable = "55P03"
+)
+
+// isLocked returns true, if the query timed out waiting for the lock a worker holds on the job.
+func isLocked(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+
+	return errors.As(err, &pgErr) && (pgErr.Code == pgQueryCanceled || pgErr.Code == pgLockNotAvailable)
+}
+
+// PayloadExamples returns the payloads of the last jobs of the given type, that have not been pruned.
+func (repo *PostgresJobsRepository) PayloadExamples(ctx context.Context, queue jobs.QueueName, jobType jobs.JobType) ([][]byte, error) {
+	payloads, err := repo.Conn().LastHistoryPayloads(ctx, models.LastHistoryPayloadsParams{
+		Queue:   queueNameFromDomain(queue),
+		JobType: string(jobType),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not get payloads: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	examples := make([][]byte, 0, len(payloads))
+
+	for _, p := range payloads {
+		if len(p) > 0 {
+			examples = append(examples, p)
+		}
+	}
+
+	return examples, nil
+}
+
+func (repo *PostgresJobsRepository) RetryPolicies(ctx context.Context) ([]jobs.RetryPolicy, error) {
+	p, err := repo.Conn().GetRetryPolicies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not get retry policies: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	policies := make([]jobs.RetryPolicy, len(p))
+
+	for i, p := range p {
+		policies[i] = jobs.RetryPolicy{
+			Queue:       queueNameToDomain(p.Queue),
+			JobType:     jobs.JobType(p.JobType),
+			Backoff:     jobs.Backoff(p.Backoff),
+			MaxAttempts: int(p.MaxAttempts),
+			BaseDelay:   time.Duration(p.BaseDelaySeconds) * time.Second,
+			MaxDelay:    time.Duration(p.MaxDelaySeconds) * time.Second,
+		}
+	}
+
+	return policies, nil
+}
+
+func (repo *PostgresJobsRepository) SaveRetryPolicy(ctx context.Context, policy jobs.RetryPolicy) error {
+	err := repo.ConnOrTX(ctx).UpsertRetryPolicy(ctx, models.UpsertRetryPolicyParams{
+		Queue:            queueNameFromDomain(policy.Queue),
+		JobType:          string(policy.JobType),
+		Backoff:          string(policy.Backoff),
+		MaxAttempts:      int32(policy.MaxAttempts),
+		BaseDelaySeconds: int32(policy.BaseDelay / time.Second),
+		MaxDelaySeconds:  int32(policy.MaxDelay / time.Second),
+	})
+	if err != nil {
+		return fmt.Errorf("%w: could not save retry policy: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	return nil
+}
+
+func (repo *PostgresJobsRepository) DeleteRetryPolicy(ctx context.Context, queue jobs.QueueName, jobType jobs.JobType) error {
+	err := repo.ConnOrTX(ctx).DeleteRetryPolicy(ctx, models.DeleteRetryPolicyParams{
+		Queue:   queueNameFromDomain(queue),
+		JobType: string(jobType),
+	})
+	if err != nil {
+		return fmt.Errorf("%w: could not delete retry policy: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	return nil
+}
+
+func (repo *PostgresJobsRepository) ResumePausedJobs(ctx context.Context, queue jobs.QueueName, jobType jobs.JobType) error {
+	err := repo.ConnOrTX(ctx).ResumePausedJobs(ctx, models.ResumePausedJobsParams{
+		Queue:   queueNameFromDomain(queue),
+		JobType: string(jobType),
+	})
+	if err != nil {
+		return fmt.Errorf("%w: could not resume paused jobs: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	return nil
+}
+
+// JobMetrics returns the metrics per job type of all attempts, that finished since the given time.
+func (repo *PostgresJobsRepository) JobMetrics(ctx context.Context, queue jobs.QueueName, since time.Time) ([]jobs.JobTypeMetrics, error) {
+	rows, err := repo.Conn().StatsJobTypeMetrics(ctx, models.StatsJobTypeMetricsParams{
+		Queue:      queueNameFromDomain(queue),
+		FinishedAt: pgtype.Timestamptz{Time: since, Valid: true, InfinityModifier: pgtype.Finite},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not query job metrics: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	metrics := make([]jobs.JobTypeMetrics, len(rows))
+
+	for i, row := range rows {
+		metrics[i] = jobs.JobTypeMetrics{
+			JobType: jobs.JobType(row.JobType),
+			Latency: jobs.Percentiles{
+				P50: secondsToDuration(row.LatencyP50),
+				P95: secondsToDuration(row.LatencyP95),
+				P99: secondsToDuration(row.LatencyP99),
+			},
+			WaitTime: jobs.Percentiles{
+				P50: secondsToDuration(row.WaitP50),
+				P95: secondsToDuration(row.WaitP95),
+				P99: secondsToDuration(row.WaitP99),
+			},
+			Processed: int(row.Processed),
+			Failed:    int(row.Failed),
+		}
+	}
+
+	return metrics, nil
+}
+
+// JobMetricsTimeSeries returns the metrics of all attempts, that finished since the given time, in bins of the given size.
+// Bins without any finished attempt are not returned.
+func (repo *PostgresJobsRepository) JobMetricsTimeSeries(
+	ctx context.Context,
+	queue jobs.QueueName,
+	since time.Time,
+	bin time.Duration,
+) ([]jobs.MetricsBucket, error) {
+	rows, err := repo.Conn().StatsJobMetricsTimeSeries(ctx, models.StatsJobMetricsTimeSeriesParams{
+		Bin:        pgtype.Interval{Microseconds: bin.Microseconds(), Valid: true},
+		Queue:      queueNameFromDomain(queue),
+		FinishedAt: pgtype.Timestamptz{Time: since, Valid: true, InfinityModifier: pgtype.Finite},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not query job metrics: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	buckets := make([]jobs.MetricsBucket, len(rows))
+
+	for i, row := range rows {
+		buckets[i] = jobs.MetricsBucket{
+			Time:       row.T.Time,
+			LatencyP95: secondsToDuration(row.LatencyP95),
+			WaitP95:    secondsToDuration(row.WaitP95),
+			Processed:  int(row.Processed),
+			Failed:     int(row.Failed),
+		}
+	}
+
+	return buckets, nil
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
+
+func (repo *PostgresJobsRepository) AlertRules(ctx context.Context) ([]jobs.AlertRule, error) {
+	r, err := repo.Conn().GetAlertRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not get alert rules: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	rules := make([]jobs.AlertRule, len(r))
+
+	for i, r := range r {
+		rules[i] = jobs.AlertRule{
+			Name:      r.Name,
+			Queue:     queueNameToDomain(r.Queue),
+			JobType:   jobs.JobType(r.JobType),
+			Condition: jobs.AlertCondition(r.Condition),
+			Channels:  r.Channels,
+			Threshold: r.Threshold,
+			For:       time.Duration(r.ForSeconds) * time.Second,
+		}
+	}
+
+	return rules, nil
+}
+
+func (repo *PostgresJobsRepository) SaveAlertRule(ctx context.Context, rule jobs.AlertRule) error {
+	channels := rule.Channels
+	if channels == nil {
+		channels = []string{}
+	}
+
+	err := repo.ConnOrTX(ctx).UpsertAlertRule(ctx, models.UpsertAlertRuleParams{
+		Name:       rule.Name,
+		Queue:      queueNameFromDomain(rule.Queue),
+		JobType:    string(rule.JobType),
+		Condition:  string(rule.Condition),
+		Threshold:  rule.Threshold,
+		ForSeconds: int32(rule.For / time.Second),
+		Channels:   channels,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: could not save alert rule: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	return nil
+}
+
+func (repo *PostgresJobsRepository) DeleteAlertRule(ctx context.Context, name string) error {
+	err := repo.ConnOrTX(ctx).DeleteAlertRule(ctx, name)
+	if err != nil {
+		return fmt.Errorf("%w: could not delete alert rule: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	return nil
+}
+
+func (repo *PostgresJobsRepository) Alerts(ctx context.Context, resolvedSince time.Time) ([]jobs.Alert, error) {
+	a, err := repo.Conn().GetAlerts(ctx, timestamp(resolvedSince))
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not get alerts: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	alerts := make([]jobs.Alert, len(a))
+
+	for i, a := range a {
+		alerts[i] = jobs.Alert{
+			PendingSince: a.PendingSince.Time,
+			FiredAt:      a.FiredAt.Time,
+			ResolvedAt:   a.ResolvedAt.Time,
+			ID:           a.ID,
+			Rule:         a.Rule,
+			Queue:        queueNameToDomain(a.Queue),
+			JobType:      jobs.JobType(a.JobType),
+			Message:      a.Message,
+			Value:        a.Value,
+		}
+	}
+
+	return alerts, nil
+}
+
+func (repo *PostgresJobsRepository) SaveAlert(ctx context.Context, alert jobs.Alert) error {
+	err := repo.ConnOrTX(ctx).UpsertAlert(ctx, models.UpsertAlertParams{
+		ID:           alert.ID,
+		Rule:         alert.Rule,
+		Queue:        queueNameFromDomain(alert.Queue),
+		JobType:      string(alert.JobType),
+		Message:      alert.Message,
+		Value:        alert.Value,
+		PendingSince: timestamp(alert.PendingSince),
+		FiredAt:      timestamp(alert.FiredAt),
+		ResolvedAt:   timestamp(alert.ResolvedAt),
+	})
+	if err != nil {
+		return fmt.Errorf("%w: could not save alert: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	return nil
+}
+
+func (repo *PostgresJobsRepository) DeleteAlert(ctx context.Context, id string) error {
+	err := repo.ConnOrTX(ctx).DeleteAlert(ctx, id)
+	if err != nil {
+		return fmt.Errorf("%w: could not delete alert: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	return nil
+}
+
+// timestamp returns a NULL timestamp for the zero time.
+func timestamp(t time.Time) pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: t, Valid: !t.IsZero(), InfinityModifier: pgtype.Finite}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
 	"github.com/go-arrower/skeleton/contexts/admin/internal/interfaces/repository"
 	"github.com/go-arrower/skeleton/contexts/admin/internal/interfaces/repository/testdata"
+	"github.com/go-arrower/skeleton/shared/infrastructure/migrations"
 )
 
 var (
@@ -240,3 +241,213 @@ func TestPostgresJobsRepository_RunJobAt(t *testing.T) {
 		assert.ErrorIs(t, err, jobs.ErrJobLockedAlready)
 	})
 }
+
+func TestPostgresJobsRepository_UpdateJob(t *testing.T) {
+	t.Parallel()
+
+	t.Run("update job", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		repo := repository.NewPostgresJobsRepository(pg)
+		jq, _ := ajobs.NewPostgresJobs(alog.NewNoopLogger(), mnoop.NewMeterProvider(), tnoop.NewTracerProvider(), pg)
+
+		_ = jq.Enqueue(ctx, testdata.SimpleJob{})
+		pending, _ := repo.PendingJobs(ctx, "")
+
+		job := pending[0]
+		job.Queue = "Other"
+		job.Priority = 5
+		job.ErrorCount = 0
+
+		err := repo.UpdateJob(ctx, job)
+		assert.NoError(t, err)
+
+		updated, err := repo.Job(ctx, job.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "Other", updated.Queue)
+		assert.Equal(t, int16(5), updated.Priority)
+	})
+
+	t.Run("park job", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		repo := repository.NewPostgresJobsRepository(pg)
+		jq, _ := ajobs.NewPostgresJobs(alog.NewNoopLogger(), mnoop.NewMeterProvider(), tnoop.NewTracerProvider(), pg)
+
+		_ = jq.Enqueue(ctx, testdata.SimpleJob{})
+		pending, _ := repo.PendingJobs(ctx, "")
+
+		job := pending[0]
+		job.RunAt = time.Time{}
+
+		err := repo.UpdateJob(ctx, job)
+		assert.NoError(t, err)
+
+		updated, _ := repo.Job(ctx, job.ID)
+		assert.True(t, updated.RunAt.IsZero())
+	})
+
+	t.Run("unknown job", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		repo := repository.NewPostgresJobsRepository(pg)
+
+		_, err := repo.Job(ctx, "non-existing-id")
+		assert.ErrorIs(t, err, jobs.ErrJobNotFound)
+	})
+}
+
+func TestPostgresJobsRepository_RetryPolicies(t *testing.T) {
+	t.Parallel()
+
+	pg := pgHandler.NewTestDatabase()
+	_ = migrations.Up(ctx, pg)
+	repo := repository.NewPostgresJobsRepository(pg)
+
+	policy := jobs.RetryPolicy{
+		Queue:       jobs.DefaultQueueName,
+		JobType:     "SimpleJob",
+		Backoff:     jobs.BackoffLinear,
+		MaxAttempts: 3,
+		BaseDelay:   time.Minute,
+		MaxDelay:    time.Hour,
+	}
+
+	err := repo.SaveRetryPolicy(ctx, policy)
+	assert.NoError(t, err)
+
+	policy.MaxAttempts = 5
+	err = repo.SaveRetryPolicy(ctx, policy)
+	assert.NoError(t, err, "saving again overwrites the policy")
+
+	policies, err := repo.RetryPolicies(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []jobs.RetryPolicy{policy}, policies)
+
+	err = repo.DeleteRetryPolicy(ctx, policy.Queue, policy.JobType)
+	assert.NoError(t, err)
+
+	policies, _ = repo.RetryPolicies(ctx)
+	assert.Empty(t, policies)
+}
+
+func TestPostgresJobsRepository_RetryPolicyDelay(t *testing.T) {
+	t.Parallel()
+
+	pg := pgHandler.NewTestDatabase()
+	_ = migrations.Up(ctx, pg)
+	repo := repository.NewPostgresJobsRepository(pg)
+	jq, _ := ajobs.NewPostgresJobs(alog.NewNoopLogger(), mnoop.NewMeterProvider(), tnoop.NewTracerProvider(), pg)
+
+	_ = repo.SaveRetryPolicy(ctx, jobs.RetryPolicy{
+		Queue:       jobs.DefaultQueueName,
+		JobType:     "SimpleJob",
+		Backoff:     jobs.BackoffExponential,
+		MaxAttempts: 0,
+		BaseDelay:   time.Minute,
+		MaxDelay:    0,
+	})
+
+	_ = jq.Enqueue(ctx, testdata.SimpleJob{})
+	pending, _ := repo.PendingJobs(ctx, "")
+
+	// the job failed so often, that an uncapped delay overflows the interval
+	_, err := pg.Exec(ctx, `UPDATE arrower.gue_jobs SET error_count = 100, last_error = 'some error' WHERE job_id = $1`, pending[0].ID)
+	assert.NoError(t, err)
+
+	job, _ := repo.Job(ctx, pending[0].ID)
+	assert.WithinDuration(t, time.Now().Add(jobs.DefaultMaxDelay), job.RunAt, time.Minute)
+}
+
+func TestPostgresJobsRepository_RetryDelay(t *testing.T) {
+	t.Parallel()
+
+	pg := pgHandler.NewTestDatabase()
+	_ = migrations.Up(ctx, pg)
+
+	policies := []jobs.RetryPolicy{
+		{Backoff: jobs.BackoffFixed, BaseDelay: time.Minute},
+		{Backoff: jobs.BackoffLinear, BaseDelay: time.Minute},
+		{Backoff: jobs.BackoffLinear, BaseDelay: time.Minute, MaxDelay: time.Hour},
+		{Backoff: jobs.BackoffExponential, BaseDelay: time.Second},
+		{Backoff: jobs.BackoffExponential, BaseDelay: time.Minute, MaxDelay: 90 * time.Minute},
+		{Backoff: jobs.BackoffExponential, BaseDelay: time.Second, MaxDelay: 60 * 365 * 24 * time.Hour},
+	}
+
+	for _, policy := range policies {
+		for _, errorCount := range []int{1, 2, 3, 7, 20, 40, 100, 10_000} {
+			var seconds float64
+
+			err := pg.QueryRow(ctx, `SELECT arrower.job_retry_delay($1, $2, $3, $4)`,
+				string(policy.Backoff), int(policy.BaseDelay.Seconds()), int(policy.MaxDelay.Seconds()), errorCount,
+			).Scan(&seconds)
+			assert.NoError(t, err)
+
+			assert.Equal(t, policy.Delay(errorCount), time.Duration(seconds)*time.Second,
+				"the database and the domain calculate the same delay: %s, %d", policy.Backoff, errorCount)
+		}
+	}
+}
+
+func TestPostgresJobsRepository_ResumePausedJobs(t *testing.T) {
+	t.Parallel()
+
+	pg := pgHandler.NewTestDatabase()
+	_ = migrations.Up(ctx, pg)
+	repo := repository.NewPostgresJobsRepository(pg)
+	jq, _ := ajobs.NewPostgresJobs(alog.NewNoopLogger(), mnoop.NewMeterProvider(), tnoop.NewTracerProvider(), pg)
+
+	_ = jq.Enqueue(ctx, testdata.SimpleJob{})
+	_ = jq.Enqueue(ctx, testdata.SimpleJob{})
+	pending, _ := repo.PendingJobs(ctx, "")
+
+	// park the first job, like the database does for a paused job
+	_, _ = pg.Exec(ctx, `INSERT INTO arrower.job_pauses (queue) VALUES ('')`)
+	_, _ = pg.Exec(ctx, `UPDATE arrower.gue_jobs SET error_count = 1 WHERE job_id = $1`, pending[0].ID)
+	_, _ = pg.Exec(ctx, `DELETE FROM arrower.job_pauses`)
+	// park the second job, like the database does for a job that reached its max attempts
+	_, _ = pg.Exec(ctx, `UPDATE arrower.gue_jobs SET run_at = 'infinity', last_error = 'some error' WHERE job_id = $1`, pending[1].ID)
+
+	job, _ := repo.Job(ctx, pending[0].ID)
+	assert.True(t, job.Paused)
+	assert.True(t, job.RunAt.IsZero())
+
+	err := repo.ResumePausedJobs(ctx, jobs.DefaultQueueName, "")
+	assert.NoError(t, err)
+
+	job, _ = repo.Job(ctx, pending[0].ID)
+	assert.False(t, job.RunAt.IsZero(), "paused job is released")
+	assert.False(t, job.Paused)
+
+	job, _ = repo.Job(ctx, pending[1].ID)
+	assert.True(t, job.RunAt.IsZero(), "other parked jobs stay parked")
+	assert.False(t, job.Paused)
+}
+
+func TestPostgresJobsRepository_JobMetrics(t *testing.T) {
+	t.Parallel()
+
+	pg := pgHandler.NewTestDatabase()
+	repo := repository.NewPostgresJobsRepository(pg)
+
+	// two attempts: one took a second and failed, the other took three seconds and succeeded
+	_, _ = pg.Exec(ctx, `INSERT INTO arrower.gue_jobs_history (job_id, priority, run_at, job_type, args, queue, run_count, run_error, created_at, updated_at, success, finished_at)
+		VALUES ('1', 0, NOW() - INTERVAL '12 seconds', 'SomeJob', '', '', 0, 'err', NOW() - INTERVAL '10 seconds', NOW(), false, NOW() - INTERVAL '9 seconds'),
+		       ('2', 0, NOW() - INTERVAL '10 seconds', 'SomeJob', '', '', 0, '', NOW() - INTERVAL '10 seconds', NOW(), true, NOW() - INTERVAL '7 seconds')`)
+
+	metrics, err := repo.JobMetrics(ctx, jobs.DefaultQueueName, time.Now().Add(-time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, metrics, 1)
+	assert.Equal(t, jobs.JobType("SomeJob"), metrics[0].JobType)
+	assert.Equal(t, 2, metrics[0].Processed)
+	assert.Equal(t, 1, metrics[0].Failed)
+	assert.Equal(t, 2*time.Second, metrics[0].Latency.P50.Round(time.Millisecond))
+	assert.Equal(t, time.Second, metrics[0].WaitTime.P50.Round(time.Millisecond))
+
+	series, err := repo.JobMetricsTimeSeries(ctx, jobs.DefaultQueueName, time.Now().Add(-time.Hour), time.Hour)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, series)
+}
@@ -103,3 +103,174 @@ func (repo *TracedJobsRepository) FinishedJobsTotal(ctx context.Context, f jobs.
 
 	return repo.repo.FinishedJobsTotal(ctx, f) //nolint:wrapcheck // this is decorator
 }
+
+func (repo *TracedJobsRepository) Job(ctx context.Context, jobID string) (jobs.PendingJob, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("arrower.jobs").
+		Start(ctx, "repo", trace.WithAttributes(
+			attribute.String("method", "Job"),
+			attribute.String("jobID", jobID),
+		))
+	defer span.End()
+
+	return repo.repo.Job(ctx, jobID) //nolint:wrapcheck // this is decorator
+}
+
+func (repo *TracedJobsRepository) UpdateJob(ctx context.Context, job jobs.PendingJob) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("arrower.jobs").
+		Start(ctx, "repo", trace.WithAttributes(
+			attribute.String("method", "UpdateJob"),
+			attribute.String("jobID", job.ID),
+		))
+	defer span.End()
+
+	return repo.repo.UpdateJob(ctx, job) //nolint:wrapcheck // this is decorator
+}
+
+func (repo *TracedJobsRepository) PayloadExamples(ctx context.Context, queue jobs.QueueName, jobType jobs.JobType) ([][]byte, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("arrower.jobs").
+		Start(ctx, "repo", trace.WithAttributes(
+			attribute.String("method", "PayloadExamples"),
+			attribute.String("queue", string(queue)),
+			attribute.String("jobType", string(jobType)),
+		))
+	defer span.End()
+
+	return repo.repo.PayloadExamples(ctx, queue, jobType) //nolint:wrapcheck // this is decorator
+}
+
+func (repo *TracedJobsRepository) RetryPolicies(ctx context.Context) ([]jobs.RetryPolicy, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("arrower.jobs").
+		Start(ctx, "repo", trace.WithAttributes(
+			attribute.String("method", "RetryPolicies"),
+		))
+	defer span.End()
+
+	return repo.repo.RetryPolicies(ctx) //nolint:wrapcheck // this is decorator
+}
+
+func (repo *TracedJobsRepository) SaveRetryPolicy(ctx context.Context, policy jobs.RetryPolicy) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("arrower.jobs").
+		Start(ctx, "repo", trace.WithAttributes(
+			attribute.String("method", "SaveRetryPolicy"),
+			attribute.String("queue", string(policy.Queue)),
+			attribute.String("jobType", string(policy.JobType)),
+		))
+	defer span.End()
+
+	return repo.repo.SaveRetryPolicy(ctx, policy) //nolint:wrapcheck // this is decorator
+}
+
+func (repo *TracedJobsRepository) DeleteRetryPolicy(ctx context.Context, queue jobs.QueueName, jobType jobs.JobType) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("arrower.jobs").
+		Start(ctx, "repo", trace.WithAttributes(
+			attribute.String("method", "DeleteRetryPolicy"),
+			attribute.String("queue", string(queue)),
+			attribute.String("jobType", string(jobType)),
+		))
+	defer span.End()
+
+	return repo.repo.DeleteRetryPolicy(ctx, queue, jobType) //nolint:wrapcheck // this is decorator
+}
+
+func (repo *TracedJobsRepository) ResumePausedJobs(ctx context.Context, queue jobs.QueueName, jobType jobs.JobType) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("arrower.jobs").
+		Start(ctx, "repo", trace.WithAttributes(
+			attribute.String("method", "ResumePausedJobs"),
+			attribute.String("queue", string(queue)),
+			attribute.String("jobType", string(jobType)),
+		))
+	defer span.End()
+
+	return repo.repo.ResumePausedJobs(ctx, queue, jobType) //nolint:wrapcheck // this is decorator
+}
+
+func (repo *TracedJobsRepository) JobMetrics(ctx context.Context, queue jobs.QueueName, since time.Time) ([]jobs.JobTypeMetrics, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("arrower.jobs").
+		Start(ctx, "repo", trace.WithAttributes(
+			attribute.String("method", "JobMetrics"),
+			attribute.String("queue", string(queue)),
+		))
+	defer span.End()
+
+	return repo.repo.JobMetrics(ctx, queue, since) //nolint:wrapcheck // this is decorator
+}
+
+func (repo *TracedJobsRepository) JobMetricsTimeSeries(
+	ctx context.Context,
+	queue jobs.QueueName,
+	since time.Time,
+	bin time.Duration,
+) ([]jobs.MetricsBucket, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("arrower.jobs").
+		Start(ctx, "repo", trace.WithAttributes(
+			attribute.String("method", "JobMetricsTimeSeries"),
+			attribute.String("queue", string(queue)),
+		))
+	defer span.End()
+
+	return repo.repo.JobMetricsTimeSeries(ctx, queue, since, bin) //nolint:wrapcheck // this is decorator
+}
+
+func (repo *TracedJobsRepository) AlertRules(ctx context.Context) ([]jobs.AlertRule, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("arrower.jobs").
+		Start(ctx, "repo", trace.WithAttributes(
+			attribute.String("method", "AlertRules"),
+		))
+	defer span.End()
+
+	return repo.repo.AlertRules(ctx) //nolint:wrapcheck // this is decorator
+}
+
+func (repo *TracedJobsRepository) SaveAlertRule(ctx context.Context, rule jobs.AlertRule) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("arrower.jobs").
+		Start(ctx, "repo", trace.WithAttributes(
+			attribute.String("method", "SaveAlertRule"),
+			attribute.String("rule", rule.Name),
+		))
+	defer span.End()
+
+	return repo.repo.SaveAlertRule(ctx, rule) //nolint:wrapcheck // this is decorator
+}
+
+func (repo *TracedJobsRepository) DeleteAlertRule(ctx context.Context, name string) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("arrower.jobs").
+		Start(ctx, "repo", trace.WithAttributes(
+			attribute.String("method", "DeleteAlertRule"),
+			attribute.String("rule", name),
+		))
+	defer span.End()
+
+	return repo.repo.DeleteAlertRule(ctx, name) //nolint:wrapcheck // this is decorator
+}
+
+func (repo *TracedJobsRepository) Alerts(ctx context.Context, resolvedSince time.Time) ([]jobs.Alert, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("arrower.jobs").
+		Start(ctx, "repo", trace.WithAttributes(
+			attribute.String("method", "Alerts"),
+		))
+	defer span.End()
+
+	return repo.repo.Alerts(ctx, resolvedSince) //nolint:wrapcheck // this is decorator
+}
+
+func (repo *TracedJobsRepository) SaveAlert(ctx context.Context, alert jobs.Alert) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("arrower.jobs").
+		Start(ctx, "repo", trace.WithAttributes(
+			attribute.String("method", "SaveAlert"),
+			attribute.String("alertID", alert.ID),
+		))
+	defer span.End()
+
+	return repo.repo.SaveAlert(ctx, alert) //nolint:wrapcheck // this is decorator
+}
+
+func (repo *TracedJobsRepository) DeleteAlert(ctx context.Context, id string) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("arrower.jobs").
+		Start(ctx, "repo", trace.WithAttributes(
+			attribute.String("method", "DeleteAlert"),
+			attribute.String("alertID", id),
+		))
+	defer span.End()
+
+	return repo.repo.DeleteAlert(ctx, id) //nolint:wrapcheck // this is decorator
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/logs"
+)
+
+var ErrArchiveFailed = errors.New("archive logs failed")
+
+func NewFileLogsArchive(dir string) *FileLogsArchive {
+	return &FileLogsArchive{dir: dir}
+}
+
+// FileLogsArchive writes logs into gzip compressed JSON lines files, one log per line as it is stored.
+// A file is only visible under its name, once all logs are written, so a failed archive never looks complete.
+// If there are no logs, no file is written.
+type FileLogsArchive struct {
+	dir string
+}
+
+var _ logs.Archive = (*FileLogsArchive)(nil)
+
+func (a *FileLogsArchive) Save(_ context.Context, name string, export func(fn func(logs.Log) error) error) (string, error) {
+	const dirPerm = 0o750
+
+	if err := os.MkdirAll(a.dir, dirPerm); err != nil {
+		return "", fmt.Errorf("%w: could not create archive dir: %v", ErrArchiveFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	path := filepath.Join(a.dir, filepath.Base(name)+".jsonl.gz")
+
+	file, err := os.CreateTemp(a.dir, filepath.Base(name)+".*.partial")
+	if err != nil {
+		return "", fmt.Errorf("%w: could not create archive file: %v", ErrArchiveFailed, err) //nolint:errorlint // prevent err in api
+	}
+	defer os.Remove(file.Name()) // noop after the rename
+
+	w := gzip.NewWriter(file)
+	written := 0
+
+	err = export(func(log logs.Log) error {
+		written++
+		_, err := w.Write(append(log.Raw, '\n'))
+
+		return err //nolint:wrapcheck // wrapped below
+	})
+	if err != nil {
+		_ = file.Close()
+
+		return "", fmt.Errorf("%w: %w", ErrArchiveFailed, err)
+	}
+
+	if err = w.Close(); err != nil {
+		_ = file.Close()
+
+		return "", fmt.Errorf("%w: could not compress archive: %v", ErrArchiveFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	if err = file.Close(); err != nil {
+		return "", fmt.Errorf("%w: could not write archive: %v", ErrArchiveFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	if written == 0 {
+		return "", nil
+	}
+
+	if err = os.Rename(file.Name(), path); err != nil {
+		return "", fmt.Errorf("%w: could not name archive: %v", ErrArchiveFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	return path, nil
+}
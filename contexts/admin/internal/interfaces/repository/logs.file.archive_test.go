@@ -0,0 +1,74 @@
+package repository_test
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/logs"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/interfaces/repository"
+)
+
+func TestFileLogsArchive_Save(t *testing.T) {
+	t.Parallel()
+
+	t.Run("save", func(t *testing.T) {
+		t.Parallel()
+
+		dir := filepath.Join(t.TempDir(), "archive")
+		archive := repository.NewFileLogsArchive(dir)
+
+		path, err := archive.Save(context.Background(), "logs", func(fn func(logs.Log) error) error {
+			_ = fn(logs.Log{Raw: []byte(`{"msg":"first"}`)})
+
+			return fn(logs.Log{Raw: []byte(`{"msg":"second"}`)})
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "logs.jsonl.gz"), path)
+
+		file, err := os.Open(path)
+		assert.NoError(t, err)
+
+		r, err := gzip.NewReader(file)
+		assert.NoError(t, err)
+
+		content, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, "{\"msg\":\"first\"}\n{\"msg\":\"second\"}\n", string(content))
+	})
+
+	t.Run("no logs", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		archive := repository.NewFileLogsArchive(dir)
+
+		path, err := archive.Save(context.Background(), "logs", func(_ func(logs.Log) error) error { return nil })
+		assert.NoError(t, err)
+		assert.Empty(t, path)
+
+		files, _ := os.ReadDir(dir)
+		assert.Empty(t, files)
+	})
+
+	t.Run("failed export leaves no file", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		archive := repository.NewFileLogsArchive(dir)
+
+		_, err := archive.Save(context.Background(), "logs", func(_ func(logs.Log) error) error {
+			return errors.New("some error") //nolint:goerr113 // fine for tests
+		})
+		assert.ErrorIs(t, err, repository.ErrArchiveFailed)
+
+		files, _ := os.ReadDir(dir)
+		assert.Empty(t, files)
+	})
+}
@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-arrower/arrower/postgres"
+	"github.com/google/uuid"
+	pgxv5 "github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/logs"
+)
+
+func NewPostgresLogsRepository(pg *pgxpool.Pool) *PostgresLogsRepository {
+	return &PostgresLogsRepository{pg: pg}
+}
+
+// PostgresLogsRepository searches the logs written by alog into arrower.log.
+// The queries are built at runtime from a logs.Query, all values are passed as parameters.
+type PostgresLogsRepository struct {
+	pg *pgxpool.Pool
+}
+
+var _ logs.Repository = (*PostgresLogsRepository)(nil)
+
+// levelRank orders the levels of a log by their severity, see logs.Level.
+// The levels of arrower itself are below debug.
+const levelRank = `CASE UPPER(log->>'level')
+	WHEN 'ERROR' THEN 8
+	WHEN 'WARN' THEN 4
+	WHEN 'INFO' THEN 0
+	WHEN 'DEBUG' THEN -4
+	ELSE -8 END`
+
+func (repo *PostgresLogsRepository) Search(ctx context.Context, search logs.Search) ([]logs.Log, error) {
+	if search.Limit <= 0 || search.Limit > logs.MaxLimit {
+		search.Limit = logs.DefaultLimit
+	}
+
+	sql, args, err := searchSQL(search, search.Limit, !search.After.IsZero())
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := repo.pg.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not search logs: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	all, err := pgxv5.CollectRows(rows, scanLog)
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not read logs: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	if search.After.IsZero() {
+		// the newest logs are selected, but shown in the order they happened
+		for i, j := 0, len(all)-1; i < j; i, j = i+1, j-1 {
+			all[i], all[j] = all[j], all[i]
+		}
+	}
+
+	return all, nil
+}
+
+func (repo *PostgresLogsRepository) Export(ctx context.Context, search logs.Search, fn func(logs.Log) error) error {
+	sql, args, err := searchSQL(search, 0, true)
+	if err != nil {
+		return err
+	}
+
+	rows, err := repo.pg.Query(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("%w: could not export logs: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		log, err := scanLog(rows)
+		if err != nil {
+			return fmt.Errorf("%w: could not read logs: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+		}
+
+		if err := fn(log); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%w: could not export logs: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	return nil
+}
+
+func (repo *PostgresLogsRepository) TableSize(ctx context.Context) (logs.TableSize, error) {
+	var size logs.TableSize
+
+	err := repo.pg.QueryRow(ctx,
+		`SELECT COUNT(*), pg_size_pretty(pg_total_relation_size('arrower.log')) FROM arrower.log`,
+	).Scan(&size.Logs, &size.Size)
+	if err != nil {
+		return logs.TableSize{}, fmt.Errorf("%w: could not get log table size: %v", postgres.ErrQueryFailed, err) //nolint:errorlint,lll // prevent err in api
+	}
+
+	return size, nil
+}
+
+func (repo *PostgresLogsRepository) NewestBeyond(ctx context.Context, n int) (time.Time, error) {
+	var t time.Time
+
+	err := repo.pg.QueryRow(ctx, `SELECT time FROM arrower.log ORDER BY time DESC OFFSET $1 LIMIT 1`, n).Scan(&t)
+	if errors.Is(err, pgxv5.ErrNoRows) {
+		return time.Time{}, nil
+	}
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: could not get log time: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	return t, nil
+}
+
+func (repo *PostgresLogsRepository) Delete(ctx context.Context, until time.Time) (int64, error) {
+	tag, err := repo.pg.Exec(ctx, `DELETE FROM arrower.log WHERE time <= $1`, until)
+	if err != nil {
+		return 0, fmt.Errorf("%w: could not delete logs: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (repo *PostgresLogsRepository) Vacuum(ctx context.Context) error {
+	_, err := repo.pg.Exec(ctx, `VACUUM FULL arrower.log`)
+	if err != nil {
+		return fmt.Errorf("%w: could not vacuum logs: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	return nil
+}
+
+func (repo *PostgresLogsRepository) SavedSearches(ctx context.Context, userID string) ([]logs.SavedSearch, error) {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid user id: %v", logs.ErrInvalidSavedSearch, err) //nolint:errorlint // prevent err in api
+	}
+
+	rows, err := repo.pg.Query(ctx, `SELECT name, query, range FROM arrower.log_searches WHERE user_id = $1 ORDER BY name`, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not get saved searches: %v", postgres.ErrQueryFailed, err) //nolint:errorlint,lll // prevent err in api
+	}
+
+	searches, err := pgxv5.CollectRows(rows, func(row pgxv5.CollectableRow) (logs.SavedSearch, error) {
+		s := logs.SavedSearch{UserID: userID} //nolint:exhaustruct // set by scan
+
+		err := row.Scan(&s.Name, &s.Query, &s.Range)
+
+		return s, err //nolint:wrapcheck // wrapped below
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not read saved searches: %v", postgres.ErrQueryFailed, err) //nolint:errorlint,lll // prevent err in api
+	}
+
+	return searches, nil
+}
+
+func (repo *PostgresLogsRepository) SaveSearch(ctx context.Context, search logs.SavedSearch) error {
+	id, err := uuid.Parse(search.UserID)
+	if err != nil {
+		return fmt.Errorf("%w: invalid user id: %v", logs.ErrInvalidSavedSearch, err) //nolint:errorlint // prevent err in api
+	}
+
+	_, err = repo.pg.Exec(ctx, `
+		INSERT INTO arrower.log_searches (user_id, name, query, range)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, name) DO UPDATE SET query = excluded.query, range = excluded.range, updated_at = NOW()`,
+		id, search.Name, search.Query, search.Range,
+	)
+	if err != nil {
+		return fmt.Errorf("%w: could not save search: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	return nil
+}
+
+func (repo *PostgresLogsRepository) DeleteSearch(ctx context.Context, userID string, name string) error {
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return fmt.Errorf("%w: invalid user id: %v", logs.ErrInvalidSavedSearch, err) //nolint:errorlint // prevent err in api
+	}
+
+	_, err = repo.pg.Exec(ctx, `DELETE FROM arrower.log_searches WHERE user_id = $1 AND name = $2`, id, name)
+	if err != nil {
+		return fmt.Errorf("%w: could not delete search: %v", postgres.ErrQueryFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	return nil
+}
+
+// logHash breaks the tie between logs of the same time. arrower.log is owned by alog and has no id,
+// altering it would rewrite the table under an exclusive lock.
+const logHash = "md5(log::TEXT)"
+
+// searchSQL returns the query for the search. A limit of 0 returns all logs.
+// If asc is false, the newest logs are returned first.
+func searchSQL(search logs.Search, limit int, asc bool) (string, []any, error) {
+	args := []any{}
+	param := func(v any) string {
+		args = append(args, v)
+
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	where := []string{}
+
+	order := "DESC"
+	if asc {
+		order = "ASC"
+	}
+
+	if !search.After.IsZero() {
+		where = append(where, "(time, "+logHash+") > ("+param(search.After.Time)+", "+param(search.After.Hash)+")")
+	} else if !search.From.IsZero() {
+		where = append(where, "time >= "+param(search.From))
+	}
+
+	if !search.To.IsZero() {
+		where = append(where, "time <= "+param(search.To))
+	}
+
+	for _, c := range search.Query {
+		condition, err := conditionSQL(c, param)
+		if err != nil {
+			return "", nil, err
+		}
+
+		where = append(where, condition)
+	}
+
+	sql := `SELECT ` + logHash + `, time, user_id, log FROM arrower.log`
+	if len(where) > 0 {
+		sql += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	sql += " ORDER BY time " + order + ", " + logHash + " " + order
+
+	if limit > 0 {
+		sql += " LIMIT " + param(limit)
+	}
+
+	return sql, args, nil
+}
+
+// conditionSQL returns the condition in SQL. Keys and values are never part of the SQL itself,
+// only the operator is, and it is one of the known operators.
+func conditionSQL(c logs.Condition, param func(any) string) (string, error) {
+	switch c.Key {
+	case logs.KeyLevel:
+		level, err := logs.ParseLevel(c.Value)
+		if err != nil {
+			return "", err //nolint:wrapcheck // the domain error is the one to return
+		}
+
+		op := string(c.Op)
+		if c.Op == logs.OpNotEqual {
+			op = "<>"
+		}
+
+		return "(" + levelRank + ") " + op + " " + param(int(level)), nil
+	case logs.KeyUserID:
+		return textConditionSQL("COALESCE(user_id::TEXT, '')", c, param)
+	case logs.KeyMsg:
+		return textConditionSQL("COALESCE(log->>'msg', '')", c, param)
+	case logs.KeyTraceID:
+		return textConditionSQL("COALESCE(log->>'traceID', '')", c, param)
+	}
+
+	attr := "COALESCE(log #>> " + param(strings.Split(c.Key, ".")) + "::TEXT[], '')"
+
+	if c.IsOrdering() {
+		return fmt.Sprintf(`(CASE WHEN %[1]s ~ '^-?[0-9]+(\.[0-9]+)?$' THEN %[1]s::NUMERIC END) %[2]s %[3]s::NUMERIC`,
+			attr, c.Op, param(c.Value),
+		), nil
+	}
+
+	return textConditionSQL(attr, c, param)
+}
+
+func textConditionSQL(expr string, c logs.Condition, param func(any) string) (string, error) {
+	switch c.Op { //nolint:exhaustive // ordering is only possible for levels and numbers
+	case logs.OpEqual:
+		return expr + " = " + param(c.Value), nil
+	case logs.OpNotEqual:
+		return expr + " <> " + param(c.Value), nil
+	case logs.OpContains:
+		return expr + " ILIKE " + param("%"+escapeLike(c.Value)+"%"), nil
+	case logs.OpNotContains:
+		return expr + " NOT ILIKE " + param("%"+escapeLike(c.Value)+"%"), nil
+	default:
+		return "", fmt.Errorf("%w: %s can not be used with %s", logs.ErrInvalidQuery, c.Op, c.Key)
+	}
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func scanLog(row pgxv5.CollectableRow) (logs.Log, error) {
+	var (
+		hash   string
+		t      time.Time
+		userID uuid.NullUUID
+		raw    []byte
+	)
+
+	if err := row.Scan(&hash, &t, &userID, &raw); err != nil {
+		return logs.Log{}, err //nolint:wrapcheck // wrapped by the caller
+	}
+
+	log := logs.Log{Hash: hash, Time: t, UserID: "", Attrs: map[string]any{}, Raw: raw}
+	if userID.Valid {
+		log.UserID = userID.UUID.String()
+	}
+
+	_ = json.Unmarshal(raw, &log.Attrs) // a log, that is not valid json, is still shown with its time
+
+	return log, nil
+}
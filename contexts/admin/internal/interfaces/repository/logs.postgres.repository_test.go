@@ -0,0 +1,211 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/logs"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/interfaces/repository"
+	"github.com/go-arrower/skeleton/shared/infrastructure/migrations"
+)
+
+func TestPostgresLogsRepository_Search(t *testing.T) {
+	t.Parallel()
+
+	pg := pgHandler.NewTestDatabase()
+	_ = migrations.Up(ctx, pg)
+	repo := repository.NewPostgresLogsRepository(pg)
+
+	userID := uuid.New()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	insertLog(t, pg, now.Add(-3*time.Minute), uuid.Nil, `{"level":"DEBUG","msg":"cache miss"}`)
+	insertLog(t, pg, now.Add(-2*time.Minute), userID, `{"level":"WARN","msg":"connection timeout","request":{"method":"GET"},"duration_ms":300}`)
+	insertLog(t, pg, now.Add(-1*time.Minute), userID, `{"level":"ERROR","msg":"'; DROP TABLE arrower.log; --","traceID":"abc"}`)
+
+	tests := map[string]struct {
+		query    string
+		expected []string
+	}{
+		"all":               {"", []string{"cache miss", "connection timeout", "'; DROP TABLE arrower.log; --"}},
+		"level":             {"level>=warn", []string{"connection timeout", "'; DROP TABLE arrower.log; --"}},
+		"user":              {"user_id=" + userID.String() + " level<error", []string{"connection timeout"}},
+		"free text":         {"TIMEOUT", []string{"connection timeout"}},
+		"nested attribute":  {"request.method=GET", []string{"connection timeout"}},
+		"number":            {"duration_ms>250", []string{"connection timeout"}},
+		"trace":             {"trace_id=abc", []string{"'; DROP TABLE arrower.log; --"}},
+		"like is escaped":   {"msg~%", nil},
+		"injection is data": {`msg="'; DROP TABLE arrower.log; --"`, []string{"'; DROP TABLE arrower.log; --"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			query, err := logs.ParseQuery(tt.query)
+			assert.NoError(t, err)
+
+			found, err := repo.Search(ctx, logs.Search{Query: query, From: now.Add(-time.Hour)})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, msgs(found))
+		})
+	}
+
+	t.Run("newest logs in order", func(t *testing.T) {
+		t.Parallel()
+
+		found, err := repo.Search(ctx, logs.Search{From: now.Add(-time.Hour), Limit: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"connection timeout", "'; DROP TABLE arrower.log; --"}, msgs(found))
+	})
+
+	t.Run("tail", func(t *testing.T) {
+		t.Parallel()
+
+		found, err := repo.Search(ctx, logs.Search{After: logs.Cursor{Time: now.Add(-3 * time.Minute), Hash: ""}, Limit: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"cache miss"}, msgs(found), "the cursor is before the first log of the same time")
+
+		found, err = repo.Search(ctx, logs.Search{After: found[0].Cursor(), Limit: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"connection timeout"}, msgs(found))
+	})
+
+	t.Run("export", func(t *testing.T) {
+		t.Parallel()
+
+		var exported []logs.Log
+
+		err := repo.Export(ctx, logs.Search{To: now.Add(-90 * time.Second)}, func(log logs.Log) error {
+			exported = append(exported, log)
+
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"cache miss", "connection timeout"}, msgs(exported))
+		assert.Equal(t, userID.String(), exported[1].UserID)
+	})
+}
+
+func TestPostgresLogsRepository_SearchSameTime(t *testing.T) {
+	t.Parallel()
+
+	pg := pgHandler.NewTestDatabase()
+	_ = migrations.Up(ctx, pg)
+	repo := repository.NewPostgresLogsRepository(pg)
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	insertLog(t, pg, now, uuid.Nil, `{"msg":"first"}`)
+	insertLog(t, pg, now, uuid.Nil, `{"msg":"second"}`)
+
+	found, err := repo.Search(ctx, logs.Search{From: now.Add(-time.Minute), Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, found, 1)
+
+	all, err := repo.Search(ctx, logs.Search{After: logs.Cursor{Time: now, Hash: ""}})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"first", "second"}, msgs(all))
+	assert.NotEqual(t, all[0].Hash, all[1].Hash)
+
+	found, err = repo.Search(ctx, logs.Search{After: all[0].Cursor()})
+	assert.NoError(t, err)
+	assert.Equal(t, msgs(all[1:]), msgs(found), "a log of the same time is not skipped")
+
+	found, err = repo.Search(ctx, logs.Search{After: found[0].Cursor()})
+	assert.NoError(t, err)
+	assert.Empty(t, found, "a log is not repeated")
+}
+
+func TestPostgresLogsRepository_Prune(t *testing.T) {
+	t.Parallel()
+
+	pg := pgHandler.NewTestDatabase()
+	repo := repository.NewPostgresLogsRepository(pg)
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	insertLog(t, pg, now.Add(-3*time.Minute), uuid.Nil, `{"msg":"first"}`)
+	insertLog(t, pg, now.Add(-2*time.Minute), uuid.Nil, `{"msg":"second"}`)
+	insertLog(t, pg, now.Add(-1*time.Minute), uuid.Nil, `{"msg":"third"}`)
+
+	size, err := repo.TableSize(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), size.Logs)
+	assert.NotEmpty(t, size.Size)
+
+	beyond, err := repo.NewestBeyond(ctx, 1)
+	assert.NoError(t, err)
+	assert.True(t, now.Add(-2*time.Minute).Equal(beyond))
+
+	beyond, err = repo.NewestBeyond(ctx, 3)
+	assert.NoError(t, err)
+	assert.True(t, beyond.IsZero(), "not more logs than n")
+
+	deleted, err := repo.Delete(ctx, now.Add(-2*time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	err = repo.Vacuum(ctx)
+	assert.NoError(t, err)
+
+	size, _ = repo.TableSize(ctx)
+	assert.Equal(t, int64(1), size.Logs)
+}
+
+func TestPostgresLogsRepository_SavedSearches(t *testing.T) {
+	t.Parallel()
+
+	pg := pgHandler.NewTestDatabase()
+	_ = migrations.Up(ctx, pg)
+	repo := repository.NewPostgresLogsRepository(pg)
+
+	userID := uuid.NewString()
+	search := logs.SavedSearch{UserID: userID, Name: "errors", Query: "level>=error", Range: 60}
+
+	err := repo.SaveSearch(ctx, search)
+	assert.NoError(t, err)
+
+	search.Query = "level>=warn"
+	err = repo.SaveSearch(ctx, search)
+	assert.NoError(t, err, "saving again overwrites the search")
+
+	searches, err := repo.SavedSearches(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, []logs.SavedSearch{search}, searches)
+
+	searches, err = repo.SavedSearches(ctx, uuid.NewString())
+	assert.NoError(t, err)
+	assert.Empty(t, searches, "searches are per user")
+
+	err = repo.DeleteSearch(ctx, userID, search.Name)
+	assert.NoError(t, err)
+
+	searches, err = repo.SavedSearches(ctx, userID)
+	assert.NoError(t, err)
+	assert.Empty(t, searches)
+}
+
+func insertLog(t *testing.T, pg *pgxpool.Pool, at time.Time, userID uuid.UUID, log string) {
+	t.Helper()
+
+	id := uuid.NullUUID{UUID: userID, Valid: userID != uuid.Nil}
+
+	_, err := pg.Exec(ctx, `INSERT INTO arrower.log (time, user_id, log) VALUES ($1, $2, $3)`, at, id, log)
+	assert.NoError(t, err)
+}
+
+func msgs(all []logs.Log) []string {
+	var msgs []string
+	for _, l := range all {
+		msgs = append(msgs, l.Msg())
+	}
+
+	return msgs
+}
@@ -8,19 +8,6 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-type ArrowerGueJob struct {
-	JobID      string
-	Priority   int16
-	RunAt      pgtype.Timestamptz
-	JobType    string
-	Args       []byte
-	ErrorCount int32
-	LastError  string
-	Queue      string
-	CreatedAt  pgtype.Timestamptz
-	UpdatedAt  pgtype.Timestamptz
-}
-
 type ArrowerGueJobsHistory struct {
 	JobID      string
 	Priority   int16
@@ -37,12 +24,37 @@ type ArrowerGueJobsHistory struct {
 	PrunedAt   pgtype.Timestamptz
 }
 
-type ArrowerGueJobsWorkerPool struct {
-	ID        string
-	Queue     string
-	Workers   int16
-	GitHash   string
-	JobTypes  []string
-	CreatedAt pgtype.Timestamptz
-	UpdatedAt pgtype.Timestamptz
+type ArrowerJobAlert struct {
+	ID           string
+	Rule         string
+	Queue        string
+	JobType      string
+	Message      string
+	Value        float64
+	PendingSince pgtype.Timestamptz
+	FiredAt      pgtype.Timestamptz
+	ResolvedAt   pgtype.Timestamptz
+}
+
+type ArrowerJobAlertRule struct {
+	Name       string
+	Queue      string
+	JobType    string
+	Condition  string
+	Threshold  float64
+	ForSeconds int32
+	Channels   []string
+	CreatedAt  pgtype.Timestamptz
+	UpdatedAt  pgtype.Timestamptz
+}
+
+type ArrowerJobRetryPolicy struct {
+	Queue            string
+	JobType          string
+	Backoff          string
+	MaxAttempts      int32
+	BaseDelaySeconds int32
+	MaxDelaySeconds  int32
+	CreatedAt        pgtype.Timestamptz
+	UpdatedAt        pgtype.Timestamptz
 }
@@ -11,6 +11,28 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const deleteAlert = `-- name: DeleteAlert :exec
+DELETE
+FROM arrower.job_alerts
+WHERE id = $1
+`
+
+func (q *Queries) DeleteAlert(ctx context.Context, id string) error {
+	_, err := q.db.Exec(ctx, deleteAlert, id)
+	return err
+}
+
+const deleteAlertRule = `-- name: DeleteAlertRule :exec
+DELETE
+FROM arrower.job_alert_rules
+WHERE name = $1
+`
+
+func (q *Queries) DeleteAlertRule(ctx context.Context, name string) error {
+	_, err := q.db.Exec(ctx, deleteAlertRule, name)
+	return err
+}
+
 const deleteJob = `-- name: DeleteJob :exec
 DELETE
 FROM arrower.gue_jobs
@@ -22,6 +44,97 @@ func (q *Queries) DeleteJob(ctx context.Context, jobID string) error {
 	return err
 }
 
+const deleteRetryPolicy = `-- name: DeleteRetryPolicy :exec
+DELETE
+FROM arrower.job_retry_policies
+WHERE queue = $1
+  AND job_type = $2
+`
+
+type DeleteRetryPolicyParams struct {
+	Queue   string
+	JobType string
+}
+
+func (q *Queries) DeleteRetryPolicy(ctx context.Context, arg DeleteRetryPolicyParams) error {
+	_, err := q.db.Exec(ctx, deleteRetryPolicy, arg.Queue, arg.JobType)
+	return err
+}
+
+const getAlertRules = `-- name: GetAlertRules :many
+SELECT name, queue, job_type, condition, threshold, for_seconds, channels, created_at, updated_at
+FROM arrower.job_alert_rules
+ORDER BY name
+`
+
+func (q *Queries) GetAlertRules(ctx context.Context) ([]ArrowerJobAlertRule, error) {
+	rows, err := q.db.Query(ctx, getAlertRules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ArrowerJobAlertRule
+	for rows.Next() {
+		var i ArrowerJobAlertRule
+		if err := rows.Scan(
+			&i.Name,
+			&i.Queue,
+			&i.JobType,
+			&i.Condition,
+			&i.Threshold,
+			&i.ForSeconds,
+			&i.Channels,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAlerts = `-- name: GetAlerts :many
+SELECT id, rule, queue, job_type, message, value, pending_since, fired_at, resolved_at
+FROM arrower.job_alerts
+WHERE resolved_at IS NULL
+   OR resolved_at > $1
+ORDER BY resolved_at DESC NULLS FIRST, pending_since DESC
+`
+
+func (q *Queries) GetAlerts(ctx context.Context, resolvedAt pgtype.Timestamptz) ([]ArrowerJobAlert, error) {
+	rows, err := q.db.Query(ctx, getAlerts, resolvedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ArrowerJobAlert
+	for rows.Next() {
+		var i ArrowerJobAlert
+		if err := rows.Scan(
+			&i.ID,
+			&i.Rule,
+			&i.Queue,
+			&i.JobType,
+			&i.Message,
+			&i.Value,
+			&i.PendingSince,
+			&i.FiredAt,
+			&i.ResolvedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getFinishedJobs = `-- name: GetFinishedJobs :many
 SELECT f.job_id, f.priority, f.run_at, f.job_type, f.args, f.queue, f.run_count, f.run_error, f.created_at, f.updated_at, f.success, f.finished_at, f.pruned_at
 FROM (SELECT DISTINCT ON (job_id) job_id, priority, run_at, job_type, args, queue, run_count, run_error, created_at, updated_at, success, finished_at, pruned_at
@@ -159,6 +272,46 @@ func (q *Queries) GetFinishedJobsByQueueAndType(ctx context.Context, arg GetFini
 	return items, nil
 }
 
+const getJob = `-- name: GetJob :one
+SELECT j.job_id, j.priority, j.run_at, j.job_type, j.args, j.error_count, j.last_error, j.queue, j.created_at, j.updated_at, (p.job_id IS NOT NULL)::BOOLEAN AS paused
+FROM arrower.gue_jobs j
+         LEFT JOIN arrower.paused_jobs p USING (job_id)
+WHERE j.job_id = $1
+`
+
+type GetJobRow struct {
+	JobID      string
+	Priority   int16
+	RunAt      pgtype.Timestamptz
+	JobType    string
+	Args       []byte
+	ErrorCount int32
+	LastError  string
+	Queue      string
+	CreatedAt  pgtype.Timestamptz
+	UpdatedAt  pgtype.Timestamptz
+	Paused     bool
+}
+
+func (q *Queries) GetJob(ctx context.Context, jobID string) (GetJobRow, error) {
+	row := q.db.QueryRow(ctx, getJob, jobID)
+	var i GetJobRow
+	err := row.Scan(
+		&i.JobID,
+		&i.Priority,
+		&i.RunAt,
+		&i.JobType,
+		&i.Args,
+		&i.ErrorCount,
+		&i.LastError,
+		&i.Queue,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Paused,
+	)
+	return i, err
+}
+
 const getJobHistory = `-- name: GetJobHistory :many
 SELECT job_id, priority, run_at, job_type, args, queue, run_count, run_error, created_at, updated_at, success, finished_at, pruned_at
 FROM arrower.gue_jobs_history
@@ -201,22 +354,37 @@ func (q *Queries) GetJobHistory(ctx context.Context, jobID string) ([]ArrowerGue
 }
 
 const getPendingJobs = `-- name: GetPendingJobs :many
-SELECT job_id, priority, run_at, job_type, args, error_count, last_error, queue, created_at, updated_at
-FROM arrower.gue_jobs
-WHERE queue = $1
-ORDER BY priority, run_at ASC
+SELECT j.job_id, j.priority, j.run_at, j.job_type, j.args, j.error_count, j.last_error, j.queue, j.created_at, j.updated_at, (p.job_id IS NOT NULL)::BOOLEAN AS paused
+FROM arrower.gue_jobs j
+         LEFT JOIN arrower.paused_jobs p USING (job_id)
+WHERE j.queue = $1
+ORDER BY j.priority, j.run_at ASC
 LIMIT 100
 `
 
-func (q *Queries) GetPendingJobs(ctx context.Context, queue string) ([]ArrowerGueJob, error) {
+type GetPendingJobsRow struct {
+	JobID      string
+	Priority   int16
+	RunAt      pgtype.Timestamptz
+	JobType    string
+	Args       []byte
+	ErrorCount int32
+	LastError  string
+	Queue      string
+	CreatedAt  pgtype.Timestamptz
+	UpdatedAt  pgtype.Timestamptz
+	Paused     bool
+}
+
+func (q *Queries) GetPendingJobs(ctx context.Context, queue string) ([]GetPendingJobsRow, error) {
 	rows, err := q.db.Query(ctx, getPendingJobs, queue)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ArrowerGueJob
+	var items []GetPendingJobsRow
 	for rows.Next() {
-		var i ArrowerGueJob
+		var i GetPendingJobsRow
 		if err := rows.Scan(
 			&i.JobID,
 			&i.Priority,
@@ -228,6 +396,7 @@ func (q *Queries) GetPendingJobs(ctx context.Context, queue string) ([]ArrowerGu
 			&i.Queue,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Paused,
 		); err != nil {
 			return nil, err
 		}
@@ -267,22 +436,76 @@ func (q *Queries) GetQueues(ctx context.Context) ([]string, error) {
 	return items, nil
 }
 
+const getRetryPolicies = `-- name: GetRetryPolicies :many
+SELECT queue, job_type, backoff, max_attempts, base_delay_seconds, max_delay_seconds, created_at, updated_at
+FROM arrower.job_retry_policies
+ORDER BY queue, job_type
+`
+
+func (q *Queries) GetRetryPolicies(ctx context.Context) ([]ArrowerJobRetryPolicy, error) {
+	rows, err := q.db.Query(ctx, getRetryPolicies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ArrowerJobRetryPolicy
+	for rows.Next() {
+		var i ArrowerJobRetryPolicy
+		if err := rows.Scan(
+			&i.Queue,
+			&i.JobType,
+			&i.Backoff,
+			&i.MaxAttempts,
+			&i.BaseDelaySeconds,
+			&i.MaxDelaySeconds,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getWorkerPools = `-- name: GetWorkerPools :many
-SELECT id, queue, workers, git_hash, job_types, created_at, updated_at
-FROM arrower.gue_jobs_worker_pool
-WHERE updated_at > NOW() - INTERVAL '2 minutes'
-ORDER BY queue, id
+SELECT pool.id,
+       pool.queue,
+       pool.workers,
+       pool.git_hash,
+       pool.job_types,
+       pool.created_at,
+       pool.updated_at,
+       COALESCE(instance.role, '')::TEXT AS role
+FROM arrower.gue_jobs_worker_pool AS pool
+         LEFT JOIN arrower.skeleton_instances AS instance ON instance.name = pool.id
+WHERE pool.updated_at > NOW() - INTERVAL '2 minutes'
+ORDER BY pool.queue, pool.id
 `
 
-func (q *Queries) GetWorkerPools(ctx context.Context) ([]ArrowerGueJobsWorkerPool, error) {
+type GetWorkerPoolsRow struct {
+	ID        string
+	Queue     string
+	Workers   int16
+	GitHash   string
+	JobTypes  []string
+	CreatedAt pgtype.Timestamptz
+	UpdatedAt pgtype.Timestamptz
+	Role      string
+}
+
+func (q *Queries) GetWorkerPools(ctx context.Context) ([]GetWorkerPoolsRow, error) {
 	rows, err := q.db.Query(ctx, getWorkerPools)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ArrowerGueJobsWorkerPool
+	var items []GetWorkerPoolsRow
 	for rows.Next() {
-		var i ArrowerGueJobsWorkerPool
+		var i GetWorkerPoolsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Queue,
@@ -291,6 +514,7 @@ func (q *Queries) GetWorkerPools(ctx context.Context) ([]ArrowerGueJobsWorkerPoo
 			&i.JobTypes,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Role,
 		); err != nil {
 			return nil, err
 		}
@@ -493,6 +717,28 @@ type ScheduleJobsParams struct {
 	Args      []byte
 }
 
+const resumePausedJobs = `-- name: ResumePausedJobs :exec
+WITH released AS (
+    DELETE FROM arrower.paused_jobs
+        WHERE queue = $1
+            AND (job_type = $2 OR $2::TEXT = '')
+        RETURNING job_id)
+UPDATE arrower.gue_jobs
+SET run_at     = NOW(),
+    updated_at = NOW()
+WHERE job_id IN (SELECT job_id FROM released)
+`
+
+type ResumePausedJobsParams struct {
+	Queue   string
+	JobType string
+}
+
+func (q *Queries) ResumePausedJobs(ctx context.Context, arg ResumePausedJobsParams) error {
+	_, err := q.db.Exec(ctx, resumePausedJobs, arg.Queue, arg.JobType)
+	return err
+}
+
 const statsAvgDurationOfJobs = `-- name: StatsAvgDurationOfJobs :one
 SELECT COALESCE(AVG(EXTRACT(MICROSECONDS FROM (finished_at - created_at))), 0)::FLOAT AS durration_in_microseconds
 FROM arrower.gue_jobs_history
@@ -520,6 +766,123 @@ func (q *Queries) StatsFailedJobs(ctx context.Context, queue string) (int64, err
 	return count, err
 }
 
+const statsJobMetricsTimeSeries = `-- name: StatsJobMetricsTimeSeries :many
+SELECT DATE_BIN($1::INTERVAL, finished_at, TIMESTAMP WITH TIME ZONE'2001-01-01')::TIMESTAMPTZ                    AS t,
+       COUNT(*)                                                                                                    AS processed,
+       COUNT(*) FILTER (WHERE success = false)                                                                     AS failed,
+       PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (finished_at - created_at)))::FLOAT         AS latency_p95,
+       PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY GREATEST(EXTRACT(EPOCH FROM (created_at - run_at)), 0))::FLOAT AS wait_p95
+FROM arrower.gue_jobs_history
+WHERE queue = $2
+  AND finished_at >= $3
+GROUP BY t
+ORDER BY t
+`
+
+type StatsJobMetricsTimeSeriesParams struct {
+	Bin        pgtype.Interval
+	Queue      string
+	FinishedAt pgtype.Timestamptz
+}
+
+type StatsJobMetricsTimeSeriesRow struct {
+	T          pgtype.Timestamptz
+	Processed  int64
+	Failed     int64
+	LatencyP95 float64
+	WaitP95    float64
+}
+
+func (q *Queries) StatsJobMetricsTimeSeries(ctx context.Context, arg StatsJobMetricsTimeSeriesParams) ([]StatsJobMetricsTimeSeriesRow, error) {
+	rows, err := q.db.Query(ctx, statsJobMetricsTimeSeries, arg.Bin, arg.Queue, arg.FinishedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []StatsJobMetricsTimeSeriesRow
+	for rows.Next() {
+		var i StatsJobMetricsTimeSeriesRow
+		if err := rows.Scan(
+			&i.T,
+			&i.Processed,
+			&i.Failed,
+			&i.LatencyP95,
+			&i.WaitP95,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const statsJobTypeMetrics = `-- name: StatsJobTypeMetrics :many
+SELECT job_type,
+       COUNT(*)                                                                                                    AS processed,
+       COUNT(*) FILTER (WHERE success = false)                                                                     AS failed,
+       PERCENTILE_CONT(0.50) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (finished_at - created_at)))::FLOAT         AS latency_p50,
+       PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (finished_at - created_at)))::FLOAT         AS latency_p95,
+       PERCENTILE_CONT(0.99) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (finished_at - created_at)))::FLOAT         AS latency_p99,
+       PERCENTILE_CONT(0.50) WITHIN GROUP (ORDER BY GREATEST(EXTRACT(EPOCH FROM (created_at - run_at)), 0))::FLOAT AS wait_p50,
+       PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY GREATEST(EXTRACT(EPOCH FROM (created_at - run_at)), 0))::FLOAT AS wait_p95,
+       PERCENTILE_CONT(0.99) WITHIN GROUP (ORDER BY GREATEST(EXTRACT(EPOCH FROM (created_at - run_at)), 0))::FLOAT AS wait_p99
+FROM arrower.gue_jobs_history
+WHERE queue = $1
+  AND finished_at >= $2
+GROUP BY job_type
+ORDER BY job_type
+`
+
+type StatsJobTypeMetricsParams struct {
+	Queue      string
+	FinishedAt pgtype.Timestamptz
+}
+
+type StatsJobTypeMetricsRow struct {
+	JobType    string
+	Processed  int64
+	Failed     int64
+	LatencyP50 float64
+	LatencyP95 float64
+	LatencyP99 float64
+	WaitP50    float64
+	WaitP95    float64
+	WaitP99    float64
+}
+
+func (q *Queries) StatsJobTypeMetrics(ctx context.Context, arg StatsJobTypeMetricsParams) ([]StatsJobTypeMetricsRow, error) {
+	rows, err := q.db.Query(ctx, statsJobTypeMetrics, arg.Queue, arg.FinishedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []StatsJobTypeMetricsRow
+	for rows.Next() {
+		var i StatsJobTypeMetricsRow
+		if err := rows.Scan(
+			&i.JobType,
+			&i.Processed,
+			&i.Failed,
+			&i.LatencyP50,
+			&i.LatencyP95,
+			&i.LatencyP99,
+			&i.WaitP50,
+			&i.WaitP95,
+			&i.WaitP99,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const statsPendingJobs = `-- name: StatsPendingJobs :one
 SELECT COUNT(*)
 FROM arrower.gue_jobs
@@ -640,6 +1003,38 @@ func (q *Queries) TotalFinishedJobsByQueueAndType(ctx context.Context, arg Total
 	return count, err
 }
 
+const updateJob = `-- name: UpdateJob :exec
+UPDATE arrower.gue_jobs
+SET queue       = $1,
+    priority    = $2,
+    args        = $3,
+    error_count = $4,
+    run_at      = $5,
+    updated_at  = NOW()
+WHERE job_id = $6
+`
+
+type UpdateJobParams struct {
+	Queue      string
+	Priority   int16
+	Args       []byte
+	ErrorCount int32
+	RunAt      pgtype.Timestamptz
+	JobID      string
+}
+
+func (q *Queries) UpdateJob(ctx context.Context, arg UpdateJobParams) error {
+	_, err := q.db.Exec(ctx, updateJob,
+		arg.Queue,
+		arg.Priority,
+		arg.Args,
+		arg.ErrorCount,
+		arg.RunAt,
+		arg.JobID,
+	)
+	return err
+}
+
 const updateRunAt = `-- name: UpdateRunAt :exec
 UPDATE arrower.gue_jobs
 SET run_at = $1
@@ -656,6 +1051,108 @@ func (q *Queries) UpdateRunAt(ctx context.Context, arg UpdateRunAtParams) error
 	return err
 }
 
+const upsertAlert = `-- name: UpsertAlert :exec
+INSERT INTO arrower.job_alerts (id, rule, queue, job_type, message, value, pending_since, fired_at, resolved_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (id) DO UPDATE SET message     = $5,
+                               value       = $6,
+                               fired_at    = $8,
+                               resolved_at = $9
+`
+
+type UpsertAlertParams struct {
+	ID           string
+	Rule         string
+	Queue        string
+	JobType      string
+	Message      string
+	Value        float64
+	PendingSince pgtype.Timestamptz
+	FiredAt      pgtype.Timestamptz
+	ResolvedAt   pgtype.Timestamptz
+}
+
+func (q *Queries) UpsertAlert(ctx context.Context, arg UpsertAlertParams) error {
+	_, err := q.db.Exec(ctx, upsertAlert,
+		arg.ID,
+		arg.Rule,
+		arg.Queue,
+		arg.JobType,
+		arg.Message,
+		arg.Value,
+		arg.PendingSince,
+		arg.FiredAt,
+		arg.ResolvedAt,
+	)
+	return err
+}
+
+const upsertAlertRule = `-- name: UpsertAlertRule :exec
+INSERT INTO arrower.job_alert_rules (name, queue, job_type, condition, threshold, for_seconds, channels)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (name) DO UPDATE SET queue       = $2,
+                                 job_type    = $3,
+                                 condition   = $4,
+                                 threshold   = $5,
+                                 for_seconds = $6,
+                                 channels    = $7,
+                                 updated_at  = NOW()
+`
+
+type UpsertAlertRuleParams struct {
+	Name       string
+	Queue      string
+	JobType    string
+	Condition  string
+	Threshold  float64
+	ForSeconds int32
+	Channels   []string
+}
+
+func (q *Queries) UpsertAlertRule(ctx context.Context, arg UpsertAlertRuleParams) error {
+	_, err := q.db.Exec(ctx, upsertAlertRule,
+		arg.Name,
+		arg.Queue,
+		arg.JobType,
+		arg.Condition,
+		arg.Threshold,
+		arg.ForSeconds,
+		arg.Channels,
+	)
+	return err
+}
+
+const upsertRetryPolicy = `-- name: UpsertRetryPolicy :exec
+INSERT INTO arrower.job_retry_policies (queue, job_type, backoff, max_attempts, base_delay_seconds, max_delay_seconds)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (queue, job_type) DO UPDATE SET backoff            = $3,
+                                            max_attempts       = $4,
+                                            base_delay_seconds = $5,
+                                            max_delay_seconds  = $6,
+                                            updated_at         = NOW()
+`
+
+type UpsertRetryPolicyParams struct {
+	Queue            string
+	JobType          string
+	Backoff          string
+	MaxAttempts      int32
+	BaseDelaySeconds int32
+	MaxDelaySeconds  int32
+}
+
+func (q *Queries) UpsertRetryPolicy(ctx context.Context, arg UpsertRetryPolicyParams) error {
+	_, err := q.db.Exec(ctx, upsertRetryPolicy,
+		arg.Queue,
+		arg.JobType,
+		arg.Backoff,
+		arg.MaxAttempts,
+		arg.BaseDelaySeconds,
+		arg.MaxDelaySeconds,
+	)
+	return err
+}
+
 const upsertWorkerToPool = `-- name: UpsertWorkerToPool :exec
 INSERT INTO arrower.gue_jobs_worker_pool (id, queue, workers, created_at, updated_at)
 VALUES ($1, $2, $3, STATEMENT_TIMESTAMP(), $4)
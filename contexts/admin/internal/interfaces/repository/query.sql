@@ -63,10 +63,11 @@ FROM arrower.gue_jobs_history;
 
 
 -- name: GetPendingJobs :many
-SELECT *
-FROM arrower.gue_jobs
-WHERE queue = $1
-ORDER BY priority, run_at ASC
+SELECT j.*, (p.job_id IS NOT NULL)::BOOLEAN AS paused
+FROM arrower.gue_jobs j
+         LEFT JOIN arrower.paused_jobs p USING (job_id)
+WHERE j.queue = $1
+ORDER BY j.priority, j.run_at ASC
 LIMIT 100;
 
 -- name: GetFinishedJobs :many
@@ -143,10 +144,18 @@ WHERE queue = $1
 
 
 -- name: GetWorkerPools :many
-SELECT *
-FROM arrower.gue_jobs_worker_pool
-WHERE updated_at > NOW() - INTERVAL '2 minutes'
-ORDER BY queue, id;
+SELECT pool.id,
+       pool.queue,
+       pool.workers,
+       pool.git_hash,
+       pool.job_types,
+       pool.created_at,
+       pool.updated_at,
+       COALESCE(instance.role, '')::TEXT AS role
+FROM arrower.gue_jobs_worker_pool AS pool
+         LEFT JOIN arrower.skeleton_instances AS instance ON instance.name = pool.id
+WHERE pool.updated_at > NOW() - INTERVAL '2 minutes'
+ORDER BY pool.queue, pool.id;
 
 -- name: UpsertWorkerToPool :exec
 INSERT INTO arrower.gue_jobs_worker_pool (id, queue, workers, created_at, updated_at)
@@ -176,4 +185,122 @@ WHERE queue = $1
 SELECT *
 FROM arrower.gue_jobs_history
 WHERE job_id = $1
-ORDER BY created_at DESC;
\ No newline at end of file
+ORDER BY created_at DESC;
+
+
+-- name: GetJob :one
+SELECT j.*, (p.job_id IS NOT NULL)::BOOLEAN AS paused
+FROM arrower.gue_jobs j
+         LEFT JOIN arrower.paused_jobs p USING (job_id)
+WHERE j.job_id = $1;
+
+-- name: UpdateJob :exec
+UPDATE arrower.gue_jobs
+SET queue       = $1,
+    priority    = $2,
+    args        = $3,
+    error_count = $4,
+    run_at      = $5,
+    updated_at  = NOW()
+WHERE job_id = $6;
+
+
+-- name: GetRetryPolicies :many
+SELECT *
+FROM arrower.job_retry_policies
+ORDER BY queue, job_type;
+
+-- name: UpsertRetryPolicy :exec
+INSERT INTO arrower.job_retry_policies (queue, job_type, backoff, max_attempts, base_delay_seconds, max_delay_seconds)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (queue, job_type) DO UPDATE SET backoff            = $3,
+                                            max_attempts       = $4,
+                                            base_delay_seconds = $5,
+                                            max_delay_seconds  = $6,
+                                            updated_at         = NOW();
+
+-- name: DeleteRetryPolicy :exec
+DELETE
+FROM arrower.job_retry_policies
+WHERE queue = $1
+  AND job_type = $2;
+
+-- name: ResumePausedJobs :exec
+WITH released AS (
+    DELETE FROM arrower.paused_jobs
+        WHERE queue = @queue
+            AND (job_type = @job_type OR @job_type::TEXT = '')
+        RETURNING job_id)
+UPDATE arrower.gue_jobs
+SET run_at     = NOW(),
+    updated_at = NOW()
+WHERE job_id IN (SELECT job_id FROM released);
+
+-- name: StatsJobTypeMetrics :many
+SELECT job_type,
+       COUNT(*)                                                                                                    AS processed,
+       COUNT(*) FILTER (WHERE success = false)                                                                     AS failed,
+       PERCENTILE_CONT(0.50) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (finished_at - created_at)))::FLOAT         AS latency_p50,
+       PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (finished_at - created_at)))::FLOAT         AS latency_p95,
+       PERCENTILE_CONT(0.99) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (finished_at - created_at)))::FLOAT         AS latency_p99,
+       PERCENTILE_CONT(0.50) WITHIN GROUP (ORDER BY GREATEST(EXTRACT(EPOCH FROM (created_at - run_at)), 0))::FLOAT AS wait_p50,
+       PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY GREATEST(EXTRACT(EPOCH FROM (created_at - run_at)), 0))::FLOAT AS wait_p95,
+       PERCENTILE_CONT(0.99) WITHIN GROUP (ORDER BY GREATEST(EXTRACT(EPOCH FROM (created_at - run_at)), 0))::FLOAT AS wait_p99
+FROM arrower.gue_jobs_history
+WHERE queue = $1
+  AND finished_at >= $2
+GROUP BY job_type
+ORDER BY job_type;
+
+-- name: StatsJobMetricsTimeSeries :many
+SELECT DATE_BIN(@bin::INTERVAL, finished_at, TIMESTAMP WITH TIME ZONE'2001-01-01')::TIMESTAMPTZ                    AS t,
+       COUNT(*)                                                                                                    AS processed,
+       COUNT(*) FILTER (WHERE success = false)                                                                     AS failed,
+       PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY EXTRACT(EPOCH FROM (finished_at - created_at)))::FLOAT         AS latency_p95,
+       PERCENTILE_CONT(0.95) WITHIN GROUP (ORDER BY GREATEST(EXTRACT(EPOCH FROM (created_at - run_at)), 0))::FLOAT AS wait_p95
+FROM arrower.gue_jobs_history
+WHERE queue = @queue
+  AND finished_at >= @finished_at
+GROUP BY t
+ORDER BY t;
+
+-- name: GetAlertRules :many
+SELECT *
+FROM arrower.job_alert_rules
+ORDER BY name;
+
+-- name: UpsertAlertRule :exec
+INSERT INTO arrower.job_alert_rules (name, queue, job_type, condition, threshold, for_seconds, channels)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (name) DO UPDATE SET queue       = $2,
+                                 job_type    = $3,
+                                 condition   = $4,
+                                 threshold   = $5,
+                                 for_seconds = $6,
+                                 channels    = $7,
+                                 updated_at  = NOW();
+
+-- name: DeleteAlertRule :exec
+DELETE
+FROM arrower.job_alert_rules
+WHERE name = $1;
+
+-- name: GetAlerts :many
+SELECT *
+FROM arrower.job_alerts
+WHERE resolved_at IS NULL
+   OR resolved_at > $1
+ORDER BY resolved_at DESC NULLS FIRST, pending_since DESC;
+
+-- name: UpsertAlert :exec
+INSERT INTO arrower.job_alerts (id, rule, queue, job_type, message, value, pending_since, fired_at, resolved_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (id) DO UPDATE SET message     = $5,
+                               value       = $6,
+                               fired_at    = $8,
+                               resolved_at = $9;
+
+-- name: DeleteAlert :exec
+DELETE
+FROM arrower.job_alerts
+WHERE id = $1;
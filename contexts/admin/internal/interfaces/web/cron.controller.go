@@ -0,0 +1,132 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/labstack/echo/v4"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/views/pages"
+	"github.com/go-arrower/skeleton/shared/infrastructure/cron"
+	"github.com/go-arrower/skeleton/shared/infrastructure/i18n"
+	sharedweb "github.com/go-arrower/skeleton/shared/infrastructure/web"
+)
+
+func NewCronController(logger alog.Logger, scheduler *cron.Scheduler, repo jobs.Repository) *CronController {
+	return &CronController{
+		logger:    logger,
+		scheduler: scheduler,
+		repo:      repo,
+	}
+}
+
+// CronController manages the recurring jobs of the cron.Scheduler.
+type CronController struct {
+	logger alog.Logger
+
+	scheduler *cron.Scheduler
+	repo      jobs.Repository
+}
+
+func (cc *CronController) ListSchedules() func(c echo.Context) error {
+	return func(c echo.Context) error {
+		schedules, err := cc.scheduler.Schedules(c.Request().Context())
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		queues, err := cc.repo.Queues(c.Request().Context())
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		return sharedweb.Render(c, http.StatusOK, pages.ViewCron, pages.CronPage{
+			Title:     "Recurring Jobs",
+			Schedules: pages.PresentSchedules(schedules, i18n.TimeZone(c.Request().Context())),
+			Queues:    queues,
+		})
+	}
+}
+
+func (cc *CronController) CreateSchedule() func(c echo.Context) error {
+	return func(c echo.Context) error {
+		queue := c.FormValue("queue")
+		if jobs.QueueName(queue) == jobs.DefaultQueueName {
+			queue = ""
+		}
+
+		err := cc.scheduler.Add(c.Request().Context(), cron.Schedule{ //nolint:exhaustruct // the rest is set by the scheduler
+			Name:    c.FormValue("name"),
+			Spec:    c.FormValue("spec"),
+			Queue:   queue,
+			JobType: c.FormValue("job-type"),
+			Payload: c.FormValue("payload"),
+		})
+		if errors.Is(err, cron.ErrScheduleFailed) {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		return c.Redirect(http.StatusSeeOther, "/admin/jobs/cron")
+	}
+}
+
+func (cc *CronController) PauseSchedule() func(c echo.Context) error {
+	return func(c echo.Context) error {
+		err := cc.scheduler.Pause(c.Request().Context(), c.Param("name"))
+		if err != nil {
+			return c.NoContent(http.StatusBadRequest)
+		}
+
+		return cc.renderSchedule(c)
+	}
+}
+
+func (cc *CronController) ResumeSchedule() func(c echo.Context) error {
+	return func(c echo.Context) error {
+		err := cc.scheduler.Resume(c.Request().Context(), c.Param("name"))
+		if err != nil {
+			return c.NoContent(http.StatusBadRequest)
+		}
+
+		return cc.renderSchedule(c)
+	}
+}
+
+func (cc *CronController) TriggerSchedule() func(c echo.Context) error {
+	return func(c echo.Context) error {
+		err := cc.scheduler.Trigger(c.Request().Context(), c.Param("name"))
+		if err != nil {
+			return c.NoContent(http.StatusBadRequest)
+		}
+
+		return cc.renderSchedule(c)
+	}
+}
+
+func (cc *CronController) DeleteSchedule() func(c echo.Context) error {
+	return func(c echo.Context) error {
+		err := cc.scheduler.Remove(c.Request().Context(), c.Param("name"))
+		if err != nil {
+			return c.NoContent(http.StatusBadRequest)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+// renderSchedule returns the updated table row of a schedule, so htmx can swap it.
+func (cc *CronController) renderSchedule(c echo.Context) error {
+	schedule, err := cc.scheduler.Schedule(c.Request().Context(), c.Param("name"))
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return sharedweb.Render(c, http.StatusOK, pages.ViewCronSchedule, pages.PresentSchedule(schedule, i18n.TimeZone(c.Request().Context())))
+}
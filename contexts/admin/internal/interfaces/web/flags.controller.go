@@ -0,0 +1,167 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/labstack/echo/v4"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/views/pages"
+	"github.com/go-arrower/skeleton/contexts/auth"
+	"github.com/go-arrower/skeleton/shared/infrastructure/feature"
+	"github.com/go-arrower/skeleton/shared/infrastructure/i18n"
+	sharedweb "github.com/go-arrower/skeleton/shared/infrastructure/web"
+)
+
+func NewFlagsController(logger alog.Logger, flags *feature.Flags) *FlagsController {
+	return &FlagsController{
+		logger: logger,
+		flags:  flags,
+	}
+}
+
+// FlagsController manages the feature flags of all instances.
+type FlagsController struct {
+	logger alog.Logger
+
+	flags *feature.Flags
+}
+
+func (fc *FlagsController) ListFlags() func(c echo.Context) error {
+	return func(c echo.Context) error {
+		flags, err := fc.flags.All(c.Request().Context())
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		return sharedweb.Render(c, http.StatusOK, pages.ViewFlags, pages.FlagsPage{
+			Title: "Feature Flags",
+			Flags: pages.PresentFlags(flags, i18n.TimeZone(c.Request().Context())),
+		})
+	}
+}
+
+// CreateFlag adds a disabled flag, that is on for everybody once it is enabled.
+func (fc *FlagsController) CreateFlag() func(c echo.Context) error {
+	return func(c echo.Context) error {
+		key := strings.TrimSpace(c.FormValue("key"))
+
+		err := fc.flags.Create(c.Request().Context(), feature.Flag{ //nolint:exhaustruct // the rest is changed on the flag's page
+			Key:         key,
+			Description: strings.TrimSpace(c.FormValue("description")),
+			Enabled:     false,
+			Percentage:  100, //nolint:gomnd // on for everybody
+			UpdatedBy:   auth.CurrentUserID(c.Request().Context()),
+		})
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		return c.Redirect(http.StatusSeeOther, "/admin/flags/"+key)
+	}
+}
+
+func (fc *FlagsController) ShowFlag() func(c echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		flag, err := fc.flags.Flag(ctx, c.Param("key"))
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		history, err := fc.flags.History(ctx, flag.Key)
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		return sharedweb.Render(c, http.StatusOK, pages.ViewFlag, pages.FlagPage{
+			Title:   "Feature Flag " + flag.Key,
+			Flag:    pages.PresentFlag(flag, i18n.TimeZone(ctx)),
+			History: pages.PresentFlagHistory(history, i18n.TimeZone(ctx)),
+		})
+	}
+}
+
+// SaveFlag changes the rollout of an existing flag.
+func (fc *FlagsController) SaveFlag() func(c echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		flag, err := fc.flags.Flag(ctx, c.Param("key"))
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		percentage, _ := strconv.Atoi(c.FormValue("percentage"))
+
+		flag.Description = strings.TrimSpace(c.FormValue("description"))
+		flag.Enabled = c.FormValue("enabled") == "true"
+		flag.Percentage = percentage
+		flag.Users = splitList(c.FormValue("users"))
+		flag.Tenants = splitList(c.FormValue("tenants"))
+		flag.Superusers = c.FormValue("superusers") == "true"
+		flag.UpdatedBy = auth.CurrentUserID(ctx)
+
+		if err := fc.flags.Save(ctx, flag); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		return c.Redirect(http.StatusSeeOther, "/admin/flags/"+flag.Key)
+	}
+}
+
+// ToggleFlag turns the flag on or off and returns the updated table row, so htmx can swap it.
+func (fc *FlagsController) ToggleFlag() func(c echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		flag, err := fc.flags.Flag(ctx, c.Param("key"))
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		flag.Enabled = !flag.Enabled
+		flag.UpdatedBy = auth.CurrentUserID(ctx)
+
+		if err := fc.flags.Save(ctx, flag); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		flag, err = fc.flags.Flag(ctx, flag.Key)
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		return sharedweb.Render(c, http.StatusOK, pages.ViewFlagRow, pages.PresentFlag(flag, i18n.TimeZone(ctx)))
+	}
+}
+
+func (fc *FlagsController) DeleteFlag() func(c echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		err := fc.flags.Delete(ctx, c.Param("key"), auth.CurrentUserID(ctx))
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
+// splitList returns the values of a comma separated form field.
+func splitList(value string) []string {
+	var list []string
+
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+
+	return list
+}
@@ -1,14 +1,13 @@
 package web
 
 import (
-	"bytes"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
 	"net/http"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-arrower/arrower/alog"
@@ -19,6 +18,9 @@ import (
 	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
 	"github.com/go-arrower/skeleton/contexts/admin/internal/interfaces/repository/models"
 	"github.com/go-arrower/skeleton/contexts/admin/internal/views/pages"
+	"github.com/go-arrower/skeleton/shared/infrastructure/i18n"
+	sharedweb "github.com/go-arrower/skeleton/shared/infrastructure/web"
+	"github.com/go-arrower/skeleton/shared/infrastructure/web/htmx"
 )
 
 const (
@@ -60,9 +62,9 @@ func (jc *JobsController) ListQueues() func(c echo.Context) error {
 			return fmt.Errorf("%w", err)
 		}
 
-		return c.Render(http.StatusOK, "jobs.home", echo.Map{
-			"Title":  "Queues",
-			"Queues": res.QueueStats,
+		return sharedweb.Render(c, http.StatusOK, pages.ViewQueues, pages.QueuesPage{
+			Title:  "Queues",
+			Queues: res.QueueStats,
 		})
 	}
 }
@@ -147,6 +149,26 @@ func (jc *JobsController) ProcessedJobsLineChartData() func(echo.Context) error
 	}
 }
 
+func (jc *JobsController) ShowQueueMetrics() func(c echo.Context) error {
+	return func(c echo.Context) error {
+		queue := c.Param("queue")
+
+		res, err := jc.appDI.GetJobMetrics.H(c.Request().Context(), application.GetJobMetricsQuery{
+			Queue:  jobs.QueueName(queue),
+			Window: c.QueryParam("window"),
+		})
+		if errors.Is(err, jobs.ErrInvalidWindow) {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		return sharedweb.Render(c, http.StatusOK, pages.ViewQueueMetrics, pages.PresentJobMetrics(queue, res.Window, res.JobTypes, res.Series, i18n.TimeZone(c.Request().Context())))
+	}
+}
+
 func (jc *JobsController) ShowQueue() func(c echo.Context) error {
 	return func(c echo.Context) error {
 		queue := c.Param("queue")
@@ -156,15 +178,12 @@ func (jc *JobsController) ShowQueue() func(c echo.Context) error {
 			return fmt.Errorf("%w", err)
 		}
 
-		page := buildQueuePage(queue, res.Jobs, res.Kpis)
+		page := pages.PresentQueue(queue, res.Jobs, res.Kpis)
+		if res.EstimateUntilEmpty > 0 {
+			page.Stats.EstimateUntilEmpty = res.EstimateUntilEmpty.Truncate(time.Second)
+		}
 
-		return c.Render(http.StatusOK, "jobs.queue",
-			echo.Map{
-				"Title":     page.QueueName + " queue",
-				"QueueName": page.QueueName,
-				"Jobs":      page.Jobs,
-				"Stats":     page.Stats,
-			})
+		return sharedweb.Render(c, http.StatusOK, pages.ViewQueue, page)
 	}
 }
 
@@ -175,9 +194,15 @@ func (jc *JobsController) ListWorkers() func(c echo.Context) error {
 			return fmt.Errorf("%w", err)
 		}
 
-		return c.Render(http.StatusOK, "jobs.workers", echo.Map{
-			"Title":   "Workers",
-			"workers": presentWorkers(res.Pool),
+		controls, err := jc.appDI.GetQueueControls.H(c.Request().Context(), application.GetQueueControlsQuery{})
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		return sharedweb.Render(c, http.StatusOK, pages.ViewWorkers, pages.WorkersPage{
+			Title:    "Workers",
+			Workers:  presentWorkers(res.Pool),
+			Controls: pages.PresentQueueControls(controls.Controls),
 		})
 	}
 }
@@ -225,14 +250,11 @@ func (jc *JobsController) ShowMaintenance() func(c echo.Context) error {
 			queues = append(queues, queue)
 		}
 
-		return c.Render(http.StatusOK, "jobs.maintenance",
-			echo.Map{
-				"Title":   "Maintenance",
-				"Jobs":    size.Jobs,
-				"History": size.History,
-				"Queues":  queues,
-			},
-		)
+		return sharedweb.Render(c, http.StatusOK, pages.ViewJobsMaintenance, pages.JobsMaintenancePage{
+			Title:  "Maintenance",
+			Size:   pages.JobsTableSize{Jobs: size.Jobs, History: size.History},
+			Queues: queues,
+		})
 	}
 }
 
@@ -246,12 +268,10 @@ func (jc *JobsController) VacuumJobTables() func(echo.Context) error {
 		}
 
 		// reload the dashboard badges with the size, by using htmx's oob technique
-		return c.Render(http.StatusOK, "jobs.maintenance#table-size",
-			echo.Map{
-				"Jobs":    size.Jobs,
-				"History": size.History,
-			},
-		)
+		return sharedweb.Render(c, http.StatusOK, pages.ViewJobsTableSize, pages.JobsTableSize{
+			Jobs:    size.Jobs,
+			History: size.History,
+		})
 	}
 }
 
@@ -272,15 +292,13 @@ func (jc *JobsController) DeleteHistory() func(echo.Context) error {
 		}
 
 		// trigger size change of history table, for other size-estimation widgets to reload.
-		c.Response().Header().Set("HX-Trigger", historyTableSizeChangedJSEvent)
+		htmx.Trigger(c, historyTableSizeChangedJSEvent)
 
 		// reload the dashboard badges with the new size, by using htmx's oob technique.
-		return c.Render(http.StatusOK, "jobs.maintenance#table-size",
-			echo.Map{
-				"Jobs":    size.Jobs,
-				"History": size.History,
-			},
-		)
+		return sharedweb.Render(c, http.StatusOK, pages.ViewJobsTableSize, pages.JobsTableSize{
+			Jobs:    size.Jobs,
+			History: size.History,
+		})
 	}
 }
 
@@ -302,7 +320,7 @@ func (jc *JobsController) PruneHistory() func(echo.Context) error {
 			CreatedAt: pgtype.Timestamptz{Time: estimateBefore, Valid: true, InfinityModifier: pgtype.Finite},
 		})
 
-		c.Response().Header().Set("HX-Trigger", historyTableSizeChangedJSEvent)
+		htmx.Trigger(c, historyTableSizeChangedJSEvent)
 
 		return c.NoContent(http.StatusOK)
 	}
@@ -364,15 +382,14 @@ func (jc *JobsController) CreateJobs() func(c echo.Context) error {
 
 		year, month, day := time.Now().Date()
 
-		return c.Render(http.StatusOK, "jobs.schedule",
-			echo.Map{
-				"Title":    "Schedule a Job",
-				"Queues":   queues,
-				"JobTypes": jobType,
-				"RunAt":    time.Now().Format(htmlDatetimeLayout),
-				"RunAtMin": fmt.Sprintf("%d-%02d-%02dT00:00", year, month, day),
-			},
-		)
+		return sharedweb.Render(c, http.StatusOK, pages.ViewSchedule, pages.SchedulePage{
+			Title:    "Schedule a Job",
+			Queues:   queues,
+			JobTypes: jobType,
+			RunAt:    time.Now().Format(htmlDatetimeLayout),
+			RunAtMin: fmt.Sprintf("%d-%02d-%02dT00:00", year, month, day),
+			Payloads: pages.PayloadExamples{Queue: "", JobType: "", Payloads: nil},
+		})
 	}
 }
 
@@ -385,9 +402,7 @@ func (jc *JobsController) ShowJobTypes() func(_ echo.Context) error {
 			application.JobTypesForQueueQuery{Queue: jobs.QueueName(queue)},
 		)
 
-		return c.Render(http.StatusOK, "jobs.schedule#known-job-types", echo.Map{
-			"JobTypes": jobType,
-		})
+		return sharedweb.Render(c, http.StatusOK, pages.ViewScheduleJobTypes, jobType)
 	}
 }
 
@@ -405,8 +420,7 @@ func (jc *JobsController) PayloadExamples() func(_ echo.Context) error {
 			JobType: jobType,
 		})
 
-		return c.Render(http.StatusOK, "jobs.schedule#payload-examples",
-			pages.PresentJobsExamplePayloads(queue, jobType, payloads))
+		return sharedweb.Render(c, http.StatusOK, pages.ViewPayloadExamples, pages.PresentJobsExamplePayloads(queue, jobType, payloads))
 	}
 }
 
@@ -459,6 +473,7 @@ func presentWorkers(pool []jobs.WorkerPool) []pages.JobWorker {
 	for i, _ := range pool {
 		jobWorkers[i].ID = pool[i].ID
 		jobWorkers[i].Queue = pool[i].Queue
+		jobWorkers[i].Role = pool[i].Role
 		jobWorkers[i].Workers = pool[i].Workers
 		jobWorkers[i].Version = pool[i].Version
 		jobWorkers[i].JobTypes = pool[i].JobTypes
@@ -504,132 +519,290 @@ func notSeenSinceTimeString(t time.Time, warningSecondsWorkerPoolNotSeenSince ti
 	return "now"
 }
 
-type (
-	QueueStats struct {
-		PendingJobsPerType   map[string]int
-		QueueName            string
-		PendingJobs          int
-		FailedJobs           int
-		ProcessedJobs        int
-		AvailableWorkers     int
-		PendingJobsErrorRate float64 // can be calculated: FailedJobs * 100 / PendingJobs
-		AverageTimePerJob    time.Duration
-		EstimateUntilEmpty   time.Duration // can be calculated
-	}
+func (jc *JobsController) FinishedJobs() func(echo.Context) error {
+	return func(c echo.Context) error {
+		if updateJobTypeSelectOptions := c.QueryParam("updateJobTypes"); updateJobTypeSelectOptions == "true" {
+			q := c.QueryParam("queue")
+			if q == string(jobs.DefaultQueueName) {
+				q = ""
+			}
 
-	ListQueuesPage struct {
-		Queues map[jobs.QueueName]jobs.QueueStats
-	}
+			jobTypes, err := jc.queries.JobTypes(c.Request().Context(), q)
+			if err != nil {
+				return fmt.Errorf("%w", err)
+			}
+
+			return sharedweb.Render(c, http.StatusOK, pages.ViewFinishedJobTypes, pages.KnownJobTypes{
+				JobType:  jobTypes,
+				Selected: c.QueryParam("job-type"),
+			})
+		}
+
+		filter := jobs.Filter{ // todo, see if echo can autobind to this; same for total count controller
+			Queue:   jobs.QueueName(c.QueryParam("queue")),
+			JobType: jobs.JobType(c.QueryParam("job-type")),
+		}
+
+		finishedJobs, err := jc.repo.FinishedJobs(c.Request().Context(), filter)
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		if filter != (jobs.Filter{}) {
+			htmx.Trigger(c, finishedJobsFilterChangedJSEvent)
+
+			return sharedweb.Render(c, http.StatusOK, pages.ViewFinishedJobsList, pages.PresentFinishedJobs(finishedJobs))
+		}
 
-	QueuePage struct {
-		Jobs      []jobs.PendingJob
-		QueueName string
-		Stats     QueueStats
+		queues, err := jc.repo.Queues(c.Request().Context())
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		return sharedweb.Render(c, http.StatusOK, pages.ViewFinishedJobs, pages.NewFinishedJobs(finishedJobs, queues))
 	}
-)
+}
 
-func buildQueuePage(queue string, jobs []jobs.PendingJob, kpis jobs.QueueKPIs) QueuePage {
-	jobs = prettyFormatPayload(jobs)
+func (jc *JobsController) FinishedJobsTotal() func(ctx echo.Context) error {
+	return func(c echo.Context) error {
+		filter := jobs.Filter{
+			Queue:   jobs.QueueName(c.QueryParam("queue")),
+			JobType: jobs.JobType(c.QueryParam("job-type")),
+		}
 
-	return QueuePage{
-		QueueName: queue,
-		Stats:     queueKpiToStats(queue, kpis),
+		total, err := jc.repo.FinishedJobsTotal(c.Request().Context(), filter)
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
 
-		Jobs: jobs,
+		return c.String(http.StatusOK, strconv.FormatInt(total, 10))
 	}
 }
 
-func prettyFormatPayload(pJobs []jobs.PendingJob) []jobs.PendingJob {
-	for i := 0; i < len(pJobs); i++ { //nolint:varnamelen
-		var m application.JobPayload
+func (jc *JobsController) ShowJob() func(ctx echo.Context) error {
+	return func(c echo.Context) error {
+		history, err := jc.queries.GetJobHistory(c.Request().Context(), c.Param("job_id"))
+		if err != nil {
+			return fmt.Errorf("%v", err)
+		}
 
-		_ = json.Unmarshal([]byte(pJobs[i].Payload), &m)
-		data, _ := json.Marshal(m.JobData)
+		// a job is only editable, as long as it is still pending
+		var pending *pages.EditableJob
 
-		var prettyJSON bytes.Buffer
+		job, err := jc.repo.Job(c.Request().Context(), c.Param("job_id"))
+		if err != nil && !errors.Is(err, jobs.ErrJobNotFound) {
+			return fmt.Errorf("%w", err)
+		}
+
+		if err == nil {
+			var examples [][]byte
 
-		if err := json.Indent(&prettyJSON, data, "", "  "); err != nil {
+			examples, err = jc.repo.PayloadExamples(c.Request().Context(), jobs.QueueName(job.Queue), jobs.JobType(job.Type))
+			if err != nil {
+				return fmt.Errorf("%w", err)
+			}
+
+			editable := pages.PresentEditableJob(job, examples)
+			pending = &editable
 		}
 
-		if pJobs[i].Queue == "" {
-			pJobs[i].Queue = string(jobs.DefaultQueueName)
+		queues, err := jc.repo.Queues(c.Request().Context())
+		if err != nil {
+			return fmt.Errorf("%w", err)
 		}
-		pJobs[i].Payload = prettyJSON.String()
-		pJobs[i].RunAtFmt = fmtRunAtTime(pJobs[i].RunAt)
-	}
 
-	return pJobs
+		return sharedweb.Render(c, http.StatusOK, pages.ViewJob, pages.JobPage{
+			Title:   "Job",
+			Jobs:    pages.ConvertFinishedJobsForShow(history, i18n.TimeZone(c.Request().Context())),
+			Pending: pending,
+			Queues:  queues,
+		})
+	}
 }
 
-func fmtRunAtTime(tme time.Time) string {
-	now := time.Now()
+func (jc *JobsController) UpdateJob() func(ctx echo.Context) error {
+	return func(c echo.Context) error {
+		jobID := c.Param("job_id")
+
+		priority, err := strconv.ParseInt(c.FormValue("priority"), 10, 16)
+		if err != nil {
+			return c.String(http.StatusBadRequest, fmt.Sprintf("invalid priority: has to be between %d and %d", math.MinInt16, math.MaxInt16))
+		}
+
+		err = jc.appDI.UpdateJob.H(c.Request().Context(), application.UpdateJobCommand{
+			JobID:           jobID,
+			Queue:           jobs.QueueName(c.FormValue("queue")),
+			Payload:         c.FormValue("payload"),
+			Priority:        int16(priority),
+			ResetErrorCount: c.FormValue("reset-error-count") == "on",
+			RetryNow:        c.FormValue("action") == "retry",
+		})
+		if errors.Is(err, jobs.ErrInvalidPayload) {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
 
-	isToday := tme.Year() == now.Year() && tme.Month() == now.Month() && tme.Day() == now.Day()
-	if isToday {
-		return fmt.Sprintf("%02d:%02d", tme.Hour(), tme.Minute())
+		return c.Redirect(http.StatusSeeOther, "/admin/jobs/job/"+jobID)
 	}
+}
+
+func (jc *JobsController) ListRetryPolicies() func(ctx echo.Context) error {
+	return func(c echo.Context) error {
+		policies, err := jc.repo.RetryPolicies(c.Request().Context())
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		queues, err := jc.repo.Queues(c.Request().Context())
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
 
-	return tme.Format("2006.01.02 15:04")
+		return sharedweb.Render(c, http.StatusOK, pages.ViewRetries, pages.RetriesPage{
+			Title:    "Retry Policies",
+			Policies: pages.PresentRetryPolicies(policies),
+			Queues:   queues,
+		})
+	}
 }
 
-func queueKpiToStats(queue string, kpis jobs.QueueKPIs) QueueStats {
-	var errorRate float64
+func (jc *JobsController) SaveRetryPolicy() func(ctx echo.Context) error {
+	return func(c echo.Context) error {
+		maxAttempts, err := strconv.Atoi(c.FormValue("max-attempts"))
+		if err != nil {
+			return c.String(http.StatusBadRequest, "invalid max attempts")
+		}
+
+		baseDelay, err := time.ParseDuration(c.FormValue("base-delay"))
+		if err != nil {
+			return c.String(http.StatusBadRequest, "invalid base delay")
+		}
 
-	if kpis.FailedJobs != 0 {
-		errorRate = float64(kpis.FailedJobs * 100 / kpis.PendingJobs)
+		var maxDelay time.Duration
+		if c.FormValue("max-delay") != "" {
+			maxDelay, err = time.ParseDuration(c.FormValue("max-delay"))
+			if err != nil {
+				return c.String(http.StatusBadRequest, "invalid max delay")
+			}
+		}
+
+		err = jc.appDI.SaveRetryPolicy.H(c.Request().Context(), application.SaveRetryPolicyCommand{
+			Policy: jobs.RetryPolicy{
+				Queue:       jobs.QueueName(c.FormValue("queue")),
+				JobType:     jobs.JobType(c.FormValue("job-type")),
+				Backoff:     jobs.Backoff(c.FormValue("backoff")),
+				MaxAttempts: maxAttempts,
+				BaseDelay:   baseDelay,
+				MaxDelay:    maxDelay,
+			},
+		})
+		if errors.Is(err, jobs.ErrInvalidRetryPolicy) {
+			return c.String(http.StatusBadRequest, err.Error())
+		}
+
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		return c.Redirect(http.StatusSeeOther, "/admin/jobs/retries")
 	}
+}
 
-	var duration time.Duration
-	if kpis.AvailableWorkers != 0 {
-		duration = time.Duration(kpis.PendingJobs/kpis.AvailableWorkers) * kpis.AverageTimePerJob
+func (jc *JobsController) DeleteRetryPolicy() func(ctx echo.Context) error {
+	return func(c echo.Context) error {
+		err := jc.repo.DeleteRetryPolicy(
+			c.Request().Context(),
+			jobs.QueueName(c.FormValue("queue")),
+			jobs.JobType(c.FormValue("job-type")),
+		)
+		if err != nil {
+			return c.NoContent(http.StatusBadRequest)
+		}
+
+		return c.NoContent(http.StatusOK)
 	}
+}
+
+func (jc *JobsController) PauseQueue() func(ctx echo.Context) error {
+	return func(c echo.Context) error {
+		err := jc.appDI.PauseQueue.H(c.Request().Context(), application.PauseQueueCommand{
+			Queue:   jobs.QueueName(c.Param("queue")),
+			JobType: jobs.JobType(c.FormValue("job-type")),
+		})
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
 
-	return QueueStats{
-		QueueName:            queue,
-		PendingJobs:          kpis.PendingJobs,
-		PendingJobsPerType:   kpis.PendingJobsPerType,
-		FailedJobs:           kpis.FailedJobs,
-		ProcessedJobs:        kpis.ProcessedJobs,
-		AvailableWorkers:     kpis.AvailableWorkers,
-		PendingJobsErrorRate: errorRate,
-		AverageTimePerJob:    kpis.AverageTimePerJob.Truncate(time.Second),
-		EstimateUntilEmpty:   duration.Truncate(time.Second),
+		return jc.renderQueueControls(c)
 	}
 }
 
-func (jc *JobsController) FinishedJobs() func(echo.Context) error {
+func (jc *JobsController) ResumeQueue() func(ctx echo.Context) error {
 	return func(c echo.Context) error {
-		if updateJobTypeSelectOptions := c.QueryParam("updateJobTypes"); updateJobTypeSelectOptions == "true" {
-			q := c.QueryParam("queue")
-			if q == string(jobs.DefaultQueueName) {
-				q = ""
-			}
+		err := jc.appDI.ResumeQueue.H(c.Request().Context(), application.ResumeQueueCommand{
+			Queue:   jobs.QueueName(c.Param("queue")),
+			JobType: jobs.JobType(c.FormValue("job-type")),
+		})
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
 
-			jobTypes, err := jc.queries.JobTypes(c.Request().Context(), q)
-			if err != nil {
-				return fmt.Errorf("%w", err)
-			}
+		return jc.renderQueueControls(c)
+	}
+}
 
-			return c.Render(http.StatusOK, "jobs.finished#known-job-types", echo.Map{
-				"JobType":  jobTypes,
-				"Selected": c.QueryParam("job-type"),
-			})
+func (jc *JobsController) ScaleQueue() func(ctx echo.Context) error {
+	return func(c echo.Context) error {
+		workers, err := strconv.Atoi(c.FormValue("workers"))
+		if err != nil {
+			return c.String(http.StatusBadRequest, "invalid number of workers")
 		}
 
-		filter := jobs.Filter{ // todo, see if echo can autobind to this; same for total count controller
-			Queue:   jobs.QueueName(c.QueryParam("queue")),
-			JobType: jobs.JobType(c.QueryParam("job-type")),
+		err = jc.appDI.ScaleQueue.H(c.Request().Context(), application.ScaleQueueCommand{
+			Queue:   jobs.QueueName(c.Param("queue")),
+			Workers: workers,
+		})
+		if errors.Is(err, application.ErrInvalidWorkers) {
+			return c.String(http.StatusBadRequest, err.Error())
 		}
 
-		finishedJobs, err := jc.repo.FinishedJobs(c.Request().Context(), filter)
 		if err != nil {
 			return fmt.Errorf("%w", err)
 		}
 
-		if filter != (jobs.Filter{}) {
-			c.Response().Header().Set("HX-TRIGGER", finishedJobsFilterChangedJSEvent)
+		return jc.renderQueueControls(c)
+	}
+}
+
+// renderQueueControls returns the updated controls of a queue, so htmx can swap them.
+func (jc *JobsController) renderQueueControls(c echo.Context) error {
+	res, err := jc.appDI.GetQueueControls.H(c.Request().Context(), application.GetQueueControlsQuery{})
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
 
-			return c.Render(http.StatusOK, "jobs.finished#jobs.list", pages.NewFinishedJobs(finishedJobs, nil))
+	for _, control := range res.Controls {
+		if control.Queue == jobs.QueueName(c.Param("queue")) {
+			return sharedweb.Render(c, http.StatusOK, pages.ViewQueueControls, pages.PresentQueueControl(control))
+		}
+	}
+
+	return c.NoContent(http.StatusNotFound)
+}
+
+func (jc *JobsController) ListAlerts() func(ctx echo.Context) error {
+	return func(c echo.Context) error {
+		rules, err := jc.repo.AlertRules(c.Request().Context())
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		alerts, err := jc.repo.Alerts(c.Request().Context(), time.Now().Add(-24*time.Hour))
+		if err != nil {
+			return fmt.Errorf("%w", err)
 		}
 
 		queues, err := jc.repo.Queues(c.Request().Context())
@@ -637,36 +810,78 @@ func (jc *JobsController) FinishedJobs() func(echo.Context) error {
 			return fmt.Errorf("%w", err)
 		}
 
-		return c.Render(http.StatusOK, "jobs.finished", pages.NewFinishedJobs(finishedJobs, queues))
+		active, resolved := pages.PresentAlerts(alerts)
+
+		return sharedweb.Render(c, http.StatusOK, pages.ViewAlerts, pages.AlertsPage{
+			Title:    "Alerts",
+			Active:   active,
+			Resolved: resolved,
+			Rules:    pages.PresentAlertRules(rules),
+			Queues:   queues,
+		})
 	}
 }
 
-func (jc *JobsController) FinishedJobsTotal() func(ctx echo.Context) error {
+func (jc *JobsController) SaveAlertRule() func(ctx echo.Context) error {
 	return func(c echo.Context) error {
-		filter := jobs.Filter{
-			Queue:   jobs.QueueName(c.QueryParam("queue")),
-			JobType: jobs.JobType(c.QueryParam("job-type")),
+		var (
+			threshold float64
+			duration  time.Duration
+			err       error
+		)
+
+		if c.FormValue("threshold") != "" {
+			threshold, err = strconv.ParseFloat(c.FormValue("threshold"), 64)
+			if err != nil {
+				return c.String(http.StatusBadRequest, "invalid threshold")
+			}
+		}
+
+		if c.FormValue("for") != "" {
+			duration, err = time.ParseDuration(c.FormValue("for"))
+			if err != nil {
+				return c.String(http.StatusBadRequest, "invalid duration")
+			}
+		}
+
+		channels := []string{}
+
+		for _, channel := range strings.Split(c.FormValue("channels"), "\n") {
+			if channel = strings.TrimSpace(channel); channel != "" {
+				channels = append(channels, channel)
+			}
+		}
+
+		err = jc.appDI.SaveAlertRule.H(c.Request().Context(), application.SaveAlertRuleCommand{
+			Rule: jobs.AlertRule{
+				Name:      c.FormValue("name"),
+				Queue:     jobs.QueueName(c.FormValue("queue")),
+				JobType:   jobs.JobType(c.FormValue("job-type")),
+				Condition: jobs.AlertCondition(c.FormValue("condition")),
+				Channels:  channels,
+				Threshold: threshold,
+				For:       duration,
+			},
+		})
+		if errors.Is(err, jobs.ErrInvalidAlertRule) {
+			return c.String(http.StatusBadRequest, err.Error())
 		}
 
-		total, err := jc.repo.FinishedJobsTotal(c.Request().Context(), filter)
 		if err != nil {
 			return fmt.Errorf("%w", err)
 		}
 
-		return c.String(http.StatusOK, strconv.FormatInt(total, 10))
+		return c.Redirect(http.StatusSeeOther, "/admin/jobs/alerts")
 	}
 }
 
-func (jc *JobsController) ShowJob() func(ctx echo.Context) error {
+func (jc *JobsController) DeleteAlertRule() func(ctx echo.Context) error {
 	return func(c echo.Context) error {
-		jobs, err := jc.queries.GetJobHistory(c.Request().Context(), c.Param("job_id"))
+		err := jc.repo.DeleteAlertRule(c.Request().Context(), c.FormValue("name"))
 		if err != nil {
-			return fmt.Errorf("%v", err)
+			return c.NoContent(http.StatusBadRequest)
 		}
 
-		return c.Render(http.StatusOK, "jobs.job", echo.Map{
-			"Title": "Job",
-			"Jobs":  pages.ConvertFinishedJobsForShow(jobs),
-		})
+		return c.NoContent(http.StatusOK)
 	}
 }
@@ -1,7 +1,9 @@
 package web
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -9,23 +11,34 @@ import (
 	"time"
 
 	"github.com/go-arrower/arrower/alog"
-	"github.com/go-arrower/arrower/alog/models"
 	"github.com/go-arrower/arrower/setting"
-	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/labstack/echo/v4"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/application"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/logs"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/views/pages"
+	"github.com/go-arrower/skeleton/contexts/auth"
+	"github.com/go-arrower/skeleton/shared/infrastructure/i18n"
+	sharedweb "github.com/go-arrower/skeleton/shared/infrastructure/web"
+)
+
+const (
+	logsTimeLayout   = "2006-01-02T15:04:05.999999999" // time of the cursor of the last log shown, to tail the logs
+	defaultLogsRange = 15                              // minutes
 )
 
 func NewLogsController(
 	logger alog.Logger,
 	settings setting.Settings,
-	queries *models.Queries,
+	repo logs.Repository,
+	appDI application.App,
 	routes *echo.Group,
 ) *LogsController {
 	return &LogsController{
 		logger:   logger,
 		settings: settings,
-		queries:  queries,
+		repo:     repo,
+		appDI:    appDI,
 		r:        routes,
 	}
 }
@@ -33,113 +46,322 @@ func NewLogsController(
 type LogsController struct {
 	logger   alog.Logger
 	settings setting.Settings
-	queries  *models.Queries
+	repo     logs.Repository
+	appDI    application.App
 	r        *echo.Group
 }
 
-func (lc *LogsController) ShowLogs() {
-	// FIXME: how to add route with and without trailing slash
+// logsFilter are the query parameters of the logs page.
+type logsFilter struct {
+	Query string `query:"q"`
+	// Range is the number of minutes before now. It is ignored, if From is set.
+	Range int    `query:"range"`
+	From  string `query:"from"`
+	To    string `query:"to"`
+	Limit int    `query:"limit"`
+	// Time and Hash are the cursor of the last log shown, only the logs after it are returned.
+	Time string `query:"time"`
+	Hash string `query:"hash"`
+}
+
+// search returns the search of the filter. Times are in the time zone of the admin.
+func (f *logsFilter) search(loc *time.Location) (logs.Search, error) {
+	if f.Range <= 0 {
+		f.Range = defaultLogsRange
+	}
+
+	if f.Limit <= 0 || f.Limit > logs.MaxLimit {
+		f.Limit = logs.DefaultLimit
+	}
+
+	query, err := logs.ParseQuery(f.Query)
+	if err != nil {
+		return logs.Search{}, fmt.Errorf("%w", err)
+	}
+
+	search := logs.Search{
+		Query: query,
+		From:  time.Now().Add(-time.Duration(f.Range) * time.Minute),
+		To:    time.Time{},
+		After: logs.Cursor{},
+		Limit: f.Limit,
+	}
+
+	if t, err := time.ParseInLocation(htmlDatetimeLayout, f.From, loc); err == nil {
+		search.From = t
+	}
 
-	const defaultLogs = 1000
-
-	type filter struct {
-		Range int    `query:"range"`
-		Level string `query:"level"`
-		K0    string `query:"k0"`
-		F0    string `query:"f0"`
-		K1    string `query:"k1"`
-		F1    string `query:"f1"`
-		K2    string `query:"k2"`
-		F2    string `query:"f2"`
+	if t, err := time.ParseInLocation(htmlDatetimeLayout, f.To, loc); err == nil {
+		search.To = t
 	}
-	type log struct {
-		Time   time.Time
-		Log    map[string]any
-		UserID string
+
+	if t, err := time.Parse(logsTimeLayout, f.Time); err == nil {
+		search.After = logs.Cursor{Time: t, Hash: f.Hash}
 	}
 
+	return search, nil
+}
+
+func (f *logsFilter) present() pages.LogsFilter {
+	return pages.LogsFilter{
+		Query: f.Query,
+		Range: f.Range,
+		From:  f.From,
+		To:    f.To,
+		Limit: f.Limit,
+	}
+}
+
+func (lc *LogsController) ShowLogs() {
+	// FIXME: how to add route with and without trailing slash
+
 	lc.r.GET("/", func(c echo.Context) error {
-		timeParam := c.QueryParam("time")
-		searchMsgParam := c.QueryParam("msg")
+		ctx := c.Request().Context()
+		loc := i18n.TimeZone(ctx)
 
-		var filter filter
-		err := c.Bind(&filter)
+		var filter logsFilter
+		if err := c.Bind(&filter); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest).SetInternal(err)
+		}
+
+		page := pages.LogsPage{ //nolint:exhaustruct // the logs, searches, and settings are set below
+			Title:       "Logs",
+			LastLogTime: time.Now().UTC().Format(logsTimeLayout),
+		}
+
+		search, err := filter.search(loc)
+		page.Filter = filter.present()
+
+		switch {
+		case errors.Is(err, logs.ErrInvalidQuery):
+			page.QueryError = err.Error()
+		case err != nil:
+			return err
+		default:
+			found, err := lc.repo.Search(ctx, search)
+			if err != nil {
+				return fmt.Errorf("%w", err)
+			}
+
+			for i := range found {
+				found[i].Time = found[i].Time.In(loc)
+			}
+
+			page.Logs = found
+
+			// keep tailing from the last log shown, so no log is missed
+			last := search.After
+			if len(found) > 0 {
+				last = found[len(found)-1].Cursor()
+			}
+
+			if !last.IsZero() {
+				page.LastLogTime = last.Time.UTC().Format(logsTimeLayout)
+				page.LastLogHash = last.Hash
+			}
+		}
+
+		page.Searches, err = lc.repo.SavedSearches(ctx, auth.CurrentUserID(ctx))
 		if err != nil {
-			return c.String(http.StatusBadRequest, "bad request")
+			lc.logger.InfoContext(ctx, "could not load saved log searches", slog.String("err", err.Error()))
 		}
 
-		if filter.Range == 0 {
-			filter.Range = 15
+		settingLevel, err := lc.settings.Setting(ctx, alog.SettingLogLevel)
+		if err != nil {
+			return fmt.Errorf("%w", err)
 		}
 
-		filterTime := time.Now().UTC().Add(-time.Duration(filter.Range) * time.Minute)
-		if t, err := time.Parse("2006-01-02T15:04:05.999999999", timeParam); err == nil {
-			filterTime = t
+		page.Settings = pages.LogSettings{
+			// !!! assumes all loggers in all replicas are configured the same
+			Enabled: alog.Unwrap(lc.logger).UsesSettings(),
+			Level:   getLevelName(slog.Level(settingLevel.MustInt())),
 		}
 
-		filterTime = filterTime.Add(-1 * time.Hour)
+		return sharedweb.Render(c, http.StatusOK, pages.ViewLogs, page)
+	}).Name = "admin.logs"
+}
+
+// ExportLogs downloads all logs of the search as CSV or as JSON lines, one log per line as it is stored.
+func (lc *LogsController) ExportLogs() {
+	lc.r.GET("/export", func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		var filter logsFilter
+		if err := c.Bind(&filter); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest).SetInternal(err)
+		}
 
-		level := []string{"INFO"}
-		if filter.Level == "DEBUG" {
-			level = []string{"INFO", "DEBUG"}
+		search, err := filter.search(i18n.TimeZone(ctx))
+		if err != nil {
+			return err
 		}
 
-		queryParams := models.GetRecentLogsParams{
-			Time:  pgtype.Timestamptz{Time: filterTime, Valid: true, InfinityModifier: pgtype.Finite},
-			Msg:   "%" + searchMsgParam + "%",
-			Level: level,
-			Limit: defaultLogs,
+		format := c.QueryParam("format")
+		if format != "csv" && format != "jsonl" {
+			return echo.NewHTTPError(http.StatusBadRequest, "unknown format")
 		}
 
-		if filter.K0 != "" {
-			queryParams.F0 = fmt.Sprintf(`$.**.%s ? (@ like_regex "^.*%s.*" flag "i")`, filter.K0, filter.F0)
+		res := c.Response()
+		res.Header().Set(echo.HeaderContentDisposition,
+			fmt.Sprintf(`attachment; filename="logs-%s.%s"`, time.Now().UTC().Format("20060102-150405"), format),
+		)
+
+		if format == "jsonl" {
+			res.Header().Set(echo.HeaderContentType, "application/jsonl")
+			res.WriteHeader(http.StatusOK)
+
+			return lc.repo.Export(ctx, search, func(log logs.Log) error { //nolint:wrapcheck // the response is already sent
+				_, err := res.Write(append(log.Raw, '\n'))
+
+				return err //nolint:wrapcheck // see above
+			})
 		}
-		if filter.K1 != "" {
-			queryParams.F1 = fmt.Sprintf(`$.**.%s ? (@ like_regex "^.*%s.*" flag "i")`, filter.K1, filter.F1)
+
+		res.Header().Set(echo.HeaderContentType, "text/csv")
+		res.WriteHeader(http.StatusOK)
+
+		w := csv.NewWriter(res)
+		_ = w.Write([]string{"time", "level", "user_id", "trace_id", "msg", "attributes"})
+
+		err = lc.repo.Export(ctx, search, func(log logs.Log) error {
+			attrs, _ := json.Marshal(log.Attrs)
+
+			return w.Write([]string{ //nolint:wrapcheck // the response is already sent
+				log.Time.UTC().Format(time.RFC3339Nano), log.Level(), log.UserID, log.TraceID(), log.Msg(), string(attrs),
+			})
+		})
+		w.Flush()
+
+		return err //nolint:wrapcheck // the response is already sent
+	}).Name = "admin.logs.export"
+}
+
+// SaveSearch saves the current search of the admin under a name.
+func (lc *LogsController) SaveSearch() {
+	lc.r.POST("/searches", func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		rangeMinutes, _ := strconv.Atoi(c.FormValue("range"))
+
+		search := logs.SavedSearch{
+			UserID: auth.CurrentUserID(ctx),
+			Name:   c.FormValue("name"),
+			Query:  c.FormValue("q"),
+			Range:  rangeMinutes,
 		}
-		if filter.K2 != "" {
-			queryParams.F2 = fmt.Sprintf(`$.**.%s ? (@ like_regex "^.*%s.*" flag "i")`, filter.K2, filter.F2)
+
+		if err := search.Validate(); err != nil {
+			return fmt.Errorf("%w", err)
 		}
 
-		rawLogs, _ := lc.queries.GetRecentLogs(c.Request().Context(), queryParams)
+		if err := lc.repo.SaveSearch(ctx, search); err != nil {
+			return fmt.Errorf("%w", err)
+		}
 
-		var logs []log
-		for _, l := range rawLogs {
-			log := log{}
+		return lc.renderSavedSearches(c)
+	})
+}
 
-			json.Unmarshal(l.Log, &log.Log)
-			log.Time = l.Time.Time
-			log.UserID = l.UserID.UUID.String()
-			if (uuid.NullUUID{}) == l.UserID {
-				log.UserID = ""
-			}
+// DeleteSearch deletes a saved search of the admin.
+func (lc *LogsController) DeleteSearch() {
+	lc.r.POST("/searches/delete", func(c echo.Context) error {
+		ctx := c.Request().Context()
 
-			logs = append(logs, log)
+		err := lc.repo.DeleteSearch(ctx, auth.CurrentUserID(ctx), c.FormValue("name"))
+		if err != nil {
+			return fmt.Errorf("%w", err)
 		}
 
-		settingLevel, err := lc.settings.Setting(c.Request().Context(), alog.SettingLogLevel)
+		return lc.renderSavedSearches(c)
+	})
+}
+
+func (lc *LogsController) renderSavedSearches(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	searches, err := lc.repo.SavedSearches(ctx, auth.CurrentUserID(ctx))
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return sharedweb.Render(c, http.StatusOK, pages.ViewSavedSearches, searches)
+}
+
+func (lc *LogsController) ShowMaintenance() {
+	lc.r.GET("/maintenance", func(c echo.Context) error {
+		res, err := lc.appDI.GetLogRetention.H(c.Request().Context(), application.GetLogRetentionQuery{})
 		if err != nil {
-			return c.String(http.StatusBadRequest, err.Error())
+			return fmt.Errorf("%w", err)
 		}
 
-		vals := echo.Map{
-			"Title":     "Logs",
-			"SearchMsg": searchMsgParam,
-			"Logs":      logs,
-			"Filter":    filter,
-			"Settings": map[string]any{
-				// !!! assumes all loggers in all replicas are configured the same
-				"Enabled": alog.Unwrap(lc.logger).UsesSettings(),
-				"Level":   getLevelName(slog.Level(settingLevel.MustInt())),
+		return sharedweb.Render(c, http.StatusOK, pages.ViewLogsMaintenance, pages.LogsMaintenancePage{
+			Title:     "Log Maintenance",
+			Size:      pages.PresentLogsTableSize(res.Size, ""),
+			Retention: pages.PresentLogRetention(res.Policy, res.CanArchive),
+		})
+	}).Name = "admin.logs.maintenance"
+}
+
+// SaveRetention saves the retention policy, the maintenance job prunes the logs with.
+func (lc *LogsController) SaveRetention() {
+	lc.r.POST("/retention", func(c echo.Context) error {
+		days, _ := strconv.Atoi(c.FormValue("days"))
+		rows, _ := strconv.Atoi(c.FormValue("rows"))
+
+		err := lc.appDI.SaveLogRetention.H(c.Request().Context(), application.SaveLogRetentionCommand{
+			Policy: logs.RetentionPolicy{
+				MaxAgeDays: days,
+				MaxRows:    rows,
+				Archive:    c.FormValue("archive") == "true",
 			},
+		})
+		if err != nil {
+			return fmt.Errorf("%w", err)
 		}
 
-		if len(logs) == 0 {
-			vals["LastLogTime"] = time.Now()
+		res, err := lc.appDI.GetLogRetention.H(c.Request().Context(), application.GetLogRetentionQuery{})
+		if err != nil {
+			return fmt.Errorf("%w", err)
 		}
 
-		return c.Render(http.StatusOK, "logs.show", vals)
-	}).Name = "admin.logs"
+		return sharedweb.Render(c, http.StatusOK, pages.ViewLogRetention, pages.PresentLogRetention(res.Policy, res.CanArchive))
+	})
+}
+
+// PruneLogs deletes the logs older than the given days, independent of the retention policy.
+func (lc *LogsController) PruneLogs() {
+	lc.r.POST("/prune", func(c echo.Context) error {
+		days, err := strconv.Atoi(c.FormValue("days"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "invalid days").SetInternal(err)
+		}
+
+		res, err := lc.appDI.PruneLogs.H(c.Request().Context(), application.PruneLogsRequest{Days: days})
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		return sharedweb.Render(c, http.StatusOK, pages.ViewLogsTableSize,
+			pages.PresentLogsTableSize(res.Size, pages.PresentPruneResult(res.Deleted, res.Archive)),
+		)
+	})
+}
+
+func (lc *LogsController) VacuumLogs() {
+	lc.r.POST("/vacuum", func(c echo.Context) error {
+		ctx := c.Request().Context()
+
+		if err := lc.repo.Vacuum(ctx); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		size, err := lc.repo.TableSize(ctx)
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		return sharedweb.Render(c, http.StatusOK, pages.ViewLogsTableSize, pages.PresentLogsTableSize(size, "Vacuumed"))
+	})
 }
 
 func (lc *LogsController) SettingLogs() {
@@ -156,8 +378,9 @@ func (lc *LogsController) SettingLogs() {
 			return c.String(http.StatusBadRequest, err.Error())
 		}
 
-		return c.Render(http.StatusOK, "logs.show#level-setting", echo.Map{
-			"Level": getLevelName(slog.Level(level)),
+		return sharedweb.Render(c, http.StatusOK, pages.ViewLogLevel, pages.LogSettings{
+			Enabled: alog.Unwrap(lc.logger).UsesSettings(),
+			Level:   getLevelName(slog.Level(level)),
 		})
 	})
 }
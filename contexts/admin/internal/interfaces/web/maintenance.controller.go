@@ -0,0 +1,77 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/application"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/views/pages"
+	"github.com/go-arrower/skeleton/shared/infrastructure/maintenance"
+	sharedweb "github.com/go-arrower/skeleton/shared/infrastructure/web"
+)
+
+func NewMaintenanceController(appDI application.App, routes *echo.Group) *MaintenanceController {
+	return &MaintenanceController{
+		appDI: appDI,
+		r:     routes,
+	}
+}
+
+type MaintenanceController struct {
+	appDI application.App
+	r     *echo.Group
+}
+
+func (mc *MaintenanceController) ShowMaintenance() {
+	mc.r.GET("/maintenance", func(c echo.Context) error {
+		res, err := mc.appDI.GetMaintenance.H(c.Request().Context(), application.GetMaintenanceQuery{})
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		return sharedweb.Render(c, http.StatusOK, pages.ViewMaintenance, pages.MaintenancePage{
+			Title: "Maintenance",
+			Mode:  pages.PresentMaintenanceMode(res.Mode),
+		})
+	}).Name = "admin.maintenance"
+}
+
+// SaveMaintenance turns the maintenance of all instances on or off.
+func (mc *MaintenanceController) SaveMaintenance() {
+	mc.r.POST("/maintenance", func(c echo.Context) error {
+		retryAfter, _ := strconv.Atoi(c.FormValue("retry_after"))
+
+		var allowedIPs []string
+
+		for _, ip := range strings.Split(c.FormValue("allowed_ips"), ",") {
+			if ip = strings.TrimSpace(ip); ip != "" {
+				allowedIPs = append(allowedIPs, ip)
+			}
+		}
+
+		err := mc.appDI.SaveMaintenance.H(c.Request().Context(), application.SaveMaintenanceCommand{
+			Mode: maintenance.Mode{
+				Message:    strings.TrimSpace(c.FormValue("message")),
+				AllowedIPs: allowedIPs,
+				RetryAfter: time.Duration(retryAfter) * time.Second,
+				Enabled:    c.FormValue("enabled") == "true",
+				PauseJobs:  c.FormValue("pause_jobs") == "true",
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		res, err := mc.appDI.GetMaintenance.H(c.Request().Context(), application.GetMaintenanceQuery{})
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		return sharedweb.Render(c, http.StatusOK, pages.ViewMaintenanceMode, pages.PresentMaintenanceMode(res.Mode))
+	})
+}
@@ -0,0 +1,93 @@
+package pages
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/go-arrower/skeleton/shared/views/components"
+)
+
+// ComponentsPage shows all shared UI components with sample data, so contexts can be styled consistently.
+type ComponentsPage struct {
+	Title   string
+	Table   components.Table
+	Fields  []components.Field
+	Confirm components.Confirm
+	Flashes []string
+	KPIs    []components.KPI
+}
+
+type sampleRow struct {
+	name    string
+	queue   string
+	pending int
+}
+
+// PresentComponents returns the gallery with sample data. The table is sorted and paginated by the query.
+func PresentComponents(query url.Values) ComponentsPage {
+	table := components.NewTable("sample-table", "/admin/components", query,
+		components.Column{Key: "name", Label: "Job Type", Sortable: true},
+		components.Column{Key: "queue", Label: "Queue", Sortable: true},
+		components.Column{Key: "pending", Label: "Pending", Sortable: true},
+		components.Column{Key: "", Label: "Description", Sortable: false},
+	)
+	table.PageSize = 5
+
+	rows := make([]sampleRow, 12)
+	for i := range rows {
+		rows[i] = sampleRow{
+			name:    fmt.Sprintf("SendMail%02d", i+1),
+			queue:   []string{"Default", "Mail", "Reports"}[i%3],
+			pending: (i * 7) % 10,
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		var less bool
+
+		switch table.Sort {
+		case "queue":
+			less = rows[i].queue < rows[j].queue
+		case "pending":
+			less = rows[i].pending < rows[j].pending
+		default:
+			less = rows[i].name < rows[j].name
+		}
+
+		if table.Desc {
+			return !less
+		}
+
+		return less
+	})
+
+	table.Total = len(rows)
+
+	for _, r := range rows[min(table.Offset(), len(rows)):min(table.Offset()+table.PageSize, len(rows))] {
+		table.Rows = append(table.Rows, []string{r.name, r.queue, strconv.Itoa(r.pending), "a sample job"})
+	}
+
+	return ComponentsPage{
+		Title: "Components",
+		Table: table,
+		Fields: []components.Field{
+			{Label: "Email", Name: "email", Type: "email", Placeholder: "user@example.com", Required: true},
+			{Label: "Password", Name: "password", Type: "password", Error: "Password must be at least 8 characters in length"},
+		},
+		Confirm: components.Confirm{
+			ID:      "confirm-sample",
+			Button:  "Delete",
+			Title:   "Delete all jobs?",
+			Message: "This removes all pending jobs of the queue and can not be undone.",
+			Action:  "/admin/components",
+			Target:  "",
+		},
+		Flashes: []string{"Saved successfully", "Job scheduled"},
+		KPIs: []components.KPI{
+			{Title: "Pending Jobs", Value: "1,337", Description: "in all queues"},
+			{Title: "Failure Rate", Value: "0.4%", Description: "of the last hour"},
+		},
+	}
+}
@@ -0,0 +1,78 @@
+package pages
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/feature"
+)
+
+type FlagsPage struct {
+	Title string
+	Flags []FeatureFlag
+}
+
+type FlagPage struct {
+	Title   string
+	Flag    FeatureFlag
+	History []FlagChange
+}
+
+type FeatureFlag struct {
+	Key         string
+	Description string
+	Enabled     bool
+	Percentage  int
+	Users       string
+	Tenants     string
+	Superusers  bool
+	UpdatedFmt  string
+	UpdatedBy   string
+}
+
+type FlagChange struct {
+	Flag    FeatureFlag
+	Deleted bool
+}
+
+func PresentFlags(flags []feature.Flag, loc *time.Location) []FeatureFlag {
+	f := make([]FeatureFlag, len(flags))
+
+	for i, flag := range flags {
+		f[i] = PresentFlag(flag, loc)
+	}
+
+	return f
+}
+
+func PresentFlag(flag feature.Flag, loc *time.Location) FeatureFlag {
+	updated := "-"
+	if !flag.UpdatedAt.IsZero() {
+		updated = formatAsDateOrTimeToday(flag.UpdatedAt, loc)
+	}
+
+	return FeatureFlag{
+		Key:         flag.Key,
+		Description: flag.Description,
+		Enabled:     flag.Enabled,
+		Percentage:  flag.Percentage,
+		Users:       strings.Join(flag.Users, ", "),
+		Tenants:     strings.Join(flag.Tenants, ", "),
+		Superusers:  flag.Superusers,
+		UpdatedFmt:  updated,
+		UpdatedBy:   flag.UpdatedBy,
+	}
+}
+
+func PresentFlagHistory(history []feature.Change, loc *time.Location) []FlagChange {
+	h := make([]FlagChange, len(history))
+
+	for i, change := range history {
+		h[i] = FlagChange{
+			Flag:    PresentFlag(change.Flag, loc),
+			Deleted: change.Deleted,
+		}
+	}
+
+	return h
+}
@@ -0,0 +1,4 @@
+package pages
+
+// HomePage is the dashboard of the admin. It has no data of its own.
+type HomePage struct{}
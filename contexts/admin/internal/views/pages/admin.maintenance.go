@@ -0,0 +1,31 @@
+package pages
+
+import (
+	"strings"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/maintenance"
+)
+
+type MaintenancePage struct {
+	Title string
+	Mode  MaintenanceMode
+}
+
+type MaintenanceMode struct {
+	Message    string
+	AllowedIPs string
+	// RetryAfter is in seconds.
+	RetryAfter int
+	Enabled    bool
+	PauseJobs  bool
+}
+
+func PresentMaintenanceMode(mode maintenance.Mode) MaintenanceMode {
+	return MaintenanceMode{
+		Message:    mode.Message,
+		AllowedIPs: strings.Join(mode.AllowedIPs, ", "),
+		RetryAfter: int(mode.RetryAfter.Seconds()),
+		Enabled:    mode.Enabled,
+		PauseJobs:  mode.PauseJobs,
+	}
+}
@@ -0,0 +1,28 @@
+package pages
+
+import (
+	"sort"
+
+	"github.com/labstack/echo/v4"
+)
+
+type RoutesPage struct {
+	Routes []*echo.Route
+}
+
+// PresentRoutes sorts the routes by path and then by method.
+func PresentRoutes(routes []*echo.Route) RoutesPage {
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path < routes[j].Path {
+			return true
+		}
+
+		if routes[i].Path == routes[j].Path {
+			return routes[i].Method < routes[j].Method
+		}
+
+		return false
+	})
+
+	return RoutesPage{Routes: routes}
+}
@@ -10,8 +10,10 @@ import (
 	"github.com/go-arrower/skeleton/contexts/admin/internal/application"
 )
 
-func formatAsDateOrTimeToday(t time.Time) string {
-	now := time.Now()
+// formatAsDateOrTimeToday formats the time in the time zone loc, showing the date only if it is not today.
+func formatAsDateOrTimeToday(t time.Time, loc *time.Location) string {
+	t = t.In(loc)
+	now := time.Now().In(loc)
 	isToday := t.Year() == now.Year() && t.Month() == now.Month() && t.Day() == now.Day()
 
 	createdAt := t.Format("2006.01.02 15:04")
@@ -66,6 +68,22 @@ func TimeAgo(t time.Time) string {
 	}
 }
 
+// FormatDuration rounds a duration to a precision, that is readable for its size.
+func FormatDuration(d time.Duration) string {
+	switch {
+	case d == 0:
+		return "-"
+	case d < time.Millisecond:
+		return d.Round(time.Microsecond).String()
+	case d < time.Second:
+		return d.Round(time.Millisecond).String()
+	case d < time.Minute:
+		return d.Round(10 * time.Millisecond).String()
+	default:
+		return d.Round(time.Second).String()
+	}
+}
+
 func prettyJobPayloadAsFormattedJSON(p []byte) string {
 	return prettyJSON(p)
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/go-arrower/skeleton/contexts/admin/internal/views/pages"
+	"github.com/go-arrower/skeleton/shared/infrastructure/cron"
 )
 
 func TestTimeAgo(t *testing.T) {
@@ -37,3 +38,42 @@ func TestTimeAgo(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatDuration(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		duration time.Duration
+		expected string
+	}{
+		"zero":         {0, "-"},
+		"microseconds": {1234 * time.Nanosecond, "1µs"},
+		"milliseconds": {1234567 * time.Nanosecond, "1ms"},
+		"seconds":      {1234567890 * time.Nanosecond, "1.23s"},
+		"minutes":      {90*time.Second + 400*time.Millisecond, "1m30s"},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, pages.FormatDuration(tt.duration))
+		})
+	}
+}
+
+func TestPresentSchedule(t *testing.T) {
+	t.Parallel()
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	assert.NoError(t, err)
+
+	nextRun := time.Date(2020, 1, 1, 12, 30, 0, 0, time.UTC)
+
+	schedule := pages.PresentSchedule(cron.Schedule{NextRunAt: nextRun}, berlin)
+	assert.Equal(t, "2020.01.01 13:30", schedule.NextRunFmt)
+
+	schedule = pages.PresentSchedule(cron.Schedule{NextRunAt: nextRun}, time.UTC)
+	assert.Equal(t, "2020.01.01 12:30", schedule.NextRunFmt)
+}
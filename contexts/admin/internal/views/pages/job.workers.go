@@ -1,11 +1,61 @@
 package pages
 
+import (
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+)
+
+type WorkersPage struct {
+	Title    string
+	Workers  []JobWorker
+	Controls []QueueControls
+}
+
 type JobWorker struct {
 	ID                      string
 	Queue                   string
+	Role                    string
 	NotSeenSince            string
 	Version                 string
 	JobTypes                []string
 	Workers                 int
 	LastSeenAtColourSuccess bool
 }
+
+type QueueControls struct {
+	Queue    string
+	JobTypes []JobTypeControls
+	Workers  int // zero is the default of the queue
+	Paused   bool
+}
+
+type JobTypeControls struct {
+	JobType string
+	Paused  bool
+}
+
+func PresentQueueControls(controls []jobs.QueueControls) []QueueControls {
+	c := make([]QueueControls, len(controls))
+
+	for i, control := range controls {
+		c[i] = PresentQueueControl(control)
+	}
+
+	return c
+}
+
+func PresentQueueControl(control jobs.QueueControls) QueueControls {
+	jobTypes := make([]JobTypeControls, len(control.JobTypes))
+	for i, jt := range control.JobTypes {
+		jobTypes[i] = JobTypeControls{
+			JobType: string(jt),
+			Paused:  control.PausedJobTypes[jt],
+		}
+	}
+
+	return QueueControls{
+		Queue:    string(control.Queue),
+		Workers:  control.Workers,
+		JobTypes: jobTypes,
+		Paused:   control.Paused,
+	}
+}
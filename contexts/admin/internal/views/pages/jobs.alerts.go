@@ -0,0 +1,112 @@
+package pages
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+)
+
+type AlertRule struct {
+	Name      string
+	Queue     string
+	JobType   string
+	Condition string
+	Channels  string
+	For       string
+}
+
+func PresentAlertRules(rules []jobs.AlertRule) []AlertRule {
+	r := make([]AlertRule, len(rules))
+
+	for i, rule := range rules {
+		jobType := "all"
+		if rule.JobType != "" {
+			jobType = string(rule.JobType)
+		}
+
+		channels := "log"
+		if len(rule.Channels) > 0 {
+			channels = strings.Join(rule.Channels, ", ")
+		}
+
+		duration := "immediately"
+		if rule.For > 0 {
+			duration = rule.For.String()
+		}
+
+		r[i] = AlertRule{
+			Name:      rule.Name,
+			Queue:     string(rule.Queue),
+			JobType:   jobType,
+			Condition: presentCondition(rule),
+			Channels:  channels,
+			For:       duration,
+		}
+	}
+
+	return r
+}
+
+func presentCondition(rule jobs.AlertRule) string {
+	switch rule.Condition {
+	case jobs.ConditionPendingAbove:
+		return fmt.Sprintf("more than %.0f pending jobs", rule.Threshold)
+	case jobs.ConditionFailureRateAbove:
+		return fmt.Sprintf("failure rate above %.1f%%", rule.Threshold)
+	case jobs.ConditionNoWorkers:
+		return "no workers"
+	default:
+		return string(rule.Condition)
+	}
+}
+
+type AlertsPage struct {
+	Title    string
+	Active   []Alert
+	Resolved []Alert
+	Rules    []AlertRule
+	Queues   jobs.QueueNames
+}
+
+type Alert struct {
+	Rule     string
+	Queue    string
+	Message  string
+	Since    string
+	Resolved string
+	Firing   bool
+}
+
+// PresentAlerts splits the alerts into the open and the resolved ones.
+func PresentAlerts(alerts []jobs.Alert) ([]Alert, []Alert) {
+	open := []Alert{}
+	resolved := []Alert{}
+
+	for _, alert := range alerts {
+		since := alert.PendingSince
+		if alert.IsFiring() || alert.IsResolved() {
+			since = alert.FiredAt
+		}
+
+		a := Alert{
+			Rule:     alert.Rule,
+			Queue:    string(alert.Queue),
+			Message:  alert.Message,
+			Since:    TimeAgo(since),
+			Resolved: "",
+			Firing:   alert.IsFiring(),
+		}
+
+		if alert.IsResolved() {
+			a.Resolved = TimeAgo(alert.ResolvedAt)
+			resolved = append(resolved, a)
+
+			continue
+		}
+
+		open = append(open, a)
+	}
+
+	return open, resolved
+}
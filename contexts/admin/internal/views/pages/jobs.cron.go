@@ -0,0 +1,65 @@
+package pages
+
+import (
+	"time"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+	"github.com/go-arrower/skeleton/shared/infrastructure/cron"
+)
+
+type CronPage struct {
+	Title     string
+	Schedules []Schedule
+	Queues    jobs.QueueNames
+}
+
+type Schedule struct {
+	Name        string
+	Spec        string
+	Queue       string
+	JobType     string
+	Payload     string
+	LastRunFmt  string
+	NextRunFmt  string
+	Paused      bool
+	IsRemovable bool
+}
+
+func PresentSchedules(schedules []cron.Schedule, loc *time.Location) []Schedule {
+	s := make([]Schedule, len(schedules))
+
+	for i, schedule := range schedules {
+		s[i] = PresentSchedule(schedule, loc)
+	}
+
+	return s
+}
+
+func PresentSchedule(schedule cron.Schedule, loc *time.Location) Schedule {
+	queue := schedule.Queue
+	if queue == "" {
+		queue = string(jobs.DefaultQueueName)
+	}
+
+	lastRun := "never"
+	if !schedule.LastRunAt.IsZero() {
+		lastRun = TimeAgo(schedule.LastRunAt)
+	}
+
+	nextRun := "-"
+	if !schedule.Paused && !schedule.NextRunAt.IsZero() {
+		nextRun = formatAsDateOrTimeToday(schedule.NextRunAt, loc)
+	}
+
+	return Schedule{
+		Name:        schedule.Name,
+		Spec:        schedule.Spec,
+		Queue:       queue,
+		JobType:     schedule.JobType,
+		Payload:     prettyJSON([]byte(schedule.Payload)),
+		LastRunFmt:  lastRun,
+		NextRunFmt:  nextRun,
+		Paused:      schedule.Paused,
+		IsRemovable: schedule.Source == cron.SourceAdmin,
+	}
+}
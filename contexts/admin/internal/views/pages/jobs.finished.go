@@ -3,14 +3,29 @@ package pages
 import (
 	"encoding/json"
 
-	"github.com/labstack/echo/v4"
-
 	"github.com/go-arrower/skeleton/contexts/admin/internal/application"
 	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
 )
 
-func NewFinishedJobs(jobs []jobs.PendingJob, queues jobs.QueueNames) echo.Map {
-	type finishedJob struct {
+type (
+	FinishedJobsPage struct {
+		Title    string
+		Queues   jobs.QueueNames
+		JobTypes KnownJobTypes
+		List     FinishedJobsList
+	}
+
+	FinishedJobsList struct {
+		Jobs []FinishedJob
+	}
+
+	// KnownJobTypes are the options to filter the finished jobs by.
+	KnownJobTypes struct {
+		JobType  []string
+		Selected string
+	}
+
+	FinishedJob struct {
 		EnqueuedAtFmt string
 		FinishedAtFmt string
 		ID            string
@@ -18,8 +33,19 @@ func NewFinishedJobs(jobs []jobs.PendingJob, queues jobs.QueueNames) echo.Map {
 		Queue         string
 		Payload       string
 	}
+)
+
+func NewFinishedJobs(jobs []jobs.PendingJob, queues jobs.QueueNames) FinishedJobsPage {
+	return FinishedJobsPage{
+		Title:    "Finished Jobs",
+		Queues:   queues,
+		JobTypes: KnownJobTypes{JobType: nil, Selected: ""},
+		List:     PresentFinishedJobs(jobs),
+	}
+}
 
-	fjobs := make([]finishedJob, len(jobs))
+func PresentFinishedJobs(jobs []jobs.PendingJob) FinishedJobsList {
+	fjobs := make([]FinishedJob, len(jobs))
 
 	for i := 0; i < len(jobs); i++ {
 		var m application.JobPayload
@@ -33,8 +59,5 @@ func NewFinishedJobs(jobs []jobs.PendingJob, queues jobs.QueueNames) echo.Map {
 		fjobs[i].Queue = jobs[i].Queue
 	}
 
-	return echo.Map{
-		"Jobs":   fjobs,
-		"Queues": queues,
-	}
+	return FinishedJobsList{Jobs: fjobs}
 }
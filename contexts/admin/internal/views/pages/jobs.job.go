@@ -1,9 +1,21 @@
 package pages
 
 import (
+	"encoding/json"
+	"time"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/application"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
 	"github.com/go-arrower/skeleton/contexts/admin/internal/interfaces/repository/models"
 )
 
+type JobPage struct {
+	Title   string
+	Jobs    []HistoricJob
+	Pending *EditableJob
+	Queues  jobs.QueueNames
+}
+
 type HistoricJob struct {
 	models.ArrowerGueJobsHistory
 	PrettyPayload string
@@ -12,14 +24,14 @@ type HistoricJob struct {
 	FinishedAgo   string
 }
 
-func ConvertFinishedJobsForShow(jobs []models.ArrowerGueJobsHistory) []HistoricJob {
+func ConvertFinishedJobsForShow(jobs []models.ArrowerGueJobsHistory, loc *time.Location) []HistoricJob {
 	fjobs := make([]HistoricJob, len(jobs))
 
 	for i, j := range jobs {
 		fjobs[i] = HistoricJob{
 			ArrowerGueJobsHistory: j,
 			PrettyPayload:         prettyJobPayloadAsFormattedJSON(j.Args),
-			CreatedAt:             formatAsDateOrTimeToday(j.CreatedAt.Time),
+			CreatedAt:             formatAsDateOrTimeToday(j.CreatedAt.Time, loc),
 			EnqueuedAgo:           TimeAgo(j.CreatedAt.Time),
 			FinishedAgo:           TimeAgo(j.FinishedAt.Time),
 		}
@@ -27,3 +39,48 @@ func ConvertFinishedJobsForShow(jobs []models.ArrowerGueJobsHistory) []HistoricJ
 
 	return fjobs
 }
+
+type EditableJob struct {
+	ID         string
+	Type       string
+	Queue      string
+	Payload    string
+	Examples   []string
+	ErrorCount int32
+	Priority   int16
+	Parked     bool
+	Paused     bool
+}
+
+// PresentEditableJob prepares a pending job to be changed by an admin.
+// Only the job data of the payload is editable, examples are the job data of previous jobs of the same type.
+func PresentEditableJob(job jobs.PendingJob, examples [][]byte) EditableJob {
+	var payload application.JobPayload
+	_ = json.Unmarshal([]byte(job.Payload), &payload)
+
+	prettyExamples := make([]string, len(examples))
+
+	for i, e := range examples {
+		var example application.JobPayload
+		_ = json.Unmarshal(e, &example)
+
+		prettyExamples[i] = prettyJobPayloadDataAsFormattedJSON(example)
+	}
+
+	queue := job.Queue
+	if queue == "" {
+		queue = string(jobs.DefaultQueueName)
+	}
+
+	return EditableJob{
+		ID:         job.ID,
+		Type:       job.Type,
+		Queue:      queue,
+		Payload:    prettyJobPayloadDataAsFormattedJSON(payload),
+		Examples:   prettyExamples,
+		ErrorCount: job.ErrorCount,
+		Priority:   job.Priority,
+		Parked:     job.RunAt.IsZero(),
+		Paused:     job.Paused,
+	}
+}
@@ -0,0 +1,13 @@
+package pages
+
+type JobsMaintenancePage struct {
+	Title string
+	Size  JobsTableSize
+	// Queues are the names of all queues, with "Default" for the default queue.
+	Queues []string
+}
+
+type JobsTableSize struct {
+	Jobs    string
+	History string
+}
@@ -0,0 +1,98 @@
+package pages
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+)
+
+type JobMetrics struct {
+	Queue    string
+	Window   string
+	Windows  []string
+	JobTypes []JobTypeMetrics
+	Chart    MetricsChart
+}
+
+type JobTypeMetrics struct {
+	JobType     string
+	Throughput  string
+	FailureRate string
+	LatencyP50  string
+	LatencyP95  string
+	LatencyP99  string
+	WaitP50     string
+	WaitP95     string
+	WaitP99     string
+	Processed   int
+	Failed      int
+}
+
+// MetricsChart is the time series of a queue, in the format echarts expects.
+type MetricsChart struct {
+	XAxis      []string
+	Processed  []int
+	Failed     []int
+	LatencyP95 []float64 // seconds
+	WaitP95    []float64 // seconds
+}
+
+func PresentJobMetrics(
+	queue string,
+	window jobs.MetricsWindow,
+	metrics []jobs.JobTypeMetrics,
+	series []jobs.MetricsBucket,
+	loc *time.Location,
+) JobMetrics {
+	windows := make([]string, len(jobs.MetricsWindows))
+	for i, w := range jobs.MetricsWindows {
+		windows[i] = w.Name
+	}
+
+	jobTypes := make([]JobTypeMetrics, len(metrics))
+	for i, m := range metrics {
+		jobTypes[i] = JobTypeMetrics{
+			JobType:     string(m.JobType),
+			Throughput:  fmt.Sprintf("%.2f/min", m.Throughput(window.Range)),
+			FailureRate: fmt.Sprintf("%.1f%%", m.FailureRate()),
+			LatencyP50:  FormatDuration(m.Latency.P50),
+			LatencyP95:  FormatDuration(m.Latency.P95),
+			LatencyP99:  FormatDuration(m.Latency.P99),
+			WaitP50:     FormatDuration(m.WaitTime.P50),
+			WaitP95:     FormatDuration(m.WaitTime.P95),
+			WaitP99:     FormatDuration(m.WaitTime.P99),
+			Processed:   m.Processed,
+			Failed:      m.Failed,
+		}
+	}
+
+	layout := "15:04"
+	if window.Bin >= 24*time.Hour {
+		layout = "01.02"
+	}
+
+	chart := MetricsChart{
+		XAxis:      make([]string, len(series)),
+		Processed:  make([]int, len(series)),
+		Failed:     make([]int, len(series)),
+		LatencyP95: make([]float64, len(series)),
+		WaitP95:    make([]float64, len(series)),
+	}
+
+	for i, b := range series {
+		chart.XAxis[i] = b.Time.In(loc).Format(layout)
+		chart.Processed[i] = b.Processed
+		chart.Failed[i] = b.Failed
+		chart.LatencyP95[i] = b.LatencyP95.Seconds()
+		chart.WaitP95[i] = b.WaitP95.Seconds()
+	}
+
+	return JobMetrics{
+		Queue:    queue,
+		Window:   window.Name,
+		Windows:  windows,
+		JobTypes: jobTypes,
+		Chart:    chart,
+	}
+}
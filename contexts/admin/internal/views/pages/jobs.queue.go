@@ -0,0 +1,111 @@
+package pages
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/application"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+)
+
+type (
+	QueueStats struct {
+		PendingJobsPerType   map[string]int
+		QueueName            string
+		PendingJobs          int
+		FailedJobs           int
+		ProcessedJobs        int
+		AvailableWorkers     int
+		PendingJobsErrorRate float64 // can be calculated: FailedJobs * 100 / PendingJobs
+		AverageTimePerJob    time.Duration
+		EstimateUntilEmpty   time.Duration // can be calculated
+	}
+
+	QueuesPage struct {
+		Title  string
+		Queues map[jobs.QueueName]jobs.QueueStats
+	}
+
+	QueuePage struct {
+		Title     string
+		Jobs      []jobs.PendingJob
+		QueueName string
+		Stats     QueueStats
+	}
+)
+
+func PresentQueue(queue string, jobs []jobs.PendingJob, kpis jobs.QueueKPIs) QueuePage {
+	jobs = prettyFormatPayload(jobs)
+
+	return QueuePage{
+		Title:     queue + " queue",
+		QueueName: queue,
+		Stats:     queueKpiToStats(queue, kpis),
+
+		Jobs: jobs,
+	}
+}
+
+func prettyFormatPayload(pJobs []jobs.PendingJob) []jobs.PendingJob {
+	for i := 0; i < len(pJobs); i++ { //nolint:varnamelen
+		var m application.JobPayload
+
+		_ = json.Unmarshal([]byte(pJobs[i].Payload), &m)
+		data, _ := json.Marshal(m.JobData)
+
+		var prettyJSON bytes.Buffer
+
+		if err := json.Indent(&prettyJSON, data, "", "  "); err != nil {
+		}
+
+		if pJobs[i].Queue == "" {
+			pJobs[i].Queue = string(jobs.DefaultQueueName)
+		}
+		pJobs[i].Payload = prettyJSON.String()
+		pJobs[i].RunAtFmt = fmtRunAtTime(pJobs[i].RunAt)
+
+		if pJobs[i].RunAt.IsZero() {
+			pJobs[i].RunAtFmt = "parked"
+		}
+	}
+
+	return pJobs
+}
+
+func fmtRunAtTime(tme time.Time) string {
+	now := time.Now()
+
+	isToday := tme.Year() == now.Year() && tme.Month() == now.Month() && tme.Day() == now.Day()
+	if isToday {
+		return fmt.Sprintf("%02d:%02d", tme.Hour(), tme.Minute())
+	}
+
+	return tme.Format("2006.01.02 15:04")
+}
+
+func queueKpiToStats(queue string, kpis jobs.QueueKPIs) QueueStats {
+	var errorRate float64
+
+	if kpis.FailedJobs != 0 {
+		errorRate = float64(kpis.FailedJobs * 100 / kpis.PendingJobs)
+	}
+
+	var duration time.Duration
+	if kpis.AvailableWorkers != 0 {
+		duration = time.Duration(kpis.PendingJobs/kpis.AvailableWorkers) * kpis.AverageTimePerJob
+	}
+
+	return QueueStats{
+		QueueName:            queue,
+		PendingJobs:          kpis.PendingJobs,
+		PendingJobsPerType:   kpis.PendingJobsPerType,
+		FailedJobs:           kpis.FailedJobs,
+		ProcessedJobs:        kpis.ProcessedJobs,
+		AvailableWorkers:     kpis.AvailableWorkers,
+		PendingJobsErrorRate: errorRate,
+		AverageTimePerJob:    kpis.AverageTimePerJob.Truncate(time.Second),
+		EstimateUntilEmpty:   duration.Truncate(time.Second),
+	}
+}
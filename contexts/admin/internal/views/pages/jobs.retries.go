@@ -0,0 +1,69 @@
+package pages
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+)
+
+type RetriesPage struct {
+	Title    string
+	Policies []RetryPolicy
+	Queues   jobs.QueueNames
+}
+
+type RetryPolicy struct {
+	Queue       string
+	JobType     string
+	Backoff     string
+	MaxAttempts string
+	BaseDelay   string
+	MaxDelay    string
+	Preview     string
+}
+
+func PresentRetryPolicies(policies []jobs.RetryPolicy) []RetryPolicy {
+	p := make([]RetryPolicy, len(policies))
+
+	for i, policy := range policies {
+		maxAttempts := "unlimited"
+		if policy.MaxAttempts > 0 {
+			maxAttempts = strconv.Itoa(policy.MaxAttempts)
+		}
+
+		maxDelay := "-"
+		if policy.MaxDelay > 0 {
+			maxDelay = policy.MaxDelay.String()
+		}
+
+		p[i] = RetryPolicy{
+			Queue:       string(policy.Queue),
+			JobType:     string(policy.JobType),
+			Backoff:     string(policy.Backoff),
+			MaxAttempts: maxAttempts,
+			BaseDelay:   policy.BaseDelay.String(),
+			MaxDelay:    maxDelay,
+			Preview:     previewRetries(policy),
+		}
+	}
+
+	return p
+}
+
+// previewRetries shows the delays of the first retries, so an admin can judge the policy at a glance.
+func previewRetries(policy jobs.RetryPolicy) string {
+	const previewAttempts = 5
+
+	delays := []string{}
+
+	for attempt := 1; attempt <= previewAttempts; attempt++ {
+		if policy.Exhausted(attempt) {
+			return strings.Join(append(delays, "stop"), ", ")
+		}
+
+		delays = append(delays, policy.Delay(attempt).String())
+	}
+
+	return strings.Join(append(delays, "…"), ", ")
+}
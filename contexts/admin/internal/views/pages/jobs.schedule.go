@@ -3,13 +3,28 @@ package pages
 import (
 	"encoding/json"
 
-	"github.com/labstack/echo/v4"
-
 	"github.com/go-arrower/skeleton/contexts/admin/internal/application"
 	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
 )
 
-func PresentJobsExamplePayloads(queue, jobType string, payloads [][]byte) echo.Map {
+type SchedulePage struct {
+	Title    string
+	Queues   jobs.QueueNames
+	JobTypes []jobs.JobType
+	// RunAt and RunAtMin are formatted for a datetime-local input.
+	RunAt    string
+	RunAtMin string
+	Payloads PayloadExamples
+}
+
+// PayloadExamples are the payloads of the last jobs of a type, to copy from when scheduling a new one.
+type PayloadExamples struct {
+	Queue    string
+	JobType  string
+	Payloads []string
+}
+
+func PresentJobsExamplePayloads(queue, jobType string, payloads [][]byte) PayloadExamples {
 	prettyPayloads := make([]string, len(payloads))
 
 	for i, p := range payloads {
@@ -23,9 +38,9 @@ func PresentJobsExamplePayloads(queue, jobType string, payloads [][]byte) echo.M
 		queue = string(jobs.DefaultQueueName)
 	}
 
-	return echo.Map{
-		"Queue":    queue,
-		"JobType":  jobType,
-		"Payloads": prettyPayloads,
+	return PayloadExamples{
+		Queue:    queue,
+		JobType:  jobType,
+		Payloads: prettyPayloads,
 	}
 }
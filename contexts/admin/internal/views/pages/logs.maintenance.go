@@ -0,0 +1,56 @@
+package pages
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/logs"
+)
+
+type LogsMaintenancePage struct {
+	Title     string
+	Size      LogsTableSize
+	Retention LogRetention
+}
+
+type LogsTableSize struct {
+	Logs string
+	Size string
+	// Message tells the admin the result of the last operation, if any.
+	Message string
+}
+
+type LogRetention struct {
+	MaxAgeDays int
+	MaxRows    int
+	Archive    bool
+	CanArchive bool
+	Enabled    bool
+}
+
+func PresentLogsTableSize(size logs.TableSize, message string) LogsTableSize {
+	return LogsTableSize{
+		Logs:    strconv.FormatInt(size.Logs, 10),
+		Size:    size.Size,
+		Message: message,
+	}
+}
+
+func PresentLogRetention(policy logs.RetentionPolicy, canArchive bool) LogRetention {
+	return LogRetention{
+		MaxAgeDays: policy.MaxAgeDays,
+		MaxRows:    policy.MaxRows,
+		Archive:    policy.Archive,
+		CanArchive: canArchive,
+		Enabled:    policy.IsEnabled(),
+	}
+}
+
+// PresentPruneResult describes what a prune did, e.g. to show it next to the new table size.
+func PresentPruneResult(deleted int64, archive string) string {
+	if archive == "" {
+		return fmt.Sprintf("Deleted %d logs", deleted)
+	}
+
+	return fmt.Sprintf("Deleted %d logs, archived to %s", deleted, archive)
+}
@@ -0,0 +1,31 @@
+package pages
+
+import "github.com/go-arrower/skeleton/contexts/admin/internal/domain/logs"
+
+type LogsPage struct {
+	Title      string
+	Filter     LogsFilter
+	Logs       []logs.Log
+	QueryError string
+	// LastLogTime and LastLogHash are the cursor of the last log shown, to tail the logs from.
+	LastLogTime string
+	LastLogHash string
+	Searches    []logs.SavedSearch
+	Settings    LogSettings
+}
+
+// LogsFilter is the search of the admin, as it is shown in the search form.
+type LogsFilter struct {
+	Query string
+	// Range is the number of minutes before now.
+	Range int
+	From  string
+	To    string
+	Limit int
+}
+
+type LogSettings struct {
+	// Enabled is true, if the level of the loggers can be changed by a setting.
+	Enabled bool
+	Level   string
+}
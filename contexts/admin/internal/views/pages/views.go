@@ -0,0 +1,78 @@
+package pages
+
+import (
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/jobs"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/domain/logs"
+	"github.com/go-arrower/skeleton/shared/infrastructure/web"
+)
+
+var (
+	ViewCron             = web.NewView[CronPage]("jobs.cron")
+	ViewCronSchedule     = web.NewView[Schedule]("jobs.cron#schedule")
+	ViewWorkers          = web.NewView[WorkersPage]("jobs.workers")
+	ViewQueueControls    = web.NewView[QueueControls]("jobs.workers#queue-controls")
+	ViewQueueMetrics     = web.NewView[JobMetrics]("jobs.queue#metrics")
+	ViewAlerts           = web.NewView[AlertsPage]("jobs.alerts")
+	ViewComponents       = web.NewView[ComponentsPage]("admin.components")
+	ViewLogsMaintenance  = web.NewView[LogsMaintenancePage]("logs.maintenance")
+	ViewLogsTableSize    = web.NewView[LogsTableSize]("logs.maintenance#table-size")
+	ViewLogRetention     = web.NewView[LogRetention]("logs.maintenance#retention")
+	ViewMaintenance      = web.NewView[MaintenancePage]("admin.maintenance")
+	ViewMaintenanceMode  = web.NewView[MaintenanceMode]("admin.maintenance#mode")
+	ViewFlags            = web.NewView[FlagsPage]("admin.flags")
+	ViewFlagRow          = web.NewView[FeatureFlag]("admin.flags#flag")
+	ViewFlag             = web.NewView[FlagPage]("admin.flag")
+	ViewAdminHome        = web.NewView[HomePage]("admin.home")
+	ViewRoutes           = web.NewView[RoutesPage]("admin.routes")
+	ViewQueues           = web.NewView[QueuesPage]("jobs.home")
+	ViewQueue            = web.NewView[QueuePage]("jobs.queue")
+	ViewJob              = web.NewView[JobPage]("jobs.job")
+	ViewFinishedJobs     = web.NewView[FinishedJobsPage]("jobs.finished")
+	ViewFinishedJobsList = web.NewView[FinishedJobsList]("jobs.finished#jobs.list")
+	ViewFinishedJobTypes = web.NewView[KnownJobTypes]("jobs.finished#known-job-types")
+	ViewJobsMaintenance  = web.NewView[JobsMaintenancePage]("jobs.maintenance")
+	ViewJobsTableSize    = web.NewView[JobsTableSize]("jobs.maintenance#table-size")
+	ViewRetries          = web.NewView[RetriesPage]("jobs.retries")
+	ViewSchedule         = web.NewView[SchedulePage]("jobs.schedule")
+	ViewScheduleJobTypes = web.NewView[[]jobs.JobType]("jobs.schedule#known-job-types")
+	ViewPayloadExamples  = web.NewView[PayloadExamples]("jobs.schedule#payload-examples")
+	ViewLogs             = web.NewView[LogsPage]("logs.show")
+	ViewSavedSearches    = web.NewView[[]logs.SavedSearch]("logs.show#saved-searches")
+	ViewLogLevel         = web.NewView[LogSettings]("logs.show#level-setting")
+)
+
+// Views are all typed views of the admin context, so they can be checked by web.CheckViews.
+var Views = []web.TypedView{
+	ViewCron,
+	ViewCronSchedule,
+	ViewWorkers,
+	ViewQueueControls,
+	ViewQueueMetrics,
+	ViewAlerts,
+	ViewComponents,
+	ViewLogsMaintenance,
+	ViewLogsTableSize,
+	ViewLogRetention,
+	ViewMaintenance,
+	ViewMaintenanceMode,
+	ViewFlags,
+	ViewFlagRow,
+	ViewFlag,
+	ViewAdminHome,
+	ViewRoutes,
+	ViewQueues,
+	ViewQueue,
+	ViewJob,
+	ViewFinishedJobs,
+	ViewFinishedJobsList,
+	ViewFinishedJobTypes,
+	ViewJobsMaintenance,
+	ViewJobsTableSize,
+	ViewRetries,
+	ViewSchedule,
+	ViewScheduleJobTypes,
+	ViewPayloadExamples,
+	ViewLogs,
+	ViewSavedSearches,
+	ViewLogLevel,
+}
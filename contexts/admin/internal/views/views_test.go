@@ -0,0 +1,19 @@
+package views_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/contexts/admin/internal/views"
+	"github.com/go-arrower/skeleton/contexts/admin/internal/views/pages"
+	"github.com/go-arrower/skeleton/shared/infrastructure/web"
+	sharedviews "github.com/go-arrower/skeleton/shared/views"
+)
+
+func TestAdminViews(t *testing.T) {
+	t.Parallel()
+
+	err := web.CheckViews(sharedviews.SharedViews, views.AdminViews, pages.Views...)
+	assert.NoError(t, err)
+}
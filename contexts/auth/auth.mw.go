@@ -5,10 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-arrower/arrower"
 	"github.com/labstack/echo-contrib/session"
 	"github.com/labstack/echo/v4"
+	"golang.org/x/text/language"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/i18n"
 )
 
 var ErrInvalidSessionValue = errors.New("invalid session value")
@@ -27,6 +31,8 @@ const (
 	SessKeyIsSuperuser            = "auth.user_is_superuser"
 	SessIsSuperuserLoggedInAsUser = "auth.superuser.is_logged_in_as_user"
 	SessSuperuserOriginalUserID   = "auth.superuser.original_user_id"
+	SessKeyLocale                 = "auth.user_locale"
+	SessKeyTimeZone               = "auth.user_time_zone"
 )
 
 // EnsureUserIsLoggedInMiddleware makes sure the routes can only be accessed by a logged-in user.
@@ -135,6 +141,7 @@ func EnsureUserIsSuperuserMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 // EnrichCtxWithUserInfoMiddleware checks if a User is logged in and puts those values into the http request's context,
 // so they are available in other parts of the app. For convenience use the helpers like: IsLoggedIn.
 // If you want to ensure only logged-in users can access a URL use EnsureUserIsLoggedInMiddleware instead.
+// The locale and time zone of the User replace the ones resolved by i18n.Middleware.
 func EnrichCtxWithUserInfoMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		sess, err := session.Get(SessionName, c)
@@ -173,6 +180,18 @@ func EnrichCtxWithUserInfoMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 			}
 		}
 
+		if locale, ok := sess.Values[SessKeyLocale].(string); ok {
+			if tag, err := language.Parse(locale); err == nil && tag != language.Und {
+				c.SetRequest(c.Request().WithContext(i18n.WithLocale(c.Request().Context(), tag)))
+			}
+		}
+
+		if timeZone, ok := sess.Values[SessKeyTimeZone].(string); ok {
+			if loc, err := time.LoadLocation(timeZone); err == nil {
+				c.SetRequest(c.Request().WithContext(i18n.WithTimeZone(c.Request().Context(), loc)))
+			}
+		}
+
 		return next(c)
 	}
 }
@@ -9,8 +9,10 @@ import (
 	"github.com/labstack/echo-contrib/session"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
 
 	"github.com/go-arrower/skeleton/contexts/auth"
+	"github.com/go-arrower/skeleton/shared/infrastructure/i18n"
 )
 
 func TestEnsureUserIsLoggedInMiddleware(t *testing.T) {
@@ -125,6 +127,8 @@ func TestEnrichCtxWithUserInfoMiddleware(t *testing.T) {
 			assert.True(t, auth.IsLoggedIn(ctx))
 			assert.Equal(t, "1337", auth.CurrentUserID(ctx))
 			assert.True(t, auth.IsSuperUser(ctx))
+			assert.Equal(t, language.MustParse("de-DE"), i18n.Locale(ctx))
+			assert.Equal(t, "Europe/Berlin", i18n.TimeZone(ctx).String())
 
 			return c.NoContent(http.StatusOK)
 		})
@@ -202,6 +206,8 @@ func newEnrichRouterToAssertOnHandler(handler func(c echo.Context) error) *echo.
 		sess.Values[auth.SessKeyLoggedIn] = true
 		sess.Values[auth.SessKeyUserID] = "1337"
 		sess.Values[auth.SessKeyIsSuperuser] = true
+		sess.Values[auth.SessKeyLocale] = "de-DE"
+		sess.Values[auth.SessKeyTimeZone] = "Europe/Berlin"
 
 		_ = sess.Save(c.Request(), c.Response())
 
@@ -1 +1,184 @@
 package init
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-arrower/arrower/mw"
+	"github.com/spf13/cobra"
+
+	"github.com/go-arrower/skeleton/contexts/auth/internal/application"
+	"github.com/go-arrower/skeleton/contexts/auth/internal/domain"
+	"github.com/go-arrower/skeleton/contexts/auth/internal/interfaces/repository"
+	"github.com/go-arrower/skeleton/shared/infrastructure"
+)
+
+var errEmptyPassword = errors.New("empty password")
+
+// RegisterCommands adds the commands of the auth context to the root command.
+// They use the Container returned by setup, without starting the background services.
+func RegisterCommands(root *cobra.Command, setup infrastructure.Setup) {
+	user := &cobra.Command{Use: "user", Short: "Manage the users"}
+	user.AddCommand(
+		createUserCommand(setup),
+		&cobra.Command{
+			Use:   "block <login>",
+			Short: "Block a user, so it can no longer login",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+
+				_, repo, err := commandRepository(ctx, setup)
+				if err != nil {
+					return err
+				}
+
+				usr, err := repo.FindByLogin(ctx, domain.Login(args[0]))
+				if err != nil {
+					return fmt.Errorf("could not get user: %w", err)
+				}
+
+				_, err = application.BlockUser(repo)(ctx, application.BlockUserRequest{UserID: usr.ID})
+				if err != nil {
+					return fmt.Errorf("could not block user: %w", err)
+				}
+
+				return nil
+			},
+		},
+		resetPasswordCommand(setup),
+	)
+
+	authCmd := &cobra.Command{Use: contextName, Short: "Manage the auth context"}
+	authCmd.AddCommand(user)
+
+	root.AddCommand(authCmd)
+}
+
+func createUserCommand(setup infrastructure.Setup) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create <login>",
+		Short: "Create a verified user, even if the registration is disabled",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			di, repo, err := commandRepository(ctx, setup)
+			if err != nil {
+				return err
+			}
+
+			password, err := commandPassword(cmd)
+			if err != nil {
+				return err
+			}
+
+			superuser, _ := cmd.Flags().GetBool("superuser")
+
+			res, err := mw.Validate(di.Validator.Validate, application.CreateUser(repo))(ctx, application.CreateUserRequest{
+				Login:     args[0],
+				Password:  password,
+				Superuser: superuser,
+			})
+			if err != nil {
+				return fmt.Errorf("could not create user: %w", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "created user %s\n", res.UserID)
+
+			if password == "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "password: %s\n", res.Password)
+			}
+
+			return nil
+		},
+	}
+	cmd.Flags().Bool("superuser", false, "create a superuser, that can access the admin")
+	cmd.Flags().Bool(passwordStdinFlag, false, "read the password from stdin. If not set, a password is generated and shown")
+
+	return cmd
+}
+
+func resetPasswordCommand(setup infrastructure.Setup) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reset-password <login>",
+		Short: "Set a new password for a user and log it out of all its devices",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			di, repo, err := commandRepository(ctx, setup)
+			if err != nil {
+				return err
+			}
+
+			password, err := commandPassword(cmd)
+			if err != nil {
+				return err
+			}
+
+			res, err := mw.Validate(di.Validator.Validate, application.ResetPassword(repo))(ctx, application.ResetPasswordRequest{
+				Login:    args[0],
+				Password: password,
+			})
+			if err != nil {
+				return fmt.Errorf("could not reset password: %w", err)
+			}
+
+			if password == "" {
+				fmt.Fprintf(cmd.OutOrStdout(), "password: %s\n", res.Password)
+			}
+
+			return nil
+		},
+	}
+	cmd.Flags().Bool(passwordStdinFlag, false, "read the new password from stdin. If not set, a password is generated and shown")
+
+	return cmd
+}
+
+// passwordStdinFlag reads the password from stdin, so it does not show in the process list or the shell history,
+// e.g.: cat password.txt | skeleton auth user create --password-stdin admin@example.com.
+const passwordStdinFlag = "password-stdin"
+
+// commandPassword returns the password read from stdin or nothing, if a password is to be generated.
+// Only the first line is read, so a password file may end with a newline.
+func commandPassword(cmd *cobra.Command) (string, error) {
+	if fromStdin, _ := cmd.Flags().GetBool(passwordStdinFlag); !fromStdin {
+		return "", nil
+	}
+
+	password, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("could not read password: %w", err)
+	}
+
+	password = strings.TrimRight(password, "\r\n")
+	if password == "" {
+		return "", fmt.Errorf("could not read password: %w", errEmptyPassword)
+	}
+
+	return password, nil
+}
+
+// commandRepository returns the user repository.
+func commandRepository(
+	ctx context.Context,
+	setup infrastructure.Setup,
+) (*infrastructure.Container, *repository.PostgresRepository, error) {
+	di, err := setup(ctx, infrastructure.WithoutBackgroundServices())
+	if err != nil {
+		return nil, nil, err //nolint:wrapcheck // the error is shown as is
+	}
+
+	repo, err := repository.NewPostgresRepository(di.PGx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create user repository: %w", err)
+	}
+
+	return di, repo, nil
+}
@@ -1,17 +1,19 @@
 package init
 
-type PWConfirmation struct {
-	Active  bool
-	Timeout int // how long the token is valid
+// Config is the section of the auth context in the application's config, e.g. auth.register_allowed.
+type Config struct {
+	Mailer              any  `mapstructure:"-"`                // smtp <> local etc.
+	UserProvider        any  `mapstructure:"-"`                // future music
+	RegisterAllowed     bool `mapstructure:"register_allowed"` // enabled | disabled
+	RegisterAdminRoutes bool `mapstructure:"register_admin_routes"`
 }
 
-type Config struct {
-	Mailer                     any // smtp <> local etc.
-	UserProvider               any // future music
-	PWConfirmation             PWConfirmation
-	PwHashCost                 int
-	LoginThrottle              int // time in sec until a new login attempt can be made
-	InsecureAllowAnyPWStrength bool
-	RegisterAllowed            bool // enabled | disabled
-	RegisterAdminRoutes        bool
+// DefaultConfig returns the defaults of the auth context, used for all values that are not configured.
+func DefaultConfig() *Config {
+	return &Config{
+		Mailer:              nil,
+		UserProvider:        nil,
+		RegisterAllowed:     true,
+		RegisterAdminRoutes: true,
+	}
 }
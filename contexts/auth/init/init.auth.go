@@ -3,7 +3,9 @@ package init
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"log/slog"
+	"net/http"
 	"os"
 
 	"github.com/go-arrower/skeleton/contexts/auth/internal/domain"
@@ -13,19 +15,26 @@ import (
 	"github.com/go-arrower/arrower/setting"
 
 	"github.com/go-arrower/arrower/mw"
+	"github.com/labstack/echo/v4"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/go-arrower/skeleton/contexts/auth/internal/application"
+	authinfrastructure "github.com/go-arrower/skeleton/contexts/auth/internal/infrastructure"
 	"github.com/go-arrower/skeleton/contexts/auth/internal/interfaces/repository"
 	"github.com/go-arrower/skeleton/contexts/auth/internal/interfaces/repository/models"
 	"github.com/go-arrower/skeleton/contexts/auth/internal/interfaces/web"
+	"github.com/go-arrower/skeleton/contexts/auth/internal/views"
 	"github.com/go-arrower/skeleton/shared/infrastructure"
+	"github.com/go-arrower/skeleton/shared/infrastructure/i18n"
+	"github.com/go-arrower/skeleton/shared/infrastructure/ratelimit"
+	sharedweb "github.com/go-arrower/skeleton/shared/infrastructure/web"
 )
 
 const contextName = "auth"
 
-func NewAuthContext(di *infrastructure.Container) (*AuthContext, error) {
+// NewAuthContext initialises the auth context with its section of the config, see DefaultConfig.
+func NewAuthContext(di *infrastructure.Container, conf Config) (*AuthContext, error) {
 	// todo if di == nil => load and initialise all dependencies from config
 
 	if err := di.EnsureAllDependenciesPresent(); err != nil {
@@ -38,9 +47,22 @@ func NewAuthContext(di *infrastructure.Container) (*AuthContext, error) {
 	_ = meter
 	_ = tracer
 
-	_ = di.WebRenderer.AddContext("auth", os.DirFS("contexts/auth/internal/views")) // todo build path automatically, as it is a convention (?)
+	var views fs.FS = views.AuthViews
+	if di.Config.Debug {
+		views = os.DirFS("contexts/auth/internal/views") // todo build path automatically, as it is a convention (?)
+	}
+
+	err := di.WebRenderer.AddContext(contextName, views)
+	if err != nil {
+		return nil, fmt.Errorf("could not add context views: %w", err)
+	}
 
-	err := di.WebRenderer.AddLayoutData(contextName, "default", func(ctx context.Context) (map[string]any, error) {
+	messages, err := i18n.NewCatalog(views)
+	if err != nil {
+		return nil, fmt.Errorf("could not load messages: %w", err)
+	}
+
+	err = di.WebRenderer.AddLayoutData(contextName, "default", func(ctx context.Context) (map[string]any, error) {
 		return map[string]any{
 			"Title": "arrower auth",
 		}, nil
@@ -50,7 +72,7 @@ func NewAuthContext(di *infrastructure.Container) (*AuthContext, error) {
 	}
 
 	{ // register default auth settings
-		_ = di.Settings.Save(context.Background(), auth.SettingAllowRegistration, setting.NewValue(true))
+		_ = di.Settings.Save(context.Background(), auth.SettingAllowRegistration, setting.NewValue(conf.RegisterAllowed))
 		_ = di.Settings.Save(context.Background(), auth.SettingAllowLogin, setting.NewValue(true))
 
 		//_ = di.Settings.Add(context.Background(), admin.Setting{
@@ -91,12 +113,14 @@ func NewAuthContext(di *infrastructure.Container) (*AuthContext, error) {
 		ListUsers: application.NewListUsersQueryHandler(repo),
 	}
 
-	userController := web.NewUserController(app, webRoutes, []byte("secret"), di.Settings)
+	userController := web.NewUserController(app, webRoutes, []byte(di.Config.Web.Secret.Secret()), di.Settings)
 	userController.Queries = queries
+	userController.Validator = di.Validator
+	userController.Messages = messages
 	userController.CmdLoginUser = mw.Traced(di.TraceProvider,
 		mw.Metric(di.MeterProvider,
 			mw.Logged(logger,
-				mw.Validate(nil,
+				mw.Validate(di.Validator.Validate,
 					application.LoginUser(di.Logger, repo, di.ArrowerQueue, domain.NewAuthenticationService(di.Settings)),
 				),
 			),
@@ -105,7 +129,7 @@ func NewAuthContext(di *infrastructure.Container) (*AuthContext, error) {
 	userController.CmdRegisterUser = mw.Traced(di.TraceProvider,
 		mw.Metric(di.MeterProvider,
 			mw.Logged(logger,
-				mw.Validate(nil,
+				mw.Validate(di.Validator.Validate,
 					application.RegisterUser(di.Logger, repo, registrator, di.ArrowerQueue),
 				),
 			),
@@ -114,7 +138,7 @@ func NewAuthContext(di *infrastructure.Container) (*AuthContext, error) {
 	userController.CmdShowUserUser = mw.Traced(di.TraceProvider,
 		mw.Metric(di.MeterProvider,
 			mw.Logged(logger,
-				mw.Validate(nil,
+				mw.Validate(di.Validator.Validate,
 					application.ShowUser(repo),
 				),
 			),
@@ -123,7 +147,7 @@ func NewAuthContext(di *infrastructure.Container) (*AuthContext, error) {
 	userController.CmdNewUser = mw.TracedU(di.TraceProvider,
 		mw.MetricU(di.MeterProvider,
 			mw.LoggedU(logger,
-				mw.ValidateU(nil,
+				mw.ValidateU(di.Validator.Validate,
 					application.NewUser(repo, registrator),
 				),
 			),
@@ -132,7 +156,7 @@ func NewAuthContext(di *infrastructure.Container) (*AuthContext, error) {
 	userController.CmdVerifyUser = mw.TracedU(di.TraceProvider,
 		mw.MetricU(di.MeterProvider,
 			mw.LoggedU(logger,
-				mw.ValidateU(nil,
+				mw.ValidateU(di.Validator.Validate,
 					application.VerifyUser(repo),
 				),
 			),
@@ -141,7 +165,7 @@ func NewAuthContext(di *infrastructure.Container) (*AuthContext, error) {
 	userController.CmdBlockUser = mw.Traced(di.TraceProvider,
 		mw.Metric(di.MeterProvider,
 			mw.Logged(logger,
-				mw.Validate(nil,
+				mw.Validate(di.Validator.Validate,
 					application.BlockUser(repo),
 				),
 			),
@@ -150,7 +174,7 @@ func NewAuthContext(di *infrastructure.Container) (*AuthContext, error) {
 	userController.CmdUnBlockUser = mw.Traced(di.TraceProvider,
 		mw.Metric(di.MeterProvider,
 			mw.Logged(logger,
-				mw.Validate(nil,
+				mw.Validate(di.Validator.Validate,
 					application.UnblockUser(repo),
 				),
 			),
@@ -165,14 +189,22 @@ func NewAuthContext(di *infrastructure.Container) (*AuthContext, error) {
 		meterProvider:      di.MeterProvider,
 		queries:            queries,
 		repo:               repo,
+		rateLimit:          di.RateLimiter.Middleware(contextName, di.Config.RateLimits.Auth.Limit(), ratelimit.ByIP),
 	}
 
+	registerErrors(di.ErrorHandler)
+
 	authContext.registerWebRoutes(webRoutes)
 	authContext.registerAPIRoutes(di.APIRouter)
-	authContext.registerAdminRoutes(adminRouter, localDI{queries: queries}) // todo only, if admin context is present
+	if conf.RegisterAdminRoutes {
+		authContext.registerAdminRoutes(adminRouter, localDI{queries: queries, messages: messages}) // todo only, if admin context is present
+	}
 
 	authContext.registerJobs(di.ArrowerQueue)
 
+	di.Lifecycle.OnShutdown(contextName+" context", authContext.Shutdown)
+	di.Health.Register("ip2location", authinfrastructure.NewIP2LocationService("").Check, infrastructure.Optional())
+
 	return &authContext, nil
 }
 
@@ -185,12 +217,34 @@ type AuthContext struct {
 	meterProvider metric.MeterProvider
 	queries       *models.Queries
 	repo          domain.Repository
+
+	// rateLimit limits the logins and registrations of each IP, so the passwords can not be guessed.
+	rateLimit echo.MiddlewareFunc
+}
+
+// registerErrors answers requests failing with an error of the auth context with a fitting status code.
+func registerErrors(errorHandler *sharedweb.ErrorHandler) {
+	if errorHandler == nil {
+		return
+	}
+
+	errorHandler.Register(http.StatusNotFound, domain.ErrNotFound)
+	errorHandler.Register(http.StatusUnauthorized, application.ErrLoginFailed)
+	errorHandler.Register(http.StatusConflict, domain.ErrUserAlreadyExists)
+	errorHandler.Register(http.StatusBadRequest,
+		application.ErrInvalidInput,
+		domain.ErrInvalidUserDetails,
+		domain.ErrInvalidBirthday,
+		domain.ErrVerificationFailed,
+	)
 }
 
+// Shutdown is called by the Lifecycle of the Container, before the shared dependencies are shut down.
 func (c *AuthContext) Shutdown(ctx context.Context) error {
 	return nil
 }
 
 type localDI struct {
-	queries *models.Queries
+	queries  *models.Queries
+	messages *i18n.Catalog
 }
@@ -6,21 +6,23 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/go-arrower/skeleton/contexts/auth"
+	"github.com/go-arrower/skeleton/contexts/auth/internal/views"
+	sharedweb "github.com/go-arrower/skeleton/shared/infrastructure/web"
 )
 
 // registerWebRoutes initialises all routes of this Context.
 func (c *AuthContext) registerWebRoutes(router *echo.Group) {
 	router.GET("/login", c.userController.Login()).Name = auth.RouteLogin
-	router.POST("/login", c.userController.Login())
+	router.POST("/login", c.userController.Login(), c.rateLimit)
 	router.GET("/logout", c.userController.Logout()).Name = auth.RouteLogout // todo make POST to prevent CSRF
 	router.GET("/register", c.userController.Create())
-	router.POST("/register", c.userController.Register())
+	router.POST("/register", c.userController.Register(), c.rateLimit)
 	router.GET("/:userID/verify/:token", c.userController.Verify()).Name = auth.RouteVerifyUser
 
 	router.GET("/profile", c.userController.Profile(), auth.EnsureUserIsLoggedInMiddleware).Name = "auth.profile"
 	router.GET("/", nil, func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			return c.Render(http.StatusOK, "home", nil)
+			return sharedweb.Render(c, http.StatusOK, views.ViewHome, views.HomePage{})
 		}
 	})
 }
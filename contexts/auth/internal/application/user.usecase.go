@@ -349,3 +349,99 @@ func UnblockUser(repo domain.Repository) func(context.Context, BlockUserRequest)
 		}, nil
 	}
 }
+
+type (
+	CreateUserRequest struct {
+		Login string `validate:"max=1024,required,email"`
+		// Password is generated, if it is empty.
+		Password  string `validate:"max=1024"`
+		Superuser bool
+	}
+	CreateUserResponse struct {
+		UserID   domain.ID
+		Password string
+	}
+)
+
+// CreateUser creates a verified user, independent of the registration being allowed or not.
+// It is meant for operators, e.g. to create the first superuser.
+func CreateUser(repo domain.Repository) func(context.Context, CreateUserRequest) (CreateUserResponse, error) {
+	return func(ctx context.Context, in CreateUserRequest) (CreateUserResponse, error) {
+		exists, err := repo.ExistsByLogin(ctx, domain.Login(in.Login))
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return CreateUserResponse{}, fmt.Errorf("could not check if user exists: %w", err)
+		}
+
+		if exists {
+			return CreateUserResponse{}, domain.ErrUserAlreadyExists
+		}
+
+		password := in.Password
+		if password == "" {
+			password = domain.NewRandomPassword()
+		}
+
+		usr, err := domain.NewUser(in.Login, password)
+		if err != nil {
+			return CreateUserResponse{}, fmt.Errorf("could not create user: %w", err)
+		}
+
+		usr.RegisteredAt = time.Now().UTC()
+		usr.Verified = usr.Verified.SetTrue()
+
+		if in.Superuser {
+			usr.SuperUser = usr.SuperUser.SetTrue()
+		}
+
+		err = repo.Save(ctx, usr)
+		if err != nil {
+			return CreateUserResponse{}, fmt.Errorf("could not save user: %w", err)
+		}
+
+		return CreateUserResponse{UserID: usr.ID, Password: password}, nil
+	}
+}
+
+type (
+	ResetPasswordRequest struct {
+		Login string `validate:"max=1024,required,email"`
+		// Password is generated, if it is empty.
+		Password string `validate:"max=1024"`
+	}
+	ResetPasswordResponse struct {
+		Password string
+	}
+)
+
+// ResetPassword sets a new password for the user and logs it out of all its devices.
+func ResetPassword(repo domain.Repository) func(context.Context, ResetPasswordRequest) (ResetPasswordResponse, error) {
+	return func(ctx context.Context, in ResetPasswordRequest) (ResetPasswordResponse, error) {
+		usr, err := repo.FindByLogin(ctx, domain.Login(in.Login))
+		if err != nil {
+			return ResetPasswordResponse{}, fmt.Errorf("could not get user: %w", err)
+		}
+
+		password := in.Password
+		if password == "" {
+			password = domain.NewRandomPassword()
+		}
+
+		usr.PasswordHash, err = domain.NewStrongPasswordHash(password)
+		if err != nil {
+			return ResetPasswordResponse{}, fmt.Errorf("could not reset password: %w", err)
+		}
+
+		err = repo.Save(ctx, usr)
+		if err != nil {
+			return ResetPasswordResponse{}, fmt.Errorf("could not save user: %w", err)
+		}
+
+		// whoever knew the old password is logged out
+		err = repo.DeleteSessions(ctx, usr.ID)
+		if err != nil {
+			return ResetPasswordResponse{}, fmt.Errorf("could not delete sessions: %w", err)
+		}
+
+		return ResetPasswordResponse{Password: password}, nil
+	}
+}
@@ -280,3 +280,78 @@ func TestUnblockUser(t *testing.T) {
 		assert.True(t, !usr.IsBlocked())
 	})
 }
+
+func TestCreateUser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("create superuser", func(t *testing.T) {
+		t.Parallel()
+
+		repo := repository.NewMemoryRepository()
+
+		cmd := application.CreateUser(repo)
+		res, err := cmd(ctx, application.CreateUserRequest{Login: newUserLogin, Superuser: true})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, res.Password, "a password is generated")
+
+		// verify
+		usr, err := repo.FindByID(ctx, res.UserID)
+		assert.NoError(t, err)
+		assert.True(t, usr.IsVerified())
+		assert.True(t, usr.IsSuperuser())
+		assert.True(t, usr.PasswordHash.Matches(res.Password))
+	})
+
+	t.Run("user exists", func(t *testing.T) {
+		t.Parallel()
+
+		repo := repository.NewMemoryRepository()
+		repo.Save(ctx, userVerified)
+
+		cmd := application.CreateUser(repo)
+		_, err := cmd(ctx, application.CreateUserRequest{Login: user0Login, Password: strongPassword})
+		assert.ErrorIs(t, err, domain.ErrUserAlreadyExists)
+	})
+
+	t.Run("weak password", func(t *testing.T) {
+		t.Parallel()
+
+		repo := repository.NewMemoryRepository()
+
+		cmd := application.CreateUser(repo)
+		_, err := cmd(ctx, application.CreateUserRequest{Login: newUserLogin, Password: "123"})
+		assert.ErrorIs(t, err, domain.ErrInvalidUserDetails)
+	})
+}
+
+func TestResetPassword(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reset password", func(t *testing.T) {
+		t.Parallel()
+
+		repo := repository.NewMemoryRepository()
+		repo.Save(ctx, userVerified)
+
+		cmd := application.ResetPassword(repo)
+		res, err := cmd(ctx, application.ResetPasswordRequest{Login: user0Login})
+		assert.NoError(t, err)
+
+		// verify
+		usr, err := repo.FindByID(ctx, userIDZero)
+		assert.NoError(t, err)
+		assert.True(t, usr.PasswordHash.Matches(res.Password))
+		assert.False(t, usr.PasswordHash.Matches(strongPassword))
+		assert.Empty(t, usr.Sessions, "the user is logged out")
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		t.Parallel()
+
+		repo := repository.NewMemoryRepository()
+
+		cmd := application.ResetPassword(repo)
+		_, err := cmd(ctx, application.ResetPasswordRequest{Login: newUserLogin})
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
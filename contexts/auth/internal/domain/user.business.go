@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"regexp"
@@ -161,6 +162,26 @@ func isWeakPassword(password string) bool {
 	return false
 }
 
+const passwordChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!@#$%^&*"
+
+// NewRandomPassword returns a password, that is strong enough for NewStrongPasswordHash.
+func NewRandomPassword() string {
+	const length = 20
+
+	for {
+		buf := make([]byte, length)
+		_, _ = rand.Read(buf)
+
+		for i := range buf {
+			buf[i] = passwordChars[int(buf[i])%len(passwordChars)]
+		}
+
+		if password := string(buf); !isWeakPassword(password) {
+			return password
+		}
+	}
+}
+
 type PasswordHash string // todo make VO that can not be changed??
 
 func (pw PasswordHash) Matches(checkPW string) bool {
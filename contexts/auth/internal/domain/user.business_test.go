@@ -244,6 +244,16 @@ func TestNewStrongPasswordHash(t *testing.T) {
 	}
 }
 
+func TestNewRandomPassword(t *testing.T) {
+	t.Parallel()
+
+	pw := domain.NewRandomPassword()
+
+	_, err := domain.NewStrongPasswordHash(pw)
+	assert.NoError(t, err)
+	assert.NotEqual(t, pw, domain.NewRandomPassword())
+}
+
 func TestName(t *testing.T) {
 	t.Parallel()
 
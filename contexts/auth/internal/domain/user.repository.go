@@ -30,6 +30,8 @@ type Repository interface {
 	DeleteByID(context.Context, ID) error
 	DeleteByIDs(context.Context, []ID) error
 	DeleteAll(context.Context) error
+	// DeleteSessions logs the user out of all its devices.
+	DeleteSessions(context.Context, ID) error
 
 	// todo investigate if this is good or token should have its own repo or whatever the heck an aggregate is
 	CreateVerificationToken(context.Context, VerificationToken) error
@@ -1,13 +1,16 @@
 package infrastructure
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
+	"os"
 	"path"
 	"runtime"
 
 	"github.com/go-arrower/skeleton/contexts/auth/internal/domain"
+	"github.com/go-arrower/skeleton/shared/infrastructure"
 
 	"github.com/ip2location/ip2location-go/v9"
 )
@@ -38,17 +41,9 @@ type IP2Location struct {
 }
 
 func (s *IP2Location) ResolveIP(ip string) (domain.ResolvedIP, error) {
-	db, err := ip2location.OpenDB(s.dbPath)
+	db, err := ip2location.OpenDB(s.path())
 	if err != nil {
-		// if not found, it might have been called from test coed from another package:
-		// then get the path relative to the runtime dir.
-		_, f, _, _ := runtime.Caller(0)
-		searchDir := path.Join(path.Dir(f))
-
-		db, err = ip2location.OpenDB(searchDir + "/" + s.dbPath)
-		if err != nil {
-			return domain.ResolvedIP{}, fmt.Errorf("%w: %v", ErrResolveFailed, err)
-		}
+		return domain.ResolvedIP{}, fmt.Errorf("%w: %v", ErrResolveFailed, err)
 	}
 
 	ipAddr := net.ParseIP(ip)
@@ -71,3 +66,29 @@ func (s *IP2Location) ResolveIP(ip string) (domain.ResolvedIP, error) {
 		City:        results.City,
 	}, nil
 }
+
+// path returns the path of the DB file.
+func (s *IP2Location) path() string {
+	if _, err := os.Stat(s.dbPath); err == nil {
+		return s.dbPath
+	}
+
+	// if not found, it might have been called from test coed from another package:
+	// then get the path relative to the runtime dir.
+	_, f, _, _ := runtime.Caller(0)
+	searchDir := path.Join(path.Dir(f))
+
+	return searchDir + "/" + s.dbPath
+}
+
+// Check is a health check, that the DB file exists and there is enough disk space left to update it.
+func (s *IP2Location) Check(ctx context.Context) error {
+	dbPath := s.path()
+
+	var size uint64
+	if info, err := os.Stat(dbPath); err == nil {
+		size = uint64(info.Size()) //nolint:gosec // file size is never negative
+	}
+
+	return infrastructure.DiskSpaceCheck(dbPath, size)(ctx) //nolint:wrapcheck // health check error
+}
@@ -87,6 +87,17 @@ func (repo *MemoryRepository) ExistsByLogin(ctx context.Context, login domain.Lo
 	return false, domain.ErrNotFound
 }
 
+func (repo *MemoryRepository) DeleteSessions(ctx context.Context, userID domain.ID) error {
+	usr, err := repo.FindByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	usr.Sessions = nil
+
+	return repo.Save(ctx, usr) //nolint:wrapcheck // the error is returned as is
+}
+
 func (repo *MemoryRepository) CreateVerificationToken(
 	ctx context.Context,
 	token domain.VerificationToken,
@@ -52,7 +52,7 @@ func userFromModelWithSession(dbUser models.AuthUser, sessions []models.AuthSess
 		RegisteredAt:      dbUser.CreatedAt.Time,
 		Name:              domain.NewName(dbUser.NameFirstname, dbUser.NameLastname, dbUser.NameDisplayname),
 		Birthday:          domain.Birthday{}, // todo
-		Locale:            domain.Locale{},   // todo
+		Locale:            domain.Locale(language.Make(dbUser.Locale)),
 		TimeZone:          domain.TimeZone(dbUser.TimeZone),
 		ProfilePictureURL: domain.URL(dbUser.PictureUrl),
 		Profile:           profile,
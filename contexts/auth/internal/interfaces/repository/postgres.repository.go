@@ -231,6 +231,20 @@ func (repo *PostgresRepository) DeleteAll(ctx context.Context) error {
 	return nil
 }
 
+func (repo *PostgresRepository) DeleteSessions(ctx context.Context, userID domain.ID) error {
+	id, err := uuid.Parse(string(userID))
+	if err != nil {
+		return fmt.Errorf("%w: could not parse as uuid: %s: %w", domain.ErrPersistenceFailed, id, err)
+	}
+
+	err = repo.db.ConnOrTX(ctx).DeleteSessionsByUserID(ctx, uuid.NullUUID{UUID: id, Valid: true})
+	if err != nil {
+		return fmt.Errorf("%w: could not delete sessions: %s: %w", domain.ErrPersistenceFailed, userID, err)
+	}
+
+	return nil
+}
+
 func (repo *PostgresRepository) CreateVerificationToken(
 	ctx context.Context,
 	token domain.VerificationToken,
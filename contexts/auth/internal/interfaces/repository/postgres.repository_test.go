@@ -400,6 +400,25 @@ func TestPostgresRepository_DeleteAll(t *testing.T) {
 	})
 }
 
+func TestPostgresRepository_DeleteSessions(t *testing.T) {
+	t.Parallel()
+
+	pg := pgHandler.NewTestDatabase()
+	repo, _ := repository.NewPostgresRepository(pg)
+
+	usr, _ := repo.FindByID(ctx, testdata.UserIDZero)
+	assert.NotEmpty(t, usr.Sessions)
+
+	err := repo.DeleteSessions(ctx, testdata.UserIDZero)
+	assert.NoError(t, err)
+
+	usr, _ = repo.FindByID(ctx, testdata.UserIDZero)
+	assert.Empty(t, usr.Sessions)
+
+	c, _ := repo.Count(ctx)
+	assert.Equal(t, 3, c, "the user is kept")
+}
+
 func TestPostgresRepository_CreateVerificationToken(t *testing.T) {
 	t.Parallel()
 
@@ -23,6 +23,11 @@ DELETE
 FROM auth.session
 WHERE key = $1;
 
+-- name: DeleteSessionsByUserID :exec
+DELETE
+FROM auth.session
+WHERE user_id = $1;
+
 -- name: UpsertSessionData :exec
 INSERT INTO auth.session (key, data, expires_at_utc)
 VALUES ($1, $2, $3)
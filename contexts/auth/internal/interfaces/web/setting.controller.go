@@ -6,6 +6,8 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/go-arrower/skeleton/contexts/auth/internal/interfaces/repository/models"
+	"github.com/go-arrower/skeleton/contexts/auth/internal/views"
+	sharedweb "github.com/go-arrower/skeleton/shared/infrastructure/web"
 )
 
 /*
@@ -29,6 +31,6 @@ type SettingsController struct {
 
 func (sc SettingsController) List() func(echo.Context) error {
 	return func(c echo.Context) error {
-		return c.Render(http.StatusOK, "auth.settings", nil)
+		return sharedweb.Render(c, http.StatusOK, views.ViewSettings, views.SettingsPage{})
 	}
 }
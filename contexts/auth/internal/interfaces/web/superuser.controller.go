@@ -10,10 +10,12 @@ import (
 
 	"github.com/go-arrower/skeleton/contexts/auth"
 	"github.com/go-arrower/skeleton/contexts/auth/internal/interfaces/repository/models"
+	"github.com/go-arrower/skeleton/shared/infrastructure/i18n"
 )
 
 type SuperUserController struct {
-	Queries *models.Queries
+	Queries  *models.Queries
+	Messages *i18n.Catalog
 }
 
 func (cont SuperUserController) AdminLoginAsUser() echo.HandlerFunc {
@@ -22,12 +24,12 @@ func (cont SuperUserController) AdminLoginAsUser() echo.HandlerFunc {
 
 		user, err := cont.Queries.FindUserByID(c.Request().Context(), uuid.MustParse(userID))
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return fmt.Errorf("%w", err)
 		}
 
 		sess, err := session.Get(auth.SessionName, c)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return fmt.Errorf("%w", err)
 		}
 
 		if originalUserID, ok := sess.Values[auth.SessKeyUserID].(string); ok {
@@ -35,11 +37,11 @@ func (cont SuperUserController) AdminLoginAsUser() echo.HandlerFunc {
 			sess.Values[auth.SessSuperuserOriginalUserID] = originalUserID
 
 			sess.Values[auth.SessKeyUserID] = user.ID.String()
-			sess.AddFlash(fmt.Sprintf("Angemeldet als Nutzer: %s", user.Login))
+			sess.AddFlash(cont.Messages.T(c.Request().Context(), "superuser.logged_in_as", user.Login))
 
 			err = sess.Save(c.Request(), c.Response())
 			if err != nil {
-				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+				return fmt.Errorf("%w", err)
 			}
 		}
 
@@ -51,7 +53,7 @@ func (cont SuperUserController) AdminLeaveUser() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		sess, err := session.Get(auth.SessionName, c)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return fmt.Errorf("%w", err)
 		}
 
 		if originalUserID, ok := sess.Values[auth.SessSuperuserOriginalUserID].(string); ok {
@@ -59,11 +61,11 @@ func (cont SuperUserController) AdminLeaveUser() echo.HandlerFunc {
 			delete(sess.Values, auth.SessSuperuserOriginalUserID)
 
 			sess.Values[auth.SessKeyUserID] = originalUserID
-			sess.AddFlash("Left user and back to superuser")
+			sess.AddFlash(cont.Messages.T(c.Request().Context(), "superuser.left_user"))
 
 			err = sess.Save(c.Request(), c.Response())
 			if err != nil {
-				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+				return fmt.Errorf("%w", err)
 			}
 		}
 
@@ -10,16 +10,20 @@ import (
 
 	"github.com/go-arrower/arrower/setting"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/gorilla/securecookie"
 	"github.com/gorilla/sessions"
 	"github.com/labstack/echo-contrib/session"
 	"github.com/labstack/echo/v4"
+	"golang.org/x/text/language"
 
 	"github.com/go-arrower/skeleton/contexts/auth"
 	"github.com/go-arrower/skeleton/contexts/auth/internal/application"
 	"github.com/go-arrower/skeleton/contexts/auth/internal/interfaces/repository/models"
+	"github.com/go-arrower/skeleton/contexts/auth/internal/views"
+	"github.com/go-arrower/skeleton/shared/infrastructure/i18n"
+	sharedweb "github.com/go-arrower/skeleton/shared/infrastructure/web"
+	"github.com/go-arrower/skeleton/shared/infrastructure/web/htmx"
 )
 
 /*
@@ -45,6 +49,10 @@ type UserController struct {
 	r *echo.Group
 
 	Queries *models.Queries
+	// Validator translates the validation errors of the commands.
+	Validator *i18n.Validator
+	// Messages are the translated texts of the auth context.
+	Messages *i18n.Catalog
 
 	CmdLoginUser    func(context.Context, application.LoginUserRequest) (application.LoginUserResponse, error)
 	CmdRegisterUser func(context.Context, application.RegisterUserRequest) (application.RegisterUserResponse, error)
@@ -71,14 +79,14 @@ func (uc UserController) Login() func(echo.Context) error {
 		}
 
 		if c.Request().Method == http.MethodGet {
-			return c.Render(http.StatusOK, "auth=>=>auth.login", nil)
+			return sharedweb.Render(c, http.StatusOK, views.ViewLogin, views.LoginPage{}) //nolint:exhaustruct // the form is empty
 		}
 
 		// POST: Login
 
 		sess, err := session.Get(auth.SessionName, c)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return fmt.Errorf("%w", err)
 		}
 
 		loginUser := loginCredentials{ //nolint:exhaustruct // other values will be set with bind below
@@ -90,30 +98,23 @@ func (uc UserController) Login() func(echo.Context) error {
 			},
 		}
 		if err = c.Bind(&loginUser); err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return fmt.Errorf("%w", err)
 		}
 
 		response, err := uc.CmdLoginUser(c.Request().Context(), loginUser.LoginUserRequest)
 		if err != nil {
-			valErrs := make(map[string]string)
-
-			var validationErrors validator.ValidationErrors
-
-			if !errors.As(err, &validationErrors) {
-				valErrs["LoginEmail"] = "Invalid user name"
+			valErrs := uc.Validator.Errors(c.Request().Context(), err)
+			if valErrs == nil {
+				valErrs = map[string]string{"LoginEmail": uc.Messages.T(c.Request().Context(), "login.invalid")}
 			}
 
-			for _, e := range validationErrors {
-				valErrs[e.StructField()] = e.Translate(nil)
-			}
-
-			return c.Render(http.StatusOK, "auth=>=>auth.login", map[string]any{
-				"Errors":     valErrs,
-				"LoginEmail": loginUser.LoginEmail,
+			return sharedweb.Render(c, http.StatusOK, views.ViewLogin, views.LoginPage{
+				LoginEmail: loginUser.LoginEmail,
+				Errors:     valErrs,
 			})
 		}
 
-		sess.AddFlash("Login successful")
+		sess.AddFlash(uc.Messages.T(c.Request().Context(), "login.successful"))
 
 		maxAge := 0 // session cookie => browser should delete the cookie when it closes
 
@@ -134,10 +135,12 @@ func (uc UserController) Login() func(echo.Context) error {
 		sess.Values[auth.SessKeyLoggedIn] = true
 		sess.Values[auth.SessKeyUserID] = string(response.User.ID)
 		sess.Values[auth.SessKeyIsSuperuser] = response.User.IsSuperuser()
+		sess.Values[auth.SessKeyLocale] = language.Tag(response.User.Locale).String()
+		sess.Values[auth.SessKeyTimeZone] = string(response.User.TimeZone)
 
 		err = sess.Save(c.Request(), c.Response())
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return fmt.Errorf("%w", err)
 		}
 
 		err = setKnownDeviceCookie(uc.knownDeviceKeyPairs, c) // set the Cookie always to renew the MaxAge
@@ -156,7 +159,7 @@ func setKnownDeviceCookie(knownDeviceKeyPairs []securecookie.Codec, c echo.Conte
 		knownDeviceKeyPairs...,
 	)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return fmt.Errorf("%w", err)
 	}
 
 	const twentyYears = 60 * 60 * 24 * 365 * 20
@@ -198,7 +201,7 @@ func (uc UserController) Logout() func(echo.Context) error {
 
 		sess, err := session.Get(auth.SessionName, c)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return fmt.Errorf("%w", err)
 		}
 
 		delete(sess.Values, auth.SessKeyLoggedIn)
@@ -214,7 +217,7 @@ func (uc UserController) Logout() func(echo.Context) error {
 
 		err = sess.Save(c.Request(), c.Response())
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return fmt.Errorf("%w", err)
 		}
 
 		return c.Redirect(http.StatusSeeOther, "/")
@@ -235,17 +238,17 @@ func (uc UserController) List() func(echo.Context) error {
 		}
 
 		if query == "" { // prevent the empty query param `?q=` to show in the URL
-			c.Response().Header().Set("HX-Push-Url", "/admin/auth/users")
+			htmx.PushURL(c, "/admin/auth/users")
 		}
 
-		return c.Render(http.StatusOK, "users", echo.Map{
-			"Title":         "Alle Nutzer",
-			"users":         res.Users,
-			"currentUserID": auth.CurrentUserID(c.Request().Context()),
-			"filtered":      res.Filtered,
-			"total":         res.Total,
-			"query":         query,
-			"couldBeEmpty":  offset == "", // if no offset is given and the users are zero => empty list
+		return sharedweb.Render(c, http.StatusOK, views.ViewUsers, views.UsersPage{
+			Title:         uc.Messages.T(c.Request().Context(), "users.title"),
+			Users:         res.Users,
+			CurrentUserID: auth.CurrentUserID(c.Request().Context()),
+			Filtered:      res.Filtered,
+			Total:         res.Total,
+			Query:         query,
+			CouldBeEmpty:  offset == "", // if no offset is given and the users are zero => empty list
 		})
 	}
 }
@@ -256,7 +259,11 @@ func (uc UserController) Create() func(echo.Context) error {
 			return c.Redirect(http.StatusSeeOther, "/")
 		}
 
-		return c.Render(http.StatusOK, "auth=>=>auth.user.create", nil)
+		return sharedweb.Render(c, http.StatusOK, views.ViewRegister, views.RegisterPage{
+			Title:         uc.Messages.T(c.Request().Context(), "register.title"),
+			RegisterEmail: "",
+			Errors:        nil,
+		})
 	}
 }
 
@@ -268,7 +275,7 @@ func (uc UserController) Register() func(echo.Context) error {
 
 		sess, err := session.Get(auth.SessionName, c)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return fmt.Errorf("%w", err)
 		}
 
 		newUser := application.RegisterUserRequest{ //nolint:exhaustruct // other values will be set with bind below
@@ -278,27 +285,20 @@ func (uc UserController) Register() func(echo.Context) error {
 		}
 
 		if err = c.Bind(&newUser); err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return fmt.Errorf("%w", err)
 		}
 
 		response, err := uc.CmdRegisterUser(c.Request().Context(), newUser)
 		if err != nil {
-			valErrs := make(map[string]string)
-
-			var validationErrors validator.ValidationErrors
-
-			if !errors.As(err, &validationErrors) {
-				valErrs["RegisterEmail"] = "Invalid user name"
-			}
-
-			for _, e := range validationErrors {
-				valErrs[e.StructField()] = e.Translate(nil)
+			valErrs := uc.Validator.Errors(c.Request().Context(), err)
+			if valErrs == nil {
+				valErrs = map[string]string{"RegisterEmail": uc.Messages.T(c.Request().Context(), "login.invalid")}
 			}
 
-			return c.Render(http.StatusOK, "auth=>=>auth.user.create", map[string]any{
-				"Title":         "Registrieren",
-				"Errors":        valErrs,
-				"RegisterEmail": newUser.RegisterEmail,
+			return sharedweb.Render(c, http.StatusOK, views.ViewRegister, views.RegisterPage{
+				Title:         uc.Messages.T(c.Request().Context(), "register.title"),
+				RegisterEmail: newUser.RegisterEmail,
+				Errors:        valErrs,
 			})
 		}
 
@@ -314,11 +314,11 @@ func (uc UserController) Register() func(echo.Context) error {
 		sess.Values[auth.SessKeyLoggedIn] = true
 		sess.Values[auth.SessKeyUserID] = string(response.User.ID)
 
-		sess.AddFlash("Register successful")
+		sess.AddFlash(uc.Messages.T(c.Request().Context(), "register.successful"))
 
 		err = sess.Save(c.Request(), c.Response())
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return fmt.Errorf("%w", err)
 		}
 
 		err = setKnownDeviceCookie(uc.knownDeviceKeyPairs, c)
@@ -337,7 +337,7 @@ func (uc UserController) Verify() func(echo.Context) error {
 
 		token, err := uuid.Parse(t)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return echo.NewHTTPError(http.StatusBadRequest).SetInternal(err)
 		}
 
 		err = uc.CmdVerifyUser(c.Request().Context(), application.VerifyUserRequest{
@@ -345,7 +345,7 @@ func (uc UserController) Verify() func(echo.Context) error {
 			UserID: domain.ID(userID),
 		})
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return fmt.Errorf("%w", err)
 		}
 
 		return c.Redirect(http.StatusSeeOther, "/")
@@ -358,12 +358,12 @@ func (uc UserController) Show() func(echo.Context) error {
 
 		res, err := uc.CmdShowUserUser(c.Request().Context(), application.ShowUserRequest{UserID: domain.ID(userID)})
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return fmt.Errorf("%w", err)
 		}
 
-		return c.Render(http.StatusOK, "auth.user.show", echo.Map{
-			"Title": "Nutzer Profil",
-			"User":  res.User,
+		return sharedweb.Render(c, http.StatusOK, views.ViewUser, views.UserPage{
+			Title: uc.Messages.T(c.Request().Context(), "profile.title"),
+			User:  res.User,
 		})
 	}
 }
@@ -375,7 +375,7 @@ func (uc UserController) DestroySession(queries *models.Queries) func(echo.Conte
 
 		err := queries.DeleteSessionByKey(c.Request().Context(), []byte(sessionID))
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return fmt.Errorf("%w", err)
 		}
 
 		return c.Redirect(http.StatusSeeOther, "/admin/auth/users/"+userID)
@@ -384,7 +384,7 @@ func (uc UserController) DestroySession(queries *models.Queries) func(echo.Conte
 
 func (uc UserController) New() func(echo.Context) error {
 	return func(c echo.Context) error {
-		return c.Render(http.StatusOK, "auth.user.new", nil)
+		return sharedweb.Render(c, http.StatusOK, views.ViewNewUser, views.NewUserPage{}) //nolint:exhaustruct // the form is empty
 	}
 }
 
@@ -393,27 +393,23 @@ func (uc UserController) Store() func(echo.Context) error {
 		newUser := application.NewUserRequest{}
 
 		if err := c.Bind(&newUser); err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return fmt.Errorf("%w", err)
 		}
 
 		err := uc.CmdNewUser(c.Request().Context(), newUser)
 		if err != nil {
-			valErrs := make(map[string]string)
+			valErrs := uc.Validator.Errors(c.Request().Context(), err)
 
 			if errors.Is(err, domain.ErrUserAlreadyExists) {
-				valErrs["Email"] = "User already exists"
-			}
-
-			var validationErrors validator.ValidationErrors
-			if !errors.As(err, &validationErrors) {
-				for _, e := range validationErrors {
-					valErrs[e.StructField()] = e.Translate(nil)
-				}
+				valErrs = map[string]string{"Email": uc.Messages.T(c.Request().Context(), "users.already_exists")}
 			}
 
-			return c.Render(http.StatusOK, "auth.user.new", map[string]any{
-				"Errors": valErrs,
-				"Email":  newUser.Email,
+			return sharedweb.Render(c, http.StatusOK, views.ViewNewUser, views.NewUserPage{
+				Email:       newUser.Email,
+				FirstName:   newUser.FirstName,
+				LastName:    newUser.LastName,
+				DisplayName: newUser.DisplayName,
+				Errors:      valErrs,
 			})
 		}
 
@@ -427,12 +423,12 @@ func (uc UserController) BlockUser() {
 			UserID: domain.ID(c.Param("userID")),
 		})
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return fmt.Errorf("%w", err)
 		}
 
-		return c.Render(http.StatusOK, "users#user.blocked", echo.Map{
-			"ID":      uuid.MustParse(string(res.UserID)),
-			"Blocked": domain.BoolFlag(res.Blocked.At()),
+		return sharedweb.Render(c, http.StatusOK, views.ViewUserBlocked, views.BlockedUser{
+			ID:      uuid.MustParse(string(res.UserID)),
+			Blocked: domain.BoolFlag(res.Blocked.At()),
 		})
 	})
 }
@@ -443,18 +439,18 @@ func (uc UserController) UnBlockUser() {
 			UserID: domain.ID(c.Param("userID")),
 		})
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return fmt.Errorf("%w", err)
 		}
 
-		return c.Render(http.StatusOK, "users#user.blocked", echo.Map{
-			"ID":      uuid.MustParse(string(res.UserID)),
-			"Blocked": domain.BoolFlag(res.Blocked.At()),
+		return sharedweb.Render(c, http.StatusOK, views.ViewUserBlocked, views.BlockedUser{
+			ID:      uuid.MustParse(string(res.UserID)),
+			Blocked: domain.BoolFlag(res.Blocked.At()),
 		})
 	})
 }
 
 func (uc UserController) Profile() func(echo.Context) error {
 	return func(c echo.Context) error {
-		return c.Render(http.StatusOK, "profile", nil)
+		return sharedweb.Render(c, http.StatusOK, views.ViewProfile, views.ProfilePage{})
 	}
 }
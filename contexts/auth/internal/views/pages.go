@@ -0,0 +1,46 @@
+package views
+
+import "github.com/go-arrower/skeleton/contexts/auth/internal/domain"
+
+// HomePage, ProfilePage, and SettingsPage have no data of their own (yet).
+type (
+	HomePage     struct{}
+	ProfilePage  struct{}
+	SettingsPage struct{}
+)
+
+type LoginPage struct {
+	LoginEmail string
+	// Errors are the validation errors by the name of the field.
+	Errors map[string]string
+}
+
+type RegisterPage struct {
+	Title         string
+	RegisterEmail string
+	Errors        map[string]string
+}
+
+type UsersPage struct {
+	Title         string
+	Users         []domain.User
+	CurrentUserID string
+	Filtered      uint
+	Total         uint
+	Query         string
+	// CouldBeEmpty is true for the first page of users, so an empty list shows a hint.
+	CouldBeEmpty bool
+}
+
+type UserPage struct {
+	Title string
+	User  domain.User
+}
+
+type NewUserPage struct {
+	Email       string
+	FirstName   string
+	LastName    string
+	DisplayName string
+	Errors      map[string]string
+}
@@ -0,0 +1,43 @@
+package views
+
+import (
+	"embed"
+
+	"github.com/google/uuid"
+
+	"github.com/go-arrower/skeleton/contexts/auth/internal/domain"
+	"github.com/go-arrower/skeleton/shared/infrastructure/web"
+)
+
+//go:embed *.html **/*.html locales/*.json
+var AuthViews embed.FS
+
+type BlockedUser struct {
+	ID      uuid.UUID
+	Blocked domain.BoolFlag
+}
+
+var (
+	ViewHome        = web.NewView[HomePage]("home")
+	ViewProfile     = web.NewView[ProfilePage]("profile")
+	ViewSettings    = web.NewView[SettingsPage]("auth.settings")
+	ViewLogin       = web.NewView[LoginPage]("auth=>=>auth.login")
+	ViewRegister    = web.NewView[RegisterPage]("auth=>=>auth.user.create")
+	ViewUsers       = web.NewView[UsersPage]("users")
+	ViewUserBlocked = web.NewView[BlockedUser]("users#user.blocked")
+	ViewUser        = web.NewView[UserPage]("auth.user.show")
+	ViewNewUser     = web.NewView[NewUserPage]("auth.user.new")
+)
+
+// Views are all typed views of the auth context, so they can be checked by web.CheckViews.
+var Views = []web.TypedView{
+	ViewHome,
+	ViewProfile,
+	ViewSettings,
+	ViewLogin,
+	ViewRegister,
+	ViewUsers,
+	ViewUserBlocked,
+	ViewUser,
+	ViewNewUser,
+}
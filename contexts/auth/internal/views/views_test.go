@@ -0,0 +1,18 @@
+package views_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/contexts/auth/internal/views"
+	"github.com/go-arrower/skeleton/shared/infrastructure/web"
+	sharedviews "github.com/go-arrower/skeleton/shared/views"
+)
+
+func TestAuthViews(t *testing.T) {
+	t.Parallel()
+
+	err := web.CheckViews(sharedviews.SharedViews, views.AuthViews, views.Views...)
+	assert.NoError(t, err)
+}
@@ -8,73 +8,216 @@ import (
 	"math/rand"
 	"net"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/brianvoe/gofakeit/v6"
 	"github.com/go-arrower/arrower/alog"
 	"github.com/go-arrower/arrower/app"
 	"github.com/go-arrower/arrower/mw"
-	"github.com/go-arrower/arrower/secret"
 	"github.com/labstack/echo-contrib/session"
 	"github.com/labstack/echo/v4"
+	"github.com/spf13/cobra"
 
 	admin_init "github.com/go-arrower/skeleton/contexts/admin/init"
 	"github.com/go-arrower/skeleton/contexts/auth"
 	auth_init "github.com/go-arrower/skeleton/contexts/auth/init"
 	"github.com/go-arrower/skeleton/shared/application"
 	"github.com/go-arrower/skeleton/shared/infrastructure"
+	sharedweb "github.com/go-arrower/skeleton/shared/infrastructure/web"
 	"github.com/go-arrower/skeleton/shared/interfaces/web"
+	"github.com/go-arrower/skeleton/shared/views/pages"
 )
 
 func main() {
-	ctx, _ := context.WithCancel(context.Background())
-
-	arrower, shutdown, err := infrastructure.InitialiseDefaultArrowerDependencies(ctx,
-		&infrastructure.Config{
-			OrganisationName: "arrower",
-			ApplicationName:  "skeleton",
-			InstanceName:     getOutboundIP(),
-			Debug:            true,
-			Postgres: infrastructure.Postgres{
-				User:     "arrower",
-				Password: secret.New("secret"),
-				Database: "arrower",
-				Host:     "localhost",
-				Port:     5432, //nolint:gomnd
-				SSLMode:  "disable",
-				MaxConns: 100, //nolint:gomnd
-			},
-			Web: infrastructure.Web{
-				Secret:             secret.New("secret"),
-				Port:               8080,
-				Hostname:           "www.servername.tld",
-				StatusEndpoint:     true,
-				StatusEndpointPort: 2223,
-			},
-			OTEL: infrastructure.OTEL{
-				Host: "localhost",
-				Port: 4317,
-			},
-		})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p, err := newProgram()
 	if err != nil {
-		panic(err)
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1) //nolint:gocritic // cancel is not needed anymore
+	}
+
+	err = p.command().ExecuteContext(ctx)
+	if shutdownErr := p.shutdown(); shutdownErr != nil {
+		err = errors.Join(err, shutdownErr)
+	}
+
+	if errors.Is(err, infrastructure.ErrConfigPrinted) {
+		return
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1) //nolint:gocritic // cancel is not needed anymore
+	}
+}
+
+// program holds the dependencies shared by all commands.
+// The Container is initialised by the first command using it, so commands work against the same dependencies.
+type program struct {
+	config     *infrastructure.Config
+	authConfig *auth_init.Config
+	loader     *infrastructure.ConfigLoader
+
+	arrower *infrastructure.Container
+}
+
+func newProgram() (*program, error) {
+	config := infrastructure.DefaultConfig()
+	authConfig := auth_init.DefaultConfig()
+
+	loader := infrastructure.NewConfigLoader(config)
+	if err := loader.Register("auth", authConfig); err != nil {
+		return nil, fmt.Errorf("could not register auth config: %w", err)
+	}
+
+	return &program{
+		config:     config,
+		authConfig: authConfig,
+		loader:     loader,
+	}, nil
+}
+
+// command returns the root command. Without a subcommand, it serves the application, as it always did.
+// The flags of the config are accepted by all commands.
+func (p *program) command() *cobra.Command {
+	root := &cobra.Command{
+		Use:   "skeleton",
+		Short: "Serve the application or run one of the commands",
+		Args:  cobra.NoArgs,
+		PersistentPreRunE: func(*cobra.Command, []string) error {
+			return p.loader.LoadParsed() //nolint:wrapcheck // the error is shown as is
+		},
+		RunE:          p.serve,
+		SilenceErrors: true, // main prints the error
+		SilenceUsage:  true, // the usage hides the error of a command, that failed while running
+	}
+	root.PersistentFlags().AddFlagSet(p.loader.Flags())
+
+	root.AddCommand(
+		&cobra.Command{
+			Use:   "serve",
+			Short: "Serve the web application and work on the jobs, as configured by the role",
+			Args:  cobra.NoArgs,
+			RunE:  p.serve,
+		},
+		&cobra.Command{
+			Use:   "worker",
+			Short: "Work on the jobs only, without serving the web application",
+			Args:  cobra.NoArgs,
+			RunE:  p.work,
+		},
+	)
+
+	infrastructure.RegisterCommands(root, p.setup)
+	admin_init.RegisterCommands(root, p.setup)
+	auth_init.RegisterCommands(root, p.setup)
+
+	return root
+}
+
+// setup initialises the Container once. It is the infrastructure.Setup of all commands.
+func (p *program) setup(ctx context.Context, opts ...infrastructure.ContainerOption) (*infrastructure.Container, error) {
+	if p.arrower != nil {
+		return p.arrower, nil
+	}
+
+	if p.config.InstanceName == "" {
+		p.config.InstanceName = getOutboundIP()
+	}
+
+	arrower, _, err := infrastructure.InitialiseDefaultArrowerDependencies(ctx, p.config, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not initialise dependencies: %w", err)
 	}
 
 	//err = arrower.Settings.Save(ctx, alog.SettingLogLevel, setting.NewValue(int(slog.LevelDebug)))
 	//alog.Unwrap(arrower.Logger).SetLevel(slog.LevelDebug)
 	alog.Unwrap(arrower.Logger).SetLevel(alog.LevelDebug)
 
-	//
-	// load and initialise optional contexts provided by arrower
-	adminContext, _ := admin_init.NewAdminContext(ctx, arrower)
-	authContext, _ := auth_init.NewAuthContext(arrower)
+	p.arrower = arrower
+
+	return arrower, nil
+}
+
+// shutdown shuts down the Container of the commands, that run once.
+// After serve or work it does nothing, as the Lifecycle shut it down already.
+func (p *program) shutdown() error {
+	if p.arrower == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), p.arrower.Config.ShutdownTimeout)
+	defer cancel()
+
+	return p.arrower.Lifecycle.Shutdown(ctx) //nolint:wrapcheck // the error is shown as is
+}
+
+// initialiseContexts loads and initialises the optional contexts provided by arrower.
+func (p *program) initialiseContexts(ctx context.Context, arrower *infrastructure.Container) error {
+	if _, err := admin_init.NewAdminContext(ctx, arrower); err != nil {
+		return err //nolint:wrapcheck // the error is shown as is
+	}
+
+	if _, err := auth_init.NewAuthContext(arrower, *p.authConfig); err != nil {
+		return err //nolint:wrapcheck // the error is shown as is
+	}
+
+	return nil
+}
+
+// serve runs the instance in the Role of the config, until it is shut down.
+// Without the web role, only the status endpoint is served.
+func (p *program) serve(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	arrower, err := p.setup(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the contexts register their routes and jobs
+	if err = p.initialiseContexts(ctx, arrower); err != nil {
+		return err
+	}
+
+	var servers []func() error
+
+	if arrower.Config.Role.ServesWeb() {
+		registerExampleRoutes(arrower)
+
+		servers = append(servers, func() error {
+			return arrower.WebRouter.Start(fmt.Sprintf(":%d", arrower.Config.Web.Port)) //nolint:wrapcheck // wrapped by Run
+		})
+	}
 
 	//
-	// example route for a simple one-file setup
+	// start app
+	// initRegularExampleQueueLoad(ctx, arrower)
+	// and shut it down gracefully on SIGINT or SIGTERM
+	err = arrower.Lifecycle.Run(ctx, arrower.Config.ShutdownTimeout, servers...)
+	if err != nil {
+		return fmt.Errorf("could not shutdown gracefully: %w", err)
+	}
+
+	return nil
+}
+
+// work runs the instance in the worker Role, independent of the config.
+func (p *program) work(cmd *cobra.Command, args []string) error {
+	p.config.Role = infrastructure.RoleWorker
+
+	return p.serve(cmd, args)
+}
+
+// registerExampleRoutes adds the routes for a simple one-file setup.
+func registerExampleRoutes(arrower *infrastructure.Container) {
 	arrower.WebRouter.GET("/", func(c echo.Context) error {
 		sess, err := session.Get(auth.SessionName, c)
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return fmt.Errorf("%w", err)
 		}
 
 		userID := "World"
@@ -86,39 +229,20 @@ func main() {
 
 		err = sess.Save(c.Request(), c.Response())
 		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			return fmt.Errorf("%w", err)
 		}
 
-		p := map[string]interface{}{
-			"Title":   "Welcome to Arrower!",
-			"userID":  userID,
-			"Flashes": flashes,
-			"UserID":  userID,
-		}
-
-		return c.Render(http.StatusOK, "=>home", p)
-		//return c.Render(http.StatusOK, "=>home", echo.Map{
-		//	"Flashes": flashes,
-		//	"userID":  userID,
-		//})
+		return sharedweb.Render(c, http.StatusOK, pages.ViewHome, pages.Home{
+			Title:   "Welcome to Arrower!",
+			UserID:  userID,
+			Flashes: flashes,
+		})
 	})
 
 	helloController := web.NewHelloController(application.App{
 		SayHello: application.NewSayHelloRequestHandler(arrower.Logger),
 	})
 	arrower.WebRouter.GET("/hello/:name", helloController.SayHello())
-
-	//
-	// start app
-	// initRegularExampleQueueLoad(ctx, arrower)
-	arrower.WebRouter.Logger.Fatal(arrower.WebRouter.Start(fmt.Sprintf(":%d", arrower.Config.Web.Port)))
-
-	//
-	// shutdown app
-	// todo implement graceful shutdown ect
-	_ = shutdown(ctx)
-	_ = authContext.Shutdown(ctx)
-	_ = adminContext.Shutdown(ctx)
 }
 
 func initRegularExampleQueueLoad(ctx context.Context, di *infrastructure.Container) {
@@ -162,32 +286,10 @@ func initRegularExampleQueueLoad(ctx context.Context, di *infrastructure.Contain
 		))),
 	)
 
-	go func() {
-		ticker := time.NewTicker(10 * time.Second)
-		for {
-			select {
-			case <-ticker.C:
-				r := rand.Intn(100)
-
-				if r%5 == 0 {
-					_ = di.DefaultQueue.Enqueue(ctx, SomeJob{})
-				}
-
-				if r%12 == 0 {
-					for i := 0; i/2 < r; i++ {
-						// for i := range r { // fixme use new go1.22 style
-						_ = di.DefaultQueue.Enqueue(ctx, NamedJob{Name: gofakeit.Name()})
-					}
-				}
-
-				if r == 0 {
-					_ = di.DefaultQueue.Enqueue(ctx, LongRunningJob{})
-				}
-			case <-ctx.Done():
-				return
-			}
-		}
-	}()
+	// recurring example load, run by the leading instance only
+	_ = di.Scheduler.Register(ctx, "example.some-job", "*/2 * * * *", "", SomeJob{})
+	_ = di.Scheduler.Register(ctx, "example.named-job", "*/5 * * * *", "", NamedJob{Name: gofakeit.Name()})
+	_ = di.Scheduler.Register(ctx, "example.long-running-job", "@hourly", "", LongRunningJob{})
 }
 
 // Get preferred outbound ip of this machine.
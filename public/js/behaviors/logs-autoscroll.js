@@ -1,6 +1,8 @@
 if (!window.Arrower) window.Arrower = {};
 
 document.addEventListener("htmx:beforeOnLoad", leave);
+// logs is sent by the server, whenever new logs are written, see /admin/events
+document.addEventListener("htmx:sseOpen", listenForNewLogs);
 
 Arrower.Log = {
   auto: true,
@@ -73,11 +75,24 @@ document.querySelectorAll("[data-js-logs-autoscroll]").forEach((elem) => {
   }, 100);
 });
 
+function listenForNewLogs(e) {
+  e.detail.source.addEventListener("logs", loadNewLogs);
+}
+
+// loadNewLogs appends the new logs after the last row, it is the only one loading them.
+function loadNewLogs() {
+  const tails = document.querySelectorAll("[data-js-logs-tail]");
+  if (tails.length > 0) {
+    htmx.trigger(tails[tails.length - 1], "arrower:admin.logs.new");
+  }
+}
+
 function leave(e) {
   if (!e.detail.pathInfo.finalRequestPath.startsWith("/admin/logs")) {
     // user leaves page
     window.clearInterval(Arrower.Log.autoScrollerRenderer);
     document.removeEventListener("htmx:beforeOnLoad", leave);
+    document.removeEventListener("htmx:sseOpen", listenForNewLogs);
     Arrower.Log = null; // gc this state
   }
 }
@@ -64,3 +64,29 @@ async function updateProcessedLineChart(processedChart, interval) {
     ],
   });
 }
+
+// jobs.finished is sent by the server, whenever jobs have been processed, see /admin/events
+document.addEventListener("htmx:sseOpen", listenForFinishedJobs);
+document.addEventListener("htmx:beforeOnLoad", leaveProcessedJobs);
+
+function listenForFinishedJobs(e) {
+  e.detail.source.addEventListener("jobs.finished", updateAllProcessedLineCharts);
+}
+
+function updateAllProcessedLineCharts() {
+  document.querySelectorAll("[data-js-processed-jobs]").forEach((elem) => {
+    let interval = elem.getAttribute("data-interval");
+    interval = interval ? interval : "";
+
+    updateProcessedLineChart(echarts.init(elem), interval);
+  });
+}
+
+// named differently than the leave of the other behaviours, as they are loaded on the same page
+function leaveProcessedJobs(e) {
+  if (e.detail.pathInfo.finalRequestPath !== "/admin/jobs") {
+    // user leaves page
+    document.removeEventListener("htmx:beforeOnLoad", leaveProcessedJobs);
+    document.removeEventListener("htmx:sseOpen", listenForFinishedJobs);
+  }
+}
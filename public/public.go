@@ -0,0 +1,7 @@
+// Package public contains the static assets served by the web server.
+package public
+
+import "embed"
+
+//go:embed css js
+var Assets embed.FS
@@ -0,0 +1,219 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/go-arrower/arrower/setting"
+	"github.com/spf13/cobra"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/maintenance"
+	"github.com/go-arrower/skeleton/shared/infrastructure/migrations"
+)
+
+// Setup returns the Container initialised with opts.
+// Commands call it, once their flags and the config are loaded, so all commands work against the same dependencies.
+type Setup func(ctx context.Context, opts ...ContainerOption) (*Container, error)
+
+// RegisterCommands adds the commands of the shared dependencies: migrate, settings, and maintenance.
+func RegisterCommands(root *cobra.Command, setup Setup) {
+	migrate := &cobra.Command{Use: "migrate", Short: "Manage the database schema of the skeleton"}
+	migrate.AddCommand(
+		&cobra.Command{
+			Use:   "up",
+			Short: "Apply all pending migrations",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, _ []string) error {
+				di, err := setupOnce(cmd.Context(), setup, WithoutMigrations())
+				if err != nil {
+					return err
+				}
+
+				return migrations.Up(cmd.Context(), di.PGx) //nolint:wrapcheck // the error is shown as is
+			},
+		},
+		migrateDownCommand(setup),
+		&cobra.Command{
+			Use:   "status",
+			Short: "Show the applied and pending migrations",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, _ []string) error {
+				di, err := setupOnce(cmd.Context(), setup, WithoutMigrations())
+				if err != nil {
+					return err
+				}
+
+				states, err := migrations.Status(cmd.Context(), di.PGx)
+				if err != nil {
+					return err //nolint:wrapcheck // the error is shown as is
+				}
+
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0) //nolint:gomnd // padding of the columns
+				fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+
+				for _, s := range states {
+					appliedAt := "pending"
+					if s.IsApplied() {
+						appliedAt = s.AppliedAt.Format(time.RFC3339)
+					}
+
+					fmt.Fprintf(w, "%06d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+				}
+
+				return w.Flush() //nolint:wrapcheck // the error is shown as is
+			},
+		},
+	)
+
+	settings := &cobra.Command{Use: "settings", Short: "Read and change the settings of all instances"}
+	settings.AddCommand(
+		&cobra.Command{
+			Use:   "get <context> <group> <name>",
+			Short: "Show a setting",
+			Args:  cobra.ExactArgs(3), //nolint:gomnd // the parts of the key
+			RunE: func(cmd *cobra.Command, args []string) error {
+				di, err := setupOnce(cmd.Context(), setup)
+				if err != nil {
+					return err
+				}
+
+				val, err := di.Settings.Setting(cmd.Context(), setting.NewKey(args[0], args[1], args[2]))
+				if err != nil {
+					return fmt.Errorf("could not get setting: %w", err)
+				}
+
+				fmt.Fprintln(cmd.OutOrStdout(), val.String())
+
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:   "set <context> <group> <name> <value>",
+			Short: "Change a setting. Booleans and integers are stored as such, everything else as a string",
+			Args:  cobra.ExactArgs(4), //nolint:gomnd // the parts of the key and the value
+			RunE: func(cmd *cobra.Command, args []string) error {
+				di, err := setupOnce(cmd.Context(), setup)
+				if err != nil {
+					return err
+				}
+
+				err = di.Settings.Save(cmd.Context(), setting.NewKey(args[0], args[1], args[2]), parseValue(args[3]))
+				if err != nil {
+					return fmt.Errorf("could not save setting: %w", err)
+				}
+
+				return nil
+			},
+		},
+	)
+
+	maintenanceCmd := &cobra.Command{Use: "maintenance", Short: "Put all instances into maintenance"}
+	maintenanceCmd.AddCommand(
+		maintenanceOnCommand(setup),
+		&cobra.Command{
+			Use:   "off",
+			Short: "End the maintenance",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, _ []string) error {
+				di, err := setupOnce(cmd.Context(), setup)
+				if err != nil {
+					return err
+				}
+
+				mode := maintenance.Load(cmd.Context(), di.Settings)
+				mode.Enabled = false
+
+				return maintenance.Save(cmd.Context(), di.Settings, mode) //nolint:wrapcheck // the error is shown as is
+			},
+		},
+	)
+
+	root.AddCommand(migrate, settings, maintenanceCmd)
+}
+
+func migrateDownCommand(setup Setup) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "down",
+		Short: "Revert the last applied migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			di, err := setupOnce(cmd.Context(), setup, WithoutMigrations())
+			if err != nil {
+				return err
+			}
+
+			steps, _ := cmd.Flags().GetInt("steps")
+
+			return migrations.Down(cmd.Context(), di.PGx, steps) //nolint:wrapcheck // the error is shown as is
+		},
+	}
+	cmd.Flags().Int("steps", 1, "number of migrations to revert")
+
+	return cmd
+}
+
+func maintenanceOnCommand(setup Setup) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "on",
+		Short: "Start the maintenance. Superusers and the allowed IPs can still use the application",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+
+			di, err := setupOnce(ctx, setup)
+			if err != nil {
+				return err
+			}
+
+			// keep the mode as configured in the admin, unless a flag changes it
+			mode := maintenance.Load(ctx, di.Settings)
+			mode.Enabled = true
+
+			flags := cmd.Flags()
+			if flags.Changed("message") {
+				mode.Message, _ = flags.GetString("message")
+			}
+
+			if flags.Changed("retry-after") {
+				mode.RetryAfter, _ = flags.GetDuration("retry-after")
+			}
+
+			if flags.Changed("pause-jobs") {
+				mode.PauseJobs, _ = flags.GetBool("pause-jobs")
+			}
+
+			if flags.Changed("allow-ip") {
+				mode.AllowedIPs, _ = flags.GetStringSlice("allow-ip")
+			}
+
+			return maintenance.Save(ctx, di.Settings, mode) //nolint:wrapcheck // the error is shown as is
+		},
+	}
+	cmd.Flags().String("message", "", "message shown to the users")
+	cmd.Flags().Duration("retry-after", 5*time.Minute, "how long clients are told to wait") //nolint:gomnd
+	cmd.Flags().Bool("pause-jobs", false, "pause all job queues")
+	cmd.Flags().StringSlice("allow-ip", nil, "IP or CIDR, that can use the application")
+
+	return cmd
+}
+
+// setupOnce initialises the Container for a command, that runs once.
+func setupOnce(ctx context.Context, setup Setup, opts ...ContainerOption) (*Container, error) {
+	return setup(ctx, append([]ContainerOption{WithoutBackgroundServices()}, opts...)...)
+}
+
+// parseValue returns the value as the type it looks like, so it can be read by MustBool and MustInt.
+func parseValue(value string) setting.Value {
+	if b, err := strconv.ParseBool(value); err == nil {
+		return setting.NewValue(b)
+	}
+
+	if i, err := strconv.Atoi(value); err == nil {
+		return setting.NewValue(i)
+	}
+
+	return setting.NewValue(value)
+}
@@ -0,0 +1,83 @@
+package infrastructure_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure"
+)
+
+var errSetup = errors.New("some error")
+
+func TestRegisterCommands(t *testing.T) {
+	t.Parallel()
+
+	// newRoot returns the root command with the shared commands, that records the calls of setup.
+	newRoot := func(calls *int, out *bytes.Buffer) *cobra.Command {
+		root := &cobra.Command{Use: "app", SilenceErrors: true, SilenceUsage: true}
+		root.SetOut(out)
+		root.SetErr(out)
+
+		infrastructure.RegisterCommands(root, func(context.Context, ...infrastructure.ContainerOption) (*infrastructure.Container, error) {
+			*calls++
+
+			return nil, errSetup
+		})
+
+		return root
+	}
+
+	t.Run("run command", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			calls int
+			out   bytes.Buffer
+		)
+
+		root := newRoot(&calls, &out)
+		root.SetArgs([]string{"maintenance", "on", "--message", "back soon"})
+
+		err := root.ExecuteContext(context.Background())
+		assert.ErrorIs(t, err, errSetup)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("invalid args", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			calls int
+			out   bytes.Buffer
+		)
+
+		root := newRoot(&calls, &out)
+		root.SetArgs([]string{"settings", "get", "admin", "logs"})
+
+		err := root.ExecuteContext(context.Background())
+		assert.Error(t, err)
+		assert.Equal(t, 0, calls, "the dependencies are not initialised")
+	})
+
+	t.Run("help", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			calls int
+			out   bytes.Buffer
+		)
+
+		root := newRoot(&calls, &out)
+		root.SetArgs([]string{"migrate", "--help"})
+
+		err := root.ExecuteContext(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 0, calls)
+		assert.Contains(t, out.String(), "Revert the last applied migrations")
+	})
+}
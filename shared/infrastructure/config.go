@@ -1,44 +1,680 @@
 package infrastructure // todo config would be a better name OR move it to arrower.Config
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/go-arrower/arrower/secret"
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/ratelimit"
+)
+
+var (
+	ErrInvalidConfig = errors.New("invalid config")
+	// ErrConfigPrinted is returned by Load, if the config was printed because of --print-config
+	// or the usage because of --help.
+	// The application is expected to exit afterwards.
+	ErrConfigPrinted = errors.New("config printed")
 )
 
 // Config is a structure used for service configuration.
-// It can be mapped from env variables or config files, e.g. by viper.
+// It is loaded by a ConfigLoader from a config file, env variables and flags.
 type Config struct {
-	OrganisationName string `mapstructure:"organisation_name"`
-	ApplicationName  string `mapstructure:"application_name"`
+	OrganisationName string `mapstructure:"organisation_name" validate:"required"`
+	ApplicationName  string `mapstructure:"application_name"  validate:"required"`
 	InstanceName     string `mapstructure:"instance_name"`
 
 	Debug bool `mapstructure:"debug"`
+	// ShutdownTimeout is how long the application waits for running requests and jobs to finish, when it stops.
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+	// Role is what the instance runs, so the web and the workers can be scaled independently.
+	Role Role `mapstructure:"role" validate:"oneof=all web worker"`
 
 	Postgres Postgres `mapstructure:"postgres"`
 	Web      Web      `mapstructure:"web"`
+	Status   Status   `mapstructure:"status"`
 	OTEL     OTEL     `mapstructure:"otel"`
+	Mail     Mail     `mapstructure:"mail"`
+	Logs     Logs     `mapstructure:"logs"`
+
+	RateLimits RateLimits `mapstructure:"rate_limits"`
+}
+
+// Role is what an instance runs. All instances use the same database,
+// so the jobs enqueued by a web instance are worked on by the worker instances.
+type Role string
+
+const (
+	RoleAll    Role = "all"
+	RoleWeb    Role = "web"
+	RoleWorker Role = "worker"
+)
+
+// ServesWeb returns true, if the instance serves the web application.
+func (r Role) ServesWeb() bool {
+	return r == RoleAll || r == RoleWeb
+}
+
+// RunsJobs returns true, if the instance works on the jobs and runs the scheduler.
+func (r Role) RunsJobs() bool {
+	return r == RoleAll || r == RoleWorker
+}
+
+// The default ports of the OTLP collector, see OTEL.
+const (
+	defaultOTLPGRPCPort = 4317
+	defaultOTLPHTTPPort = 4318
+)
+
+// Endpoint returns the address of the collector, with the default port of the exporter, if no Port is set.
+func (o OTEL) Endpoint() string {
+	port := o.Port
+
+	if port == 0 {
+		port = defaultOTLPGRPCPort
+		if o.Traces == ExporterOTLPHTTP {
+			port = defaultOTLPHTTPPort
+		}
+	}
+
+	return fmt.Sprintf("%s:%d", o.Host, port)
 }
 
 type (
 	Postgres struct {
-		User     string        `json:"user"     mapstructure:"user"`
+		User     string        `json:"user"     mapstructure:"user"      validate:"required"`
 		Password secret.Secret `json:"-"        mapstructure:"password"`
-		Database string        `json:"database" mapstructure:"database"`
-		Host     string        `json:"host"     mapstructure:"host"`
-		Port     int           `json:"port"     mapstructure:"port"`
-		SSLMode  string        `json:"sslMode"  mapstructure:"ssl_mode"`
-		MaxConns int           `json:"maxConns" mapstructure:"max_conns"`
+		Database string        `json:"database" mapstructure:"database"  validate:"required"`
+		Host     string        `json:"host"     mapstructure:"host"      validate:"required"`
+		Port     int           `json:"port"     mapstructure:"port"      validate:"min=1,max=65535"`
+		SSLMode  string        `json:"sslMode"  mapstructure:"ssl_mode"  validate:"omitempty,oneof=disable allow prefer require verify-ca verify-full"` //nolint:lll // validation is long
+		MaxConns int           `json:"maxConns" mapstructure:"max_conns" validate:"min=0"`
 	}
 
 	Web struct {
 		Hostname           string        `json:"hostname" mapstructure:"hostname"`
-		Port               int           `json:"port"     mapstructure:"port"`
-		Secret             secret.Secret `json:"-"        mapstructure:"secret"`
+		Port               int           `json:"port"     mapstructure:"port"                 validate:"min=1,max=65535"`
+		Secret             secret.Secret `json:"-"        mapstructure:"secret"               validate:"required"`
 		StatusEndpoint     bool          `json:"-"        mapstructure:"status_endpoint"`
-		StatusEndpointPort int           `json:"-"        mapstructure:"status_endpoint_port"`
+		StatusEndpointPort int           `json:"-"        mapstructure:"status_endpoint_port" validate:"required_if=StatusEndpoint true,omitempty,min=1,max=65535"` //nolint:lll // validation is long
+	}
+
+	// Status protects the status endpoint, served on Web.StatusEndpointPort.
+	// With a User or a Token, requests have to authenticate with basic or bearer auth.
+	// With AllowedIPs, only these IPs or CIDRs are served.
+	// The probes /livez and /readyz are always served, so an orchestrator can reach them.
+	Status struct {
+		User       string        `json:"-" mapstructure:"user"`
+		Password   secret.Secret `json:"-" mapstructure:"password"    validate:"required_with=User"`
+		Token      secret.Secret `json:"-" mapstructure:"token"`
+		AllowedIPs []string      `json:"-" mapstructure:"allowed_ips" validate:"dive,ip|cidr"`
+		// Pprof serves the profiles of net/http/pprof under /debug/pprof/. It requires the endpoint to be protected.
+		Pprof bool `json:"-" mapstructure:"pprof"`
+		// ShowConfig adds the config to the status payload. Hosts, users, and secrets are always redacted.
+		ShowConfig bool `json:"-" mapstructure:"show_config"`
 	}
 
+	// OTEL configures where the traces and metrics are exported to.
+	// Host and Port are the collector of the OTLP exporters. Without a Port, the default port
+	// of the exporter is used: 4317 for otlp-grpc and 4318 for otlp-http.
 	OTEL struct {
-		Host string `json:"host" mapstructure:"host"`
-		Port int    `json:"port" mapstructure:"port"`
+		Host     string `json:"host"     mapstructure:"host"     validate:"required_if=Traces otlp-grpc,required_if=Traces otlp-http"` //nolint:lll // validation is long
+		Port     int    `json:"port"     mapstructure:"port"     validate:"omitempty,min=1,max=65535"`
+		Insecure bool   `json:"insecure" mapstructure:"insecure"`
+		// Traces is the exporter of the spans: none, stdout, file, otlp-grpc, or otlp-http.
+		Traces     string `json:"traces"     mapstructure:"traces"      validate:"oneof=none stdout file otlp-grpc otlp-http"`
+		TracesFile string `json:"tracesFile" mapstructure:"traces_file" validate:"required_if=Traces file"`
+		// Metrics is the exporter of the metrics: none, stdout, file, or prometheus, served by the status endpoint.
+		Metrics     string `json:"metrics"     mapstructure:"metrics"      validate:"oneof=none stdout file prometheus"`
+		MetricsFile string `json:"metricsFile" mapstructure:"metrics_file" validate:"required_if=Metrics file"`
+		// SampleRatio is the share of the traces, that are sampled. Spans follow the decision of their parent.
+		SampleRatio float64 `json:"sampleRatio" mapstructure:"sample_ratio" validate:"min=0,max=1"`
+	}
+
+	// Mail is the SMTP server to send mails with. Without a Host, no mails are sent.
+	Mail struct {
+		Host     string        `json:"host" mapstructure:"host"`
+		Port     int           `json:"port" mapstructure:"port"     validate:"required_with=Host,omitempty,min=1,max=65535"`
+		User     string        `json:"user" mapstructure:"user"`
+		Password secret.Secret `json:"-"    mapstructure:"password"`
+		From     string        `json:"from" mapstructure:"from"     validate:"required_with=Host"`
+	}
+
+	// RateLimits limit the requests of each client, so the login, the registration, and the API are not abused.
+	RateLimits struct {
+		// Store keeps the buckets: memory for a single instance, or postgres to share them with all instances.
+		Store string `json:"store" mapstructure:"store" validate:"oneof=memory postgres"`
+		// Auth limits the logins and registrations of each IP.
+		Auth RateLimit `json:"auth" mapstructure:"auth"`
+		// API limits the requests of each API key and of each IP, so a new key does not escape the limit.
+		API RateLimit `json:"api" mapstructure:"api"`
+	}
+
+	// RateLimit allows Requests per Period, with bursts of up to Burst requests. Without Requests, there is no limit.
+	RateLimit struct {
+		Requests int           `json:"requests" mapstructure:"requests" validate:"min=0"`
+		Period   time.Duration `json:"period"   mapstructure:"period"   validate:"required_with=Requests"`
+		Burst    int           `json:"burst"    mapstructure:"burst"    validate:"min=0"`
+	}
+
+	// Logs configures the logs written into postgres in debug mode.
+	// Without an ArchiveDir, logs can only be pruned without being archived.
+	Logs struct {
+		ArchiveDir string `json:"archiveDir" mapstructure:"archive_dir"`
+	}
+)
+
+// Limit returns the ratelimit.Limit to limit a route group with.
+func (l RateLimit) Limit() ratelimit.Limit {
+	return ratelimit.Limit{
+		Rate:   l.Requests,
+		Period: l.Period,
+		Burst:  l.Burst,
+	}
+}
+
+// DefaultConfig returns the configuration used for all values, that are not configured otherwise.
+// Secrets have no defaults, they always have to be configured.
+func DefaultConfig() *Config {
+	return &Config{
+		OrganisationName: "arrower",
+		ApplicationName:  "skeleton",
+		InstanceName:     "",
+		Debug:            false,
+		ShutdownTimeout:  30 * time.Second, //nolint:gomnd
+		Role:             RoleAll,
+		Postgres: Postgres{
+			User:     "arrower",
+			Password: secret.New(""),
+			Database: "arrower",
+			Host:     "localhost",
+			Port:     5432, //nolint:gomnd
+			SSLMode:  "disable",
+			MaxConns: 100, //nolint:gomnd
+		},
+		Web: Web{
+			Hostname:           "www.servername.tld",
+			Port:               8080, //nolint:gomnd
+			Secret:             secret.New(""),
+			StatusEndpoint:     true,
+			StatusEndpointPort: 2223, //nolint:gomnd
+		},
+		Status: Status{
+			User:       "",
+			Password:   secret.New(""),
+			Token:      secret.New(""),
+			AllowedIPs: nil,
+			Pprof:      false,
+			ShowConfig: false,
+		},
+		OTEL: OTEL{
+			Host:        "localhost",
+			Port:        0,
+			Insecure:    false,
+			Traces:      ExporterOTLPGRPC,
+			TracesFile:  "",
+			Metrics:     ExporterPrometheus,
+			MetricsFile: "",
+			SampleRatio: 0.6, //nolint:gomnd
+		},
+		Mail: Mail{}, //nolint:exhaustruct // no mail server by default
+		Logs: Logs{}, //nolint:exhaustruct // no archive by default
+		RateLimits: RateLimits{
+			Store: "memory",
+			Auth:  RateLimit{Requests: 10, Period: time.Minute, Burst: 0},  //nolint:gomnd
+			API:   RateLimit{Requests: 300, Period: time.Minute, Burst: 0}, //nolint:gomnd
+		},
 	}
+}
+
+const (
+	// EnvPrefix is the prefix of all env variables, e.g. ARROWER_POSTGRES_HOST for postgres.host.
+	EnvPrefix = "ARROWER"
+
+	defaultConfigFile = "config.yaml"
+	// secretFileSuffix is appended to the key of a secret, to read the secret from a file instead,
+	// e.g. ARROWER_POSTGRES_PASSWORD_FILE=/run/secrets/postgres as used by Docker and Kubernetes.
+	secretFileSuffix = "_file"
+	redacted         = "******"
 )
+
+type ConfigOption func(*ConfigLoader)
+
+// WithOutput sets where --print-config prints to. The default is stdout.
+func WithOutput(w io.Writer) ConfigOption {
+	return func(l *ConfigLoader) {
+		l.output = w
+	}
+}
+
+// NewConfigLoader returns a ConfigLoader, that loads into config.
+// The values of config are the defaults, e.g. as returned by DefaultConfig.
+func NewConfigLoader(config *Config, opts ...ConfigOption) *ConfigLoader {
+	l := &ConfigLoader{
+		config:   config,
+		sections: map[string]any{},
+		output:   os.Stdout,
+		flags:    nil,
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// ConfigLoader merges the configuration from, in increasing precedence:
+// the defaults, a config file (YAML, TOML or JSON), env variables prefixed with EnvPrefix and command-line flags.
+//
+// Each key of the config has a flag, e.g. --postgres.host, except secrets, so they don't show in the process list.
+// Secrets are set in the config file, the env or are read from a file given by the key with the suffix _file.
+type ConfigLoader struct {
+	config *Config
+	// sections are the configs of the Contexts, by their name.
+	sections map[string]any
+	output   io.Writer
+	flags    *pflag.FlagSet
+}
+
+// Register adds the config of a Context under its own section, e.g. auth.
+// The values of section are its defaults, it is loaded together with the Config by Load.
+func (l *ConfigLoader) Register(name string, section any) error {
+	if t := reflect.TypeOf(section); t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: section %s has to be a pointer to a struct", ErrInvalidConfig, name)
+	}
+
+	for _, f := range configFields("", reflect.TypeOf(l.config).Elem()) {
+		if strings.SplitN(f.key, ".", 2)[0] == name { //nolint:gomnd // split the first part of the key
+			return fmt.Errorf("%w: section %s is already used by the config", ErrInvalidConfig, name)
+		}
+	}
+
+	if _, exists := l.sections[name]; exists {
+		return fmt.Errorf("%w: section %s is registered already", ErrInvalidConfig, name)
+	}
+
+	if l.flags != nil {
+		return fmt.Errorf("%w: section %s is registered after the flags are created", ErrInvalidConfig, name)
+	}
+
+	l.sections[name] = section
+
+	return nil
+}
+
+// Load parses the command-line arguments and loads the configuration into the Config and
+// all registered sections and validates them.
+// args are the command-line arguments without the program name.
+func (l *ConfigLoader) Load(args []string) error {
+	flags := l.Flags()
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			return ErrConfigPrinted
+		}
+
+		return fmt.Errorf("%w: %v", ErrInvalidConfig, err) //nolint:errorlint // prevent err in api
+	}
+
+	return l.LoadParsed()
+}
+
+// Flags returns a flag for each key of the Config and the registered sections.
+// Register all sections before, as the flags are only created once.
+//
+// A command-line tool can add the flags to its own and call LoadParsed, once it has parsed them.
+func (l *ConfigLoader) Flags() *pflag.FlagSet {
+	if l.flags != nil {
+		return l.flags
+	}
+
+	l.flags = pflag.NewFlagSet(l.config.ApplicationName, pflag.ContinueOnError)
+	l.flags.SetOutput(l.output)
+	l.flags.String("config", defaultConfigFile, "path of the config file")
+	l.flags.Bool("print-config", false, "print the effective config with secrets redacted and exit")
+
+	for _, f := range l.fields() {
+		name := f.key
+		if f.secret {
+			name += secretFileSuffix
+		}
+
+		if f.bool {
+			l.flags.Bool(name, false, "env "+envName(name))
+
+			continue
+		}
+
+		l.flags.String(name, "", "env "+envName(name))
+	}
+
+	return l.flags
+}
+
+// LoadParsed loads the configuration like Load, but with the flags parsed already.
+func (l *ConfigLoader) LoadParsed() error {
+	v := viper.New()
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	setDefaults(v, "", reflect.ValueOf(l.config).Elem())
+
+	for _, name := range l.sectionNames() {
+		setDefaults(v, name+".", reflect.ValueOf(l.sections[name]).Elem())
+	}
+
+	flags := l.Flags()
+	fields := l.fields()
+
+	// the flags can be parsed by another flag set, that holds the same flags, so they are checked for changes directly
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if flag.Changed && flag.Name != "config" && flag.Name != "print-config" {
+			v.Set(flag.Name, flag.Value.String())
+		}
+	})
+
+	if err := readConfigFile(v, flags); err != nil {
+		return err
+	}
+
+	if err := readSecretFiles(v, fields); err != nil {
+		return err
+	}
+
+	if printConfig, _ := flags.GetBool("print-config"); printConfig {
+		if err := l.print(v, fields); err != nil {
+			return err
+		}
+
+		return ErrConfigPrinted
+	}
+
+	return l.decode(v)
+}
+
+// fields returns all keys of the Config and the registered sections.
+func (l *ConfigLoader) fields() []configField {
+	fields := configFields("", reflect.TypeOf(l.config).Elem())
+
+	for _, name := range l.sectionNames() {
+		fields = append(fields, configFields(name+".", reflect.TypeOf(l.sections[name]).Elem())...)
+	}
+
+	return fields
+}
+
+func (l *ConfigLoader) sectionNames() []string {
+	names := make([]string, 0, len(l.sections))
+	for name := range l.sections {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// readConfigFile reads the config file. The default file is optional, a file given explicitly is not.
+func readConfigFile(v *viper.Viper, flags *pflag.FlagSet) error {
+	path, _ := flags.GetString("config")
+	if env := os.Getenv(envName("config")); env != "" && !flags.Changed("config") {
+		path = env
+	}
+
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) && path == defaultConfigFile {
+		return nil
+	}
+
+	v.SetConfigFile(path)
+
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("%w: could not read config file: %s: %v", ErrInvalidConfig, path, err) //nolint:errorlint // prevent err in api
+	}
+
+	return nil
+}
+
+// readSecretFiles sets each secret, that has a file configured, to the content of the file.
+func readSecretFiles(v *viper.Viper, fields []configField) error {
+	for _, f := range fields {
+		if !f.secret {
+			continue
+		}
+
+		path := v.GetString(f.key + secretFileSuffix)
+		if path == "" {
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("%w: could not read secret %s: %v", ErrInvalidConfig, f.key, err) //nolint:errorlint // prevent err in api
+		}
+
+		v.Set(f.key, strings.TrimRight(string(content), "\r\n"))
+	}
+
+	return nil
+}
+
+func (l *ConfigLoader) decode(v *viper.Viper) error {
+	hook := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		secretHook,
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	))
+
+	if err := v.Unmarshal(l.config, hook); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidConfig, err) //nolint:errorlint // prevent err in api
+	}
+
+	validate := validator.New(validator.WithRequiredStructEnabled())
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return strings.SplitN(field.Tag.Get("mapstructure"), ",", 2)[0] //nolint:gomnd // only the name of the tag
+	})
+	validate.RegisterStructValidation(validateStatus, Status{}) //nolint:exhaustruct // only the type is used
+
+	if err := validateConfig(validate, "", l.config); err != nil {
+		return err
+	}
+
+	for _, name := range l.sectionNames() {
+		if err := v.UnmarshalKey(name, l.sections[name], hook); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidConfig, name, err) //nolint:errorlint // prevent err in api
+		}
+
+		if err := validateConfig(validate, name+".", l.sections[name]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateStatus rejects pprof on an endpoint, that is not protected.
+func validateStatus(sl validator.StructLevel) {
+	status := sl.Current().Interface().(Status) //nolint:forcetypeassert // registered for Status only
+
+	if status.Pprof && !isProtected(status) {
+		sl.ReportError(status.Pprof, "pprof", "Pprof", "protected", "")
+	}
+}
+
+// validateConfig returns all invalid keys at once, so they can be fixed in one go.
+func validateConfig(validate *validator.Validate, prefix string, config any) error {
+	err := validate.Struct(config)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return fmt.Errorf("%w: %v", ErrInvalidConfig, err) //nolint:errorlint // prevent err in api
+	}
+
+	msgs := make([]string, len(validationErrs))
+
+	for i, e := range validationErrs {
+		// the namespace starts with the name of the struct, e.g. Config.postgres.port
+		key := prefix + strings.SplitN(e.Namespace(), ".", 2)[1] //nolint:gomnd // drop the name of the struct
+
+		msgs[i] = fmt.Sprintf("%s: %s %s", key, e.Tag(), e.Param())
+		msgs[i] = strings.TrimSpace(msgs[i])
+	}
+
+	return fmt.Errorf("%w: %s", ErrInvalidConfig, strings.Join(msgs, "; "))
+}
+
+// print writes the effective config as YAML, with all secrets redacted.
+func (l *ConfigLoader) print(v *viper.Viper, fields []configField) error {
+	config := map[string]any{}
+
+	for _, f := range fields {
+		val := v.Get(f.key)
+		if d, ok := val.(time.Duration); ok {
+			val = d.String()
+		}
+
+		if f.secret && v.GetString(f.key) != "" {
+			val = redacted
+		}
+
+		setKey(config, f.key, val)
+
+		if file := v.GetString(f.key + secretFileSuffix); f.secret && file != "" {
+			setKey(config, f.key+secretFileSuffix, file)
+		}
+	}
+
+	out, err := yaml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("%w: could not print config: %v", ErrInvalidConfig, err) //nolint:errorlint // prevent err in api
+	}
+
+	_, err = l.output.Write(out)
+
+	return err //nolint:wrapcheck // the output is the terminal
+}
+
+// configField is a single key of the config, e.g. postgres.host.
+type configField struct {
+	key    string
+	secret bool
+	// bool is set for boolean keys, so their flag can be given without a value, e.g. --debug.
+	bool bool
+}
+
+//nolint:gochecknoglobals // used as a constant
+var secretType = reflect.TypeOf(secret.Secret{})
+
+// configFields returns all keys of the struct t, nested structs are flattened into dotted keys.
+func configFields(prefix string, t reflect.Type) []configField {
+	fields := []configField{}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+
+		name := mapstructureName(field)
+		if name == "" {
+			continue
+		}
+
+		switch {
+		case field.Type == secretType:
+			fields = append(fields, configField{key: prefix + name, secret: true, bool: false})
+		case field.Type.Kind() == reflect.Struct:
+			fields = append(fields, configFields(prefix+name+".", field.Type)...)
+		default:
+			fields = append(fields, configField{key: prefix + name, secret: false, bool: field.Type.Kind() == reflect.Bool})
+		}
+	}
+
+	return fields
+}
+
+// setDefaults sets the values of the struct val as the defaults of its keys.
+// Only keys with a default are known to viper, so this is what makes the env variables work.
+func setDefaults(v *viper.Viper, prefix string, val reflect.Value) {
+	for i := range val.NumField() {
+		field := val.Type().Field(i)
+
+		name := mapstructureName(field)
+		if name == "" {
+			continue
+		}
+
+		switch {
+		case field.Type == secretType:
+			v.SetDefault(prefix+name, val.Field(i).Interface().(secret.Secret).Secret()) //nolint:forcetypeassert // checked by the case
+			v.SetDefault(prefix+name+secretFileSuffix, "")
+		case field.Type.Kind() == reflect.Struct:
+			setDefaults(v, prefix+name+".", val.Field(i))
+		default:
+			v.SetDefault(prefix+name, val.Field(i).Interface())
+		}
+	}
+}
+
+// mapstructureName returns the key of the field, or nothing if the field is not configurable.
+func mapstructureName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+
+	name := strings.SplitN(field.Tag.Get("mapstructure"), ",", 2)[0] //nolint:gomnd // only the name of the tag
+	if name == "-" {
+		return ""
+	}
+
+	if name == "" {
+		return strings.ToLower(field.Name)
+	}
+
+	return name
+}
+
+func secretHook(_ reflect.Type, to reflect.Type, data any) (any, error) {
+	if to != secretType {
+		return data, nil
+	}
+
+	if s, ok := data.(secret.Secret); ok {
+		return s, nil
+	}
+
+	return secret.New(fmt.Sprint(data)), nil
+}
+
+func envName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// setKey sets the value of the dotted key in the nested map m.
+func setKey(m map[string]any, key string, val any) {
+	parts := strings.Split(key, ".")
+
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := m[part].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			m[part] = next
+		}
+
+		m = next
+	}
+
+	m[parts[len(parts)-1]] = val
+}
@@ -1 +1,349 @@
 package infrastructure_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure"
+)
+
+func TestConfigLoader_Load(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		config := infrastructure.DefaultConfig()
+
+		err := infrastructure.NewConfigLoader(config).Load([]string{"--web.secret_file", secretFile(t, "s")})
+		assert.NoError(t, err)
+		assert.Equal(t, infrastructure.DefaultConfig().Postgres.Host, config.Postgres.Host)
+		assert.Equal(t, 8080, config.Web.Port)
+	})
+
+	t.Run("precedence", func(t *testing.T) {
+		file := configFile(t, "config.yaml", `
+application_name: file
+web:
+  port: 1
+  secret: file-secret
+postgres:
+  host: file
+  port: 1
+`)
+		t.Setenv("ARROWER_WEB_PORT", "2")
+		t.Setenv("ARROWER_POSTGRES_PORT", "2")
+		t.Setenv("ARROWER_WEB_SECRET", "env-secret")
+
+		config := infrastructure.DefaultConfig()
+
+		err := infrastructure.NewConfigLoader(config).Load([]string{"--config", file, "--postgres.port", "3"})
+		assert.NoError(t, err)
+		assert.Equal(t, "file", config.ApplicationName, "file overwrites the default")
+		assert.Equal(t, "file", config.Postgres.Host)
+		assert.Equal(t, 2, config.Web.Port, "env overwrites the file")
+		assert.Equal(t, "env-secret", config.Web.Secret.Secret())
+		assert.Equal(t, 3, config.Postgres.Port, "flag overwrites the env")
+	})
+
+	t.Run("toml file", func(t *testing.T) {
+		t.Parallel()
+
+		file := configFile(t, "config.toml", `
+[web]
+hostname = "toml"
+secret = "s"
+`)
+		config := infrastructure.DefaultConfig()
+
+		err := infrastructure.NewConfigLoader(config).Load([]string{"--config", file})
+		assert.NoError(t, err)
+		assert.Equal(t, "toml", config.Web.Hostname)
+	})
+
+	t.Run("missing config file", func(t *testing.T) {
+		t.Parallel()
+
+		err := infrastructure.NewConfigLoader(infrastructure.DefaultConfig()).
+			Load([]string{"--config", "/does/not/exist.yaml"})
+		assert.ErrorIs(t, err, infrastructure.ErrInvalidConfig)
+	})
+
+	t.Run("secret from file", func(t *testing.T) {
+		t.Setenv("ARROWER_POSTGRES_PASSWORD_FILE", secretFile(t, "pg-secret\n"))
+
+		config := infrastructure.DefaultConfig()
+
+		err := infrastructure.NewConfigLoader(config).Load([]string{"--web.secret_file", secretFile(t, "web-secret")})
+		assert.NoError(t, err)
+		assert.Equal(t, "pg-secret", config.Postgres.Password.Secret())
+		assert.Equal(t, "web-secret", config.Web.Secret.Secret())
+	})
+
+	t.Run("missing secret file", func(t *testing.T) {
+		t.Parallel()
+
+		err := infrastructure.NewConfigLoader(infrastructure.DefaultConfig()).
+			Load([]string{"--web.secret_file", "/does/not/exist"})
+		assert.ErrorIs(t, err, infrastructure.ErrInvalidConfig)
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		t.Parallel()
+
+		err := infrastructure.NewConfigLoader(infrastructure.DefaultConfig()).
+			Load([]string{"--web.secret_file", secretFile(t, "s"), "--web.port", "70000", "--otel.host", ""})
+		assert.ErrorIs(t, err, infrastructure.ErrInvalidConfig)
+		assert.ErrorContains(t, err, "web.port")
+		assert.ErrorContains(t, err, "otel.host")
+	})
+
+	t.Run("telemetry", func(t *testing.T) {
+		t.Parallel()
+
+		config := infrastructure.DefaultConfig()
+
+		err := infrastructure.NewConfigLoader(config).Load([]string{
+			"--web.secret_file", secretFile(t, "s"),
+			"--otel.traces", "none", "--otel.host", "", "--otel.sample_ratio", "0.1",
+		})
+		assert.NoError(t, err, "no collector is required without an otlp exporter")
+		assert.Equal(t, infrastructure.ExporterNone, config.OTEL.Traces)
+		assert.Equal(t, 0.1, config.OTEL.SampleRatio)
+
+		err = infrastructure.NewConfigLoader(infrastructure.DefaultConfig()).
+			Load([]string{"--web.secret_file", secretFile(t, "s"), "--otel.traces", "file"})
+		assert.ErrorIs(t, err, infrastructure.ErrInvalidConfig)
+		assert.ErrorContains(t, err, "otel.traces_file")
+
+		err = infrastructure.NewConfigLoader(infrastructure.DefaultConfig()).
+			Load([]string{"--web.secret_file", secretFile(t, "s"), "--otel.metrics", "statsd", "--otel.sample_ratio", "2"})
+		assert.ErrorIs(t, err, infrastructure.ErrInvalidConfig)
+		assert.ErrorContains(t, err, "otel.metrics")
+		assert.ErrorContains(t, err, "otel.sample_ratio")
+	})
+
+	t.Run("status", func(t *testing.T) {
+		t.Parallel()
+
+		config := infrastructure.DefaultConfig()
+
+		err := infrastructure.NewConfigLoader(config).Load([]string{
+			"--web.secret_file", secretFile(t, "s"),
+			"--status.allowed_ips", "10.0.0.0/8,127.0.0.1", "--status.pprof",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"10.0.0.0/8", "127.0.0.1"}, config.Status.AllowedIPs)
+		assert.True(t, config.Status.Pprof)
+
+		err = infrastructure.NewConfigLoader(infrastructure.DefaultConfig()).Load([]string{
+			"--web.secret_file", secretFile(t, "s"),
+			"--status.user", "prometheus", "--status.allowed_ips", "localhost",
+		})
+		assert.ErrorIs(t, err, infrastructure.ErrInvalidConfig)
+		assert.ErrorContains(t, err, "status.password")
+		assert.ErrorContains(t, err, "status.allowed_ips")
+
+		err = infrastructure.NewConfigLoader(infrastructure.DefaultConfig()).Load([]string{
+			"--web.secret_file", secretFile(t, "s"), "--status.pprof",
+		})
+		assert.ErrorIs(t, err, infrastructure.ErrInvalidConfig)
+		assert.ErrorContains(t, err, "status.pprof: protected", "pprof is not served without protection")
+	})
+
+	t.Run("rate limits", func(t *testing.T) {
+		t.Parallel()
+
+		config := infrastructure.DefaultConfig()
+
+		err := infrastructure.NewConfigLoader(config).Load([]string{
+			"--web.secret_file", secretFile(t, "s"),
+			"--rate_limits.store", "postgres", "--rate_limits.auth.requests", "5", "--rate_limits.auth.period", "10s",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "postgres", config.RateLimits.Store)
+		assert.Equal(t, 5, config.RateLimits.Auth.Limit().Rate)
+		assert.Equal(t, 10*time.Second, config.RateLimits.Auth.Limit().Period)
+
+		err = infrastructure.NewConfigLoader(infrastructure.DefaultConfig()).Load([]string{
+			"--web.secret_file", secretFile(t, "s"),
+			"--rate_limits.store", "redis", "--rate_limits.api.period", "0s",
+		})
+		assert.ErrorIs(t, err, infrastructure.ErrInvalidConfig)
+		assert.ErrorContains(t, err, "rate_limits.store")
+		assert.ErrorContains(t, err, "rate_limits.api.period")
+	})
+
+	t.Run("role", func(t *testing.T) {
+		t.Parallel()
+
+		config := infrastructure.DefaultConfig()
+		assert.Equal(t, infrastructure.RoleAll, config.Role)
+
+		err := infrastructure.NewConfigLoader(config).
+			Load([]string{"--web.secret_file", secretFile(t, "s"), "--role", "worker"})
+		assert.NoError(t, err)
+		assert.Equal(t, infrastructure.RoleWorker, config.Role)
+		assert.True(t, config.Role.RunsJobs())
+		assert.False(t, config.Role.ServesWeb())
+
+		err = infrastructure.NewConfigLoader(infrastructure.DefaultConfig()).
+			Load([]string{"--web.secret_file", secretFile(t, "s"), "--role", "cron"})
+		assert.ErrorIs(t, err, infrastructure.ErrInvalidConfig)
+		assert.ErrorContains(t, err, "role")
+	})
+
+	t.Run("secret is required", func(t *testing.T) {
+		t.Parallel()
+
+		err := infrastructure.NewConfigLoader(infrastructure.DefaultConfig()).Load(nil)
+		assert.ErrorIs(t, err, infrastructure.ErrInvalidConfig)
+		assert.ErrorContains(t, err, "web.secret")
+	})
+
+	t.Run("print config", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+
+		err := infrastructure.NewConfigLoader(infrastructure.DefaultConfig(), infrastructure.WithOutput(buf)).
+			Load([]string{"--print-config", "--web.secret_file", secretFile(t, "web-secret")})
+		assert.ErrorIs(t, err, infrastructure.ErrConfigPrinted)
+		assert.Contains(t, buf.String(), "hostname: www.servername.tld")
+		assert.Contains(t, buf.String(), "secret: '******'")
+		assert.NotContains(t, buf.String(), "web-secret")
+	})
+
+	t.Run("unknown flag", func(t *testing.T) {
+		t.Parallel()
+
+		err := infrastructure.NewConfigLoader(infrastructure.DefaultConfig(), infrastructure.WithOutput(&bytes.Buffer{})).
+			Load([]string{"--unknown"})
+		assert.ErrorIs(t, err, infrastructure.ErrInvalidConfig)
+	})
+
+	t.Run("help", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+
+		err := infrastructure.NewConfigLoader(infrastructure.DefaultConfig(), infrastructure.WithOutput(buf)).
+			Load([]string{"--help"})
+		assert.ErrorIs(t, err, infrastructure.ErrConfigPrinted)
+		assert.Contains(t, buf.String(), "--postgres.host")
+		assert.Contains(t, buf.String(), "ARROWER_POSTGRES_HOST")
+		assert.Contains(t, buf.String(), "--postgres.password_file")
+		assert.NotContains(t, buf.String(), "--postgres.password ")
+	})
+}
+
+func TestConfigLoader_LoadParsed(t *testing.T) {
+	t.Parallel()
+
+	config := infrastructure.DefaultConfig()
+	loader := infrastructure.NewConfigLoader(config)
+
+	// e.g. the flags of a command-line tool, that also accepts the config flags
+	flags := pflag.NewFlagSet("cmd", pflag.ContinueOnError)
+	flags.Bool("superuser", false, "")
+	flags.AddFlagSet(loader.Flags())
+
+	err := flags.Parse([]string{"create", "--superuser", "--web.secret_file", secretFile(t, "s"), "--postgres.port", "3"})
+	assert.NoError(t, err)
+
+	err = loader.LoadParsed()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, config.Postgres.Port)
+	assert.Equal(t, "s", config.Web.Secret.Secret())
+	assert.Equal(t, []string{"create"}, flags.Args())
+
+	err = loader.Register("late", &struct{}{})
+	assert.ErrorIs(t, err, infrastructure.ErrInvalidConfig, "the flags of the section would be missing")
+}
+
+func TestConfigLoader_Register(t *testing.T) {
+	t.Parallel()
+
+	type section struct {
+		Enabled bool `mapstructure:"enabled"`
+		Limit   int  `mapstructure:"limit"   validate:"max=10"`
+	}
+
+	t.Run("load section", func(t *testing.T) {
+		t.Parallel()
+
+		sec := &section{Enabled: true, Limit: 1}
+
+		loader := infrastructure.NewConfigLoader(infrastructure.DefaultConfig())
+		err := loader.Register("example", sec)
+		assert.NoError(t, err)
+
+		err = loader.Load([]string{"--web.secret_file", secretFile(t, "s"), "--example.limit", "5"})
+		assert.NoError(t, err)
+		assert.True(t, sec.Enabled, "keep the default")
+		assert.Equal(t, 5, sec.Limit)
+	})
+
+	t.Run("bool flag", func(t *testing.T) {
+		t.Parallel()
+
+		sec := &section{Enabled: true, Limit: 1}
+
+		loader := infrastructure.NewConfigLoader(infrastructure.DefaultConfig())
+		_ = loader.Register("example", sec)
+
+		err := loader.Load([]string{"--web.secret_file", secretFile(t, "s"), "--example.enabled=false"})
+		assert.NoError(t, err)
+		assert.False(t, sec.Enabled)
+
+		err = loader.Load([]string{"--example.enabled", "--web.secret_file", secretFile(t, "s")})
+		assert.NoError(t, err)
+		assert.True(t, sec.Enabled, "a bool flag needs no value")
+	})
+
+	t.Run("validate section", func(t *testing.T) {
+		t.Parallel()
+
+		loader := infrastructure.NewConfigLoader(infrastructure.DefaultConfig())
+		_ = loader.Register("example", &section{})
+
+		err := loader.Load([]string{"--web.secret_file", secretFile(t, "s"), "--example.limit", "11"})
+		assert.ErrorIs(t, err, infrastructure.ErrInvalidConfig)
+		assert.ErrorContains(t, err, "example.limit")
+	})
+
+	t.Run("invalid section", func(t *testing.T) {
+		t.Parallel()
+
+		loader := infrastructure.NewConfigLoader(infrastructure.DefaultConfig())
+
+		err := loader.Register("example", section{})
+		assert.ErrorIs(t, err, infrastructure.ErrInvalidConfig)
+
+		err = loader.Register("postgres", &section{})
+		assert.ErrorIs(t, err, infrastructure.ErrInvalidConfig)
+
+		_ = loader.Register("example", &section{})
+		err = loader.Register("example", &section{})
+		assert.ErrorIs(t, err, infrastructure.ErrInvalidConfig)
+	})
+}
+
+func configFile(t *testing.T, name string, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+
+	err := os.WriteFile(path, []byte(content), 0o600)
+	assert.NoError(t, err)
+
+	return path
+}
+
+func secretFile(t *testing.T, secret string) string {
+	t.Helper()
+
+	return configFile(t, "secret", secret)
+}
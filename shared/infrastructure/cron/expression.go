@@ -0,0 +1,250 @@
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidExpression = errors.New("invalid cron expression")
+
+// Expression is a parsed cron expression in the standard five-field format:
+//
+//	┌───────────── minute (0 - 59)
+//	│ ┌───────────── hour (0 - 23)
+//	│ │ ┌───────────── day of the month (1 - 31)
+//	│ │ │ ┌───────────── month (1 - 12)
+//	│ │ │ │ ┌───────────── day of the week (0 - 7, Sunday = 0 or 7)
+//	│ │ │ │ │
+//	* * * * *
+//
+// Each field supports lists (1,2), ranges (1-5), and steps (*/15 or 0-30/10).
+// The descriptors @yearly, @monthly, @weekly, @daily, and @hourly are supported as well.
+type Expression struct {
+	spec string
+
+	minute     uint64
+	hour       uint64
+	dayOfMonth uint64
+	month      uint64
+	dayOfWeek  uint64
+
+	// restrictedDays reports if both day fields are restricted,
+	// in which case cron matches if either of them matches.
+	restrictedDays bool
+}
+
+type bounds struct {
+	min, max int
+}
+
+var (
+	minuteBounds     = bounds{0, 59}
+	hourBounds       = bounds{0, 23}
+	dayOfMonthBounds = bounds{1, 31}
+	monthBounds      = bounds{1, 12}
+	dayOfWeekBounds  = bounds{0, 7}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse returns the Expression for the given spec.
+func Parse(spec string) (Expression, error) {
+	spec = strings.TrimSpace(spec)
+
+	expanded := spec
+	if d, ok := descriptors[spec]; ok {
+		expanded = d
+	}
+
+	const numFields = 5
+
+	fields := strings.Fields(expanded)
+	if len(fields) != numFields {
+		return Expression{}, fmt.Errorf("%w: expected %d fields, got %d: %s", ErrInvalidExpression, numFields, len(fields), spec)
+	}
+
+	var (
+		expr = Expression{spec: spec} //nolint:exhaustruct // fields are set below
+		err  error
+	)
+
+	if expr.minute, err = parseField(fields[0], minuteBounds); err != nil {
+		return Expression{}, fmt.Errorf("%w: minute: %w", ErrInvalidExpression, err)
+	}
+
+	if expr.hour, err = parseField(fields[1], hourBounds); err != nil {
+		return Expression{}, fmt.Errorf("%w: hour: %w", ErrInvalidExpression, err)
+	}
+
+	if expr.dayOfMonth, err = parseField(fields[2], dayOfMonthBounds); err != nil {
+		return Expression{}, fmt.Errorf("%w: day of month: %w", ErrInvalidExpression, err)
+	}
+
+	if expr.month, err = parseField(fields[3], monthBounds); err != nil {
+		return Expression{}, fmt.Errorf("%w: month: %w", ErrInvalidExpression, err)
+	}
+
+	if expr.dayOfWeek, err = parseField(fields[4], dayOfWeekBounds); err != nil {
+		return Expression{}, fmt.Errorf("%w: day of week: %w", ErrInvalidExpression, err)
+	}
+
+	// allow 7 as an alias for Sunday, as many cron implementations do.
+	const sunday = 7
+	if has(expr.dayOfWeek, sunday) {
+		expr.dayOfWeek = expr.dayOfWeek&^(1<<sunday) | 1
+	}
+
+	expr.restrictedDays = fields[2] != "*" && fields[4] != "*"
+
+	return expr, nil
+}
+
+// MustParse is like Parse but panics if the spec is invalid.
+// Use it for schedules defined in code.
+func MustParse(spec string) Expression {
+	expr, err := Parse(spec)
+	if err != nil {
+		panic(err)
+	}
+
+	return expr
+}
+
+func (e Expression) String() string {
+	return e.spec
+}
+
+// Next returns the first time after t that matches the expression.
+// The returned time has a minute precision and is in the location of t.
+// If no time matches within the next five years, the zero time is returned.
+func (e Expression) Next(t time.Time) time.Time {
+	const maxYears = 5
+
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxYears, 0, 0)
+
+	for t.Before(limit) {
+		if !has(e.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+
+			continue
+		}
+
+		if !e.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+
+			continue
+		}
+
+		if !has(e.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+
+			continue
+		}
+
+		if !has(e.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (e Expression) matchesDay(t time.Time) bool {
+	dom := has(e.dayOfMonth, t.Day())
+	dow := has(e.dayOfWeek, int(t.Weekday()))
+
+	if e.restrictedDays {
+		return dom || dow
+	}
+
+	return dom && dow
+}
+
+func has(set uint64, v int) bool {
+	return set&(1<<uint(v)) != 0
+}
+
+func parseField(field string, b bounds) (uint64, error) {
+	var set uint64
+
+	for _, part := range strings.Split(field, ",") {
+		bits, err := parsePart(part, b)
+		if err != nil {
+			return 0, err
+		}
+
+		set |= bits
+	}
+
+	return set, nil
+}
+
+func parsePart(part string, b bounds) (uint64, error) {
+	rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+	step := 1
+
+	if hasStep {
+		s, err := strconv.Atoi(stepPart)
+		if err != nil || s <= 0 {
+			return 0, fmt.Errorf("invalid step: %s", part) //nolint:goerr113 // wrapped by the caller
+		}
+
+		step = s
+	}
+
+	start, end := b.min, b.max
+
+	switch {
+	case rangePart == "*":
+	case strings.Contains(rangePart, "-"):
+		from, to, _ := strings.Cut(rangePart, "-")
+
+		var err error
+
+		if start, err = strconv.Atoi(from); err != nil {
+			return 0, fmt.Errorf("invalid range: %s", part) //nolint:goerr113 // wrapped by the caller
+		}
+
+		if end, err = strconv.Atoi(to); err != nil {
+			return 0, fmt.Errorf("invalid range: %s", part) //nolint:goerr113 // wrapped by the caller
+		}
+	default:
+		v, err := strconv.Atoi(rangePart)
+		if err != nil {
+			return 0, fmt.Errorf("invalid value: %s", part) //nolint:goerr113 // wrapped by the caller
+		}
+
+		start = v
+		if !hasStep {
+			end = v
+		}
+	}
+
+	if start < b.min || end > b.max || start > end {
+		return 0, fmt.Errorf("out of range [%d-%d]: %s", b.min, b.max, part) //nolint:goerr113 // wrapped by the caller
+	}
+
+	var bits uint64
+	for v := start; v <= end; v += step {
+		bits |= 1 << uint(v)
+	}
+
+	return bits, nil
+}
@@ -0,0 +1,84 @@
+package cron_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/cron"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		spec  string
+		valid bool
+	}{
+		"every minute":       {"* * * * *", true},
+		"list":               {"0,15,30,45 * * * *", true},
+		"range":              {"0 9-17 * * 1-5", true},
+		"step":               {"*/5 * * * *", true},
+		"range with step":    {"0-30/10 * * * *", true},
+		"sunday as seven":    {"0 0 * * 7", true},
+		"descriptor":         {"@daily", true},
+		"empty":              {"", false},
+		"too few fields":     {"* * * *", false},
+		"too many fields":    {"* * * * * *", false},
+		"minute too large":   {"60 * * * *", false},
+		"day zero":           {"0 0 0 * *", false},
+		"inverted range":     {"0 17-9 * * *", false},
+		"invalid step":       {"*/0 * * * *", false},
+		"not a number":       {"a * * * *", false},
+		"unknown descriptor": {"@sometimes", false},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := cron.Parse(tt.spec)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, cron.ErrInvalidExpression)
+			}
+		})
+	}
+}
+
+func TestExpression_Next(t *testing.T) {
+	t.Parallel()
+
+	// Wednesday
+	now := time.Date(2024, time.May, 15, 10, 7, 30, 0, time.UTC)
+
+	tests := map[string]struct {
+		spec     string
+		expected time.Time
+	}{
+		"every minute":     {"* * * * *", time.Date(2024, time.May, 15, 10, 8, 0, 0, time.UTC)},
+		"every 15 minutes": {"*/15 * * * *", time.Date(2024, time.May, 15, 10, 15, 0, 0, time.UTC)},
+		"hourly":           {"@hourly", time.Date(2024, time.May, 15, 11, 0, 0, 0, time.UTC)},
+		"daily":            {"@daily", time.Date(2024, time.May, 16, 0, 0, 0, 0, time.UTC)},
+		"next month":       {"0 0 1 * *", time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		"sunday":           {"30 6 * * 0", time.Date(2024, time.May, 19, 6, 30, 0, 0, time.UTC)},
+		"sunday as seven":  {"30 6 * * 7", time.Date(2024, time.May, 19, 6, 30, 0, 0, time.UTC)},
+		"working hours":    {"0 9-17 * * 1-5", time.Date(2024, time.May, 15, 11, 0, 0, 0, time.UTC)},
+		"leap day":         {"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		"day of month or day of week": {
+			"0 0 20 * 5", time.Date(2024, time.May, 17, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, cron.MustParse(tt.spec).Next(now))
+		})
+	}
+}
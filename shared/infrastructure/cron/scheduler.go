@@ -0,0 +1,564 @@
+// Package cron schedules recurring jobs on top of the jobs.Queue.
+//
+// Schedules are stored in postgres, so all instances of the application see the same state.
+// Every instance runs a Scheduler, but only the one holding the postgres advisory lock
+// (the leader) enqueues jobs. If the leader goes away, its connection and with it the lock
+// is released and another instance takes over.
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/go-arrower/arrower/jobs"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrScheduleFailed   = errors.New("schedule failed")
+	ErrScheduleNotFound = fmt.Errorf("%w: schedule not found", ErrScheduleFailed)
+	ErrScheduleExists   = fmt.Errorf("%w: schedule exists already", ErrScheduleFailed)
+	ErrUnknownQueue     = fmt.Errorf("%w: unknown queue", ErrScheduleFailed)
+)
+
+const (
+	// defaultLockID is the key of the postgres advisory lock used for leader election.
+	// It is an arbitrary number, that is not supposed to clash with other advisory locks of the application.
+	defaultLockID   int64 = 72601
+	defaultInterval       = 10 * time.Second
+)
+
+type Source string
+
+const (
+	// SourceCode are schedules registered by a Context on startup.
+	SourceCode Source = "code"
+	// SourceAdmin are schedules created via the admin UI.
+	SourceAdmin Source = "admin"
+)
+
+// Schedule is a recurring job.
+type Schedule struct {
+	LastRunAt time.Time
+	NextRunAt time.Time
+	Name      string
+	Spec      string
+	Queue     string
+	JobType   string
+	Payload   string
+	Source    Source
+	Paused    bool
+}
+
+type Option func(*Scheduler)
+
+// WithInterval sets how often the Scheduler checks for due schedules.
+// As cron expressions have a minute precision, it should be lower than a minute.
+func WithInterval(interval time.Duration) Option {
+	return func(s *Scheduler) {
+		s.interval = interval
+	}
+}
+
+// WithLockID sets the key of the advisory lock used for leader election.
+func WithLockID(id int64) Option {
+	return func(s *Scheduler) {
+		s.lockID = id
+	}
+}
+
+// NewScheduler returns a Scheduler that enqueues into the given queues.
+// The keys of queues are the names of the queues as stored in the database, e.g. "" for the default queue.
+func NewScheduler(logger alog.Logger, pg *pgxpool.Pool, queues map[string]jobs.Queue, opts ...Option) *Scheduler {
+	s := &Scheduler{
+		logger:        logger.WithGroup("arrower.cron"),
+		pg:            pg,
+		queues:        queues,
+		registrations: map[string]registration{},
+		interval:      defaultInterval,
+		lockID:        defaultLockID,
+		mu:            sync.Mutex{},
+		leader:        nil,
+		done:          make(chan struct{}),
+		wg:            sync.WaitGroup{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+type Scheduler struct {
+	logger alog.Logger
+	pg     *pgxpool.Pool
+
+	queues        map[string]jobs.Queue
+	registrations map[string]registration
+
+	interval time.Duration
+	lockID   int64
+
+	mu     sync.Mutex
+	leader *pgxpool.Conn
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+type registration struct {
+	queue string
+	job   any
+}
+
+// Register adds a recurring job defined in code.
+// Call it on startup, every instance has to register the same schedules,
+// as the job itself is not stored, only the information to display it in the admin UI.
+func (s *Scheduler) Register(ctx context.Context, name string, spec string, queueName string, job any) error {
+	expr, err := Parse(spec)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrScheduleFailed, name, err)
+	}
+
+	if _, ok := s.queues[queueName]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownQueue, queueName)
+	}
+
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("%w: could not marshal job: %v", ErrScheduleFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	// a schedule added in the admin keeps its source, so it is not taken over by the code
+	tag, err := s.pg.Exec(ctx, `
+		INSERT INTO arrower.cron_schedules (name, spec, queue, job_type, payload, source, next_run_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (name) DO UPDATE
+			SET spec        = EXCLUDED.spec,
+				queue       = EXCLUDED.queue,
+				job_type    = EXCLUDED.job_type,
+				payload     = EXCLUDED.payload,
+				source      = EXCLUDED.source,
+				next_run_at = CASE
+								  WHEN arrower.cron_schedules.spec = EXCLUDED.spec
+									  THEN COALESCE(arrower.cron_schedules.next_run_at, EXCLUDED.next_run_at)
+								  ELSE EXCLUDED.next_run_at END,
+				updated_at  = NOW()
+			WHERE arrower.cron_schedules.source = EXCLUDED.source;`,
+		name, spec, queueName, jobType(job), payload, SourceCode, timestamp(expr.Next(time.Now())),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: could not save schedule: %s: %v", ErrScheduleFailed, name, err) //nolint:errorlint,lll // prevent err in api
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: added in the admin: %s", ErrScheduleExists, name)
+	}
+
+	s.mu.Lock()
+	s.registrations[name] = registration{queue: queueName, job: job}
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Add creates a new recurring job with a raw JSON payload, e.g. from the admin UI.
+func (s *Scheduler) Add(ctx context.Context, schedule Schedule) error {
+	expr, err := Parse(schedule.Spec)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrScheduleFailed, err)
+	}
+
+	if schedule.Name == "" || schedule.JobType == "" {
+		return fmt.Errorf("%w: name and job type are required", ErrScheduleFailed)
+	}
+
+	if schedule.Payload == "" {
+		schedule.Payload = "{}"
+	}
+
+	if !json.Valid([]byte(schedule.Payload)) {
+		return fmt.Errorf("%w: payload is not valid json", ErrScheduleFailed)
+	}
+
+	tag, err := s.pg.Exec(ctx, `
+		INSERT INTO arrower.cron_schedules (name, spec, queue, job_type, payload, source, next_run_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (name) DO NOTHING;`,
+		schedule.Name, schedule.Spec, schedule.Queue, schedule.JobType, schedule.Payload, SourceAdmin,
+		timestamp(expr.Next(time.Now())),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: could not save schedule: %v", ErrScheduleFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %s", ErrScheduleExists, schedule.Name)
+	}
+
+	return nil
+}
+
+// Remove deletes a schedule created via Add.
+// Schedules registered in code can only be paused, as they would be recreated on the next startup.
+func (s *Scheduler) Remove(ctx context.Context, name string) error {
+	tag, err := s.pg.Exec(ctx, `DELETE FROM arrower.cron_schedules WHERE name = $1 AND source = $2`, name, SourceAdmin)
+	if err != nil {
+		return fmt.Errorf("%w: could not delete schedule: %v", ErrScheduleFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %s", ErrScheduleNotFound, name)
+	}
+
+	return nil
+}
+
+// Pause stops a schedule from enqueueing new jobs, until it is resumed.
+func (s *Scheduler) Pause(ctx context.Context, name string) error {
+	return s.setPaused(ctx, name, true)
+}
+
+// Resume continues a paused schedule with its next regular run.
+func (s *Scheduler) Resume(ctx context.Context, name string) error {
+	return s.setPaused(ctx, name, false)
+}
+
+func (s *Scheduler) setPaused(ctx context.Context, name string, paused bool) error {
+	schedule, err := s.Schedule(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	expr, err := Parse(schedule.Spec)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrScheduleFailed, err)
+	}
+
+	// set the next run, so a resumed schedule does not immediately catch up on a missed run.
+	_, err = s.pg.Exec(ctx,
+		`UPDATE arrower.cron_schedules SET paused = $2, next_run_at = $3, updated_at = NOW() WHERE name = $1`,
+		name, paused, timestamp(expr.Next(time.Now())),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: could not update schedule: %v", ErrScheduleFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	return nil
+}
+
+// Trigger enqueues the job of a schedule immediately, independent of its next run and if it is paused.
+func (s *Scheduler) Trigger(ctx context.Context, name string) error {
+	schedule, err := s.Schedule(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	err = pgx.BeginFunc(ctx, s.pg, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, //nolint:govet // govet is too pedantic for shadowing errors
+			`UPDATE arrower.cron_schedules SET last_run_at = NOW(), updated_at = NOW() WHERE name = $1`, name,
+		)
+		if err != nil {
+			return fmt.Errorf("could not update last run: %v", err) //nolint:errorlint,goerr113 // prevent err in api
+		}
+
+		return s.enqueue(ctx, schedule)
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrScheduleFailed, name, err)
+	}
+
+	s.logger.LogAttrs(ctx, alog.LevelInfo, "schedule triggered manually", slog.String("name", name))
+
+	return nil
+}
+
+// Schedule returns the schedule with the given name.
+func (s *Scheduler) Schedule(ctx context.Context, name string) (Schedule, error) {
+	rows, err := s.pg.Query(ctx, selectSchedules+` WHERE name = $1`, name)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("%w: could not query schedule: %v", ErrScheduleFailed, err) //nolint:errorlint,lll // prevent err in api
+	}
+
+	schedule, err := pgx.CollectExactlyOneRow(rows, scanSchedule)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Schedule{}, fmt.Errorf("%w: %s", ErrScheduleNotFound, name)
+	}
+
+	if err != nil {
+		return Schedule{}, fmt.Errorf("%w: could not read schedule: %v", ErrScheduleFailed, err) //nolint:errorlint,lll // prevent err in api
+	}
+
+	return schedule, nil
+}
+
+// Schedules returns all known schedules ordered by name.
+func (s *Scheduler) Schedules(ctx context.Context) ([]Schedule, error) {
+	rows, err := s.pg.Query(ctx, selectSchedules+` ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not query schedules: %v", ErrScheduleFailed, err) //nolint:errorlint,lll // prevent err in api
+	}
+
+	schedules, err := pgx.CollectRows(rows, scanSchedule)
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not read schedules: %v", ErrScheduleFailed, err) //nolint:errorlint,lll // prevent err in api
+	}
+
+	return schedules, nil
+}
+
+// Start runs the Scheduler in the background until Shutdown is called or the ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.tick(ctx)
+			case <-s.done:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	s.logger.LogAttrs(ctx, alog.LevelInfo, "scheduler started", slog.Duration("interval", s.interval))
+}
+
+// Shutdown stops the Scheduler and gives up the leadership, so another instance can take over.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	select {
+	case <-s.done:
+	default:
+		close(s.done)
+	}
+
+	s.wg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.leader == nil {
+		return nil
+	}
+
+	_, err := s.leader.Exec(ctx, `SELECT pg_advisory_unlock($1)`, s.lockID)
+	s.leader.Release()
+	s.leader = nil
+
+	if err != nil {
+		return fmt.Errorf("%w: could not release leadership: %v", ErrScheduleFailed, err) //nolint:errorlint,lll // prevent err in api
+	}
+
+	return nil
+}
+
+func (s *Scheduler) tick(ctx context.Context) {
+	if !s.isLeader(ctx) {
+		return
+	}
+
+	schedules, err := s.Schedules(ctx)
+	if err != nil {
+		s.logger.LogAttrs(ctx, slog.LevelError, "could not load schedules", slog.String("err", err.Error()))
+
+		return
+	}
+
+	now := time.Now()
+
+	for _, schedule := range schedules {
+		if schedule.Paused || schedule.NextRunAt.IsZero() || schedule.NextRunAt.After(now) {
+			continue
+		}
+
+		err = s.run(ctx, schedule, now)
+		if err != nil {
+			s.logger.LogAttrs(ctx, slog.LevelError, "could not run schedule",
+				slog.String("name", schedule.Name),
+				slog.String("err", err.Error()),
+			)
+		}
+	}
+}
+
+// run enqueues the job of a due schedule.
+// The update of next_run_at is guarded by its previous value, so even if two instances consider
+// themselves the leader, e.g. during a network partition, a tick is only enqueued once.
+func (s *Scheduler) run(ctx context.Context, schedule Schedule, now time.Time) error {
+	expr, err := Parse(schedule.Spec)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrScheduleFailed, err)
+	}
+
+	err = pgx.BeginFunc(ctx, s.pg, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE arrower.cron_schedules
+			SET last_run_at = $2, next_run_at = $3, updated_at = NOW()
+			WHERE name = $1 AND next_run_at = $4`,
+			schedule.Name, timestamp(now), timestamp(expr.Next(now)), timestamp(schedule.NextRunAt),
+		)
+		if err != nil {
+			return fmt.Errorf("could not update next run: %v", err) //nolint:errorlint,goerr113 // prevent err in api
+		}
+
+		if tag.RowsAffected() == 0 { // the tick got processed already
+			return nil
+		}
+
+		// the row stays locked until the job is enqueued, and a failed enqueue rolls the tick back
+		return s.enqueue(ctx, schedule)
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrScheduleFailed, err)
+	}
+
+	s.logger.LogAttrs(ctx, alog.LevelDebug, "schedule enqueued", slog.String("name", schedule.Name))
+
+	return nil
+}
+
+// enqueue uses the registered job for schedules defined in code and the raw payload for all others.
+// It is called within the tx recording the tick, so the tick is rolled back, if the job could not be enqueued.
+func (s *Scheduler) enqueue(ctx context.Context, schedule Schedule) error {
+	s.mu.Lock()
+	reg, isRegistered := s.registrations[schedule.Name]
+	s.mu.Unlock()
+
+	var (
+		queueName = schedule.Queue
+		job       any
+	)
+
+	switch {
+	case isRegistered:
+		queueName, job = reg.queue, reg.job
+	case schedule.Source == SourceCode:
+		// a schedule from code, registered by another version of the application.
+		return fmt.Errorf("%w: not registered on this instance: %s", ErrScheduleFailed, schedule.Name)
+	default:
+		job = rawJob{typ: schedule.JobType, payload: json.RawMessage(schedule.Payload)}
+	}
+
+	queue, ok := s.queues[queueName]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownQueue, queueName)
+	}
+
+	if err := queue.Enqueue(ctx, job); err != nil {
+		return fmt.Errorf("could not enqueue job: %v", err) //nolint:errorlint,goerr113 // prevent err in api
+	}
+
+	return nil
+}
+
+// isLeader tries to become the leader, if the Scheduler is not already.
+// The advisory lock is bound to the postgres session, so the connection is kept for as long as the leadership lasts.
+func (s *Scheduler) isLeader(ctx context.Context) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.leader != nil {
+		if err := s.leader.Ping(ctx); err == nil {
+			return true
+		}
+
+		s.logger.LogAttrs(ctx, slog.LevelWarn, "lost scheduler leadership")
+		s.leader.Release()
+		s.leader = nil
+	}
+
+	conn, err := s.pg.Acquire(ctx)
+	if err != nil {
+		return false
+	}
+
+	var locked bool
+
+	err = conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, s.lockID).Scan(&locked)
+	if err != nil || !locked {
+		conn.Release()
+
+		return false
+	}
+
+	s.leader = conn
+	s.logger.LogAttrs(ctx, alog.LevelInfo, "became scheduler leader")
+
+	return true
+}
+
+const selectSchedules = `
+	SELECT name, spec, queue, job_type, payload::TEXT, source, paused, last_run_at, next_run_at
+	FROM arrower.cron_schedules`
+
+func scanSchedule(row pgx.CollectableRow) (Schedule, error) {
+	var (
+		schedule  Schedule
+		lastRunAt pgtype.Timestamptz
+		nextRunAt pgtype.Timestamptz
+	)
+
+	err := row.Scan(
+		&schedule.Name,
+		&schedule.Spec,
+		&schedule.Queue,
+		&schedule.JobType,
+		&schedule.Payload,
+		&schedule.Source,
+		&schedule.Paused,
+		&lastRunAt,
+		&nextRunAt,
+	)
+
+	schedule.LastRunAt = lastRunAt.Time
+	schedule.NextRunAt = nextRunAt.Time
+
+	return schedule, err //nolint:wrapcheck // the error is wrapped by the caller
+}
+
+func timestamp(t time.Time) pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: t, Valid: !t.IsZero(), InfinityModifier: pgtype.Finite}
+}
+
+// jobType returns a name to show for jobs registered in code.
+func jobType(job any) string {
+	if jt, ok := job.(interface{ JobType() string }); ok {
+		return jt.JobType()
+	}
+
+	t := reflect.TypeOf(job)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Name()
+}
+
+// rawJob is the job of a schedule added in the admin UI. Only its type and payload are known,
+// so it is enqueued with them instead of a value of the Go type of the job.
+type rawJob struct {
+	typ     string
+	payload json.RawMessage
+}
+
+func (j rawJob) JobType() string { return j.typ }
+
+func (j rawJob) MarshalJSON() ([]byte, error) { return j.payload, nil }
@@ -0,0 +1,284 @@
+//go:build integration
+
+// Use white box testing, to run the ticks of the Scheduler without waiting for its interval.
+//
+//nolint:testpackage
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/go-arrower/arrower/jobs"
+	"github.com/go-arrower/arrower/tests"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/migrations"
+)
+
+var (
+	ctx       = context.Background()
+	pgHandler *tests.PostgresDocker
+)
+
+func TestMain(m *testing.M) {
+	pgHandler = tests.GetPostgresDockerForIntegrationTestingInstance()
+
+	//
+	// Run tests
+	code := m.Run()
+
+	pgHandler.Cleanup()
+	os.Exit(code)
+}
+
+func TestScheduler_isLeader(t *testing.T) {
+	t.Parallel()
+
+	pg := newTestDatabase()
+
+	leader := NewScheduler(alog.NewNoopLogger(), pg, map[string]jobs.Queue{"": &fakeQueue{}})
+	other := NewScheduler(alog.NewNoopLogger(), pg, map[string]jobs.Queue{"": &fakeQueue{}})
+
+	assert.True(t, leader.isLeader(ctx))
+	assert.True(t, leader.isLeader(ctx), "the leader keeps the lock")
+	assert.False(t, other.isLeader(ctx), "the lock is held by the leader")
+
+	err := leader.Shutdown(ctx)
+	assert.NoError(t, err)
+
+	assert.True(t, other.isLeader(ctx), "another instance takes over after the leader is gone")
+
+	_ = other.Shutdown(ctx)
+}
+
+func TestScheduler_tick(t *testing.T) {
+	t.Parallel()
+
+	t.Run("enqueue due schedule", func(t *testing.T) {
+		t.Parallel()
+
+		pg := newTestDatabase()
+		queue := &fakeQueue{}
+		s := NewScheduler(alog.NewNoopLogger(), pg, map[string]jobs.Queue{"": queue}, WithLockID(1))
+
+		_ = s.Register(ctx, "a", "* * * * *", "", someJob{Name: "a"})
+		_ = s.Register(ctx, "b", "* * * * *", "", someJob{Name: "b"})
+		setDue(t, pg, "a")
+
+		s.tick(ctx)
+		assert.Equal(t, []any{someJob{Name: "a"}}, queue.enqueued(), "only the due schedule is enqueued")
+
+		schedule, _ := s.Schedule(ctx, "a")
+		assert.True(t, schedule.NextRunAt.After(time.Now()))
+		assert.False(t, schedule.LastRunAt.IsZero())
+
+		s.tick(ctx)
+		assert.Len(t, queue.enqueued(), 1, "the next run is in the future")
+
+		_ = s.Shutdown(ctx)
+	})
+
+	t.Run("follower does not enqueue", func(t *testing.T) {
+		t.Parallel()
+
+		pg := newTestDatabase()
+		queue := &fakeQueue{}
+		leader := NewScheduler(alog.NewNoopLogger(), pg, map[string]jobs.Queue{"": &fakeQueue{}}, WithLockID(2))
+		follower := NewScheduler(alog.NewNoopLogger(), pg, map[string]jobs.Queue{"": queue}, WithLockID(2))
+
+		assert.True(t, leader.isLeader(ctx))
+
+		_ = follower.Register(ctx, "a", "* * * * *", "", someJob{Name: "a"})
+		setDue(t, pg, "a")
+
+		follower.tick(ctx)
+		assert.Empty(t, queue.enqueued())
+
+		_ = leader.Shutdown(ctx)
+	})
+
+	t.Run("admin schedule", func(t *testing.T) {
+		t.Parallel()
+
+		pg := newTestDatabase()
+		queue := &fakeQueue{}
+		s := NewScheduler(alog.NewNoopLogger(), pg, map[string]jobs.Queue{"": queue}, WithLockID(3))
+
+		err := s.Add(ctx, Schedule{Name: "a", Spec: "* * * * *", JobType: "SomeJob", Payload: `{"Name":"admin"}`})
+		assert.NoError(t, err)
+		setDue(t, pg, "a")
+
+		s.tick(ctx)
+
+		enqueued := queue.enqueued()
+		assert.Len(t, enqueued, 1)
+
+		job, _ := enqueued[0].(interface{ JobType() string })
+		assert.Equal(t, "SomeJob", job.JobType())
+
+		payload, _ := json.Marshal(enqueued[0])
+		assert.JSONEq(t, `{"Name":"admin"}`, string(payload))
+
+		_ = s.Shutdown(ctx)
+	})
+}
+
+func TestScheduler_run(t *testing.T) {
+	t.Parallel()
+
+	t.Run("tick is enqueued once", func(t *testing.T) {
+		t.Parallel()
+
+		pg := newTestDatabase()
+		queue := &fakeQueue{}
+		s := NewScheduler(alog.NewNoopLogger(), pg, map[string]jobs.Queue{"": queue})
+
+		_ = s.Register(ctx, "a", "* * * * *", "", someJob{Name: "a"})
+		setDue(t, pg, "a")
+
+		schedule, _ := s.Schedule(ctx, "a")
+
+		// two leaders, e.g. during a network partition, run the same tick
+		wg := sync.WaitGroup{}
+		for range 2 {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				err := s.run(ctx, schedule, time.Now())
+				assert.NoError(t, err)
+			}()
+		}
+
+		wg.Wait()
+
+		assert.Len(t, queue.enqueued(), 1)
+	})
+
+	t.Run("failed enqueue is retried", func(t *testing.T) {
+		t.Parallel()
+
+		pg := newTestDatabase()
+		queue := &fakeQueue{err: errQueue}
+		s := NewScheduler(alog.NewNoopLogger(), pg, map[string]jobs.Queue{"": queue})
+
+		_ = s.Register(ctx, "a", "* * * * *", "", someJob{Name: "a"})
+		setDue(t, pg, "a")
+
+		schedule, _ := s.Schedule(ctx, "a")
+
+		err := s.run(ctx, schedule, time.Now())
+		assert.ErrorIs(t, err, ErrScheduleFailed)
+
+		after, _ := s.Schedule(ctx, "a")
+		assert.Equal(t, schedule.NextRunAt, after.NextRunAt, "the tick is rolled back")
+		assert.True(t, after.LastRunAt.IsZero())
+	})
+}
+
+func TestScheduler_Register(t *testing.T) {
+	t.Parallel()
+
+	t.Run("register again", func(t *testing.T) {
+		t.Parallel()
+
+		pg := newTestDatabase()
+		s := NewScheduler(alog.NewNoopLogger(), pg, map[string]jobs.Queue{"": &fakeQueue{}})
+
+		err := s.Register(ctx, "a", "* * * * *", "", someJob{Name: "a"})
+		assert.NoError(t, err)
+
+		err = s.Register(ctx, "a", "@hourly", "", someJob{Name: "a"})
+		assert.NoError(t, err, "the code changes its own schedules")
+
+		schedule, _ := s.Schedule(ctx, "a")
+		assert.Equal(t, "@hourly", schedule.Spec)
+		assert.Equal(t, SourceCode, schedule.Source)
+	})
+
+	t.Run("added in the admin", func(t *testing.T) {
+		t.Parallel()
+
+		pg := newTestDatabase()
+		s := NewScheduler(alog.NewNoopLogger(), pg, map[string]jobs.Queue{"": &fakeQueue{}})
+
+		_ = s.Add(ctx, Schedule{Name: "a", Spec: "@daily", JobType: "SomeJob"})
+
+		err := s.Register(ctx, "a", "* * * * *", "", someJob{Name: "a"})
+		assert.ErrorIs(t, err, ErrScheduleExists)
+
+		schedule, _ := s.Schedule(ctx, "a")
+		assert.Equal(t, "@daily", schedule.Spec, "the schedule of the admin is kept")
+		assert.Equal(t, SourceAdmin, schedule.Source)
+	})
+
+	t.Run("unknown queue", func(t *testing.T) {
+		t.Parallel()
+
+		pg := newTestDatabase()
+		s := NewScheduler(alog.NewNoopLogger(), pg, map[string]jobs.Queue{"": &fakeQueue{}})
+
+		err := s.Register(ctx, "a", "* * * * *", "Other", someJob{Name: "a"})
+		assert.ErrorIs(t, err, ErrUnknownQueue)
+	})
+}
+
+func newTestDatabase() *pgxpool.Pool {
+	pg := pgHandler.NewTestDatabase()
+	_ = migrations.Up(ctx, pg)
+
+	return pg
+}
+
+// setDue moves the next run of the schedule into the past.
+func setDue(t *testing.T, pg *pgxpool.Pool, name string) {
+	t.Helper()
+
+	_, err := pg.Exec(ctx, `UPDATE arrower.cron_schedules SET next_run_at = NOW() - INTERVAL '1 minute' WHERE name = $1`, name)
+	assert.NoError(t, err)
+}
+
+type someJob struct {
+	Name string
+}
+
+var errQueue = errors.New("queue failed")
+
+type fakeQueue struct {
+	mu   sync.Mutex
+	jobs []any
+	err  error
+}
+
+func (q *fakeQueue) Enqueue(_ context.Context, job jobs.Job, _ ...jobs.JobOpt) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.err != nil {
+		return q.err
+	}
+
+	q.jobs = append(q.jobs, job)
+
+	return nil
+}
+
+func (q *fakeQueue) RegisterJobFunc(_ any) error { return nil }
+
+func (q *fakeQueue) Shutdown(_ context.Context) error { return nil }
+
+func (q *fakeQueue) enqueued() []any {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return append([]any{}, q.jobs...)
+}
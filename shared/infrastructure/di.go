@@ -2,17 +2,17 @@ package infrastructure
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log/slog"
-	"net/http"
+	"net/smtp"
 	"os"
 	"runtime/debug"
 	"strings"
-	"time"
 
+	"github.com/go-arrower/skeleton/public"
 	"github.com/go-arrower/skeleton/shared/views"
 
 	"github.com/go-arrower/arrower/alog"
@@ -24,21 +24,24 @@ import (
 	"github.com/labstack/echo-contrib/echoprometheus"
 	"github.com/labstack/echo-contrib/session"
 	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
-	prometheus2 "github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
-	"go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
-	"google.golang.org/grpc"
 
 	"github.com/go-arrower/skeleton/contexts/auth"
+	"github.com/go-arrower/skeleton/shared/infrastructure/cron"
+	"github.com/go-arrower/skeleton/shared/infrastructure/feature"
+	"github.com/go-arrower/skeleton/shared/infrastructure/i18n"
+	"github.com/go-arrower/skeleton/shared/infrastructure/jobqueue"
+	"github.com/go-arrower/skeleton/shared/infrastructure/mail"
+	"github.com/go-arrower/skeleton/shared/infrastructure/maintenance"
+	"github.com/go-arrower/skeleton/shared/infrastructure/migrations"
+	"github.com/go-arrower/skeleton/shared/infrastructure/ratelimit"
 	"github.com/go-arrower/skeleton/shared/infrastructure/web"
+	"github.com/go-arrower/skeleton/shared/infrastructure/web/htmx"
 )
 
 var ErrMissingDependency = errors.New("missing dependency")
@@ -55,15 +58,34 @@ type Container struct {
 	PGx    *pgxpool.Pool
 	db     *postgres.Handler
 
-	WebRenderer *web.EchoRenderer
-	WebRouter   *echo.Echo
-	APIRouter   *echo.Group
-	AdminRouter *echo.Group
+	WebRenderer  *web.EchoRenderer
+	WebRouter    *echo.Echo
+	Validator    *i18n.Validator
+	ErrorHandler *web.ErrorHandler
+	APIRouter    *echo.Group
+	AdminRouter  *echo.Group
+	// Events are sent to the browsers of all instances as server-sent events.
+	Events *web.Events
+	// RateLimiter limits the requests of the route groups, e.g. of the login. The API is limited already.
+	RateLimiter *ratelimit.Limiter
 
 	ArrowerQueue jobs.Queue
 	DefaultQueue jobs.Queue
+	Scheduler    *cron.Scheduler
 
 	Settings setting.Settings
+
+	// Mailer is nil, if no mail server is configured.
+	Mailer mail.Mailer
+
+	// Lifecycle shuts down all dependencies. Contexts register their own shutdown with it.
+	Lifecycle *Lifecycle
+	// Health checks the dependencies. Contexts register checks for their own dependencies with it.
+	Health *Health
+	// Maintenance follows the maintenance mode, that is shared by all instances.
+	Maintenance *maintenance.Watcher
+	// Features are the feature flags, that are shared by all instances.
+	Features *feature.Flags
 }
 
 func (c *Container) EnsureAllDependenciesPresent() error {
@@ -74,71 +96,86 @@ func (c *Container) EnsureAllDependenciesPresent() error {
 	return nil
 }
 
-func InitialiseDefaultArrowerDependencies(ctx context.Context, conf *Config) (*Container, func(ctx context.Context) error, error) { //nolint:funlen,gocyclo,cyclop,lll // dependency injection is long but also straight forward.
-	container := &Container{ //nolint:exhaustruct
-		Config: conf,
+type ContainerOption func(*containerOptions)
+
+type containerOptions struct {
+	migrate    bool
+	background bool
+}
+
+// WithoutMigrations does not apply the migrations of the skeleton, e.g. so the migrate command can control them.
+func WithoutMigrations() ContainerOption {
+	return func(o *containerOptions) {
+		o.migrate = false
 	}
+}
 
-	{ // observability
-		resource := resource.NewWithAttributes(
-			semconv.SchemaURL,
-			semconv.ServiceNameKey.String(fmt.Sprintf("%s.%s", conf.OrganisationName, conf.ApplicationName)),
+// WithoutBackgroundServices does not start the job workers, the scheduler, the events, and the status endpoint,
+// e.g. for a command, that runs once and should not interfere with the running instances.
+// Otherwise, the Role of the Config decides what is started.
+func WithoutBackgroundServices() ContainerOption {
+	return func(o *containerOptions) {
+		o.background = false
+	}
+}
 
-			// NEEDS TO MATCH WITH THE LOGS LABEL (why? for the "Logs for this span" button in tempo?)
-			attribute.String(conf.OrganisationName, conf.ApplicationName),
+func InitialiseDefaultArrowerDependencies(ctx context.Context, conf *Config, opts ...ContainerOption) (*Container, func(ctx context.Context) error, error) { //nolint:funlen,gocyclo,cyclop,lll // dependency injection is long but also straight forward.
+	options := containerOptions{migrate: true, background: true}
+	for _, opt := range opts {
+		opt(&options)
+	}
 
-			// more attributes like e.g. kubernetes pod name
-		)
-		// FIND OUT: CAN RESOURCES BE ADDED TO LOGGER SO ALL THREE HAVE THE SAME VALUES?
+	var (
+		runJobs   = options.background && conf.Role.RunsJobs()
+		serveWeb  = options.background && conf.Role.ServesWeb()
+		queueOpts []jobqueue.Option
+	)
 
-		{
-			opts := []otlptracegrpc.Option{
-				otlptracegrpc.WithEndpoint(fmt.Sprintf("%s:%d", conf.OTEL.Host, conf.OTEL.Port)),
-			}
-			if conf.Debug {
-				opts = append(opts,
-					otlptracegrpc.WithInsecure(),
-					otlptracegrpc.WithDialOption(grpc.WithBlock()),
-				)
-			}
+	if !runJobs {
+		queueOpts = append(queueOpts, jobqueue.EnqueueOnly())
+	}
 
-			traceExporter, err := otlptracegrpc.New(ctx, opts...)
-			if err != nil {
-				return nil, nil, fmt.Errorf("could not connect to trace exporter: %w", err)
-			}
+	container := &Container{ //nolint:exhaustruct
+		Config:    conf,
+		Lifecycle: NewLifecycle(),
+		Health:    NewHealth(),
+	}
 
-			traceProvider := trace.NewTracerProvider(
-				trace.WithBatcher(traceExporter), // prod
-				trace.WithResource(resource),
-				// set the sampling rate based on the parent span to 60%
-				trace.WithSampler(trace.ParentBased(trace.TraceIDRatioBased(0.6))),
-			)
-			if conf.Debug {
-				traceProvider = trace.NewTracerProvider(
-					trace.WithSyncer(traceExporter),
-					trace.WithResource(resource),
-					trace.WithSampler(trace.AlwaysSample()),
-				)
-			}
+	// the traces, metrics, and logs have the same resource attributes, so they can be correlated
+	telemetry := resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceNameKey.String(fmt.Sprintf("%s.%s", conf.OrganisationName, conf.ApplicationName)),
+		semconv.ServiceInstanceIDKey.String(conf.InstanceName),
+		semconv.ServiceVersionKey.String(gitHash()),
+
+		// NEEDS TO MATCH WITH THE LOGS LABEL (why? for the "Logs for this span" button in tempo?)
+		attribute.String(conf.OrganisationName, conf.ApplicationName),
 
-			container.TraceProvider = traceProvider
-			// otel.SetTracerProvider(traceProvider)
+		// more attributes like e.g. kubernetes pod name
+	)
+
+	{ // observability
+		traceProvider, err := NewTracerProvider(ctx, conf.OTEL, telemetry, conf.Debug)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not create trace provider: %w", err)
 		}
 
-		{
-			exporter, err := prometheus.New()
-			if err != nil {
-				return nil, nil, fmt.Errorf("could not create to prometheus exporter: %w", err)
-			}
+		container.TraceProvider = traceProvider
+		container.Lifecycle.OnShutdown("traces", traceProvider.Shutdown) // flushes the remaining spans
+		// otel.SetTracerProvider(traceProvider)
 
-			meterProvider := metric.NewMeterProvider(
-				metric.WithResource(resource),
-				metric.WithReader(exporter),
-			)
+		if conf.OTEL.Traces == ExporterOTLPGRPC || conf.OTEL.Traces == ExporterOTLPHTTP {
+			container.Health.Register("trace exporter", DialCheck(conf.OTEL.Endpoint()), Optional())
+		}
 
-			container.MeterProvider = meterProvider
-			// otel.SetMeterProvider(meterProvider)
+		meterProvider, err := NewMeterProvider(conf.OTEL, telemetry)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not create meter provider: %w", err)
 		}
+
+		container.MeterProvider = meterProvider
+		container.Lifecycle.OnShutdown("metrics", meterProvider.Shutdown)
+		// otel.SetMeterProvider(meterProvider)
 	}
 
 	{ // postgres
@@ -158,48 +195,129 @@ func InitialiseDefaultArrowerDependencies(ctx context.Context, conf *Config) (*C
 
 		container.PGx = pg.PGx
 		container.db = pg
+		container.Lifecycle.OnShutdown("postgres", pg.Shutdown)
+		container.Health.Register("postgres", PingCheck(pg.PGx.Ping))
+
+		if options.migrate {
+			err = migrations.Up(ctx, container.PGx)
+			if err != nil {
+				return nil, nil, fmt.Errorf("could not migrate skeleton tables: %w", err)
+			}
+		}
 	}
 
 	container.Settings = setting.NewPostgresSettings(container.PGx)
 
+	if conf.Mail.Host != "" {
+		var auth smtp.Auth
+		if conf.Mail.User != "" {
+			auth = smtp.PlainAuth("", conf.Mail.User, conf.Mail.Password.Secret(), conf.Mail.Host)
+		}
+
+		container.Mailer = mail.NewSMTPMailer(fmt.Sprintf("%s:%d", conf.Mail.Host, conf.Mail.Port), conf.Mail.From, auth)
+	}
+
 	logger := alog.New()
 	logger = logger.With(
 		slog.String("organisation_name", conf.OrganisationName),
 		slog.String("application_name", conf.ApplicationName),
 		slog.String("instance_name", conf.InstanceName),
+		slog.String("role", string(conf.Role)),
 		slog.String("git_hash", gitHash()),
 		slog.Bool("debug", conf.Debug),
 	)
 	if conf.Debug {
 		logger = alog.NewDevelopment(container.PGx, container.Settings)
 	}
+	logger = logger.With(resourceLogAttrs(telemetry)...)
 	container.Logger = logger
+
+	if options.background {
+		// the last part shut down before postgres, so the log is still written to the database
+		container.Lifecycle.OnShutdown("log", func(ctx context.Context) error {
+			logger.InfoContext(ctx, "shutdown complete")
+			return nil
+		})
+	}
 	// slog.SetDefault(container.Logger.(*slog.Logger)) // todo test if this works even if the cast works
 
+	{ // maintenance
+		container.Maintenance = maintenance.NewWatcher(container.Logger, container.Settings)
+		container.Maintenance.OnChange(func(_ context.Context, _ maintenance.Mode, mode maintenance.Mode) {
+			container.Health.SetMaintenance(mode.Enabled)
+		})
+		container.Maintenance.Start(ctx)
+		container.Lifecycle.OnShutdown("maintenance", container.Maintenance.Shutdown)
+	}
+
+	{ // feature flags
+		container.Features = feature.New(container.Logger, feature.NewPostgresStore(container.PGx), container.PGx, func(ctx context.Context) feature.Target {
+			return feature.Target{
+				UserID:    auth.CurrentUserID(ctx),
+				Tenant:    feature.Tenant(ctx),
+				Superuser: auth.IsSuperUser(ctx),
+			}
+		})
+		if options.background {
+			container.Features.Start(ctx)
+		}
+
+		container.Lifecycle.OnShutdown("feature flags", container.Features.Shutdown)
+	}
+
 	{ // echo router
 		// todo extract echo setup to main arrower repo, ones it is "ready" and can be abstracted for easier use, analog to postgres
 		router := echo.New()
 		router.HideBanner = true
 		router.Logger.SetOutput(io.Discard)
-		router.Validator = &CustomValidator{validator: validator.New()}
+		validate, err := i18n.NewValidator()
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not create validator: %w", err)
+		}
+
+		container.Validator = validate
+		container.ErrorHandler = web.NewErrorHandler(container.Logger, validate, conf.Debug)
+		router.Validator = &CustomValidator{validator: validate.Validate}
+		router.HTTPErrorHandler = container.ErrorHandler.Handle
 		router.IPExtractor = echo.ExtractIPFromXFFHeader() // see: https://echo.labstack.com/docs/ip-address
 		router.Use(otelecho.Middleware(conf.Web.Hostname, otelecho.WithTracerProvider(container.TraceProvider)))
 		router.Use(echoprometheus.NewMiddleware(conf.ApplicationName))
-		router.Use(middleware.Static("public")) // todo if prod use fs instead
+		router.Use(web.Recover()) // after otel, so the error page of a panic shows the trace id
+
+		var (
+			viewFS   fs.FS = views.SharedViews
+			publicFS fs.FS = public.Assets
+		)
 
 		hotReload := false
 		if conf.Debug {
 			router.Debug = true
 			router.Use(injectMW)
 			hotReload = true
+
+			viewFS = os.DirFS("shared/views")
+			publicFS = os.DirFS("public")
 		}
 
-		r, _ := web.NewEchoRenderer(container.Logger, container.TraceProvider, router, os.DirFS("shared/views"), hotReload) // todo: if prod load from embed
-		err := r.AddBaseData("default", views.NewDefaultBaseDataFunc(container.Settings))
+		assets, err := web.NewAssets(publicFS, !hotReload)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not load assets: %w", err)
+		}
+
+		router.Use(assets.Middleware)
+
+		r, err := web.NewEchoRenderer(container.Logger, container.TraceProvider, router, viewFS, assets, hotReload)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not create renderer: %w", err)
+		}
+
+		err = r.AddBaseData("default", views.NewDefaultBaseDataFunc(container.Settings))
 		if err != nil {
 			return nil, nil, fmt.Errorf("could not add default base data: %w", err) // todo return shutdown, as some services like postgres are already started
 		}
 
+		r.SetFlags(container.Features.IsEnabled)
+
 		router.Renderer = r
 		container.WebRenderer = r
 
@@ -208,103 +326,136 @@ func InitialiseDefaultArrowerDependencies(ctx context.Context, conf *Config) (*C
 		container.WebRouter = router
 		container.WebRouter.Use(session.Middleware(ss))
 		// di.WebRouter.Use(middleware.CSRF())
-		container.WebRouter.Use(auth.EnrichCtxWithUserInfoMiddleware)
+
+		catalog, err := i18n.NewCatalog(viewFS)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not load messages: %w", err)
+		}
+
+		container.WebRouter.Use(i18n.Middleware(catalog))
+		container.WebRouter.Use(auth.EnrichCtxWithUserInfoMiddleware) // after i18n, so the locale of the user wins
+		container.WebRouter.Use(container.Maintenance.Middleware(auth.IsSuperUser, "/auth/login", "/auth/logout"))
 
 		container.AdminRouter = container.WebRouter.Group("/admin")
 		container.AdminRouter.Use(auth.EnsureUserIsSuperuserMiddleware)
 
+		var store ratelimit.Store = ratelimit.NewMemoryStore()
+		if conf.RateLimits.Store == "postgres" {
+			store = ratelimit.NewPostgresStore(container.PGx)
+		}
+
+		container.RateLimiter, err = ratelimit.New(container.Logger, container.MeterProvider, store)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not create rate limiter: %w", err)
+		}
+
 		container.APIRouter = router.Group("/api") // todo add api middleware
+		container.APIRouter.Use(container.RateLimiter.Middleware("api",
+			conf.RateLimits.API.Limit(),
+			ratelimit.ByAPIKey(echo.HeaderAuthorization),
+		))
+
+		container.Events = web.NewEvents(container.Logger, container.PGx)
 	}
 
 	{ // jobs
-		queue, err := jobs.NewPostgresJobs(container.Logger, container.MeterProvider, container.TraceProvider, container.PGx,
-			jobs.WithPoolName(conf.InstanceName),
-		)
+		newQueue := func(opts ...jobs.QueueOpt) jobqueue.Factory {
+			return func(workers int) (jobs.Queue, error) {
+				queueOpts := append([]jobs.QueueOpt{jobs.WithPoolName(conf.InstanceName)}, opts...)
+				if workers > 0 {
+					queueOpts = append(queueOpts, jobs.WithPoolSize(workers))
+				}
+
+				return jobs.NewPostgresJobs(container.Logger, container.MeterProvider, container.TraceProvider, container.PGx, queueOpts...) //nolint:wrapcheck,lll // wrapped by the caller
+			}
+		}
+
+		queueOpts = append(queueOpts, jobqueue.WithPostgres(container.PGx))
+
+		queue, err := jobqueue.NewControlledQueue(ctx, container.Logger, container.Settings, "", newQueue(), queueOpts...)
 		if err != nil {
 			return nil, nil, fmt.Errorf("could not start default job queue: %w", err)
 		}
 
-		arrowerQueue, err := jobs.NewPostgresJobs(
-			container.Logger,
-			container.MeterProvider,
-			container.TraceProvider,
-			container.PGx,
-			jobs.WithQueue("Arrower"),
-			jobs.WithPoolName(conf.InstanceName),
+		arrowerQueue, err := jobqueue.NewControlledQueue(ctx, container.Logger, container.Settings, "Arrower",
+			newQueue(jobs.WithQueue("Arrower")), queueOpts...,
 		)
 		if err != nil {
 			return nil, nil, fmt.Errorf("could not start arrower job queue: %w", err)
 		}
 
+		if runJobs {
+			queue.Start(ctx)
+			arrowerQueue.Start(ctx)
+		}
+
+		container.Lifecycle.OnShutdown("default queue", queue.Shutdown) // waits for the running jobs to finish
+		container.Lifecycle.OnShutdown("arrower queue", arrowerQueue.Shutdown)
+
 		container.DefaultQueue = queue
 		container.ArrowerQueue = arrowerQueue
-	}
 
-	//
-	// Start the prometheus HTTP server and pass the exporter Collector to it
-	if conf.Web.StatusEndpoint {
-		go serveMetrics(ctx, container)
-	}
-
-	return container, shutdown(container), nil
-}
+		container.Scheduler = cron.NewScheduler(container.Logger, container.PGx, map[string]jobs.Queue{
+			"":        queue,
+			"Arrower": arrowerQueue,
+		})
+		if runJobs {
+			container.Scheduler.Start(ctx)
+		}
 
-func shutdown(di *Container) func(ctx context.Context) error {
-	return func(ctx context.Context) error {
-		di.Logger.InfoContext(ctx, "shutdown...")
+		container.Lifecycle.OnShutdown("scheduler", container.Scheduler.Shutdown) // before the queues, so no jobs are enqueued anymore
 
-		_ = di.WebRouter.Shutdown(ctx)
-		_ = di.DefaultQueue.Shutdown(ctx)
-		_ = di.ArrowerQueue.Shutdown(ctx)
-		_ = di.TraceProvider.Shutdown(ctx)
-		_ = di.MeterProvider.Shutdown(ctx)
-		_ = di.db.Shutdown(ctx)
-		// todo shutdown meter/status endpoint
+		if conf.RateLimits.Store == "postgres" { // prune the full buckets outside of the requests
+			err = arrowerQueue.RegisterJobFunc(func(ctx context.Context, _ ratelimit.PruneJob) error {
+				return ratelimit.NewPostgresStore(container.PGx).Prune(ctx)
+			})
+			if err != nil {
+				return nil, nil, fmt.Errorf("could not register rate limit prune job: %w", err)
+			}
 
-		return nil // todo error handling
+			err = container.Scheduler.Register(ctx, "ratelimit.prune", "*/5 * * * *", "Arrower", ratelimit.PruneJob{})
+			if err != nil {
+				return nil, nil, fmt.Errorf("could not schedule rate limit prune job: %w", err)
+			}
+		}
 	}
-}
 
-func serveMetrics(ctx context.Context, di *Container) {
-	const (
-		metricPath = "/metrics"
-		statusPath = "/status"
-	)
-
-	serverStartedAt := time.Now()
+	// the web servers are shut down before the workers,
+	// so no new jobs are enqueued while the workers finish the running ones
+	container.Lifecycle.OnShutdown("web server", container.WebRouter.Shutdown) // drains the running requests
 
-	addr := fmt.Sprintf(":%d", di.Config.Web.StatusEndpointPort)
-
-	di.Logger.InfoContext(ctx, "serving status endpoint",
-		slog.String("addr", addr),
-		slog.String("metric_path", metricPath),
-		slog.String("status_path", statusPath),
-	)
+	if serveWeb {
+		container.Events.Start(ctx)
+		container.Lifecycle.OnShutdown("events", container.Events.Shutdown) // before the router, as open streams would block its shutdown
+	}
 
-	// http.Handle("/metrics", promhttp.Handler())
-	http.Handle(metricPath, promhttp.HandlerFor(
-		prometheus2.DefaultGatherer,
-		promhttp.HandlerOpts{ //nolint:exhaustruct
-			EnableOpenMetrics: true, // to enable Examplars in the export format
-		},
-	))
+	if options.background {
+		err := registerInstance(ctx, container.PGx, conf.InstanceName, conf.Role)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not register instance: %w", err)
+		}
+	}
 
-	http.HandleFunc(statusPath, func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK) // todo in case of error: 500 code
-		// TODO disable cache
+	//
+	// Start the prometheus HTTP server and pass the exporter Collector to it.
+	// It is served by all roles, so a worker can be monitored as well.
+	if conf.Web.StatusEndpoint && options.background {
+		server := newStatusServer(ctx, container)
 
-		statusData := getSystemStatus(di, serverStartedAt)
+		container.Lifecycle.Serve(server.ListenAndServe) // a failing status endpoint shuts the application down
+		container.Lifecycle.OnShutdown("status endpoint", server.Shutdown)
+	}
 
-		_ = json.NewEncoder(w).Encode(statusData)
-	})
+	return container, container.Lifecycle.Shutdown, nil
+}
 
-	err := http.ListenAndServe(addr, nil)
-	if err != nil {
-		di.Logger.DebugContext(ctx, "error serving http", slog.String("err", err.Error()))
+// registerInstance stores the Role of the instance, so its worker pools can be labelled with it.
+func registerInstance(ctx context.Context, pg *pgxpool.Pool, name string, role Role) error {
+	_, err := pg.Exec(ctx, `
+		INSERT INTO arrower.skeleton_instances (name, role) VALUES ($1, $2)
+		ON CONFLICT (name) DO UPDATE SET role = $2, started_at = NOW()`, name, string(role))
 
-		return
-	}
+	return err //nolint:wrapcheck // wrapped by the caller
 }
 
 type CustomValidator struct {
@@ -329,7 +480,7 @@ func injectMW(next echo.HandlerFunc) echo.HandlerFunc {
 		}
 
 		// skip htmx requests, as the code is already present on the page from a previous load
-		if c.Request().Header.Get("HX-Request") == "true" {
+		if htmx.IsRequest(c) {
 			return nil
 		}
 
@@ -351,19 +502,16 @@ const hotReloadJSCode = `<!-- Code injected by hot-reload middleware -->
 </div>
 
 <script>
+	// the assets are fingerprinted on every render, so the page links the changed stylesheets under a new url
 	function refreshCSS() {
-		var sheets = [].slice.call(document.getElementsByTagName("link"));
-		var head = document.getElementsByTagName("head")[0];
-		for (var i = 0; i < sheets.length; ++i) {
-			var elem = sheets[i];
-			head.removeChild(elem);
-			var rel = elem.rel;
-			if (elem.href && typeof rel != "string" || rel.length == 0 || rel.toLowerCase() == "stylesheet") {
-				var url = elem.href.replace(/(&|\?)_cacheOverride=\d+/, '');
-				elem.href = url + (url.indexOf('?') >= 0 ? '&' : '?') + '_cacheOverride=' + (new Date().valueOf());
+		fetch(window.location.href).then(function(res) { return res.text(); }).then(function(html) {
+			var page = new DOMParser().parseFromString(html, "text/html");
+			var next = page.querySelectorAll('link[rel="stylesheet"]');
+			var current = document.querySelectorAll('link[rel="stylesheet"]');
+			for (var i = 0; i < current.length && i < next.length; ++i) {
+				current[i].setAttribute("href", next[i].getAttribute("href"));
 			}
-			head.appendChild(elem);
-		}
+		});
 	}
 
     var loc = window.location;
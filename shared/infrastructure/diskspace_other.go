@@ -0,0 +1,10 @@
+//go:build !unix
+
+package infrastructure
+
+import "math"
+
+// freeDiskSpace is not supported on this platform, so the disk is always considered to have space left.
+func freeDiskSpace(_ string) (uint64, error) {
+	return math.MaxUint64, nil
+}
@@ -0,0 +1,15 @@
+//go:build unix
+
+package infrastructure
+
+import "syscall"
+
+func freeDiskSpace(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err //nolint:wrapcheck // wrapped by the caller
+	}
+
+	return stat.Bavail * uint64(stat.Bsize), nil //nolint:gosec // block size is never negative
+}
@@ -0,0 +1,137 @@
+// Package feature turns features on and off without a deployment,
+// for everybody or for a part of the users only.
+//
+// The Flags are kept in a Store, e.g. in Postgres, so they are shared by all instances.
+// Each instance caches them and drops its cache, whenever a Flag is changed on any instance,
+// as the change is sent to all of them with Postgres NOTIFY.
+//
+// Check a Flag in the code with IsEnabled and in the views with the template function flag:
+//
+//	if di.Features.IsEnabled(ctx, "new-checkout") { ... }
+//
+//	{{ if flag $ "new-checkout" }}...{{ end }}
+package feature
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"slices"
+	"time"
+)
+
+var (
+	ErrInvalidFlag  = errors.New("invalid feature flag")
+	ErrFlagNotFound = errors.New("feature flag not found")
+	ErrFlagExists   = errors.New("feature flag exists already")
+)
+
+const maxPercentage = 100
+
+//nolint:gochecknoglobals // used as a constant
+var validKey = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// Flag is a feature, that is on for the users it targets.
+//
+// A disabled Flag is off for everybody. An enabled Flag is on for the targeted Users, Tenants, and superusers
+// and for a Percentage of all other users. For a simple on and off, set the Percentage to 100.
+type Flag struct {
+	Key         string
+	Description string
+	Enabled     bool
+	// Percentage of the users, the Flag is on for. Each user is assigned to the same bucket for a Flag,
+	// so a user keeps the feature, when the Percentage is raised.
+	Percentage int
+	// Users are the IDs of the users, the Flag is always on for.
+	Users []string
+	// Tenants are the tenants, the Flag is always on for, see WithTenant.
+	Tenants []string
+	// Superusers have the Flag always on, e.g. to try a feature in production before anybody else.
+	Superusers bool
+
+	UpdatedAt time.Time
+	// UpdatedBy is the ID of the user, that changed the Flag last.
+	UpdatedBy string
+}
+
+func (f Flag) Validate() error {
+	if !validKey.MatchString(f.Key) {
+		return fmt.Errorf("%w: key has to be lowercase letters, digits, dots, dashes, or underscores: %s", ErrInvalidFlag, f.Key)
+	}
+
+	if f.Percentage < 0 || f.Percentage > maxPercentage {
+		return fmt.Errorf("%w: percentage has to be between 0 and 100", ErrInvalidFlag)
+	}
+
+	return nil
+}
+
+// Target is who a Flag is evaluated for. An anonymous visitor has no UserID.
+type Target struct {
+	UserID    string
+	Tenant    string
+	Superuser bool
+}
+
+// IsEnabled returns true, if the Flag is on for the target.
+func (f Flag) IsEnabled(target Target) bool {
+	if !f.Enabled {
+		return false
+	}
+
+	if f.Superusers && target.Superuser {
+		return true
+	}
+
+	if target.UserID != "" && slices.Contains(f.Users, target.UserID) {
+		return true
+	}
+
+	if target.Tenant != "" && slices.Contains(f.Tenants, target.Tenant) {
+		return true
+	}
+
+	if f.Percentage >= maxPercentage {
+		return true
+	}
+
+	// the rollout is by user, so a user sees the same on all devices. Anonymous visitors are rolled out by tenant.
+	id := target.UserID
+	if id == "" {
+		id = target.Tenant
+	}
+
+	if f.Percentage <= 0 || id == "" {
+		return false
+	}
+
+	return bucket(f.Key, id) < f.Percentage
+}
+
+// bucket assigns the id to one of 100 buckets. Each Flag has its own assignment,
+// so not the same users get all new features first.
+func bucket(key string, id string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key + "/" + id))
+
+	return int(h.Sum32() % maxPercentage)
+}
+
+type ctxTenant struct{}
+
+// WithTenant returns a ctx, that evaluates the Flags for the tenant,
+// e.g. set by a middleware that resolves the tenant from the subdomain.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, ctxTenant{}, tenant)
+}
+
+// Tenant returns the tenant set by WithTenant.
+func Tenant(ctx context.Context) string {
+	if tenant, ok := ctx.Value(ctxTenant{}).(string); ok {
+		return tenant
+	}
+
+	return ""
+}
@@ -0,0 +1,137 @@
+package feature_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/feature"
+)
+
+var ctx = context.Background()
+
+func TestFlag_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		flag  feature.Flag
+		valid bool
+	}{
+		"valid":              {feature.Flag{Key: "new-checkout", Percentage: 50}, true},
+		"dotted key":         {feature.Flag{Key: "admin.logs_v2"}, true},
+		"empty key":          {feature.Flag{Key: ""}, false},
+		"uppercase key":      {feature.Flag{Key: "NewCheckout"}, false},
+		"key with comma":     {feature.Flag{Key: "a,b"}, false},
+		"negative percent":   {feature.Flag{Key: "a", Percentage: -1}, false},
+		"more than hundred":  {feature.Flag{Key: "a", Percentage: 101}, false},
+		"hundred is allowed": {feature.Flag{Key: "a", Percentage: 100}, true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.flag.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, feature.ErrInvalidFlag)
+			}
+		})
+	}
+}
+
+func TestFlag_IsEnabled(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		flag    feature.Flag
+		target  feature.Target
+		enabled bool
+	}{
+		"disabled": {
+			feature.Flag{Key: "a", Enabled: false, Percentage: 100, Superusers: true},
+			feature.Target{UserID: "1", Superuser: true},
+			false,
+		},
+		"on for everybody": {
+			feature.Flag{Key: "a", Enabled: true, Percentage: 100},
+			feature.Target{},
+			true,
+		},
+		"off for everybody": {
+			feature.Flag{Key: "a", Enabled: true, Percentage: 0},
+			feature.Target{UserID: "1"},
+			false,
+		},
+		"user": {
+			feature.Flag{Key: "a", Enabled: true, Users: []string{"1", "2"}},
+			feature.Target{UserID: "2"},
+			true,
+		},
+		"other user": {
+			feature.Flag{Key: "a", Enabled: true, Users: []string{"1"}},
+			feature.Target{UserID: "2"},
+			false,
+		},
+		"superuser": {
+			feature.Flag{Key: "a", Enabled: true, Superusers: true},
+			feature.Target{UserID: "1", Superuser: true},
+			true,
+		},
+		"superuser not targeted": {
+			feature.Flag{Key: "a", Enabled: true},
+			feature.Target{UserID: "1", Superuser: true},
+			false,
+		},
+		"tenant": {
+			feature.Flag{Key: "a", Enabled: true, Tenants: []string{"acme"}},
+			feature.Target{Tenant: "acme"},
+			true,
+		},
+		"anonymous in a rollout": {
+			feature.Flag{Key: "a", Enabled: true, Percentage: 99},
+			feature.Target{},
+			false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.enabled, tt.flag.IsEnabled(tt.target))
+		})
+	}
+
+	t.Run("percentage", func(t *testing.T) {
+		t.Parallel()
+
+		flag := feature.Flag{Key: "rollout", Enabled: true, Percentage: 30}
+
+		enabled := map[string]bool{}
+
+		for i := range 1000 {
+			id := fmt.Sprint(i)
+			if flag.IsEnabled(feature.Target{UserID: id}) {
+				enabled[id] = true
+			}
+		}
+
+		assert.InDelta(t, 300, len(enabled), 60)
+
+		flag.Percentage = 60
+		for id := range enabled {
+			assert.True(t, flag.IsEnabled(feature.Target{UserID: id}), "a user keeps the feature, when the rollout grows")
+		}
+	})
+}
+
+func TestTenant(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "", feature.Tenant(ctx))
+	assert.Equal(t, "acme", feature.Tenant(feature.WithTenant(ctx, "acme")))
+}
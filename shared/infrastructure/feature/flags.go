@@ -0,0 +1,265 @@
+package feature
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// Channel is the Postgres channel the keys of changed Flags are sent over, so all instances drop them from cache.
+	Channel = "arrower_feature_flags"
+	// maxHistory is the number of changes kept for each Flag.
+	maxHistory     = 50
+	reconnectDelay = time.Second
+)
+
+// Change is a version of a Flag in its history.
+type Change struct {
+	Flag Flag
+	// Deleted is true, if the Flag was deleted with this change.
+	Deleted bool
+}
+
+// New returns Flags, that are kept by the store.
+// target returns who a Flag is evaluated for, e.g. the logged-in user of the request.
+// If pg is nil, changes are only seen by this instance.
+func New(logger alog.Logger, store Store, pg *pgxpool.Pool, target func(ctx context.Context) Target) *Flags {
+	return &Flags{
+		logger:     logger.WithGroup("arrower.feature"),
+		store:      store,
+		pg:         pg,
+		target:     target,
+		mu:         sync.RWMutex{},
+		cache:      map[string]Flag{},
+		generation: 0,
+		cancel:     func() {},
+		wg:         sync.WaitGroup{},
+	}
+}
+
+// Flags are the feature flags of all instances.
+type Flags struct {
+	logger alog.Logger
+	store  Store
+	pg     *pgxpool.Pool
+	target func(ctx context.Context) Target
+
+	mu sync.RWMutex
+	// cache holds the Flags loaded from the store. Flags, that do not exist, are cached as disabled.
+	cache map[string]Flag
+	// generation changes with each invalidation, so a Flag loaded before is not cached.
+	generation uint64
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// IsEnabled returns true, if the Flag is on for the target of the ctx.
+// A Flag, that does not exist, is off.
+func (f *Flags) IsEnabled(ctx context.Context, key string) bool {
+	flag, generation, ok := f.cached(key)
+	if !ok {
+		var err error
+
+		flag, err = f.Flag(ctx, key)
+		if err != nil && !isNotFound(err) {
+			f.logger.LogAttrs(ctx, slog.LevelWarn, "could not load feature flag",
+				slog.String("key", key),
+				slog.String("err", err.Error()),
+			)
+
+			return false // not cached, so it is loaded again with the next check
+		}
+
+		f.mu.Lock()
+		if f.generation == generation {
+			f.cache[key] = flag
+		}
+		f.mu.Unlock()
+	}
+
+	return flag.IsEnabled(f.target(ctx))
+}
+
+// Flag returns the Flag as stored, without the cache.
+func (f *Flags) Flag(ctx context.Context, key string) (Flag, error) {
+	return f.store.Flag(ctx, key) //nolint:wrapcheck // return the errors of the store as is
+}
+
+// All returns all Flags ordered by their key.
+func (f *Flags) All(ctx context.Context) ([]Flag, error) {
+	return f.store.All(ctx) //nolint:wrapcheck // return the errors of the store as is
+}
+
+// Create adds a new Flag. It fails, if a Flag with the key exists already.
+func (f *Flags) Create(ctx context.Context, flag Flag) error {
+	_, err := f.Flag(ctx, flag.Key)
+	if err == nil {
+		return fmt.Errorf("%w: %s", ErrFlagExists, flag.Key)
+	}
+
+	if !isNotFound(err) {
+		return err
+	}
+
+	return f.Save(ctx, flag)
+}
+
+// Save creates or changes the Flag and adds it to its history.
+// All instances use the change, once they received the notification.
+func (f *Flags) Save(ctx context.Context, flag Flag) error {
+	if err := flag.Validate(); err != nil {
+		return err
+	}
+
+	flag.UpdatedAt = time.Now().UTC()
+
+	if err := f.store.Save(ctx, Change{Flag: flag, Deleted: false}); err != nil {
+		return err //nolint:wrapcheck // return the errors of the store as is
+	}
+
+	f.changed(ctx, flag.Key)
+
+	return nil
+}
+
+// Delete removes the Flag, so it is off for everybody. Its history is kept.
+func (f *Flags) Delete(ctx context.Context, key string, deletedBy string) error {
+	flag, err := f.Flag(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	flag.UpdatedAt = time.Now().UTC()
+	flag.UpdatedBy = deletedBy
+
+	if err := f.store.Save(ctx, Change{Flag: flag, Deleted: true}); err != nil {
+		return err //nolint:wrapcheck // return the errors of the store as is
+	}
+
+	f.changed(ctx, key)
+
+	return nil
+}
+
+// History returns the changes of the Flag, the latest first.
+func (f *Flags) History(ctx context.Context, key string) ([]Change, error) {
+	return f.store.History(ctx, key) //nolint:wrapcheck // return the errors of the store as is
+}
+
+// Start listens for the changes of all instances in the background until Shutdown is called or the ctx is cancelled.
+// If the connection to Postgres is lost, it reconnects and drops the cache, as changes might be missed.
+func (f *Flags) Start(ctx context.Context) {
+	if f.pg == nil {
+		return
+	}
+
+	ctx, f.cancel = context.WithCancel(ctx)
+
+	f.wg.Add(1)
+
+	go func() {
+		defer f.wg.Done()
+
+		for {
+			err := f.listen(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+
+			f.invalidate("")
+			f.logger.LogAttrs(ctx, slog.LevelWarn, "lost connection, reconnecting", slog.String("err", err.Error()))
+
+			select {
+			case <-time.After(reconnectDelay):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	f.logger.LogAttrs(ctx, alog.LevelInfo, "feature flags started", slog.String("channel", Channel))
+}
+
+func (f *Flags) Shutdown(_ context.Context) error {
+	f.cancel()
+	f.wg.Wait()
+
+	return nil
+}
+
+func (f *Flags) listen(ctx context.Context) error {
+	conn, err := f.pg.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("could not acquire connection: %v", err) //nolint:errorlint,goerr113 // prevent err in api
+	}
+
+	// the connection is listening, so it is not given back to the pool but closed
+	pgConn := conn.Hijack()
+	defer pgConn.Close(context.Background()) //nolint:contextcheck // close even if ctx is cancelled
+
+	_, err = pgConn.Exec(ctx, "LISTEN "+Channel)
+	if err != nil {
+		return fmt.Errorf("could not listen: %v", err) //nolint:errorlint,goerr113 // prevent err in api
+	}
+
+	// changes made before the connection listened are not seen otherwise
+	f.invalidate("")
+
+	for {
+		notification, err := pgConn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("could not receive notification: %v", err) //nolint:errorlint,goerr113 // prevent err in api
+		}
+
+		f.invalidate(notification.Payload)
+	}
+}
+
+// changed drops the Flag from the cache of all instances.
+func (f *Flags) changed(ctx context.Context, key string) {
+	f.invalidate(key)
+
+	if f.pg == nil {
+		return
+	}
+
+	_, err := f.pg.Exec(ctx, `SELECT pg_notify($1, $2)`, Channel, key)
+	if err != nil {
+		f.logger.LogAttrs(ctx, slog.LevelWarn, "could not notify the instances about a changed feature flag",
+			slog.String("key", key),
+			slog.String("err", err.Error()),
+		)
+	}
+}
+
+// invalidate drops the Flag from the cache, or all Flags, if the key is empty.
+func (f *Flags) invalidate(key string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.generation++
+
+	if key == "" {
+		f.cache = map[string]Flag{}
+
+		return
+	}
+
+	delete(f.cache, key)
+}
+
+func (f *Flags) cached(key string) (Flag, uint64, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	flag, ok := f.cache[key]
+
+	return flag, f.generation, ok
+}
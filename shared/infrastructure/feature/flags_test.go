@@ -0,0 +1,188 @@
+package feature_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/feature"
+)
+
+// userTarget evaluates the flags for the user set in the ctx by withUser.
+func userTarget(ctx context.Context) feature.Target {
+	id, _ := ctx.Value(ctxUser{}).(string)
+
+	return feature.Target{UserID: id, Tenant: feature.Tenant(ctx), Superuser: id == "admin"}
+}
+
+type ctxUser struct{}
+
+func withUser(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxUser{}, id)
+}
+
+func TestFlags_IsEnabled(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown flag", func(t *testing.T) {
+		t.Parallel()
+
+		flags := feature.New(alog.NewNoopLogger(), feature.NewMemoryStore(), nil, userTarget)
+
+		assert.False(t, flags.IsEnabled(ctx, "unknown"))
+	})
+
+	t.Run("target from ctx", func(t *testing.T) {
+		t.Parallel()
+
+		flags := feature.New(alog.NewNoopLogger(), feature.NewMemoryStore(), nil, userTarget)
+
+		err := flags.Save(ctx, feature.Flag{Key: "beta", Enabled: true, Users: []string{"1"}, Superusers: true})
+		assert.NoError(t, err)
+
+		assert.True(t, flags.IsEnabled(withUser(ctx, "1"), "beta"))
+		assert.True(t, flags.IsEnabled(withUser(ctx, "admin"), "beta"))
+		assert.False(t, flags.IsEnabled(withUser(ctx, "2"), "beta"))
+		assert.False(t, flags.IsEnabled(ctx, "beta"))
+	})
+
+	t.Run("failing store", func(t *testing.T) {
+		t.Parallel()
+
+		store := &failingStore{MemoryStore: feature.NewMemoryStore(), fail: true}
+		flags := feature.New(alog.NewNoopLogger(), store, nil, userTarget)
+
+		_, err := flags.Flag(ctx, "beta")
+		assert.ErrorIs(t, err, errStore)
+		assert.NotErrorIs(t, err, feature.ErrFlagNotFound)
+
+		assert.False(t, flags.IsEnabled(ctx, "beta"))
+
+		err = flags.Create(ctx, feature.Flag{Key: "beta", Enabled: true, Percentage: 100})
+		assert.ErrorIs(t, err, errStore, "an existing flag is not overwritten")
+
+		store.fail = false
+		_ = store.Save(ctx, feature.Change{Flag: feature.Flag{Key: "beta", Enabled: true, Percentage: 100}})
+
+		assert.True(t, flags.IsEnabled(ctx, "beta"), "a flag, that could not be loaded, is not cached")
+	})
+
+	t.Run("cached until changed", func(t *testing.T) {
+		t.Parallel()
+
+		store := feature.NewMemoryStore()
+		flags := feature.New(alog.NewNoopLogger(), store, nil, userTarget)
+		other := feature.New(alog.NewNoopLogger(), store, nil, userTarget) // another instance without notifications
+
+		_ = flags.Save(ctx, feature.Flag{Key: "beta", Enabled: false})
+		assert.False(t, flags.IsEnabled(ctx, "beta"))
+
+		_ = other.Save(ctx, feature.Flag{Key: "beta", Enabled: true, Percentage: 100})
+		assert.False(t, flags.IsEnabled(ctx, "beta"), "the change was not notified")
+		assert.True(t, other.IsEnabled(ctx, "beta"), "the instance saving the flag drops its cache")
+	})
+}
+
+func TestFlags_Save(t *testing.T) {
+	t.Parallel()
+
+	t.Run("save and list", func(t *testing.T) {
+		t.Parallel()
+
+		flags := feature.New(alog.NewNoopLogger(), feature.NewMemoryStore(), nil, userTarget)
+
+		_ = flags.Save(ctx, feature.Flag{Key: "b", Enabled: true, Description: "second"})
+		_ = flags.Save(ctx, feature.Flag{Key: "a", Enabled: true, Percentage: 20})
+		_ = flags.Save(ctx, feature.Flag{Key: "b", Enabled: false, Description: "changed", UpdatedBy: "1"})
+
+		all, err := flags.All(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, all, 2)
+		assert.Equal(t, "a", all[0].Key)
+		assert.Equal(t, 20, all[0].Percentage)
+		assert.Equal(t, "changed", all[1].Description)
+		assert.False(t, all[1].UpdatedAt.IsZero())
+
+		flag, err := flags.Flag(ctx, "b")
+		assert.NoError(t, err)
+		assert.Equal(t, "1", flag.UpdatedBy)
+	})
+
+	t.Run("invalid flag", func(t *testing.T) {
+		t.Parallel()
+
+		flags := feature.New(alog.NewNoopLogger(), feature.NewMemoryStore(), nil, userTarget)
+
+		err := flags.Save(ctx, feature.Flag{Key: "a", Percentage: 200})
+		assert.ErrorIs(t, err, feature.ErrInvalidFlag)
+
+		_, err = flags.Flag(ctx, "a")
+		assert.ErrorIs(t, err, feature.ErrFlagNotFound)
+	})
+
+	t.Run("create existing flag", func(t *testing.T) {
+		t.Parallel()
+
+		flags := feature.New(alog.NewNoopLogger(), feature.NewMemoryStore(), nil, userTarget)
+
+		err := flags.Create(ctx, feature.Flag{Key: "a", Description: "first"})
+		assert.NoError(t, err)
+
+		err = flags.Create(ctx, feature.Flag{Key: "a", Description: "second"})
+		assert.ErrorIs(t, err, feature.ErrFlagExists)
+
+		flag, _ := flags.Flag(ctx, "a")
+		assert.Equal(t, "first", flag.Description)
+	})
+
+	t.Run("history", func(t *testing.T) {
+		t.Parallel()
+
+		flags := feature.New(alog.NewNoopLogger(), feature.NewMemoryStore(), nil, userTarget)
+
+		_ = flags.Save(ctx, feature.Flag{Key: "a", Enabled: false, UpdatedBy: "1"})
+		_ = flags.Save(ctx, feature.Flag{Key: "a", Enabled: true, UpdatedBy: "2"})
+
+		err := flags.Delete(ctx, "a", "3")
+		assert.NoError(t, err)
+
+		history, err := flags.History(ctx, "a")
+		assert.NoError(t, err)
+		assert.Len(t, history, 3)
+		assert.True(t, history[0].Deleted)
+		assert.Equal(t, "3", history[0].Flag.UpdatedBy)
+		assert.True(t, history[1].Flag.Enabled)
+		assert.False(t, history[2].Flag.Enabled)
+
+		all, _ := flags.All(ctx)
+		assert.Empty(t, all)
+	})
+
+	t.Run("delete unknown flag", func(t *testing.T) {
+		t.Parallel()
+
+		flags := feature.New(alog.NewNoopLogger(), feature.NewMemoryStore(), nil, userTarget)
+
+		err := flags.Delete(ctx, "unknown", "1")
+		assert.ErrorIs(t, err, feature.ErrFlagNotFound)
+	})
+}
+
+var errStore = errors.New("store failed")
+
+// failingStore fails to read the Flags, e.g. as the connection to the database is lost.
+type failingStore struct {
+	*feature.MemoryStore
+	fail bool
+}
+
+func (s *failingStore) Flag(ctx context.Context, key string) (feature.Flag, error) {
+	if s.fail {
+		return feature.Flag{Key: key}, errStore
+	}
+
+	return s.MemoryStore.Flag(ctx, key)
+}
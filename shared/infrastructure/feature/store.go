@@ -0,0 +1,267 @@
+package feature
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Store keeps the Flags and their history.
+type Store interface {
+	// Flag returns ErrFlagNotFound, if the Flag does not exist.
+	Flag(ctx context.Context, key string) (Flag, error)
+	// All returns all Flags ordered by their key.
+	All(ctx context.Context) ([]Flag, error)
+	// Save saves or deletes the Flag and adds the change to its history at once.
+	// Deleting a Flag, that does not exist, returns ErrFlagNotFound.
+	Save(ctx context.Context, change Change) error
+	// History returns the changes of the Flag, the latest first.
+	History(ctx context.Context, key string) ([]Change, error)
+}
+
+// NewMemoryStore returns a Store for a single instance, e.g. in tests.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		mu:      sync.Mutex{},
+		flags:   map[string]Flag{},
+		history: map[string][]Change{},
+	}
+}
+
+type MemoryStore struct {
+	mu      sync.Mutex
+	flags   map[string]Flag
+	history map[string][]Change
+}
+
+func (s *MemoryStore) Flag(_ context.Context, key string) (Flag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	flag, ok := s.flags[key]
+	if !ok {
+		return Flag{Key: key}, fmt.Errorf("%w: %s", ErrFlagNotFound, key) //nolint:exhaustruct // a missing flag is off
+	}
+
+	return flag, nil
+}
+
+func (s *MemoryStore) All(_ context.Context) ([]Flag, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	flags := make([]Flag, 0, len(s.flags))
+	for _, flag := range s.flags {
+		flags = append(flags, flag)
+	}
+
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Key < flags[j].Key })
+
+	return flags, nil
+}
+
+func (s *MemoryStore) Save(_ context.Context, change Change) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := change.Flag.Key
+
+	if change.Deleted {
+		if _, ok := s.flags[key]; !ok {
+			return fmt.Errorf("%w: %s", ErrFlagNotFound, key)
+		}
+
+		delete(s.flags, key)
+	} else {
+		s.flags[key] = change.Flag
+	}
+
+	history := append([]Change{change}, s.history[key]...)
+	s.history[key] = history[:min(len(history), maxHistory)]
+
+	return nil
+}
+
+func (s *MemoryStore) History(_ context.Context, key string) ([]Change, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.history[key]), nil
+}
+
+// NewPostgresStore returns a Store, that shares the Flags with all instances.
+func NewPostgresStore(pg *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{pg: pg}
+}
+
+type PostgresStore struct {
+	pg *pgxpool.Pool
+}
+
+func (s *PostgresStore) Flag(ctx context.Context, key string) (Flag, error) {
+	var b []byte
+
+	err := s.pg.QueryRow(ctx, `SELECT flag FROM arrower.feature_flags WHERE key = $1`, key).Scan(&b)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Flag{Key: key}, fmt.Errorf("%w: %s", ErrFlagNotFound, key) //nolint:exhaustruct // a missing flag is off
+	}
+
+	if err != nil {
+		return Flag{Key: key}, fmt.Errorf("could not get feature flag %s: %w", key, err) //nolint:exhaustruct,lll // a flag, that can not be loaded, is off
+	}
+
+	return unmarshalFlag(key, b)
+}
+
+func (s *PostgresStore) All(ctx context.Context) ([]Flag, error) {
+	rows, err := s.pg.Query(ctx, `SELECT key, flag FROM arrower.feature_flags ORDER BY key`)
+	if err != nil {
+		return nil, fmt.Errorf("could not get feature flags: %w", err)
+	}
+
+	defer rows.Close()
+
+	flags := []Flag{}
+
+	for rows.Next() {
+		var (
+			key string
+			b   []byte
+		)
+
+		if err := rows.Scan(&key, &b); err != nil {
+			return nil, fmt.Errorf("could not get feature flags: %w", err)
+		}
+
+		flag, err := unmarshalFlag(key, b)
+		if err != nil {
+			return nil, err
+		}
+
+		flags = append(flags, flag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not get feature flags: %w", err)
+	}
+
+	return flags, nil
+}
+
+func (s *PostgresStore) Save(ctx context.Context, change Change) error {
+	key := change.Flag.Key
+
+	flag, err := json.Marshal(change.Flag)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidFlag, err) //nolint:errorlint // prevent err in api
+	}
+
+	c, err := json.Marshal(change)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidFlag, err) //nolint:errorlint // prevent err in api
+	}
+
+	tx, err := s.pg.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx) //nolint:errcheck // a committed transaction can not be rolled back
+
+	if change.Deleted {
+		tag, err := tx.Exec(ctx, `DELETE FROM arrower.feature_flags WHERE key = $1`, key)
+		if err != nil {
+			return fmt.Errorf("could not delete feature flag: %w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("%w: %s", ErrFlagNotFound, key)
+		}
+	} else {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO arrower.feature_flags (key, flag, updated_at) VALUES ($1, $2, $3)
+			ON CONFLICT (key) DO UPDATE SET flag = EXCLUDED.flag, updated_at = EXCLUDED.updated_at`,
+			key, flag, change.Flag.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("could not save feature flag: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO arrower.feature_flag_history (key, change) VALUES ($1, $2)`, key, c)
+	if err != nil {
+		return fmt.Errorf("could not save feature flag history: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM arrower.feature_flag_history WHERE key = $1 AND id NOT IN (
+			SELECT id FROM arrower.feature_flag_history WHERE key = $1 ORDER BY id DESC LIMIT $2
+		)`,
+		key, maxHistory,
+	)
+	if err != nil {
+		return fmt.Errorf("could not trim feature flag history: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("could not commit feature flag: %w", err)
+	}
+
+	return nil
+}
+
+func (s *PostgresStore) History(ctx context.Context, key string) ([]Change, error) {
+	rows, err := s.pg.Query(ctx,
+		`SELECT change FROM arrower.feature_flag_history WHERE key = $1 ORDER BY id DESC LIMIT $2`,
+		key, maxHistory,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not get feature flag history: %w", err)
+	}
+
+	defer rows.Close()
+
+	history := []Change{}
+
+	for rows.Next() {
+		var b []byte
+		if err := rows.Scan(&b); err != nil {
+			return nil, fmt.Errorf("could not get feature flag history: %w", err)
+		}
+
+		var change Change
+		if err := json.Unmarshal(b, &change); err != nil {
+			return nil, fmt.Errorf("%w: could not read history of %s: %v", ErrInvalidFlag, key, err) //nolint:errorlint,lll // prevent err in api
+		}
+
+		history = append(history, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not get feature flag history: %w", err)
+	}
+
+	return history, nil
+}
+
+func unmarshalFlag(key string, b []byte) (Flag, error) {
+	var flag Flag
+	if err := json.Unmarshal(b, &flag); err != nil {
+		return Flag{Key: key}, fmt.Errorf("%w: could not read %s: %v", ErrInvalidFlag, key, err) //nolint:errorlint,exhaustruct,lll // prevent err in api
+	}
+
+	return flag, nil
+}
+
+// isNotFound returns true for a Flag, that does not exist.
+// All other errors, e.g. a lost connection, are no reason to treat a Flag as missing.
+func isNotFound(err error) bool {
+	return err != nil && errors.Is(err, ErrFlagNotFound)
+}
@@ -0,0 +1,99 @@
+//go:build integration
+
+package feature_test
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/go-arrower/arrower/tests"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/feature"
+	"github.com/go-arrower/skeleton/shared/infrastructure/migrations"
+)
+
+var pgHandler *tests.PostgresDocker
+
+func TestMain(m *testing.M) {
+	pgHandler = tests.GetPostgresDockerForIntegrationTestingInstance()
+
+	//
+	// Run tests
+	code := m.Run()
+
+	pgHandler.Cleanup()
+	os.Exit(code)
+}
+
+func TestPostgresStore_Save(t *testing.T) {
+	t.Parallel()
+
+	t.Run("save and delete", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		_ = migrations.Up(ctx, pg)
+		store := feature.NewPostgresStore(pg)
+
+		_, err := store.Flag(ctx, "a")
+		assert.ErrorIs(t, err, feature.ErrFlagNotFound)
+
+		_ = store.Save(ctx, feature.Change{Flag: feature.Flag{Key: "b", Enabled: true}})
+		_ = store.Save(ctx, feature.Change{Flag: feature.Flag{Key: "a", Percentage: 20}})
+		_ = store.Save(ctx, feature.Change{Flag: feature.Flag{Key: "a", Percentage: 30}})
+
+		flag, err := store.Flag(ctx, "a")
+		assert.NoError(t, err)
+		assert.Equal(t, 30, flag.Percentage)
+
+		all, err := store.All(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, all, 2)
+		assert.Equal(t, "a", all[0].Key)
+
+		err = store.Save(ctx, feature.Change{Flag: flag, Deleted: true})
+		assert.NoError(t, err)
+
+		err = store.Save(ctx, feature.Change{Flag: flag, Deleted: true})
+		assert.ErrorIs(t, err, feature.ErrFlagNotFound)
+
+		history, err := store.History(ctx, "a")
+		assert.NoError(t, err)
+		assert.Len(t, history, 3, "the history is kept after the flag is deleted")
+		assert.True(t, history[0].Deleted)
+		assert.Equal(t, 20, history[2].Flag.Percentage)
+	})
+
+	t.Run("concurrent changes", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		_ = migrations.Up(ctx, pg)
+		store := feature.NewPostgresStore(pg)
+
+		const changes = 60
+
+		wg := sync.WaitGroup{}
+
+		for i := range changes {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				_ = store.Save(ctx, feature.Change{Flag: feature.Flag{Key: "a", Percentage: i % 100}})
+				_ = store.Save(ctx, feature.Change{Flag: feature.Flag{Key: string(rune('b' + i%20))}})
+			}()
+		}
+
+		wg.Wait()
+
+		all, _ := store.All(ctx)
+		assert.Len(t, all, 21, "no flag is lost")
+
+		history, _ := store.History(ctx, "a")
+		assert.Len(t, history, 50, "the history is trimmed")
+	})
+}
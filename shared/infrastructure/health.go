@@ -0,0 +1,370 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+)
+
+var ErrUnhealthy = errors.New("unhealthy")
+
+// HealthStatus is the state of the application or of a single dependency.
+type HealthStatus string
+
+const (
+	HealthOK HealthStatus = "ok"
+	// HealthDegraded means an optional dependency fails, but the application can still serve requests.
+	HealthDegraded HealthStatus = "degraded"
+	// HealthDown means a critical dependency fails and the application can not serve requests.
+	HealthDown HealthStatus = "down"
+	// HealthMaintenance means the application is put into maintenance by an admin.
+	HealthMaintenance HealthStatus = "maintenance"
+)
+
+const (
+	defaultHealthCacheFor     = 5 * time.Second
+	defaultHealthCheckTimeout = 2 * time.Second
+)
+
+// HealthCheck returns an error, if the dependency it checks is not healthy.
+type HealthCheck func(ctx context.Context) error
+
+type HealthCheckOption func(*registeredCheck)
+
+// Optional marks a check, that does not prevent the application from serving requests.
+// If it fails, the application is degraded instead of down.
+func Optional() HealthCheckOption {
+	return func(c *registeredCheck) {
+		c.critical = false
+	}
+}
+
+// WithCheckTimeout sets how long the check may take, before it is considered failed.
+func WithCheckTimeout(timeout time.Duration) HealthCheckOption {
+	return func(c *registeredCheck) {
+		c.timeout = timeout
+	}
+}
+
+type HealthOption func(*Health)
+
+// WithCacheFor sets how long the results of the checks are reused,
+// so frequent probes from load balancers and orchestrators don't hammer the dependencies.
+func WithCacheFor(d time.Duration) HealthOption {
+	return func(h *Health) {
+		h.cacheFor = d
+	}
+}
+
+// NewHealth returns a registry for health checks. Each Context registers checks for its own dependencies.
+func NewHealth(opts ...HealthOption) *Health {
+	h := &Health{
+		mu:          sync.Mutex{},
+		checks:      []*registeredCheck{},
+		cacheFor:    defaultHealthCacheFor,
+		startedAt:   time.Now(),
+		maintenance: false,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+type Health struct {
+	mu          sync.Mutex
+	checks      []*registeredCheck
+	cacheFor    time.Duration
+	startedAt   time.Time
+	maintenance bool
+}
+
+type registeredCheck struct {
+	name     string
+	check    HealthCheck
+	critical bool
+	timeout  time.Duration
+
+	result CheckResult
+	// running is closed, when the running check is done. It is nil, if the check is not running.
+	running chan struct{}
+}
+
+type (
+	// HealthReport is the result of all checks.
+	HealthReport struct {
+		Status  HealthStatus           `json:"status"`
+		Time    time.Time              `json:"time"`
+		Uptime  string                 `json:"uptime"`
+		GitHash string                 `json:"gitHash"`
+		Checks  map[string]CheckResult `json:"checks"`
+		Runtime RuntimeStats           `json:"runtime"`
+	}
+
+	CheckResult struct {
+		Status    HealthStatus `json:"status"`
+		Critical  bool         `json:"critical"`
+		Latency   string       `json:"latency"`
+		CheckedAt time.Time    `json:"checkedAt"`
+		Error     string       `json:"error,omitempty"`
+	}
+
+	RuntimeStats struct {
+		GoVersion    string `json:"goVersion"`
+		Goroutines   int    `json:"goroutines"`
+		CPUs         int    `json:"cpus"`
+		HeapAlloc    uint64 `json:"heapAlloc"`
+		Sys          uint64 `json:"sys"`
+		NumGC        uint32 `json:"numGC"`
+		PauseTotalNs uint64 `json:"pauseTotalNs"`
+	}
+)
+
+// Register adds a check. By default, a check is critical and has to succeed within two seconds.
+func (h *Health) Register(name string, check HealthCheck, opts ...HealthCheckOption) {
+	c := &registeredCheck{
+		name:     name,
+		check:    check,
+		critical: true,
+		timeout:  defaultHealthCheckTimeout,
+		result:   CheckResult{}, //nolint:exhaustruct // not checked yet
+		running:  nil,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checks = append(h.checks, c)
+}
+
+// SetMaintenance puts the application into or out of maintenance.
+func (h *Health) SetMaintenance(maintenance bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.maintenance = maintenance
+}
+
+// Check runs all checks, whose results are older than the cache duration, and reports the overall status.
+// Concurrent calls wait for the running checks, instead of starting the checks again.
+// The checks run without holding the lock, so a slow check does not block Register or SetMaintenance.
+func (h *Health) Check(ctx context.Context) HealthReport {
+	now := time.Now()
+
+	h.mu.Lock()
+
+	var (
+		started []*registeredCheck
+		running []chan struct{}
+	)
+
+	for _, c := range h.checks {
+		switch {
+		case c.running != nil:
+			running = append(running, c.running)
+		case now.Sub(c.result.CheckedAt) >= h.cacheFor:
+			c.running = make(chan struct{})
+			started = append(started, c)
+		}
+	}
+
+	h.mu.Unlock()
+
+	results := make([]CheckResult, len(started))
+	wg := sync.WaitGroup{}
+
+	for i, c := range started {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			results[i] = c.run(ctx)
+		}()
+	}
+
+	wg.Wait()
+
+	h.mu.Lock()
+
+	for i, c := range started {
+		c.result = results[i]
+		close(c.running)
+		c.running = nil
+	}
+
+	h.mu.Unlock()
+
+	for _, done := range running {
+		select {
+		case <-done:
+		case <-ctx.Done():
+		}
+	}
+
+	return h.report(now)
+}
+
+// report returns the overall status from the latest results of the checks.
+func (h *Health) report(now time.Time) HealthReport {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	report := HealthReport{
+		Status:  HealthOK,
+		Time:    now,
+		Uptime:  now.Sub(h.startedAt).Round(time.Second).String(),
+		GitHash: gitHash(),
+		Checks:  make(map[string]CheckResult, len(h.checks)),
+		Runtime: runtimeStats(),
+	}
+
+	for _, c := range h.checks {
+		report.Checks[c.name] = c.result
+
+		switch {
+		case c.result.Status == HealthOK:
+		case c.critical:
+			report.Status = HealthDown
+		case report.Status == HealthOK:
+			report.Status = HealthDegraded
+		}
+	}
+
+	if h.maintenance && report.Status != HealthDown {
+		report.Status = HealthMaintenance
+	}
+
+	return report
+}
+
+func (c *registeredCheck) run(ctx context.Context) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.check(ctx)
+
+	res := CheckResult{
+		Status:    HealthOK,
+		Critical:  c.critical,
+		Latency:   time.Since(start).String(),
+		CheckedAt: time.Now(),
+		Error:     "",
+	}
+
+	if err != nil {
+		res.Status = HealthDown
+		res.Error = err.Error()
+	}
+
+	return res
+}
+
+// LivenessHandler serves /livez. It only reports that the process is running,
+// so an orchestrator does not restart the application because a dependency fails.
+func (h *Health) LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		writeHealth(w, http.StatusOK, map[string]any{"status": HealthOK})
+	}
+}
+
+// ReadinessHandler serves /readyz. It responds with 503, if the application can not serve requests.
+// In maintenance, the application is still ready, so it can serve the maintenance page.
+// As the probe is not protected, it only shows the overall status. The checks are shown by the status endpoint.
+func (h *Health) ReadinessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := h.Check(r.Context())
+
+		writeHealth(w, report.StatusCode(), map[string]any{"status": report.Status})
+	}
+}
+
+// StatusCode returns the HTTP status code the report is served with.
+func (r HealthReport) StatusCode() int {
+	if r.Status == HealthDown {
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusOK
+}
+
+func writeHealth(w http.ResponseWriter, code int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+func runtimeStats() RuntimeStats {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	return RuntimeStats{
+		GoVersion:    runtime.Version(),
+		Goroutines:   runtime.NumGoroutine(),
+		CPUs:         runtime.NumCPU(),
+		HeapAlloc:    mem.HeapAlloc,
+		Sys:          mem.Sys,
+		NumGC:        mem.NumGC,
+		PauseTotalNs: mem.PauseTotalNs,
+	}
+}
+
+// PingCheck checks that a connection can be established to the given dependency.
+func PingCheck(ping func(ctx context.Context) error) HealthCheck {
+	return func(ctx context.Context) error {
+		if err := ping(ctx); err != nil {
+			return fmt.Errorf("%w: %v", ErrUnhealthy, err) //nolint:errorlint // prevent err in api
+		}
+
+		return nil
+	}
+}
+
+// DialCheck checks that addr is reachable via TCP, e.g. for a trace exporter.
+func DialCheck(addr string) HealthCheck {
+	return func(ctx context.Context) error {
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr) //nolint:exhaustruct
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrUnhealthy, err) //nolint:errorlint // prevent err in api
+		}
+
+		return conn.Close() //nolint:wrapcheck // the dial was successful
+	}
+}
+
+// DiskSpaceCheck checks that the file at path exists and that its file system has at least minFree bytes left,
+// e.g. so a database file can be updated.
+func DiskSpaceCheck(path string, minFree uint64) HealthCheck {
+	return func(_ context.Context) error {
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("%w: %v", ErrUnhealthy, err) //nolint:errorlint // prevent err in api
+		}
+
+		free, err := freeDiskSpace(path)
+		if err != nil {
+			return fmt.Errorf("%w: could not get free disk space: %v", ErrUnhealthy, err) //nolint:errorlint // prevent err in api
+		}
+
+		if free < minFree {
+			return fmt.Errorf("%w: %d bytes free, want at least %d", ErrUnhealthy, free, minFree)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,199 @@
+package infrastructure_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure"
+)
+
+func TestHealth_Check(t *testing.T) {
+	t.Parallel()
+
+	ok := func(_ context.Context) error { return nil }
+	fail := func(_ context.Context) error { return errShutdown }
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		health := infrastructure.NewHealth()
+		health.Register("postgres", ok)
+
+		report := health.Check(context.Background())
+		assert.Equal(t, infrastructure.HealthOK, report.Status)
+		assert.Equal(t, infrastructure.HealthOK, report.Checks["postgres"].Status)
+		assert.NotEmpty(t, report.Runtime.GoVersion)
+		assert.Equal(t, http.StatusOK, report.StatusCode())
+	})
+
+	t.Run("optional check fails", func(t *testing.T) {
+		t.Parallel()
+
+		health := infrastructure.NewHealth()
+		health.Register("postgres", ok)
+		health.Register("traces", fail, infrastructure.Optional())
+
+		report := health.Check(context.Background())
+		assert.Equal(t, infrastructure.HealthDegraded, report.Status)
+		assert.Equal(t, errShutdown.Error(), report.Checks["traces"].Error)
+		assert.Equal(t, http.StatusOK, report.StatusCode())
+	})
+
+	t.Run("critical check fails", func(t *testing.T) {
+		t.Parallel()
+
+		health := infrastructure.NewHealth()
+		health.Register("postgres", fail)
+		health.Register("traces", fail, infrastructure.Optional())
+		health.SetMaintenance(true)
+
+		report := health.Check(context.Background())
+		assert.Equal(t, infrastructure.HealthDown, report.Status)
+		assert.Equal(t, http.StatusServiceUnavailable, report.StatusCode())
+	})
+
+	t.Run("check times out", func(t *testing.T) {
+		t.Parallel()
+
+		health := infrastructure.NewHealth()
+		health.Register("postgres", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, infrastructure.WithCheckTimeout(time.Millisecond))
+
+		report := health.Check(context.Background())
+		assert.Equal(t, infrastructure.HealthDown, report.Status)
+	})
+
+	t.Run("maintenance", func(t *testing.T) {
+		t.Parallel()
+
+		health := infrastructure.NewHealth()
+		health.Register("postgres", ok)
+		health.SetMaintenance(true)
+
+		report := health.Check(context.Background())
+		assert.Equal(t, infrastructure.HealthMaintenance, report.Status)
+		assert.Equal(t, http.StatusOK, report.StatusCode())
+	})
+
+	t.Run("cache results", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+
+		health := infrastructure.NewHealth(infrastructure.WithCacheFor(time.Hour))
+		health.Register("postgres", func(_ context.Context) error {
+			calls.Add(1)
+			return nil
+		})
+
+		health.Check(context.Background())
+		health.Check(context.Background())
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("not locked while checking", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+
+		block := make(chan struct{})
+
+		health := infrastructure.NewHealth(infrastructure.WithCacheFor(time.Hour))
+		health.Register("postgres", func(_ context.Context) error {
+			calls.Add(1)
+			<-block
+
+			return nil
+		})
+
+		reports := make(chan infrastructure.HealthReport, 2)
+
+		go func() { reports <- health.Check(context.Background()) }()
+
+		assert.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+
+		go func() { reports <- health.Check(context.Background()) }()
+
+		health.SetMaintenance(true) // does not wait for the running check
+		close(block)
+
+		assert.Equal(t, infrastructure.HealthMaintenance, (<-reports).Status)
+		assert.Equal(t, infrastructure.HealthMaintenance, (<-reports).Status)
+		assert.Equal(t, int32(1), calls.Load(), "concurrent calls wait for the running check")
+	})
+}
+
+func TestHealth_Handler(t *testing.T) {
+	t.Parallel()
+
+	health := infrastructure.NewHealth()
+	health.Register("postgres", func(_ context.Context) error { return errShutdown })
+
+	t.Run("livez", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		health.LivenessHandler()(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code, "a failing dependency does not make the process dead")
+	})
+
+	t.Run("readyz", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		health.ReadinessHandler()(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+
+		report := infrastructure.HealthReport{}
+		err := json.NewDecoder(rec.Body).Decode(&report)
+		assert.NoError(t, err)
+		assert.Equal(t, infrastructure.HealthDown, report.Status)
+		assert.Empty(t, report.Checks, "the details are only shown by the protected status endpoint")
+	})
+}
+
+func TestDialCheck(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	addr := listener.Addr().String()
+
+	err = infrastructure.DialCheck(addr)(context.Background())
+	assert.NoError(t, err)
+
+	_ = listener.Close()
+
+	err = infrastructure.DialCheck(addr)(context.Background())
+	assert.ErrorIs(t, err, infrastructure.ErrUnhealthy)
+}
+
+func TestDiskSpaceCheck(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "db.bin")
+	err := os.WriteFile(path, []byte("db"), 0o600)
+	assert.NoError(t, err)
+
+	err = infrastructure.DiskSpaceCheck(path, 1)(context.Background())
+	assert.NoError(t, err)
+
+	err = infrastructure.DiskSpaceCheck(path+".missing", 1)(context.Background())
+	assert.ErrorIs(t, err, infrastructure.ErrUnhealthy)
+}
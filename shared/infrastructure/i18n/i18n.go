@@ -0,0 +1,294 @@
+// Package i18n translates the messages of the application into the locale of a user.
+//
+// Messages are kept in catalogs, one JSON file per locale in the directory locales/ next to the views:
+//
+//	{
+//	  "nav.home": "Home",
+//	  "users.count": { "one": "%d user", "other": "%d users" }
+//	}
+//
+// A message with plural forms is selected by the first argument, see Catalog.Translate.
+// The forms are the CLDR plural categories: zero, one, two, few, many and other.
+package i18n
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-arrower/arrower"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+var ErrLoadCatalogFailed = errors.New("load catalog failed")
+
+// DefaultLocale is used, if no other locale is known or supported.
+var DefaultLocale = language.English //nolint:gochecknoglobals // language.Tag can not be a const
+
+const (
+	CtxLocale   arrower.CTXKey = "i18n.locale"
+	CtxTimeZone arrower.CTXKey = "i18n.time_zone"
+)
+
+// NewCatalog loads the messages from the locales directory of each file system, e.g. locales/de.json.
+// Messages of later file systems replace messages with the same key of earlier file systems.
+// A file system without locales results in an empty catalog, that returns the keys as messages.
+func NewCatalog(fileSystems ...fs.FS) (*Catalog, error) {
+	catalog := &Catalog{
+		messages: map[language.Tag]map[string]message{},
+		locales:  nil,
+		matcher:  nil,
+	}
+
+	for _, fsys := range fileSystems {
+		if fsys == nil {
+			continue
+		}
+
+		files, err := fs.Glob(fsys, "locales/*.json")
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrLoadCatalogFailed, err) //nolint:errorlint // prevent err in api
+		}
+
+		for _, file := range files {
+			err = catalog.load(fsys, file)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", ErrLoadCatalogFailed, file, err) //nolint:errorlint // prevent err in api
+			}
+		}
+	}
+
+	locales := []language.Tag{DefaultLocale}
+
+	for tag := range catalog.messages {
+		if tag != DefaultLocale {
+			locales = append(locales, tag)
+		}
+	}
+
+	sort.Slice(locales[1:], func(i, j int) bool { return locales[i+1].String() < locales[j+1].String() })
+
+	catalog.locales = locales
+	catalog.matcher = language.NewMatcher(locales)
+
+	return catalog, nil
+}
+
+// Catalog holds the messages of all locales.
+type Catalog struct {
+	messages map[language.Tag]map[string]message
+	locales  []language.Tag
+	matcher  language.Matcher
+}
+
+type message struct {
+	text   string
+	plural map[plural.Form]string
+}
+
+var pluralForms = map[string]plural.Form{ //nolint:gochecknoglobals // lookup table
+	"zero":  plural.Zero,
+	"one":   plural.One,
+	"two":   plural.Two,
+	"few":   plural.Few,
+	"many":  plural.Many,
+	"other": plural.Other,
+}
+
+func (m *message) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &m.text); err == nil {
+		return nil
+	}
+
+	var forms map[string]string
+	if err := json.Unmarshal(data, &forms); err != nil {
+		return fmt.Errorf("message is neither a text nor plural forms: %w", err)
+	}
+
+	m.plural = make(map[plural.Form]string, len(forms))
+
+	for name, text := range forms {
+		form, ok := pluralForms[name]
+		if !ok {
+			return fmt.Errorf("unknown plural form: %s", name) //nolint:goerr113 // wrapped by the caller
+		}
+
+		m.plural[form] = text
+	}
+
+	if _, ok := m.plural[plural.Other]; !ok {
+		return errors.New("plural form other is missing") //nolint:goerr113 // wrapped by the caller
+	}
+
+	return nil
+}
+
+func (c *Catalog) load(fsys fs.FS, file string) error {
+	tag, err := language.Parse(strings.TrimSuffix(path.Base(file), ".json"))
+	if err != nil {
+		return fmt.Errorf("file is not named after a locale: %w", err)
+	}
+
+	data, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return fmt.Errorf("could not read file: %w", err)
+	}
+
+	var messages map[string]message
+
+	err = json.Unmarshal(data, &messages)
+	if err != nil {
+		return fmt.Errorf("could not parse file: %w", err)
+	}
+
+	if c.messages[tag] == nil {
+		c.messages[tag] = map[string]message{}
+	}
+
+	for key, msg := range messages {
+		c.messages[tag][key] = msg
+	}
+
+	return nil
+}
+
+// Locales returns all locales with messages, the DefaultLocale comes first.
+func (c *Catalog) Locales() []language.Tag {
+	return c.locales
+}
+
+// Match returns the supported locale best matching the preferred ones.
+// If none matches, it returns the DefaultLocale.
+func (c *Catalog) Match(preferred ...language.Tag) language.Tag {
+	_, index, confidence := c.matcher.Match(preferred...)
+	if confidence == language.No {
+		return DefaultLocale
+	}
+
+	return c.locales[index]
+}
+
+// T translates the message into the locale of the context, see Locale.
+func (c *Catalog) T(ctx context.Context, key string, args ...any) string {
+	return c.Translate(Locale(ctx), key, args...)
+}
+
+// Translate returns the message in the given locale, formatted with args in the way of fmt.Sprintf.
+// If the message has plural forms, the first argument is the count that selects the form.
+// Missing messages fall back to the parent locale and then to the DefaultLocale. If none exists, the key is returned.
+func (c *Catalog) Translate(locale language.Tag, key string, args ...any) string {
+	msg, ok := c.Lookup(locale, key, args...)
+	if !ok {
+		return key
+	}
+
+	return msg
+}
+
+// Lookup is like Translate, but reports if the message exists.
+func (c *Catalog) Lookup(locale language.Tag, key string, args ...any) (string, bool) {
+	msg, tag, ok := c.find(locale, key)
+	if !ok {
+		return "", false
+	}
+
+	text := msg.text
+
+	if msg.plural != nil {
+		text = msg.plural[plural.Other]
+
+		if count, isCount := pluralCount(args); isCount {
+			if form, exists := msg.plural[plural.Cardinal.MatchPlural(tag, count, 0, 0, 0, 0)]; exists {
+				text = form
+			}
+		}
+	}
+
+	if len(args) == 0 || !strings.Contains(text, "%") {
+		return text, true
+	}
+
+	return fmt.Sprintf(text, args...), true
+}
+
+func (c *Catalog) find(locale language.Tag, key string) (message, language.Tag, bool) {
+	for tag := locale; ; tag = tag.Parent() {
+		if msg, ok := c.messages[tag][key]; ok {
+			return msg, tag, true
+		}
+
+		if tag.IsRoot() {
+			break
+		}
+	}
+
+	msg, ok := c.messages[DefaultLocale][key]
+
+	return msg, DefaultLocale, ok
+}
+
+func pluralCount(args []any) (int, bool) {
+	if len(args) == 0 {
+		return 0, false
+	}
+
+	switch count := args[0].(type) {
+	case int:
+		return count, true
+	case int8:
+		return int(count), true
+	case int16:
+		return int(count), true
+	case int32:
+		return int(count), true
+	case int64:
+		return int(count), true
+	case uint:
+		return int(count), true //nolint:gosec // counts of messages do not overflow
+	case uint8:
+		return int(count), true
+	case uint16:
+		return int(count), true
+	case uint32:
+		return int(count), true
+	case uint64:
+		return int(count), true //nolint:gosec // counts of messages do not overflow
+	}
+
+	return 0, false
+}
+
+// WithLocale returns a new context with the locale to translate messages into.
+func WithLocale(ctx context.Context, locale language.Tag) context.Context {
+	return context.WithValue(ctx, CtxLocale, locale)
+}
+
+// Locale returns the locale of the context, or the DefaultLocale if none is set.
+func Locale(ctx context.Context) language.Tag {
+	if locale, ok := ctx.Value(CtxLocale).(language.Tag); ok {
+		return locale
+	}
+
+	return DefaultLocale
+}
+
+// WithTimeZone returns a new context with the time zone to show times in.
+func WithTimeZone(ctx context.Context, loc *time.Location) context.Context {
+	return context.WithValue(ctx, CtxTimeZone, loc)
+}
+
+// TimeZone returns the time zone of the context, or time.Local if none is set.
+func TimeZone(ctx context.Context) *time.Location {
+	if loc, ok := ctx.Value(CtxTimeZone).(*time.Location); ok && loc != nil {
+		return loc
+	}
+
+	return time.Local
+}
@@ -0,0 +1,171 @@
+package i18n_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/i18n"
+)
+
+var sharedLocales = fstest.MapFS{
+	"locales/en.json": {Data: []byte(`{
+		"nav.home": "Home",
+		"nav.login": "Login",
+		"users.count": { "one": "%d user", "other": "%d users" }
+	}`)},
+	"locales/de.json": {Data: []byte(`{
+		"nav.home": "Startseite",
+		"users.count": { "one": "%d Nutzer", "other": "%d Nutzer insgesamt" }
+	}`)},
+}
+
+func TestNewCatalog(t *testing.T) {
+	t.Parallel()
+
+	t.Run("locales", func(t *testing.T) {
+		t.Parallel()
+
+		catalog, err := i18n.NewCatalog(sharedLocales)
+		assert.NoError(t, err)
+		assert.Equal(t, []language.Tag{language.English, language.German}, catalog.Locales())
+	})
+
+	t.Run("no locales", func(t *testing.T) {
+		t.Parallel()
+
+		catalog, err := i18n.NewCatalog(fstest.MapFS{}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []language.Tag{i18n.DefaultLocale}, catalog.Locales())
+		assert.Equal(t, "nav.home", catalog.Translate(language.German, "nav.home"))
+	})
+
+	t.Run("override messages", func(t *testing.T) {
+		t.Parallel()
+
+		catalog, err := i18n.NewCatalog(sharedLocales, fstest.MapFS{
+			"locales/de.json": {Data: []byte(`{"nav.home": "Übersicht"}`)},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "Übersicht", catalog.Translate(language.German, "nav.home"))
+		assert.Equal(t, "Home", catalog.Translate(language.English, "nav.home"))
+	})
+
+	t.Run("invalid files", func(t *testing.T) {
+		t.Parallel()
+
+		for name, file := range map[string]string{
+			"locales/xx-invalid-name.json": `{}`,
+			"locales/en.json":              `{"key": 1}`,
+		} {
+			_, err := i18n.NewCatalog(fstest.MapFS{name: {Data: []byte(file)}})
+			assert.ErrorIs(t, err, i18n.ErrLoadCatalogFailed, name)
+		}
+
+		_, err := i18n.NewCatalog(fstest.MapFS{"locales/en.json": {Data: []byte(`{"key": {"one": "x"}}`)}})
+		assert.ErrorIs(t, err, i18n.ErrLoadCatalogFailed, "missing plural form other")
+	})
+}
+
+func TestCatalog_Translate(t *testing.T) {
+	t.Parallel()
+
+	catalog, _ := i18n.NewCatalog(sharedLocales)
+
+	assert.Equal(t, "Startseite", catalog.Translate(language.German, "nav.home"))
+	assert.Equal(t, "Startseite", catalog.Translate(language.MustParse("de-AT"), "nav.home"), "parent locale")
+	assert.Equal(t, "Login", catalog.Translate(language.German, "nav.login"), "default locale")
+	assert.Equal(t, "non-existing", catalog.Translate(language.German, "non-existing"))
+
+	t.Run("plural", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, "1 user", catalog.Translate(language.English, "users.count", 1))
+		assert.Equal(t, "0 users", catalog.Translate(language.English, "users.count", 0))
+		assert.Equal(t, "2 users", catalog.Translate(language.English, "users.count", int64(2)))
+		assert.Equal(t, "1 Nutzer", catalog.Translate(language.German, "users.count", 1))
+		assert.Equal(t, "5 Nutzer insgesamt", catalog.Translate(language.German, "users.count", 5))
+	})
+}
+
+func TestCatalog_T(t *testing.T) {
+	t.Parallel()
+
+	catalog, _ := i18n.NewCatalog(sharedLocales)
+
+	assert.Equal(t, "Home", catalog.T(context.Background(), "nav.home"))
+	assert.Equal(t, "Startseite", catalog.T(i18n.WithLocale(context.Background(), language.German), "nav.home"))
+}
+
+func TestTimeZone(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, time.Local, i18n.TimeZone(context.Background()))
+
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	assert.Equal(t, loc, i18n.TimeZone(i18n.WithTimeZone(context.Background(), loc)))
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	catalog, _ := i18n.NewCatalog(sharedLocales)
+
+	tests := map[string]struct {
+		url      string
+		header   string
+		cookie   string
+		expected language.Tag
+	}{
+		"default":          {"/", "", "", language.English},
+		"accept language":  {"/", "fr-FR, de-DE;q=0.8", "", language.German},
+		"unsupported":      {"/", "fr-FR", "", language.English},
+		"cookie":           {"/", "en", "de", language.German},
+		"query param":      {"/?lang=de", "en", "en", language.German},
+		"invalid language": {"/?lang=!", "", "", language.English},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			req.Header.Set("Accept-Language", tt.header)
+
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: i18n.CookieName, Value: tt.cookie})
+			}
+
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(req, rec)
+
+			var locale language.Tag
+
+			err := i18n.Middleware(catalog)(func(c echo.Context) error {
+				locale = i18n.Locale(c.Request().Context())
+
+				return nil
+			})(c)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, locale)
+		})
+	}
+
+	t.Run("remember chosen locale", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/?lang=de", nil), rec)
+
+		_ = i18n.Middleware(catalog)(func(c echo.Context) error { return nil })(c)
+
+		assert.Contains(t, rec.Header().Get("Set-Cookie"), i18n.CookieName+"=de")
+	})
+}
@@ -0,0 +1,69 @@
+package i18n
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/text/language"
+)
+
+const (
+	// CookieName is the cookie remembering the locale chosen with the QueryParam.
+	CookieName = "arrower.locale"
+	// QueryParam lets a user choose a locale, e.g. /?lang=de.
+	QueryParam = "lang"
+
+	cookieMaxAge = 60 * 60 * 24 * 365 // one year
+)
+
+// Middleware resolves the locale of a request and puts it into the request's context, see Locale.
+// The locale is taken from the QueryParam, the cookie, or the Accept-Language header, in that order.
+// A chosen locale is remembered in a cookie.
+//
+// The locale and time zone of a logged-in user take precedence. They are set by the auth Context,
+// so its middleware has to run after this one.
+func Middleware(catalog *Catalog) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			locale := resolveLocale(c, catalog)
+
+			c.SetRequest(c.Request().WithContext(WithLocale(c.Request().Context(), locale)))
+
+			return next(c)
+		}
+	}
+}
+
+func resolveLocale(c echo.Context, catalog *Catalog) language.Tag {
+	if lang := c.QueryParam(QueryParam); lang != "" {
+		if tag, err := language.Parse(lang); err == nil {
+			locale := catalog.Match(tag)
+
+			c.SetCookie(&http.Cookie{ //nolint:exhaustruct // the other values are the defaults of the browser
+				Name:     CookieName,
+				Value:    locale.String(),
+				Path:     "/",
+				MaxAge:   cookieMaxAge,
+				Expires:  time.Now().Add(cookieMaxAge * time.Second),
+				HttpOnly: true,
+				SameSite: http.SameSiteLaxMode,
+			})
+
+			return locale
+		}
+	}
+
+	if cookie, err := c.Cookie(CookieName); err == nil {
+		if tag, err := language.Parse(cookie.Value); err == nil {
+			return catalog.Match(tag)
+		}
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(c.Request().Header.Get("Accept-Language"))
+	if err != nil || len(tags) == 0 {
+		return DefaultLocale
+	}
+
+	return catalog.Match(tags...)
+}
@@ -0,0 +1,95 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/locales/de"
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	entranslations "github.com/go-playground/validator/v10/translations/en"
+)
+
+var ErrCreateValidatorFailed = errors.New("create validator failed")
+
+// germanValidationMessages are the messages for the validation tags used by the application,
+// as the validator has no German translations of its own.
+var germanValidationMessages = map[string]string{ //nolint:gochecknoglobals // lookup table
+	"required": "{0} ist ein Pflichtfeld",
+	"email":    "{0} muss eine gültige E-Mail-Adresse sein",
+	"min":      "{0} muss mindestens {1} Zeichen lang sein",
+	"max":      "{0} darf maximal {1} Zeichen lang sein",
+	"eqfield":  "{0} muss gleich {1} sein",
+	"boolean":  "{0} muss ein boolescher Wert sein",
+	"ip":       "{0} muss eine gültige IP-Adresse sein",
+}
+
+// NewValidator returns a validator, that translates its errors into the locales of the application.
+func NewValidator() (*Validator, error) {
+	validate := validator.New()
+	translator := ut.New(en.New(), en.New(), de.New())
+
+	enTrans, _ := translator.GetTranslator("en")
+
+	err := entranslations.RegisterDefaultTranslations(validate, enTrans)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCreateValidatorFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	deTrans, _ := translator.GetTranslator("de")
+
+	for tag, text := range germanValidationMessages {
+		err = validate.RegisterTranslation(tag, deTrans, func(trans ut.Translator) error {
+			return trans.Add(tag, text, true) //nolint:wrapcheck // wrapped below
+		}, translateField)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrCreateValidatorFailed, err) //nolint:errorlint // prevent err in api
+		}
+	}
+
+	return &Validator{Validate: validate, translator: translator}, nil
+}
+
+// Validator validates structs and translates the errors into the locale of a request.
+type Validator struct {
+	*validator.Validate
+
+	translator *ut.UniversalTranslator
+}
+
+// Translate returns the message of the error in the locale of the context, see Locale.
+func (v *Validator) Translate(ctx context.Context, err validator.FieldError) string {
+	base, _ := Locale(ctx).Base()
+
+	trans, _ := v.translator.FindTranslator(base.String())
+
+	return err.Translate(trans)
+}
+
+// Errors returns the translated messages of all invalid fields by the name of their struct field.
+// Errors other than validation errors return nil.
+func (v *Validator) Errors(ctx context.Context, err error) map[string]string {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return nil
+	}
+
+	messages := make(map[string]string, len(validationErrors))
+
+	for _, e := range validationErrors {
+		messages[e.StructField()] = v.Translate(ctx, e)
+	}
+
+	return messages
+}
+
+func translateField(trans ut.Translator, err validator.FieldError) string {
+	msg, tErr := trans.T(err.Tag(), err.Field(), err.Param())
+	if tErr != nil {
+		return err.Error()
+	}
+
+	return msg
+}
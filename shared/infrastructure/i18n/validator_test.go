@@ -0,0 +1,48 @@
+package i18n_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/language"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/i18n"
+)
+
+func TestValidator_Errors(t *testing.T) {
+	t.Parallel()
+
+	validate, err := i18n.NewValidator()
+	assert.NoError(t, err)
+
+	type form struct {
+		Email    string `validate:"required,email"`
+		Password string `validate:"min=8"`
+	}
+
+	invalid := validate.Struct(form{Email: "", Password: "short"})
+
+	t.Run("english", func(t *testing.T) {
+		t.Parallel()
+
+		messages := validate.Errors(context.Background(), invalid)
+		assert.Equal(t, "Email is a required field", messages["Email"])
+		assert.Equal(t, "Password must be at least 8 characters in length", messages["Password"])
+	})
+
+	t.Run("german", func(t *testing.T) {
+		t.Parallel()
+
+		messages := validate.Errors(i18n.WithLocale(context.Background(), language.MustParse("de-DE")), invalid)
+		assert.Equal(t, "Email ist ein Pflichtfeld", messages["Email"])
+		assert.Equal(t, "Password muss mindestens 8 Zeichen lang sein", messages["Password"])
+	})
+
+	t.Run("other errors", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Nil(t, validate.Errors(context.Background(), errors.New("some error")))
+	})
+}
@@ -0,0 +1,470 @@
+// Package jobqueue lets operators control the job queues of all instances at runtime.
+//
+// The controls are stored as settings, so they are shared by all instances.
+// Each instance wraps its jobs.Queue in a ControlledQueue, that watches the settings and
+// pauses the queue, pauses single job types, or changes the number of workers.
+//
+// A paused job is not executed. Its worker returns ErrPaused instead. With WithPostgres, the ControlledQueue
+// records its pauses in the database, which parks the jobs failing while paused without counting
+// the attempt, see migration 000003. Once the queue or job type is resumed, the parked jobs are released.
+//
+// All queues are paused as well, while the maintenance of the application pauses jobs, see maintenance.
+package jobqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/go-arrower/arrower/jobs"
+	"github.com/go-arrower/arrower/setting"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/maintenance"
+)
+
+var (
+	// ErrPaused is returned by a job, that is not executed, because its queue or job type is paused.
+	ErrPaused = errors.New("job paused by admin")
+
+	ErrInvalidJobFunc = errors.New("invalid job func")
+)
+
+const (
+	settingsContext = "jobs"
+	defaultQueue    = "Default"
+	defaultInterval = 5 * time.Second
+)
+
+// SettingPaused is the key to pause all jobs of a queue.
+func SettingPaused(queue string) setting.Key {
+	return setting.NewKey(settingsContext, "queue."+queueName(queue), "paused")
+}
+
+// SettingJobTypePaused is the key to pause the jobs of one job type in a queue.
+func SettingJobTypePaused(queue string, jobType string) setting.Key {
+	return setting.NewKey(settingsContext, "queue."+queueName(queue), "paused."+jobType)
+}
+
+// SettingWorkers is the key for the number of workers per instance of a queue.
+// Zero means the default of the jobs.Queue implementation.
+func SettingWorkers(queue string) setting.Key {
+	return setting.NewKey(settingsContext, "queue."+queueName(queue), "workers")
+}
+
+// Factory returns a new jobs.Queue with the given number of workers.
+// Zero workers means the default of the implementation.
+type Factory func(workers int) (jobs.Queue, error)
+
+type Option func(*ControlledQueue)
+
+// WithInterval sets how often the settings are checked for changes.
+func WithInterval(interval time.Duration) Option {
+	return func(q *ControlledQueue) {
+		q.interval = interval
+	}
+}
+
+// WithPostgres keeps the pauses of the queue in the database, so the jobs returning ErrPaused
+// are parked until they are resumed. Without it, the queue retries them like failed jobs.
+func WithPostgres(pg *pgxpool.Pool) Option {
+	return func(q *ControlledQueue) {
+		q.pg = pg
+	}
+}
+
+// EnqueueOnly does not register the job funcs with the queue, so this instance enqueues jobs
+// without working on them, e.g. an instance that only serves the web. Without job funcs,
+// the queue starts no workers and registers no worker pool.
+func EnqueueOnly() Option {
+	return func(q *ControlledQueue) {
+		q.enqueueOnly = true
+	}
+}
+
+// NewControlledQueue returns a jobs.Queue for the queue with the given name,
+// that follows the controls set in the settings.
+func NewControlledQueue(
+	ctx context.Context,
+	logger alog.Logger,
+	settings setting.Settings,
+	queue string,
+	factory Factory,
+	opts ...Option,
+) (*ControlledQueue, error) {
+	cq := &ControlledQueue{
+		logger:      logger.With(slog.String("queue", queueName(queue))),
+		settings:    settings,
+		factory:     factory,
+		name:        queue,
+		interval:    defaultInterval,
+		jobTypes:    map[string]bool{},
+		pausedTypes: map[string]bool{},
+		done:        make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(cq)
+	}
+
+	cq.workers = cq.intSetting(ctx, SettingWorkers(queue), 0)
+
+	q, err := factory(cq.workers)
+	if err != nil {
+		return nil, fmt.Errorf("could not create queue: %w", err)
+	}
+
+	cq.queue = q
+
+	return cq, nil
+}
+
+// ControlledQueue is a jobs.Queue, that can be paused and scaled at runtime.
+type ControlledQueue struct {
+	logger   alog.Logger
+	settings setting.Settings
+	factory  Factory
+	pg       *pgxpool.Pool
+
+	queue    jobs.Queue
+	jobFuncs []any
+
+	// jobTypes are the types of all registered job funcs.
+	jobTypes    map[string]bool
+	pausedTypes map[string]bool
+
+	done chan struct{}
+
+	name     string
+	interval time.Duration
+	workers  int
+
+	mu          sync.RWMutex
+	wg          sync.WaitGroup
+	paused      bool
+	enqueueOnly bool
+}
+
+var _ jobs.Queue = (*ControlledQueue)(nil)
+
+func (q *ControlledQueue) Enqueue(ctx context.Context, job jobs.Job, opts ...jobs.JobOpt) error {
+	return q.current().Enqueue(ctx, job, opts...) //nolint:wrapcheck // this is a decorator
+}
+
+// RegisterJobFunc registers the jobFunc, so that it does not run while its queue or job type is paused.
+func (q *ControlledQueue) RegisterJobFunc(jobFunc any) error {
+	fn := reflect.ValueOf(jobFunc)
+
+	fnType := fn.Type()
+	if fnType.Kind() != reflect.Func || fnType.NumIn() != 2 || fnType.NumOut() != 1 {
+		return fmt.Errorf("%w: expected func(context.Context, Job) error, got: %s", ErrInvalidJobFunc, fnType)
+	}
+
+	jobType := jobTypeOf(fnType.In(1))
+	errPaused := reflect.ValueOf(&ErrPaused).Elem()
+
+	wrapped := reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		if q.isPaused(jobType) {
+			return []reflect.Value{errPaused}
+		}
+
+		return fn.Call(args)
+	}).Interface()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.enqueueOnly {
+		return nil
+	}
+
+	q.jobFuncs = append(q.jobFuncs, wrapped)
+	q.jobTypes[jobType] = true
+
+	return q.queue.RegisterJobFunc(wrapped) //nolint:wrapcheck // this is a decorator
+}
+
+// Start watches the settings for changes, until Shutdown is called.
+func (q *ControlledQueue) Start(ctx context.Context) {
+	q.wg.Add(1)
+
+	go func() {
+		defer q.wg.Done()
+
+		ticker := time.NewTicker(q.interval)
+		defer ticker.Stop()
+
+		for {
+			q.refresh(ctx)
+
+			select {
+			case <-q.done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (q *ControlledQueue) Shutdown(ctx context.Context) error {
+	select {
+	case <-q.done:
+	default:
+		close(q.done)
+	}
+
+	q.wg.Wait()
+
+	return q.current().Shutdown(ctx) //nolint:wrapcheck // this is a decorator
+}
+
+func (q *ControlledQueue) current() jobs.Queue {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	return q.queue
+}
+
+func (q *ControlledQueue) isPaused(jobType string) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	return q.paused || q.pausedTypes[jobType]
+}
+
+// refresh loads the controls from the settings and applies them.
+func (q *ControlledQueue) refresh(ctx context.Context) {
+	q.mu.RLock()
+	current := q.paused
+	currentWorkers := q.workers
+	currentTypes := q.pausedTypes
+	jobTypes := make([]string, 0, len(q.jobTypes))
+
+	for jt := range q.jobTypes {
+		jobTypes = append(jobTypes, jt)
+	}
+	q.mu.RUnlock()
+
+	paused := q.boolSetting(ctx, SettingPaused(q.name), current) || maintenance.PausesJobs(ctx, q.settings)
+	workers := q.intSetting(ctx, SettingWorkers(q.name), currentWorkers)
+
+	pausedTypes := make(map[string]bool, len(jobTypes))
+
+	for _, jt := range jobTypes {
+		if q.boolSetting(ctx, SettingJobTypePaused(q.name, jt), currentTypes[jt]) {
+			pausedTypes[jt] = true
+		}
+	}
+
+	// record the pauses before the jobs return ErrPaused and release them after they stopped doing so
+	q.savePauses(ctx, jobTypes, paused, pausedTypes)
+
+	q.mu.Lock()
+	if paused != q.paused {
+		q.logger.LogAttrs(ctx, alog.LevelInfo, "queue paused changed", slog.Bool("paused", paused))
+	}
+
+	q.paused = paused
+	q.pausedTypes = pausedTypes
+	q.mu.Unlock()
+
+	q.releasePauses(ctx, jobTypes, paused, pausedTypes)
+
+	if workers != q.workers {
+		q.scale(ctx, workers)
+	}
+}
+
+// savePauses records the pauses of the queue, so the database parks the jobs returning ErrPaused.
+// An empty job type pauses the whole queue.
+func (q *ControlledQueue) savePauses(ctx context.Context, jobTypes []string, paused bool, pausedTypes map[string]bool) {
+	if q.pg == nil {
+		return
+	}
+
+	_, keys := pauseKeys(jobTypes, paused, pausedTypes)
+
+	_, err := q.pg.Exec(ctx, `
+		INSERT INTO arrower.job_pauses (queue, job_type)
+		SELECT $1, UNNEST($2::TEXT[])
+		ON CONFLICT (queue, job_type) DO NOTHING`,
+		q.name, keys,
+	)
+	if err != nil {
+		q.logger.LogAttrs(ctx, slog.LevelWarn, "could not save job pauses", slog.String("err", err.Error()))
+	}
+}
+
+// releasePauses removes the pauses of this queue, that are resumed, and releases the jobs parked by them.
+// Parked jobs, that are still paused by the queue or their job type, stay parked.
+// Only the job types known to this instance are touched, as other instances can register other job types.
+func (q *ControlledQueue) releasePauses(ctx context.Context, jobTypes []string, paused bool, pausedTypes map[string]bool) {
+	if q.pg == nil {
+		return
+	}
+
+	known, keys := pauseKeys(jobTypes, paused, pausedTypes)
+
+	_, err := q.pg.Exec(ctx, `
+		WITH resumed AS (
+			DELETE FROM arrower.job_pauses
+			WHERE queue = $1 AND job_type = ANY($2::TEXT[]) AND NOT job_type = ANY($3::TEXT[])
+			RETURNING job_type
+		), released AS (
+			DELETE FROM arrower.paused_jobs p
+			WHERE p.queue = $1
+				AND EXISTS (SELECT FROM resumed r WHERE r.job_type IN ('', p.job_type))
+				AND NOT p.job_type = ANY($3::TEXT[]) AND NOT '' = ANY($3::TEXT[])
+			RETURNING job_id
+		)
+		UPDATE arrower.gue_jobs SET run_at = NOW(), updated_at = NOW()
+		WHERE job_id IN (SELECT job_id FROM released)`,
+		q.name, known, keys,
+	)
+	if err != nil {
+		q.logger.LogAttrs(ctx, slog.LevelWarn, "could not release paused jobs", slog.String("err", err.Error()))
+	}
+}
+
+// pauseKeys returns all job types this instance knows and the ones, that are paused.
+// The empty job type stands for the whole queue.
+func pauseKeys(jobTypes []string, paused bool, pausedTypes map[string]bool) ([]string, []string) {
+	known := append([]string{""}, jobTypes...)
+	keys := []string{}
+
+	if paused {
+		keys = append(keys, "")
+	}
+
+	for _, jt := range jobTypes {
+		if pausedTypes[jt] {
+			keys = append(keys, jt)
+		}
+	}
+
+	return known, keys
+}
+
+// scale replaces the queue with a new one, that has the given number of workers.
+// The jobs running on the old queue finish, before it shuts down.
+func (q *ControlledQueue) scale(ctx context.Context, workers int) {
+	newQueue, err := q.factory(workers)
+	if err != nil {
+		q.logger.LogAttrs(ctx, alog.LevelInfo, "could not scale queue", slog.String("err", err.Error()))
+
+		return
+	}
+
+	q.mu.Lock()
+
+	for _, fn := range q.jobFuncs {
+		if err := newQueue.RegisterJobFunc(fn); err != nil {
+			q.mu.Unlock()
+			_ = newQueue.Shutdown(ctx)
+
+			q.logger.LogAttrs(ctx, alog.LevelInfo, "could not scale queue", slog.String("err", err.Error()))
+
+			return
+		}
+	}
+
+	oldQueue := q.queue
+	q.queue = newQueue
+	q.workers = workers
+
+	q.mu.Unlock()
+
+	q.logger.LogAttrs(ctx, alog.LevelInfo, "queue scaled", slog.Int("workers", workers))
+
+	_ = oldQueue.Shutdown(ctx)
+}
+
+// boolSetting returns the setting, or false if it is not set.
+// If the setting can not be loaded or is not a bool, the error is logged and the current value is kept.
+func (q *ControlledQueue) boolSetting(ctx context.Context, key setting.Key, current bool) bool {
+	val, err := q.settings.Setting(ctx, key)
+	if errors.Is(err, setting.ErrNotFound) {
+		return false
+	}
+
+	if err != nil {
+		q.logger.LogAttrs(ctx, slog.LevelWarn, "could not load setting, keep current value",
+			slog.String("key", string(key)),
+			slog.String("err", err.Error()),
+		)
+
+		return current
+	}
+
+	b, err := strconv.ParseBool(val.String())
+	if err != nil {
+		q.logger.LogAttrs(ctx, slog.LevelWarn, "invalid setting, keep current value",
+			slog.String("key", string(key)),
+			slog.String("err", err.Error()),
+		)
+
+		return current
+	}
+
+	return b
+}
+
+// intSetting returns the setting, or 0 if it is not set.
+// If the setting can not be loaded or is not an int, the error is logged and the current value is kept.
+func (q *ControlledQueue) intSetting(ctx context.Context, key setting.Key, current int) int {
+	val, err := q.settings.Setting(ctx, key)
+	if errors.Is(err, setting.ErrNotFound) {
+		return 0
+	}
+
+	if err != nil {
+		q.logger.LogAttrs(ctx, slog.LevelWarn, "could not load setting, keep current value",
+			slog.String("key", string(key)),
+			slog.String("err", err.Error()),
+		)
+
+		return current
+	}
+
+	i, err := strconv.Atoi(val.String())
+	if err != nil {
+		q.logger.LogAttrs(ctx, slog.LevelWarn, "invalid setting, keep current value",
+			slog.String("key", string(key)),
+			slog.String("err", err.Error()),
+		)
+
+		return current
+	}
+
+	return i
+}
+
+// jobTypeOf returns the job type the same way the arrower queue does:
+// by the JobType method, if the job implements it, or by the name of its type.
+func jobTypeOf(t reflect.Type) string {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if jt, ok := reflect.New(t).Elem().Interface().(interface{ JobType() string }); ok {
+		return jt.JobType()
+	}
+
+	return t.Name()
+}
+
+func queueName(queue string) string {
+	if queue == "" {
+		return defaultQueue
+	}
+
+	return queue
+}
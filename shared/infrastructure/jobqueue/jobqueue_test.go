@@ -0,0 +1,347 @@
+package jobqueue_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/go-arrower/arrower/jobs"
+	"github.com/go-arrower/arrower/setting"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/jobqueue"
+	"github.com/go-arrower/skeleton/shared/infrastructure/maintenance"
+)
+
+var ctx = context.Background()
+
+func TestControlledQueue_RegisterJobFunc(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid job func", func(t *testing.T) {
+		t.Parallel()
+
+		q, _ := jobqueue.NewControlledQueue(ctx, alog.NewNoopLogger(), setting.NewInMemorySettings(), "", newFakeQueueFactory().New)
+
+		err := q.RegisterJobFunc(func() {})
+		assert.ErrorIs(t, err, jobqueue.ErrInvalidJobFunc)
+	})
+
+	t.Run("pause queue", func(t *testing.T) {
+		t.Parallel()
+
+		settings := setting.NewInMemorySettings()
+		factory := newFakeQueueFactory()
+		q, _ := jobqueue.NewControlledQueue(ctx, alog.NewNoopLogger(), settings, "", factory.New,
+			jobqueue.WithInterval(time.Millisecond),
+		)
+		q.Start(ctx)
+		defer q.Shutdown(ctx)
+
+		_ = q.RegisterJobFunc(func(context.Context, someJob) error { return nil })
+		assert.NoError(t, factory.Last().run(someJob{}))
+
+		_ = settings.Save(ctx, jobqueue.SettingPaused("Default"), setting.NewValue(true))
+
+		assert.Eventually(t, func() bool {
+			return factory.Last().run(someJob{}) == jobqueue.ErrPaused
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("pause job type", func(t *testing.T) {
+		t.Parallel()
+
+		settings := setting.NewInMemorySettings()
+		factory := newFakeQueueFactory()
+		q, _ := jobqueue.NewControlledQueue(ctx, alog.NewNoopLogger(), settings, "Other", factory.New,
+			jobqueue.WithInterval(time.Millisecond),
+		)
+		q.Start(ctx)
+		defer q.Shutdown(ctx)
+
+		_ = q.RegisterJobFunc(func(context.Context, someJob) error { return nil })
+		_ = q.RegisterJobFunc(func(context.Context, otherJob) error { return nil })
+
+		_ = settings.Save(ctx, jobqueue.SettingJobTypePaused("Other", "custom-type"), setting.NewValue(true))
+
+		assert.Eventually(t, func() bool {
+			return factory.Last().run(otherJob{}) == jobqueue.ErrPaused
+		}, time.Second, time.Millisecond)
+		assert.NoError(t, factory.Last().run(someJob{}), "other job types continue to run")
+	})
+
+	t.Run("pause during maintenance", func(t *testing.T) {
+		t.Parallel()
+
+		settings := setting.NewInMemorySettings()
+		factory := newFakeQueueFactory()
+		q, _ := jobqueue.NewControlledQueue(ctx, alog.NewNoopLogger(), settings, "", factory.New,
+			jobqueue.WithInterval(time.Millisecond),
+		)
+		q.Start(ctx)
+		defer q.Shutdown(ctx)
+
+		_ = q.RegisterJobFunc(func(context.Context, someJob) error { return nil })
+
+		_ = maintenance.Save(ctx, settings, maintenance.Mode{Enabled: true, PauseJobs: true})
+
+		assert.Eventually(t, func() bool {
+			return factory.Last().run(someJob{}) == jobqueue.ErrPaused
+		}, time.Second, time.Millisecond)
+
+		_ = maintenance.Save(ctx, settings, maintenance.Mode{Enabled: false, PauseJobs: true})
+
+		assert.Eventually(t, func() bool {
+			return factory.Last().run(someJob{}) == nil
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("enqueue only", func(t *testing.T) {
+		t.Parallel()
+
+		factory := newFakeQueueFactory()
+		q, _ := jobqueue.NewControlledQueue(ctx, alog.NewNoopLogger(), setting.NewInMemorySettings(), "", factory.New,
+			jobqueue.EnqueueOnly(),
+		)
+
+		err := q.RegisterJobFunc(func(context.Context, someJob) error { return nil })
+		assert.NoError(t, err)
+		assert.Equal(t, 0, factory.Last().registered(), "no workers are started")
+
+		_ = q.Enqueue(ctx, someJob{})
+		assert.Equal(t, 1, factory.Last().enqueued())
+	})
+}
+
+func TestControlledQueue_Scale(t *testing.T) {
+	t.Parallel()
+
+	settings := setting.NewInMemorySettings()
+	_ = settings.Save(ctx, jobqueue.SettingWorkers("Default"), setting.NewValue(2))
+
+	factory := newFakeQueueFactory()
+	q, _ := jobqueue.NewControlledQueue(ctx, alog.NewNoopLogger(), settings, "", factory.New,
+		jobqueue.WithInterval(time.Millisecond),
+	)
+	assert.Equal(t, 2, factory.Last().workers)
+
+	_ = q.RegisterJobFunc(func(context.Context, someJob) error { return nil })
+
+	q.Start(ctx)
+	defer q.Shutdown(ctx)
+
+	_ = settings.Save(ctx, jobqueue.SettingWorkers("Default"), setting.NewValue(8))
+
+	assert.Eventually(t, func() bool {
+		return factory.Last().workers == 8
+	}, time.Second, time.Millisecond)
+	assert.NoError(t, factory.Last().run(someJob{}), "job funcs are registered on the new queue")
+	assert.True(t, factory.First().isShutdown())
+
+	_ = q.Enqueue(ctx, someJob{})
+	assert.Equal(t, 1, factory.Last().enqueued())
+}
+
+func TestControlledQueue_InvalidSetting(t *testing.T) {
+	t.Parallel()
+
+	settings := setting.NewInMemorySettings()
+	_ = settings.Save(ctx, jobqueue.SettingWorkers("Default"), setting.NewValue(2))
+	_ = settings.Save(ctx, jobqueue.SettingPaused("Default"), setting.NewValue(true))
+
+	factory := newFakeQueueFactory()
+	q, _ := jobqueue.NewControlledQueue(ctx, alog.NewNoopLogger(), settings, "", factory.New,
+		jobqueue.WithInterval(time.Millisecond),
+	)
+	_ = q.RegisterJobFunc(func(context.Context, someJob) error { return nil })
+
+	q.Start(ctx)
+	defer q.Shutdown(ctx)
+
+	assert.Eventually(t, func() bool {
+		return factory.Last().run(someJob{}) == jobqueue.ErrPaused
+	}, time.Second, time.Millisecond)
+
+	_ = settings.Save(ctx, jobqueue.SettingWorkers("Default"), setting.NewValue("many"))
+	_ = settings.Save(ctx, jobqueue.SettingPaused("Default"), setting.NewValue("not a bool"))
+
+	assert.Never(t, func() bool {
+		return factory.Last().workers != 2 || factory.Last().run(someJob{}) != jobqueue.ErrPaused
+	}, 50*time.Millisecond, time.Millisecond, "the current values are kept")
+}
+
+func TestControlledQueue_SettingsUnavailable(t *testing.T) {
+	t.Parallel()
+
+	settings := &failingSettings{Settings: setting.NewInMemorySettings()}
+	_ = settings.Save(ctx, jobqueue.SettingWorkers("Default"), setting.NewValue(2))
+	_ = settings.Save(ctx, jobqueue.SettingPaused("Default"), setting.NewValue(true))
+
+	factory := newFakeQueueFactory()
+	q, _ := jobqueue.NewControlledQueue(ctx, alog.NewNoopLogger(), settings, "", factory.New,
+		jobqueue.WithInterval(time.Millisecond),
+	)
+	_ = q.RegisterJobFunc(func(context.Context, someJob) error { return nil })
+
+	q.Start(ctx)
+	defer q.Shutdown(ctx)
+
+	assert.Eventually(t, func() bool {
+		return factory.Last().run(someJob{}) == jobqueue.ErrPaused
+	}, time.Second, time.Millisecond)
+
+	settings.fail(true)
+
+	assert.Never(t, func() bool {
+		return factory.Last().workers != 2 || factory.Last().run(someJob{}) != jobqueue.ErrPaused
+	}, 50*time.Millisecond, time.Millisecond, "the current values are kept")
+}
+
+type (
+	someJob  struct{}
+	otherJob struct{}
+)
+
+func (otherJob) JobType() string { return "custom-type" }
+
+var errSettings = errors.New("settings unavailable")
+
+// failingSettings fails to load any setting, e.g. if the database is not reachable.
+type failingSettings struct {
+	setting.Settings
+
+	mu      sync.Mutex
+	failing bool
+}
+
+func (s *failingSettings) Setting(ctx context.Context, key setting.Key) (setting.Value, error) {
+	s.mu.Lock()
+	failing := s.failing
+	s.mu.Unlock()
+
+	if failing {
+		return setting.Value{}, errSettings
+	}
+
+	return s.Settings.Setting(ctx, key) //nolint:wrapcheck // this is a decorator
+}
+
+func (s *failingSettings) fail(failing bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failing = failing
+}
+
+func newFakeQueueFactory() *fakeQueueFactory {
+	return &fakeQueueFactory{}
+}
+
+type fakeQueueFactory struct {
+	mu     sync.Mutex
+	queues []*fakeQueue
+}
+
+func (f *fakeQueueFactory) New(workers int) (jobs.Queue, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	q := &fakeQueue{workers: workers}
+	f.queues = append(f.queues, q)
+
+	return q, nil
+}
+
+func (f *fakeQueueFactory) First() *fakeQueue {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.queues[0]
+}
+
+func (f *fakeQueueFactory) Last() *fakeQueue {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.queues[len(f.queues)-1]
+}
+
+// fakeQueue keeps the registered job funcs, so a test can run them like a worker would.
+type fakeQueue struct {
+	mu       sync.Mutex
+	funcs    []any
+	jobs     []jobs.Job
+	workers  int
+	shutdown bool
+}
+
+func (q *fakeQueue) Enqueue(_ context.Context, job jobs.Job, _ ...jobs.JobOpt) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.jobs = append(q.jobs, job)
+
+	return nil
+}
+
+func (q *fakeQueue) RegisterJobFunc(jobFunc any) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.funcs = append(q.funcs, jobFunc)
+
+	return nil
+}
+
+func (q *fakeQueue) Shutdown(_ context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.shutdown = true
+
+	return nil
+}
+
+func (q *fakeQueue) registered() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.funcs)
+}
+
+func (q *fakeQueue) run(job any) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, f := range q.funcs {
+		switch fn := f.(type) {
+		case func(context.Context, someJob) error:
+			if j, ok := job.(someJob); ok {
+				return fn(ctx, j)
+			}
+		case func(context.Context, otherJob) error:
+			if j, ok := job.(otherJob); ok {
+				return fn(ctx, j)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (q *fakeQueue) isShutdown() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.shutdown
+}
+
+func (q *fakeQueue) enqueued() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.jobs)
+}
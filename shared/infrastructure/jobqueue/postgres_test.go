@@ -0,0 +1,158 @@
+//go:build integration
+
+package jobqueue_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/go-arrower/arrower/jobs"
+	"github.com/go-arrower/arrower/setting"
+	"github.com/go-arrower/arrower/tests"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	mnoop "go.opentelemetry.io/otel/metric/noop"
+	tnoop "go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/jobqueue"
+	"github.com/go-arrower/skeleton/shared/infrastructure/migrations"
+)
+
+var pgHandler *tests.PostgresDocker
+
+func TestMain(m *testing.M) {
+	pgHandler = tests.GetPostgresDockerForIntegrationTestingInstance()
+
+	//
+	// Run tests
+	code := m.Run()
+
+	pgHandler.Cleanup()
+	os.Exit(code)
+}
+
+func TestControlledQueue_WithPostgres(t *testing.T) {
+	t.Parallel()
+
+	t.Run("park and release paused jobs", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		_ = migrations.Up(ctx, pg)
+
+		settings := setting.NewInMemorySettings()
+		q, _ := jobqueue.NewControlledQueue(ctx, alog.NewNoopLogger(), settings, "", newFakeQueueFactory().New,
+			jobqueue.WithInterval(time.Millisecond),
+			jobqueue.WithPostgres(pg),
+		)
+		_ = q.RegisterJobFunc(func(_ context.Context, _ someJob) error { return nil })
+
+		q.Start(ctx)
+		defer q.Shutdown(ctx)
+
+		jobID := enqueue(t, pg)
+
+		_ = settings.Save(ctx, jobqueue.SettingPaused("Default"), setting.NewValue(true))
+		assert.Eventually(t, func() bool { return pauses(pg) == 1 }, time.Second, time.Millisecond)
+
+		// fail the job, like the worker does
+		_, err := pg.Exec(ctx, `UPDATE arrower.gue_jobs SET error_count = 1, last_error = 'some error' WHERE job_id = $1`, jobID)
+		assert.NoError(t, err)
+
+		parked, errorCount := job(pg, jobID)
+		assert.True(t, parked)
+		assert.Equal(t, 0, errorCount, "the attempt is not counted")
+
+		_ = settings.Save(ctx, jobqueue.SettingPaused("Default"), setting.NewValue(false))
+		assert.Eventually(t, func() bool {
+			parked, _ := job(pg, jobID)
+
+			return !parked && pauses(pg) == 0
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("job type still paused", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		_ = migrations.Up(ctx, pg)
+
+		settings := setting.NewInMemorySettings()
+		q, _ := jobqueue.NewControlledQueue(ctx, alog.NewNoopLogger(), settings, "", newFakeQueueFactory().New,
+			jobqueue.WithInterval(time.Millisecond),
+			jobqueue.WithPostgres(pg),
+		)
+		_ = q.RegisterJobFunc(func(_ context.Context, _ someJob) error { return nil })
+
+		q.Start(ctx)
+		defer q.Shutdown(ctx)
+
+		jobID := enqueue(t, pg)
+
+		_ = settings.Save(ctx, jobqueue.SettingPaused("Default"), setting.NewValue(true))
+		_ = settings.Save(ctx, jobqueue.SettingJobTypePaused("Default", "someJob"), setting.NewValue(true))
+		assert.Eventually(t, func() bool { return pauses(pg) == 2 }, time.Second, time.Millisecond)
+
+		_, _ = pg.Exec(ctx, `UPDATE arrower.gue_jobs SET error_count = 1 WHERE job_id = $1`, jobID)
+
+		_ = settings.Save(ctx, jobqueue.SettingPaused("Default"), setting.NewValue(false))
+		assert.Eventually(t, func() bool { return pauses(pg) == 1 }, time.Second, time.Millisecond)
+
+		parked, _ := job(pg, jobID)
+		assert.True(t, parked, "the job type is still paused")
+	})
+
+	t.Run("not paused", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		_ = migrations.Up(ctx, pg)
+
+		jobID := enqueue(t, pg)
+
+		_, _ = pg.Exec(ctx, `UPDATE arrower.gue_jobs SET error_count = 1, last_error = 'job paused by admin' WHERE job_id = $1`, jobID)
+
+		parked, errorCount := job(pg, jobID)
+		assert.False(t, parked, "the error message does not park a job")
+		assert.Equal(t, 1, errorCount)
+	})
+}
+
+func enqueue(t *testing.T, pg *pgxpool.Pool) string {
+	t.Helper()
+
+	jq, _ := jobs.NewPostgresJobs(alog.NewNoopLogger(), mnoop.NewMeterProvider(), tnoop.NewTracerProvider(), pg)
+
+	err := jq.Enqueue(ctx, someJob{})
+	assert.NoError(t, err)
+
+	var jobID string
+	_ = pg.QueryRow(ctx, `SELECT job_id FROM arrower.gue_jobs`).Scan(&jobID)
+
+	return jobID
+}
+
+func pauses(pg *pgxpool.Pool) int {
+	var count int
+	_ = pg.QueryRow(ctx, `SELECT COUNT(*) FROM arrower.job_pauses`).Scan(&count)
+
+	return count
+}
+
+// job returns if the job is parked and how often it failed.
+func job(pg *pgxpool.Pool, jobID string) (bool, int) {
+	var (
+		parked     bool
+		errorCount int
+	)
+
+	_ = pg.QueryRow(ctx,
+		`SELECT run_at = 'infinity' AND EXISTS (SELECT FROM arrower.paused_jobs WHERE job_id = $1), error_count FROM arrower.gue_jobs WHERE job_id = $1`,
+		jobID,
+	).Scan(&parked, &errorCount)
+
+	return parked, errorCount
+}
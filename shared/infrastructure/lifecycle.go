@@ -0,0 +1,128 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrServerFailed   = errors.New("server failed")
+	ErrShutdownFailed = errors.New("shutdown failed")
+)
+
+// NewLifecycle returns a Lifecycle without any parts to shut down.
+func NewLifecycle() *Lifecycle {
+	return &Lifecycle{
+		mu:      sync.Mutex{},
+		hooks:   []shutdownHook{},
+		servers: []func() error{},
+		done:    false,
+	}
+}
+
+// Lifecycle shuts down all parts of the application in the reverse order they were started in,
+// so each part can still use its dependencies while shutting down.
+type Lifecycle struct {
+	mu      sync.Mutex
+	hooks   []shutdownHook
+	servers []func() error
+	done    bool
+}
+
+type shutdownHook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// OnShutdown registers fn to be called on Shutdown. Register each part right after it is started.
+func (l *Lifecycle) OnShutdown(name string, fn func(ctx context.Context) error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.hooks = append(l.hooks, shutdownHook{name: name, fn: fn})
+}
+
+// Serve registers serve to be run by Run, together with the servers given to it.
+// Use it for servers of the infrastructure, e.g. the status endpoint, so the application stops, if they fail.
+// Register the server's shutdown with OnShutdown.
+func (l *Lifecycle) Serve(serve func() error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.servers = append(l.servers, serve)
+}
+
+// Shutdown calls all registered functions in reverse order. If one fails, the others are still called,
+// so no resource is left open. All errors are returned together.
+// Shutdown runs only once, calling it again does nothing.
+func (l *Lifecycle) Shutdown(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.done {
+		return nil
+	}
+
+	l.done = true
+
+	var errs []error
+
+	for i := len(l.hooks) - 1; i >= 0; i-- {
+		hook := l.hooks[i]
+
+		if err := hook.fn(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", hook.name, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrShutdownFailed, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// Run runs the servers and the ones registered by Serve, until the application receives SIGINT or SIGTERM, the ctx is cancelled, or a server fails.
+// Then it shuts down the application and waits at most timeout for all parts to finish their work,
+// e.g. HTTP requests to be drained and running jobs to finish.
+func (l *Lifecycle) Run(ctx context.Context, timeout time.Duration, servers ...func() error) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	l.mu.Lock()
+	servers = append(servers, l.servers...)
+	l.mu.Unlock()
+
+	failed := make(chan error, len(servers))
+
+	for _, serve := range servers {
+		go func() {
+			if err := serve(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				failed <- err
+			}
+		}()
+	}
+
+	var err error
+
+	select {
+	case <-ctx.Done():
+	case serverErr := <-failed:
+		err = fmt.Errorf("%w: %w", ErrServerFailed, serverErr)
+	}
+
+	// restore the default behaviour, so a second signal stops the application immediately
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return errors.Join(err, l.Shutdown(shutdownCtx))
+}
@@ -0,0 +1,143 @@
+package infrastructure_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure"
+)
+
+var errShutdown = errors.New("some error")
+
+func TestLifecycle_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reverse order", func(t *testing.T) {
+		t.Parallel()
+
+		var order []string
+
+		lifecycle := infrastructure.NewLifecycle()
+		lifecycle.OnShutdown("postgres", func(_ context.Context) error {
+			order = append(order, "postgres")
+			return nil
+		})
+		lifecycle.OnShutdown("web server", func(_ context.Context) error {
+			order = append(order, "web server")
+			return nil
+		})
+
+		err := lifecycle.Shutdown(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"web server", "postgres"}, order)
+	})
+
+	t.Run("aggregate errors", func(t *testing.T) {
+		t.Parallel()
+
+		called := false
+
+		lifecycle := infrastructure.NewLifecycle()
+		lifecycle.OnShutdown("postgres", func(_ context.Context) error {
+			called = true
+			return nil
+		})
+		lifecycle.OnShutdown("queue", func(_ context.Context) error { return errShutdown })
+		lifecycle.OnShutdown("web server", func(_ context.Context) error { return context.DeadlineExceeded })
+
+		err := lifecycle.Shutdown(context.Background())
+		assert.ErrorIs(t, err, infrastructure.ErrShutdownFailed)
+		assert.ErrorIs(t, err, errShutdown)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorContains(t, err, "queue")
+		assert.True(t, called, "shut down all parts, even if one fails")
+	})
+
+	t.Run("only once", func(t *testing.T) {
+		t.Parallel()
+
+		calls := 0
+
+		lifecycle := infrastructure.NewLifecycle()
+		lifecycle.OnShutdown("postgres", func(_ context.Context) error {
+			calls++
+			return nil
+		})
+
+		_ = lifecycle.Shutdown(context.Background())
+		_ = lifecycle.Shutdown(context.Background())
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestLifecycle_Run(t *testing.T) {
+	t.Parallel()
+
+	t.Run("cancelled ctx", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stopped := make(chan struct{})
+
+		lifecycle := infrastructure.NewLifecycle()
+		lifecycle.OnShutdown("server", func(_ context.Context) error {
+			close(stopped)
+			return nil
+		})
+
+		go cancel()
+
+		err := lifecycle.Run(ctx, time.Second, func() error {
+			<-stopped
+			return http.ErrServerClosed
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("server fails", func(t *testing.T) {
+		t.Parallel()
+
+		shutdown := false
+
+		lifecycle := infrastructure.NewLifecycle()
+		lifecycle.OnShutdown("postgres", func(_ context.Context) error {
+			shutdown = true
+			return nil
+		})
+
+		err := lifecycle.Run(context.Background(), time.Second, func() error { return errShutdown })
+		assert.ErrorIs(t, err, infrastructure.ErrServerFailed)
+		assert.ErrorIs(t, err, errShutdown)
+		assert.True(t, shutdown)
+	})
+
+	t.Run("registered server fails", func(t *testing.T) {
+		t.Parallel()
+
+		lifecycle := infrastructure.NewLifecycle()
+		lifecycle.Serve(func() error { return errShutdown })
+
+		err := lifecycle.Run(context.Background(), time.Second)
+		assert.ErrorIs(t, err, infrastructure.ErrServerFailed)
+		assert.ErrorIs(t, err, errShutdown)
+	})
+
+	t.Run("shutdown timeout", func(t *testing.T) {
+		t.Parallel()
+
+		lifecycle := infrastructure.NewLifecycle()
+		lifecycle.OnShutdown("server", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		err := lifecycle.Run(context.Background(), time.Millisecond, func() error { return errShutdown })
+		assert.ErrorIs(t, err, infrastructure.ErrShutdownFailed)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,146 @@
+// Package mail sends emails.
+//
+// Contexts depend on the Mailer port only, so the transport can be replaced,
+// e.g. by a local mail catcher in development or by a provider API in production.
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+var ErrSendFailed = errors.New("could not send mail")
+
+// Mailer sends a plain text email.
+type Mailer interface {
+	Send(ctx context.Context, to []string, subject string, body string) error
+}
+
+// NewSMTPMailer returns a Mailer, that sends via the SMTP server at addr.
+// The auth is optional, e.g. for a local mail catcher.
+func NewSMTPMailer(addr string, from string, auth smtp.Auth) *SMTPMailer {
+	return &SMTPMailer{
+		addr: addr,
+		from: from,
+		auth: auth,
+	}
+}
+
+type SMTPMailer struct {
+	auth smtp.Auth
+	addr string
+	from string
+}
+
+var _ Mailer = (*SMTPMailer)(nil)
+
+// Send sends the mail, until the deadline of the ctx is reached or the ctx is cancelled.
+func (m *SMTPMailer) Send(ctx context.Context, to []string, subject string, body string) error {
+	if len(to) == 0 {
+		return fmt.Errorf("%w: no recipient", ErrSendFailed)
+	}
+
+	for _, addr := range to {
+		if strings.ContainsAny(addr, "\r\n") {
+			return fmt.Errorf("%w: invalid recipient: %q", ErrSendFailed, addr)
+		}
+	}
+
+	msg := strings.Join([]string{
+		"From: " + headerValue(m.from),
+		"To: " + headerValue(strings.Join(to, ", ")),
+		"Subject: " + headerValue(subject),
+		"MIME-Version: 1.0",
+		`Content-Type: text/plain; charset="utf-8"`,
+		"",
+		body,
+	}, "\r\n")
+
+	err := m.send(ctx, to, []byte(msg))
+	if err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+
+		return fmt.Errorf("%w: %v", ErrSendFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	return nil
+}
+
+// send works like smtp.SendMail, but stops once the ctx is done.
+func (m *SMTPMailer) send(ctx context.Context, to []string, msg []byte) error {
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return fmt.Errorf("could not connect: %w", err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	// closing the connection aborts the running command, if the ctx is cancelled
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	host, _, _ := net.SplitHostPort(m.addr)
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+
+		return fmt.Errorf("could not greet: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}); err != nil {
+			return fmt.Errorf("could not start tls: %w", err)
+		}
+	}
+
+	if m.auth != nil {
+		if ok, _ := client.Extension("AUTH"); ok {
+			if err := client.Auth(m.auth); err != nil {
+				return fmt.Errorf("could not authenticate: %w", err)
+			}
+		}
+	}
+
+	if err := client.Mail(m.from); err != nil {
+		return fmt.Errorf("could not set sender: %w", err)
+	}
+
+	for _, addr := range to {
+		if err := client.Rcpt(addr); err != nil {
+			return fmt.Errorf("could not add recipient: %w", err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("could not start data: %w", err)
+	}
+
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("could not write data: %w", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("could not send data: %w", err)
+	}
+
+	return client.Quit() //nolint:wrapcheck // wrapped by Send
+}
+
+// headerValue prevents header injection.
+func headerValue(v string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(v)
+}
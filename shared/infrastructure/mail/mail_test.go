@@ -0,0 +1,62 @@
+package mail_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/mail"
+)
+
+func TestSMTPMailer_Send(t *testing.T) {
+	t.Parallel()
+
+	t.Run("header injection", func(t *testing.T) {
+		t.Parallel()
+
+		mailer := mail.NewSMTPMailer("localhost:0", "from@example.com", nil)
+
+		err := mailer.Send(context.Background(), []string{"to@example.com\r\nBcc: other@example.com"}, "subject", "body")
+		assert.ErrorIs(t, err, mail.ErrSendFailed)
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		t.Parallel()
+
+		// a server, that accepts connections but never greets
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+
+		defer l.Close()
+
+		go func() {
+			var conns []net.Conn
+
+			for {
+				conn, err := l.Accept()
+				if err != nil {
+					for _, c := range conns {
+						_ = c.Close()
+					}
+
+					return
+				}
+
+				conns = append(conns, conn)
+			}
+		}()
+
+		mailer := mail.NewSMTPMailer(l.Addr().String(), "from@example.com", nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err = mailer.Send(ctx, []string{"to@example.com"}, "subject", "body")
+		assert.ErrorIs(t, err, mail.ErrSendFailed)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
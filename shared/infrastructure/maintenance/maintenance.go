@@ -0,0 +1,290 @@
+// Package maintenance puts the application of all instances into maintenance.
+//
+// The Mode is stored as settings, so it is shared by all instances.
+// Each instance runs a Watcher, that follows the settings, and answers the web requests
+// of everybody, except superusers and allow-listed IPs, with a 503 while the maintenance is on.
+//
+// If the Mode pauses jobs, the job queues park their jobs until the maintenance is over, see jobqueue.
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/go-arrower/arrower/setting"
+)
+
+var ErrInvalidMode = errors.New("invalid maintenance mode")
+
+const (
+	settingsContext   = "maintenance"
+	defaultInterval   = 5 * time.Second
+	defaultRetryAfter = 5 * time.Minute
+)
+
+//nolint:gochecknoglobals // used as constants
+var (
+	SettingEnabled = setting.NewKey(settingsContext, "mode", "enabled")
+	// SettingMessage is shown to the users on the maintenance page.
+	SettingMessage = setting.NewKey(settingsContext, "mode", "message")
+	// SettingRetryAfter is the number of seconds clients are told to wait, before they try again.
+	SettingRetryAfter = setting.NewKey(settingsContext, "mode", "retry_after")
+	// SettingPauseJobs pauses all job queues during the maintenance.
+	SettingPauseJobs = setting.NewKey(settingsContext, "mode", "pause_jobs")
+	// SettingAllowedIPs is a comma separated list of IPs and CIDRs, that can use the application during the maintenance.
+	SettingAllowedIPs = setting.NewKey(settingsContext, "mode", "allowed_ips")
+)
+
+// Mode is the maintenance of the application.
+type Mode struct {
+	Message    string
+	AllowedIPs []string
+	RetryAfter time.Duration
+	Enabled    bool
+	PauseJobs  bool
+}
+
+// PausesJobs returns true, if the job queues are to be paused.
+func (m Mode) PausesJobs() bool {
+	return m.Enabled && m.PauseJobs
+}
+
+// PausesJobs returns true, if the current Mode pauses the job queues.
+// It only reads the settings needed, so it can be checked frequently.
+func PausesJobs(ctx context.Context, settings setting.Settings) bool {
+	for _, key := range []setting.Key{SettingEnabled, SettingPauseJobs} {
+		val, err := settings.Setting(ctx, key)
+		if err != nil {
+			return false
+		}
+
+		if b, err := strconv.ParseBool(val.String()); err != nil || !b {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m Mode) Validate() error {
+	if m.RetryAfter < 0 {
+		return fmt.Errorf("%w: retry after can not be negative", ErrInvalidMode)
+	}
+
+	for _, ip := range m.AllowedIPs {
+		if net.ParseIP(ip) == nil {
+			if _, _, err := net.ParseCIDR(ip); err != nil {
+				return fmt.Errorf("%w: not an IP or CIDR: %s", ErrInvalidMode, ip)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Load returns the current Mode. Settings, that are not set or invalid, have their defaults.
+func Load(ctx context.Context, settings setting.Settings) Mode {
+	mode := Mode{
+		Message:    "",
+		AllowedIPs: nil,
+		RetryAfter: defaultRetryAfter,
+		Enabled:    false,
+		PauseJobs:  false,
+	}
+
+	if val, err := settings.Setting(ctx, SettingEnabled); err == nil {
+		mode.Enabled, _ = strconv.ParseBool(val.String())
+	}
+
+	if val, err := settings.Setting(ctx, SettingMessage); err == nil {
+		mode.Message = val.String()
+	}
+
+	if val, err := settings.Setting(ctx, SettingRetryAfter); err == nil {
+		if secs, err := strconv.Atoi(val.String()); err == nil && secs > 0 {
+			mode.RetryAfter = time.Duration(secs) * time.Second
+		}
+	}
+
+	if val, err := settings.Setting(ctx, SettingPauseJobs); err == nil {
+		mode.PauseJobs, _ = strconv.ParseBool(val.String())
+	}
+
+	if val, err := settings.Setting(ctx, SettingAllowedIPs); err == nil {
+		mode.AllowedIPs = splitIPs(val.String())
+	}
+
+	return mode
+}
+
+// Save stores the Mode, so all instances follow it with their next refresh.
+func Save(ctx context.Context, settings setting.Settings, mode Mode) error {
+	if err := mode.Validate(); err != nil {
+		return err
+	}
+
+	values := []struct {
+		key   setting.Key
+		value setting.Value
+	}{
+		{SettingMessage, setting.NewValue(mode.Message)},
+		{SettingAllowedIPs, setting.NewValue(strings.Join(mode.AllowedIPs, ","))},
+		{SettingRetryAfter, setting.NewValue(int(mode.RetryAfter.Seconds()))},
+		{SettingPauseJobs, setting.NewValue(mode.PauseJobs)},
+		// last, so the other settings are in place, once the instances see the maintenance
+		{SettingEnabled, setting.NewValue(mode.Enabled)},
+	}
+
+	for _, v := range values {
+		if err := settings.Save(ctx, v.key, v.value); err != nil {
+			return fmt.Errorf("could not save maintenance mode: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func splitIPs(ips string) []string {
+	var list []string
+
+	for _, ip := range strings.Split(ips, ",") {
+		if ip = strings.TrimSpace(ip); ip != "" {
+			list = append(list, ip)
+		}
+	}
+
+	return list
+}
+
+type Option func(*Watcher)
+
+// WithInterval sets how often the settings are checked for changes.
+func WithInterval(interval time.Duration) Option {
+	return func(w *Watcher) {
+		w.interval = interval
+	}
+}
+
+// NewWatcher returns a Watcher, that follows the Mode stored in the settings.
+func NewWatcher(logger alog.Logger, settings setting.Settings, opts ...Option) *Watcher {
+	w := &Watcher{
+		logger:   logger,
+		settings: settings,
+		interval: defaultInterval,
+		mu:       sync.RWMutex{},
+		mode:     Mode{},
+		onChange: nil,
+		done:     make(chan struct{}),
+		wg:       sync.WaitGroup{},
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+// Watcher keeps the Mode of this instance in sync with the settings.
+type Watcher struct {
+	logger   alog.Logger
+	settings setting.Settings
+	interval time.Duration
+
+	mu       sync.RWMutex
+	mode     Mode
+	onChange []func(ctx context.Context, old Mode, mode Mode)
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// Mode returns the Mode as of the last refresh.
+func (w *Watcher) Mode() Mode {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.mode
+}
+
+// OnChange calls fn each time the Mode changes, e.g. to release the jobs parked during the maintenance.
+// The first refresh after Start counts as a change.
+func (w *Watcher) OnChange(fn func(ctx context.Context, old Mode, mode Mode)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.onChange = append(w.onChange, fn)
+}
+
+// Start loads the Mode and follows the settings in the background, until Shutdown is called or the ctx is cancelled.
+func (w *Watcher) Start(ctx context.Context) {
+	w.refresh(ctx)
+
+	w.wg.Add(1)
+
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.refresh(ctx)
+			}
+		}
+	}()
+}
+
+func (w *Watcher) Shutdown(_ context.Context) error {
+	select {
+	case <-w.done:
+	default:
+		close(w.done)
+	}
+
+	w.wg.Wait()
+
+	return nil
+}
+
+// refresh loads the Mode from the settings and notifies about a change.
+func (w *Watcher) refresh(ctx context.Context) {
+	mode := Load(ctx, w.settings)
+
+	w.mu.Lock()
+	old := w.mode
+	w.mode = mode
+	onChange := w.onChange
+	w.mu.Unlock()
+
+	if old.Enabled == mode.Enabled && old.PauseJobs == mode.PauseJobs &&
+		old.Message == mode.Message && old.RetryAfter == mode.RetryAfter &&
+		strings.Join(old.AllowedIPs, ",") == strings.Join(mode.AllowedIPs, ",") {
+		return
+	}
+
+	if old.Enabled != mode.Enabled {
+		w.logger.LogAttrs(ctx, alog.LevelInfo, "maintenance changed",
+			slog.Bool("enabled", mode.Enabled),
+			slog.Bool("pause_jobs", mode.PauseJobs),
+		)
+	}
+
+	for _, fn := range onChange {
+		fn(ctx, old, mode)
+	}
+}
@@ -0,0 +1,141 @@
+package maintenance_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/go-arrower/arrower/setting"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/maintenance"
+)
+
+var ctx = context.Background()
+
+func TestMode_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		mode  maintenance.Mode
+		valid bool
+	}{
+		"empty":          {maintenance.Mode{}, true},
+		"ip":             {maintenance.Mode{AllowedIPs: []string{"127.0.0.1", "::1"}}, true},
+		"cidr":           {maintenance.Mode{AllowedIPs: []string{"10.0.0.0/8"}}, true},
+		"invalid ip":     {maintenance.Mode{AllowedIPs: []string{"localhost"}}, false},
+		"negative retry": {maintenance.Mode{RetryAfter: -time.Second}, false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.mode.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, maintenance.ErrInvalidMode)
+			}
+		})
+	}
+}
+
+func TestSave(t *testing.T) {
+	t.Parallel()
+
+	t.Run("save and load", func(t *testing.T) {
+		t.Parallel()
+
+		settings := setting.NewInMemorySettings()
+		mode := maintenance.Mode{
+			Message:    "Back soon",
+			AllowedIPs: []string{"10.0.0.0/8", "127.0.0.1"},
+			RetryAfter: time.Minute,
+			Enabled:    true,
+			PauseJobs:  true,
+		}
+
+		err := maintenance.Save(ctx, settings, mode)
+		assert.NoError(t, err)
+		assert.Equal(t, mode, maintenance.Load(ctx, settings))
+		assert.True(t, maintenance.PausesJobs(ctx, settings))
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		t.Parallel()
+
+		settings := setting.NewInMemorySettings()
+
+		err := maintenance.Save(ctx, settings, maintenance.Mode{Enabled: true, AllowedIPs: []string{"invalid"}})
+		assert.ErrorIs(t, err, maintenance.ErrInvalidMode)
+		assert.False(t, maintenance.Load(ctx, settings).Enabled)
+	})
+
+	t.Run("defaults", func(t *testing.T) {
+		t.Parallel()
+
+		settings := setting.NewInMemorySettings()
+
+		mode := maintenance.Load(ctx, settings)
+		assert.False(t, mode.Enabled)
+		assert.Equal(t, 5*time.Minute, mode.RetryAfter)
+		assert.False(t, maintenance.PausesJobs(ctx, settings))
+	})
+}
+
+func TestWatcher(t *testing.T) {
+	t.Parallel()
+
+	t.Run("follow the settings", func(t *testing.T) {
+		t.Parallel()
+
+		settings := setting.NewInMemorySettings()
+
+		w := maintenance.NewWatcher(alog.NewNoopLogger(), settings, maintenance.WithInterval(time.Millisecond))
+		w.Start(ctx)
+		defer w.Shutdown(ctx)
+
+		assert.False(t, w.Mode().Enabled)
+
+		_ = maintenance.Save(ctx, settings, maintenance.Mode{Enabled: true})
+
+		assert.Eventually(t, func() bool {
+			return w.Mode().Enabled
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("on change", func(t *testing.T) {
+		t.Parallel()
+
+		settings := setting.NewInMemorySettings()
+		_ = maintenance.Save(ctx, settings, maintenance.Mode{Enabled: true, PauseJobs: true})
+
+		var (
+			mu      sync.Mutex
+			changes []bool
+		)
+
+		w := maintenance.NewWatcher(alog.NewNoopLogger(), settings, maintenance.WithInterval(time.Millisecond))
+		w.OnChange(func(_ context.Context, old maintenance.Mode, mode maintenance.Mode) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			changes = append(changes, old.PausesJobs() && !mode.PausesJobs())
+		})
+		w.Start(ctx)
+		defer w.Shutdown(ctx)
+
+		_ = maintenance.Save(ctx, settings, maintenance.Mode{Enabled: false})
+
+		assert.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+
+			return len(changes) == 2
+		}, time.Second, time.Millisecond)
+		assert.Equal(t, []bool{false, true}, changes, "the first refresh is a change, the second ends the paused jobs")
+	})
+}
@@ -0,0 +1,110 @@
+package maintenance
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/web"
+)
+
+// ViewMaintenance is the page shown to the users during the maintenance.
+var ViewMaintenance = web.NewView[Page]("maintenance") //nolint:gochecknoglobals // typed views are declared once
+
+// Page is the data of ViewMaintenance.
+type Page struct {
+	Title string
+	// Message is set by the admin, if empty a default message is shown.
+	Message string
+}
+
+// Middleware answers all requests with a 503 during the maintenance: a page for the web and a Problem for the API.
+// Superusers, allow-listed IPs, and paths starting with one of the bypass prefixes are served as usual,
+// e.g. the login, so a superuser can log in during the maintenance.
+// It has to run after the middleware that puts the user into the request's context.
+func (w *Watcher) Middleware(isSuperuser func(ctx context.Context) bool, bypass ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			mode := w.Mode()
+			if !mode.Enabled {
+				return next(c)
+			}
+
+			if isSuperuser(c.Request().Context()) || isAllowed(c.RealIP(), mode.AllowedIPs) {
+				return next(c)
+			}
+
+			for _, prefix := range bypass {
+				if strings.HasPrefix(c.Request().URL.Path, prefix) {
+					return next(c)
+				}
+			}
+
+			return unavailable(c, mode)
+		}
+	}
+}
+
+func unavailable(c echo.Context, mode Mode) error {
+	const code = http.StatusServiceUnavailable
+
+	c.Response().Header().Set("Retry-After", strconv.Itoa(int(mode.RetryAfter.Seconds())))
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+
+	if c.Request().Method == http.MethodHead {
+		return c.NoContent(code) //nolint:wrapcheck // return the error of echo as is
+	}
+
+	if web.IsAPIRequest(c) {
+		c.Response().Header().Set(echo.HeaderContentType, "application/problem+json")
+
+		return c.JSON(code, web.Problem{ //nolint:wrapcheck // return the error of echo as is
+			Type:     "about:blank",
+			Title:    http.StatusText(code),
+			Status:   code,
+			Detail:   mode.Message,
+			Instance: c.Request().URL.Path,
+			Errors:   nil,
+			TraceID:  "",
+		})
+	}
+
+	err := web.Render(c, code, ViewMaintenance, Page{
+		Title:   http.StatusText(code),
+		Message: mode.Message,
+	})
+	if err != nil {
+		// the views can be broken by the maintenance, so fall back to plain text
+		return c.String(code, http.StatusText(code)) //nolint:wrapcheck // return the error of echo as is
+	}
+
+	return nil
+}
+
+// isAllowed returns true, if ip matches one of the allowed IPs or CIDRs.
+func isAllowed(ip string, allowed []string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+
+	for _, a := range allowed {
+		if _, network, err := net.ParseCIDR(a); err == nil {
+			if network.Contains(addr) {
+				return true
+			}
+
+			continue
+		}
+
+		if allowedAddr := net.ParseIP(a); allowedAddr != nil && allowedAddr.Equal(addr) {
+			return true
+		}
+	}
+
+	return false
+}
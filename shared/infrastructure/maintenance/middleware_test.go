@@ -0,0 +1,104 @@
+package maintenance_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/go-arrower/arrower/setting"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/maintenance"
+)
+
+func TestWatcher_Middleware(t *testing.T) {
+	t.Parallel()
+
+	settings := setting.NewInMemorySettings()
+	_ = maintenance.Save(ctx, settings, maintenance.Mode{
+		Message:    "Back soon",
+		AllowedIPs: []string{"10.0.0.0/8"},
+		RetryAfter: 2 * time.Minute,
+		Enabled:    true,
+	})
+
+	w := maintenance.NewWatcher(alog.NewNoopLogger(), settings)
+	w.Start(ctx)
+	t.Cleanup(func() { _ = w.Shutdown(ctx) })
+
+	notSuperuser := func(context.Context) bool { return false }
+
+	serve := func(isSuperuser func(context.Context) bool, req *http.Request) *httptest.ResponseRecorder {
+		e := echo.New()
+		e.Use(w.Middleware(isSuperuser, "/auth/login"))
+		e.Any("/*", func(c echo.Context) error { return c.String(http.StatusOK, "ok") })
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	t.Run("web request", func(t *testing.T) {
+		t.Parallel()
+
+		rec := serve(notSuperuser, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Equal(t, "120", rec.Header().Get("Retry-After"))
+		assert.Equal(t, "no-store", rec.Header().Get(echo.HeaderCacheControl))
+	})
+
+	t.Run("api request", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+		req.Header.Set(echo.HeaderAccept, echo.MIMEApplicationJSON)
+
+		rec := serve(notSuperuser, req)
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Equal(t, "application/problem+json", rec.Header().Get(echo.HeaderContentType))
+		assert.Contains(t, rec.Body.String(), "Back soon")
+	})
+
+	t.Run("head request", func(t *testing.T) {
+		t.Parallel()
+
+		rec := serve(notSuperuser, httptest.NewRequest(http.MethodHead, "/", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("superuser", func(t *testing.T) {
+		t.Parallel()
+
+		rec := serve(func(context.Context) bool { return true }, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("allowed ip", func(t *testing.T) {
+		t.Parallel()
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "10.1.2.3:1234"
+
+		rec := serve(notSuperuser, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("bypass path", func(t *testing.T) {
+		t.Parallel()
+
+		rec := serve(notSuperuser, httptest.NewRequest(http.MethodGet, "/auth/login", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS arrower.cron_schedules
+(
+    name        TEXT PRIMARY KEY,
+    spec        TEXT        NOT NULL,
+    queue       TEXT        NOT NULL DEFAULT '',
+    job_type    TEXT        NOT NULL,
+    payload     JSONB       NOT NULL DEFAULT '{}',
+    source      TEXT        NOT NULL DEFAULT 'code',
+    paused      BOOLEAN     NOT NULL DEFAULT FALSE,
+    last_run_at TIMESTAMPTZ,
+    next_run_at TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS apply_job_retry_policy ON arrower.gue_jobs;
+DROP FUNCTION IF EXISTS arrower.apply_job_retry_policy();
+DROP FUNCTION IF EXISTS arrower.job_retry_delay(TEXT, INTEGER, INTEGER, INTEGER);
+DROP TABLE IF EXISTS arrower.job_retry_policies;
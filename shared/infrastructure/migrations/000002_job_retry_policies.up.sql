@@ -0,0 +1,89 @@
+CREATE TABLE IF NOT EXISTS arrower.job_retry_policies
+(
+    queue              TEXT        NOT NULL DEFAULT '',
+    job_type           TEXT        NOT NULL,
+    backoff            TEXT        NOT NULL DEFAULT 'exponential',
+    max_attempts       INTEGER     NOT NULL DEFAULT 0,
+    base_delay_seconds INTEGER     NOT NULL DEFAULT 1,
+    max_delay_seconds  INTEGER     NOT NULL DEFAULT 0,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (queue, job_type)
+);
+
+
+-- job_retry_delay returns the seconds to wait before the next attempt of a job, that failed error_count times.
+-- The delay is capped, so it does not overflow the interval of run_at.
+-- Without a max delay, the delay is capped at jobs.DefaultMaxDelay.
+--
+-- It is the only definition of the delay in the database and is tested against jobs.RetryPolicy.Delay.
+CREATE OR REPLACE FUNCTION arrower.job_retry_delay(backoff TEXT, base_delay_seconds INTEGER,
+                                                   max_delay_seconds INTEGER,
+                                                   error_count INTEGER) RETURNS DOUBLE PRECISION AS
+$$
+DECLARE
+    delay_seconds             DOUBLE PRECISION;
+    -- 7 days, see jobs.DefaultMaxDelay
+    default_max_delay_seconds CONSTANT DOUBLE PRECISION := 604800;
+    -- larger exponents are capped by any max delay anyway
+    max_exponent              CONSTANT INTEGER          := 31;
+BEGIN
+    error_count := GREATEST(error_count, 1);
+
+    delay_seconds := CASE backoff
+                         WHEN 'fixed' THEN base_delay_seconds
+                         WHEN 'linear' THEN base_delay_seconds::DOUBLE PRECISION * error_count
+                         ELSE base_delay_seconds * POWER(2, LEAST(error_count - 1, max_exponent))
+        END;
+
+    IF max_delay_seconds > 0 THEN
+        RETURN LEAST(delay_seconds, max_delay_seconds);
+    END IF;
+
+    RETURN LEAST(delay_seconds, default_max_delay_seconds);
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+
+-- apply_job_retry_policy is called, whenever a worker records a failed attempt of a job
+-- by increasing its error_count. If a retry policy exists for the queue and job type,
+-- it replaces the run_at set by the worker with the delay of the policy.
+-- Jobs that reached max_attempts are parked with run_at = 'infinity', so no worker
+-- picks them up again until an admin retries them manually.
+CREATE OR REPLACE FUNCTION arrower.apply_job_retry_policy() RETURNS TRIGGER AS
+$$
+DECLARE
+    policy arrower.job_retry_policies%ROWTYPE;
+BEGIN
+    IF NEW.error_count <= OLD.error_count THEN
+        RETURN NEW;
+    END IF;
+
+    SELECT *
+    INTO policy
+    FROM arrower.job_retry_policies
+    WHERE queue = NEW.queue
+      AND job_type = NEW.job_type;
+
+    IF NOT FOUND THEN
+        RETURN NEW;
+    END IF;
+
+    IF policy.max_attempts > 0 AND NEW.error_count >= policy.max_attempts THEN
+        NEW.run_at := 'infinity';
+        RETURN NEW;
+    END IF;
+
+    NEW.run_at := NOW() + MAKE_INTERVAL(secs => arrower.job_retry_delay(
+            policy.backoff, policy.base_delay_seconds, policy.max_delay_seconds, NEW.error_count
+        ));
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER apply_job_retry_policy
+    BEFORE UPDATE OF error_count
+    ON arrower.gue_jobs
+    FOR EACH ROW
+EXECUTE FUNCTION arrower.apply_job_retry_policy();
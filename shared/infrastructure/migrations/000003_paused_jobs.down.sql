@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS park_paused_job ON arrower.gue_jobs;
+DROP FUNCTION IF EXISTS arrower.park_paused_job();
+DROP TABLE IF EXISTS arrower.paused_jobs;
+DROP TABLE IF EXISTS arrower.job_pauses;
@@ -0,0 +1,55 @@
+-- Jobs of a paused queue or job type are not executed, the worker returns jobqueue.ErrPaused instead.
+-- Each jobqueue.ControlledQueue keeps the pauses it follows in job_pauses, an empty job_type pauses the whole queue.
+-- park_paused_job parks a job, that fails while it is paused, without counting it as a failed attempt
+-- and records it in paused_jobs, so resuming releases only the jobs, that were parked by a pause.
+--
+-- Triggers run in alphabetical order, so park_paused_job overrides the run_at set by apply_job_retry_policy.
+CREATE TABLE IF NOT EXISTS arrower.job_pauses
+(
+    queue      TEXT        NOT NULL,
+    job_type   TEXT        NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (queue, job_type)
+);
+
+CREATE TABLE IF NOT EXISTS arrower.paused_jobs
+(
+    job_id     TEXT        NOT NULL PRIMARY KEY,
+    queue      TEXT        NOT NULL,
+    job_type   TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS paused_jobs_queue_idx ON arrower.paused_jobs (queue, job_type);
+
+
+CREATE OR REPLACE FUNCTION arrower.park_paused_job() RETURNS TRIGGER AS
+$$
+BEGIN
+    IF NEW.error_count <= OLD.error_count THEN
+        RETURN NEW;
+    END IF;
+
+    IF NOT EXISTS (SELECT
+                   FROM arrower.job_pauses
+                   WHERE queue = NEW.queue
+                     AND job_type IN ('', NEW.job_type)) THEN
+        RETURN NEW;
+    END IF;
+
+    NEW.error_count := OLD.error_count;
+    NEW.run_at := 'infinity';
+
+    INSERT INTO arrower.paused_jobs (job_id, queue, job_type)
+    VALUES (NEW.job_id, NEW.queue, NEW.job_type)
+    ON CONFLICT (job_id) DO NOTHING;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER park_paused_job
+    BEFORE UPDATE OF error_count
+    ON arrower.gue_jobs
+    FOR EACH ROW
+EXECUTE FUNCTION arrower.park_paused_job();
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS arrower.job_alerts;
+DROP TABLE IF EXISTS arrower.job_alert_rules;
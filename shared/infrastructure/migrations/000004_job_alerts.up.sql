@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS arrower.job_alert_rules
+(
+    name        TEXT PRIMARY KEY,
+    queue       TEXT             NOT NULL DEFAULT '',
+    job_type    TEXT             NOT NULL DEFAULT '',
+    condition   TEXT             NOT NULL,
+    threshold   DOUBLE PRECISION NOT NULL DEFAULT 0,
+    for_seconds INTEGER          NOT NULL DEFAULT 0,
+    channels    TEXT[]           NOT NULL DEFAULT '{}',
+    created_at  TIMESTAMPTZ      NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ      NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS arrower.job_alerts
+(
+    id            TEXT PRIMARY KEY,
+    rule          TEXT             NOT NULL REFERENCES arrower.job_alert_rules (name) ON DELETE CASCADE,
+    queue         TEXT             NOT NULL DEFAULT '',
+    job_type      TEXT             NOT NULL DEFAULT '',
+    message       TEXT             NOT NULL DEFAULT '',
+    value         DOUBLE PRECISION NOT NULL DEFAULT 0,
+    pending_since TIMESTAMPTZ      NOT NULL,
+    fired_at      TIMESTAMPTZ,
+    resolved_at   TIMESTAMPTZ
+);
+
+-- at most one alert per rule is open at a time
+CREATE UNIQUE INDEX IF NOT EXISTS job_alerts_open_rule_idx ON arrower.job_alerts (rule) WHERE resolved_at IS NULL;
+CREATE INDEX IF NOT EXISTS job_alerts_resolved_at_idx ON arrower.job_alerts (resolved_at DESC);
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS notify_logs ON arrower.log;
+DROP TRIGGER IF EXISTS notify_jobs_workers ON arrower.gue_jobs_worker_pool;
+DROP TRIGGER IF EXISTS notify_jobs_finished ON arrower.gue_jobs_history;
+DROP TRIGGER IF EXISTS notify_jobs_queues ON arrower.gue_jobs;
+DROP FUNCTION IF EXISTS arrower.notify_event();
@@ -0,0 +1,40 @@
+-- Changes to the jobs, workers and logs are published as events to all instances, see web.Events.
+-- The triggers run once per statement, so a batch of changes results in a single event.
+-- Postgres drops duplicate notifications of the same transaction.
+--
+-- The channel has to be kept in sync with web.EventsChannel.
+CREATE OR REPLACE FUNCTION arrower.notify_event() RETURNS TRIGGER AS
+$$
+BEGIN
+    PERFORM pg_notify('arrower_events', json_build_object('topic', TG_ARGV[0])::TEXT);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS notify_jobs_queues ON arrower.gue_jobs;
+CREATE TRIGGER notify_jobs_queues
+    AFTER INSERT OR UPDATE OR DELETE
+    ON arrower.gue_jobs
+    FOR EACH STATEMENT
+EXECUTE FUNCTION arrower.notify_event('jobs.queues');
+
+DROP TRIGGER IF EXISTS notify_jobs_finished ON arrower.gue_jobs_history;
+CREATE TRIGGER notify_jobs_finished
+    AFTER INSERT
+    ON arrower.gue_jobs_history
+    FOR EACH STATEMENT
+EXECUTE FUNCTION arrower.notify_event('jobs.finished');
+
+DROP TRIGGER IF EXISTS notify_jobs_workers ON arrower.gue_jobs_worker_pool;
+CREATE TRIGGER notify_jobs_workers
+    AFTER INSERT OR UPDATE OR DELETE
+    ON arrower.gue_jobs_worker_pool
+    FOR EACH STATEMENT
+EXECUTE FUNCTION arrower.notify_event('jobs.workers');
+
+DROP TRIGGER IF EXISTS notify_logs ON arrower.log;
+CREATE TRIGGER notify_logs
+    AFTER INSERT
+    ON arrower.log
+    FOR EACH STATEMENT
+EXECUTE FUNCTION arrower.notify_event('logs');
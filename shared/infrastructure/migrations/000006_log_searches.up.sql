@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS arrower.log_searches
+(
+    user_id    UUID        NOT NULL,
+    name       TEXT        NOT NULL,
+    query      TEXT        NOT NULL DEFAULT '',
+    range      INTEGER     NOT NULL DEFAULT 15, -- minutes before now
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, name)
+);
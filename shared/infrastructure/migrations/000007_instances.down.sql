@@ -0,0 +1 @@
+DROP TABLE IF EXISTS arrower.skeleton_instances;
@@ -0,0 +1,7 @@
+-- The role of each instance, see infrastructure.Role. The worker pools of the job queues are labelled with it.
+CREATE TABLE IF NOT EXISTS arrower.skeleton_instances
+(
+    name       TEXT PRIMARY KEY,
+    role       TEXT        NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
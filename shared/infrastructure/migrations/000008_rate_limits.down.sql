@@ -0,0 +1 @@
+DROP TABLE IF EXISTS arrower.rate_limits;
@@ -0,0 +1,11 @@
+-- The token buckets of the rate limits, shared by all instances, see ratelimit.PostgresStore.
+-- Buckets, that are full again, are pruned.
+CREATE UNLOGGED TABLE IF NOT EXISTS arrower.rate_limits
+(
+    key        TEXT PRIMARY KEY,
+    tokens     DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ      NOT NULL,
+    full_at    TIMESTAMPTZ      NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS rate_limits_full_at_idx ON arrower.rate_limits (full_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS arrower.feature_flag_history;
+DROP TABLE IF EXISTS arrower.feature_flags;
@@ -0,0 +1,18 @@
+-- The feature flags, see feature.PostgresStore.
+-- Each change is kept in the history of its flag, also after the flag is deleted.
+CREATE TABLE IF NOT EXISTS arrower.feature_flags
+(
+    key        TEXT PRIMARY KEY,
+    flag       JSONB       NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS arrower.feature_flag_history
+(
+    id         BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    key        TEXT        NOT NULL,
+    change     JSONB       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS feature_flag_history_key_id_idx ON arrower.feature_flag_history (key, id DESC);
@@ -0,0 +1,322 @@
+// Package migrations contains the database schema of the skeleton itself.
+//
+// Arrower brings its own migrations, applied by postgres.ConnectAndMigrate.
+// The tables in this package build on top of them and are versioned separately,
+// so both can evolve independently.
+package migrations
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrMigrationFailed = errors.New("migration failed")
+
+//go:embed *.sql
+var files embed.FS
+
+const (
+	upSuffix   = ".up.sql"
+	downSuffix = ".down.sql"
+)
+
+type migration struct {
+	version int
+	name    string
+	up      string
+	down    string
+}
+
+// Up applies all migrations, that are not applied yet.
+// Each migration runs in its own transaction. Instances starting at the same time migrate one after the other.
+func Up(ctx context.Context, pg *pgxpool.Pool) error {
+	all, err := load(files)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMigrationFailed, err)
+	}
+
+	return withLock(ctx, pg, func(conn *pgxpool.Conn) error {
+		return up(ctx, conn, all)
+	})
+}
+
+func up(ctx context.Context, pg *pgxpool.Conn, all []migration) error {
+	err := ensureVersionTable(ctx, pg)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMigrationFailed, err)
+	}
+
+	applied, err := appliedVersions(ctx, pg)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMigrationFailed, err)
+	}
+
+	for _, m := range all {
+		if applied[m.version] {
+			continue
+		}
+
+		err = pgx.BeginFunc(ctx, pg, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, m.up); err != nil { //nolint:govet // govet is too pedantic for shadowing errors
+				return fmt.Errorf("could not apply %d_%s: %v", m.version, m.name, err) //nolint:errorlint,goerr113,lll // prevent err in api
+			}
+
+			_, err := tx.Exec(ctx, `INSERT INTO arrower.skeleton_migrations (version, name) VALUES ($1, $2)`, m.version, m.name)
+
+			return err //nolint:wrapcheck // the error is wrapped by the caller
+		})
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrMigrationFailed, err)
+		}
+	}
+
+	return nil
+}
+
+// Down reverts the last steps migrations, that are applied, in reverse order.
+// Each migration runs in its own transaction.
+func Down(ctx context.Context, pg *pgxpool.Pool, steps int) error {
+	all, err := load(files)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMigrationFailed, err)
+	}
+
+	return withLock(ctx, pg, func(conn *pgxpool.Conn) error {
+		return down(ctx, conn, all, steps)
+	})
+}
+
+func down(ctx context.Context, pg *pgxpool.Conn, all []migration, steps int) error {
+	err := ensureVersionTable(ctx, pg)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMigrationFailed, err)
+	}
+
+	applied, err := appliedVersions(ctx, pg)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrMigrationFailed, err)
+	}
+
+	for i := len(all) - 1; i >= 0 && steps > 0; i-- {
+		m := all[i]
+		if !applied[m.version] {
+			continue
+		}
+
+		steps--
+
+		err = pgx.BeginFunc(ctx, pg, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, m.down); err != nil { //nolint:govet // govet is too pedantic for shadowing errors
+				return fmt.Errorf("could not revert %d_%s: %v", m.version, m.name, err) //nolint:errorlint,goerr113,lll // prevent err in api
+			}
+
+			_, err := tx.Exec(ctx, `DELETE FROM arrower.skeleton_migrations WHERE version = $1`, m.version)
+
+			return err //nolint:wrapcheck // the error is wrapped by the caller
+		})
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrMigrationFailed, err)
+		}
+	}
+
+	return nil
+}
+
+// State is the state of a migration in the database.
+type State struct {
+	Name string
+	// AppliedAt is zero, if the migration is not applied yet.
+	AppliedAt time.Time
+	Version   int
+}
+
+func (s State) IsApplied() bool {
+	return !s.AppliedAt.IsZero()
+}
+
+// Status returns the State of all migrations, ordered by their version.
+func Status(ctx context.Context, pg *pgxpool.Pool) ([]State, error) {
+	all, err := load(files)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMigrationFailed, err)
+	}
+
+	err = ensureVersionTable(ctx, pg)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMigrationFailed, err)
+	}
+
+	rows, err := pg.Query(ctx, `SELECT version, applied_at FROM arrower.skeleton_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not query applied versions: %v", ErrMigrationFailed, err) //nolint:errorlint,lll // prevent err in api
+	}
+
+	appliedAt := map[int32]time.Time{}
+
+	var (
+		version int32
+		at      time.Time
+	)
+
+	_, err = pgx.ForEachRow(rows, []any{&version, &at}, func() error {
+		appliedAt[version] = at
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not read applied versions: %v", ErrMigrationFailed, err) //nolint:errorlint,lll // prevent err in api
+	}
+
+	states := make([]State, len(all))
+	for i, m := range all {
+		states[i] = State{
+			Name:      m.name,
+			AppliedAt: appliedAt[int32(m.version)], //nolint:gosec // versions are small
+			Version:   m.version,
+		}
+	}
+
+	return states, nil
+}
+
+// lockID is the key of the advisory lock held while migrating.
+const lockID = 7_263_109_457
+
+// withLock runs fn with the advisory lock held by the connection, so only one instance migrates at a time.
+func withLock(ctx context.Context, pg *pgxpool.Pool, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := pg.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: could not acquire connection: %v", ErrMigrationFailed, err) //nolint:errorlint,lll // prevent err in api
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, lockID)
+	if err != nil {
+		return fmt.Errorf("%w: could not lock: %v", ErrMigrationFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	defer conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, lockID) //nolint:errcheck,contextcheck,lll // the lock is released with the session otherwise
+
+	return fn(conn)
+}
+
+// querier is a pool or a single connection.
+type querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+func ensureVersionTable(ctx context.Context, pg querier) error {
+	_, err := pg.Exec(ctx, `
+		CREATE SCHEMA IF NOT EXISTS arrower;
+		CREATE TABLE IF NOT EXISTS arrower.skeleton_migrations
+		(
+			version    INTEGER PRIMARY KEY,
+			name       TEXT        NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		);`)
+	if err != nil {
+		return fmt.Errorf("could not create version table: %v", err) //nolint:errorlint,goerr113 // prevent err in api
+	}
+
+	return nil
+}
+
+func appliedVersions(ctx context.Context, pg querier) (map[int]bool, error) {
+	rows, err := pg.Query(ctx, `SELECT version FROM arrower.skeleton_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("could not query applied versions: %v", err) //nolint:errorlint,goerr113 // prevent err in api
+	}
+
+	versions, err := pgx.CollectRows(rows, pgx.RowTo[int32])
+	if err != nil {
+		return nil, fmt.Errorf("could not read applied versions: %v", err) //nolint:errorlint,goerr113 // prevent err in api
+	}
+
+	applied := make(map[int]bool, len(versions))
+	for _, v := range versions {
+		applied[int(v)] = true
+	}
+
+	return applied, nil
+}
+
+// load reads all migrations from the given fs and returns them ordered by their version.
+// Files have to follow the naming convention: <version>_<name>.up.sql and <version>_<name>.down.sql.
+func load(fsys fs.FS) ([]migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("could not read migrations: %v", err) //nolint:errorlint,goerr113 // prevent err in api
+	}
+
+	byVersion := map[int]*migration{}
+
+	for _, name := range names {
+		version, title, isUp, err := parseFileName(name)
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, fmt.Errorf("could not read migration: %s: %v", name, err) //nolint:errorlint,goerr113,lll // prevent err in api
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &migration{version: version, name: title} //nolint:exhaustruct // up and down are set below
+			byVersion[version] = m
+		}
+
+		if isUp {
+			m.up = string(content)
+		} else {
+			m.down = string(content)
+		}
+	}
+
+	all := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		all = append(all, *m)
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].version < all[j].version
+	})
+
+	return all, nil
+}
+
+func parseFileName(name string) (int, string, bool, error) {
+	isUp := strings.HasSuffix(name, upSuffix)
+	isDown := strings.HasSuffix(name, downSuffix)
+
+	if !isUp && !isDown {
+		return 0, "", false, fmt.Errorf("invalid migration file name: %s", name) //nolint:goerr113 // prevent err in api
+	}
+
+	base := strings.TrimSuffix(strings.TrimSuffix(name, upSuffix), downSuffix)
+
+	v, title, found := strings.Cut(base, "_")
+	if !found {
+		return 0, "", false, fmt.Errorf("invalid migration file name: %s", name) //nolint:goerr113 // prevent err in api
+	}
+
+	version, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, "", false, fmt.Errorf("invalid migration version: %s", name) //nolint:goerr113 // prevent err in api
+	}
+
+	return version, title, isUp, nil
+}
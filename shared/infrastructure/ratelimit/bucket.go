@@ -0,0 +1,96 @@
+// Package ratelimit limits the requests of each client with a token bucket.
+//
+// Each client has a bucket, that holds up to Limit.Burst tokens and is refilled with Limit.Rate tokens per Limit.Period.
+// A request takes one token, without a token left it is answered with a 429.
+// The buckets are kept by a Store: in memory for a single instance, or in Postgres to share them with all instances.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit allows Rate requests per Period, with bursts of up to Burst requests.
+// Without a Burst, up to Rate requests can be made at once.
+type Limit struct {
+	Rate   int
+	Period time.Duration
+	Burst  int
+}
+
+// Enabled returns false, if the Limit allows all requests.
+func (l Limit) Enabled() bool {
+	return l.Rate > 0 && l.Period > 0
+}
+
+func (l Limit) capacity() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+
+	return float64(l.Rate)
+}
+
+func (l Limit) perSecond() float64 {
+	return float64(l.Rate) / l.Period.Seconds()
+}
+
+// Result is the state of a bucket after a request took a token from it.
+type Result struct {
+	Allowed bool
+	// Limit is the number of tokens of the full bucket.
+	Limit     int
+	Remaining int
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until the next request is allowed, if this one is not.
+	RetryAfter time.Duration
+}
+
+// Store keeps the buckets of all clients.
+type Store interface {
+	// Take takes a token from the bucket of the key.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// take refills the bucket for the time passed since it was updated and takes a token, if one is left.
+func (b bucket) take(limit Limit, now time.Time) (bucket, Result) {
+	capacity := limit.capacity()
+	perSecond := limit.perSecond()
+
+	elapsed := now.Sub(b.updatedAt).Seconds()
+	if elapsed < 0 { // the clocks of the instances can differ
+		elapsed = 0
+	}
+
+	tokens := math.Min(capacity, b.tokens+elapsed*perSecond)
+
+	allowed := tokens >= 1
+	if allowed {
+		tokens--
+	}
+
+	res := Result{
+		Allowed:    allowed,
+		Limit:      int(capacity),
+		Remaining:  int(tokens),
+		Reset:      seconds((capacity - tokens) / perSecond),
+		RetryAfter: 0,
+	}
+
+	if !allowed {
+		res.RetryAfter = seconds((1 - tokens) / perSecond)
+	}
+
+	return bucket{tokens: tokens, updatedAt: now}, res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,88 @@
+// Use white box testing, to control the clock of the MemoryStore.
+//
+//nolint:testpackage
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore_Take(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	limit := Limit{Rate: 2, Period: time.Second, Burst: 3}
+
+	newStore := func() (*MemoryStore, *time.Time) {
+		now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		store := NewMemoryStore()
+		store.now = func() time.Time { return now }
+
+		return store, &now
+	}
+
+	t.Run("burst", func(t *testing.T) {
+		t.Parallel()
+
+		store, _ := newStore()
+
+		for i := range 3 {
+			res, err := store.Take(ctx, "a", limit)
+			assert.NoError(t, err)
+			assert.True(t, res.Allowed)
+			assert.Equal(t, 3, res.Limit)
+			assert.Equal(t, 2-i, res.Remaining)
+		}
+
+		res, _ := store.Take(ctx, "a", limit)
+		assert.False(t, res.Allowed)
+		assert.Equal(t, 0, res.Remaining)
+		assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+		assert.Equal(t, 1500*time.Millisecond, res.Reset)
+
+		res, _ = store.Take(ctx, "b", limit)
+		assert.True(t, res.Allowed, "each key has its own bucket")
+	})
+
+	t.Run("refill", func(t *testing.T) {
+		t.Parallel()
+
+		store, now := newStore()
+
+		for range 4 {
+			_, _ = store.Take(ctx, "a", limit)
+		}
+
+		*now = now.Add(500 * time.Millisecond)
+
+		res, _ := store.Take(ctx, "a", limit)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 0, res.Remaining)
+
+		*now = now.Add(time.Hour)
+
+		res, _ = store.Take(ctx, "a", limit)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 2, res.Remaining, "the bucket is not filled over its burst")
+	})
+
+	t.Run("prune full buckets", func(t *testing.T) {
+		t.Parallel()
+
+		store, now := newStore()
+
+		for i := range pruneEvery - 1 {
+			_, _ = store.Take(ctx, strconv.Itoa(i), limit)
+		}
+
+		*now = now.Add(time.Minute)
+		_, _ = store.Take(ctx, "last", limit)
+
+		assert.Len(t, store.buckets, 1)
+	})
+}
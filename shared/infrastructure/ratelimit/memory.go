@@ -0,0 +1,66 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// pruneEvery is the number of takes after which the MemoryStore drops the buckets, that are full again.
+const pruneEvery = 1000
+
+// NewMemoryStore returns a Store for a single instance.
+// With more instances, each one allows the requests of a Limit, so use the PostgresStore instead.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		mu:      sync.Mutex{},
+		buckets: map[string]memoryBucket{},
+		takes:   0,
+		now:     time.Now,
+	}
+}
+
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]memoryBucket
+	takes   int
+
+	now func() time.Time
+}
+
+type memoryBucket struct {
+	bucket
+	// fullAt is when the bucket is full again, so it can be dropped.
+	fullAt time.Time
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = memoryBucket{bucket: bucket{tokens: limit.capacity(), updatedAt: now}, fullAt: now}
+	}
+
+	updated, res := b.take(limit, now)
+	s.buckets[key] = memoryBucket{bucket: updated, fullAt: now.Add(res.Reset)}
+
+	s.takes++
+	if s.takes%pruneEvery == 0 {
+		s.prune(now)
+	}
+
+	return res, nil
+}
+
+// prune drops the buckets, that are full again, as they are the same as new ones.
+func (s *MemoryStore) prune(now time.Time) {
+	for key, b := range s.buckets {
+		if !b.fullAt.After(now) {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// New returns a Limiter, that keeps the buckets in the store.
+func New(logger alog.Logger, meterProvider metric.MeterProvider, store Store) (*Limiter, error) {
+	rejected, err := meterProvider.Meter("arrower.ratelimit").Int64Counter("ratelimit.rejected",
+		metric.WithDescription("requests rejected by a rate limit"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create rejected counter: %w", err)
+	}
+
+	return &Limiter{
+		logger:   logger.WithGroup("arrower.ratelimit"),
+		store:    store,
+		rejected: rejected,
+	}, nil
+}
+
+// Limiter limits the requests of the route groups, it is used by.
+type Limiter struct {
+	logger   alog.Logger
+	store    Store
+	rejected metric.Int64Counter
+}
+
+// KeyFunc returns who the requests are limited for, e.g. the IP of the client.
+// A request is limited by each of the keys, so it is rejected, once one of their buckets is empty.
+// It returns at least one key.
+type KeyFunc func(c echo.Context) []string
+
+// ByIP limits the requests of each IP.
+func ByIP(c echo.Context) []string {
+	return []string{"ip:" + c.RealIP()}
+}
+
+// ByUser limits the requests of each user. Requests without a user are limited by their IP.
+func ByUser(userID func(ctx context.Context) string) KeyFunc {
+	return func(c echo.Context) []string {
+		if id := userID(c.Request().Context()); id != "" {
+			return []string{"user:" + id}
+		}
+
+		return ByIP(c)
+	}
+}
+
+// ByAPIKey limits the requests of each API key, sent in the header, and of each IP.
+// The IP is limited as well, as any value of the header gets a new bucket, e.g. a random one sent with each request.
+// The key is hashed, so it is not stored.
+func ByAPIKey(header string) KeyFunc {
+	return func(c echo.Context) []string {
+		key := c.Request().Header.Get(header)
+		if key == "" {
+			return ByIP(c)
+		}
+
+		sum := sha256.Sum256([]byte(key))
+
+		return append(ByIP(c), "key:"+hex.EncodeToString(sum[:]))
+	}
+}
+
+// Middleware limits the requests of each key to the limit. Limits with another name have their own buckets,
+// so each route group can be limited on its own. The state of the bucket is sent in the RateLimit headers,
+// a rejected request is answered with a 429 and a Retry-After header.
+// If the Store fails, the request is served, so a database outage does not lock out all clients.
+func (l *Limiter) Middleware(name string, limit Limit, key KeyFunc) echo.MiddlewareFunc {
+	policy := fmt.Sprintf("%d;w=%d", int(limit.capacity()), int(limit.Period.Seconds()))
+	attrs := metric.WithAttributes(attribute.String("limit", name))
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if !limit.Enabled() {
+			return next
+		}
+
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+
+			res, err := l.take(ctx, name, key(c), limit)
+			if err != nil {
+				l.logger.LogAttrs(ctx, slog.LevelWarn, "could not take token, request is served",
+					slog.String("limit", name),
+					slog.String("err", err.Error()),
+				)
+
+				return next(c)
+			}
+
+			header := c.Response().Header()
+			header.Set("RateLimit-Policy", policy)
+			header.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			header.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+
+			if !res.Allowed {
+				l.rejected.Add(ctx, 1, attrs)
+				header.Set("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+
+				return echo.NewHTTPError(http.StatusTooManyRequests) //nolint:wrapcheck // answered by the error handler
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// take takes a token from the bucket of each key. The request is allowed, if all buckets allow it,
+// and the Result has the fewest remaining tokens and the longest wait of all buckets.
+func (l *Limiter) take(ctx context.Context, name string, keys []string, limit Limit) (Result, error) {
+	var res Result
+
+	for i, key := range keys {
+		r, err := l.store.Take(ctx, name+":"+key, limit)
+		if err != nil {
+			return Result{}, err //nolint:wrapcheck // logged by the caller
+		}
+
+		if i == 0 {
+			res = r
+
+			continue
+		}
+
+		res.Allowed = res.Allowed && r.Allowed
+		res.Remaining = min(res.Remaining, r.Remaining)
+		res.Reset = max(res.Reset, r.Reset)
+		res.RetryAfter = max(res.RetryAfter, r.RetryAfter)
+	}
+
+	return res, nil
+}
+
+// ceilSeconds rounds up, so a client retrying after the seconds is allowed.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
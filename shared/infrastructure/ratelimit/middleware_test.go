@@ -0,0 +1,177 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/ratelimit"
+)
+
+func TestLimiter_Middleware(t *testing.T) {
+	t.Parallel()
+
+	limit := ratelimit.Limit{Rate: 2, Period: time.Minute, Burst: 0}
+
+	serve := func(mw echo.MiddlewareFunc, req *http.Request) *httptest.ResponseRecorder {
+		e := echo.New()
+		e.IPExtractor = echo.ExtractIPDirect()
+		e.Use(mw)
+		e.Any("/*", func(c echo.Context) error { return c.String(http.StatusOK, "ok") })
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	request := func(ip string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+		req.RemoteAddr = ip + ":1234"
+
+		return req
+	}
+
+	t.Run("limit by ip", func(t *testing.T) {
+		t.Parallel()
+
+		reader := metric.NewManualReader()
+		limiter, err := ratelimit.New(alog.NewNoopLogger(), metric.NewMeterProvider(metric.WithReader(reader)), ratelimit.NewMemoryStore())
+		assert.NoError(t, err)
+
+		mw := limiter.Middleware("auth", limit, ratelimit.ByIP)
+
+		rec := serve(mw, request("10.0.0.1"))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "2;w=60", rec.Header().Get("RateLimit-Policy"))
+		assert.Equal(t, "2", rec.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "1", rec.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "30", rec.Header().Get("RateLimit-Reset"))
+
+		_ = serve(mw, request("10.0.0.1"))
+		rec = serve(mw, request("10.0.0.1"))
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "30", rec.Header().Get("Retry-After"))
+
+		rec = serve(mw, request("10.0.0.2"))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		data := metricdata.ResourceMetrics{}
+		_ = reader.Collect(context.Background(), &data)
+		assert.Len(t, data.ScopeMetrics, 1)
+		sum, _ := data.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[int64])
+		assert.Equal(t, int64(1), sum.DataPoints[0].Value)
+	})
+
+	t.Run("limit by api key", func(t *testing.T) {
+		t.Parallel()
+
+		limiter, _ := ratelimit.New(alog.NewNoopLogger(), metric.NewMeterProvider(), ratelimit.NewMemoryStore())
+		mw := limiter.Middleware("api", limit, ratelimit.ByAPIKey(echo.HeaderAuthorization))
+
+		withKey := func(key string) *http.Request {
+			req := request("10.0.0.1")
+			req.Header.Set(echo.HeaderAuthorization, key)
+
+			return req
+		}
+
+		_ = serve(mw, withKey("a"))
+		_ = serve(mw, withKey("a"))
+		assert.Equal(t, http.StatusTooManyRequests, serve(mw, withKey("a")).Code)
+		assert.Equal(t, http.StatusTooManyRequests, serve(mw, withKey("b")).Code, "same ip with another key")
+
+		req := withKey("a")
+		req.RemoteAddr = "10.0.0.2:1234"
+		assert.Equal(t, http.StatusTooManyRequests, serve(mw, req).Code, "same key from another ip")
+
+		req = withKey("b")
+		req.RemoteAddr = "10.0.0.2:1234"
+		assert.Equal(t, http.StatusOK, serve(mw, req).Code, "another key from another ip")
+	})
+
+	t.Run("varying api key", func(t *testing.T) {
+		t.Parallel()
+
+		limiter, _ := ratelimit.New(alog.NewNoopLogger(), metric.NewMeterProvider(), ratelimit.NewMemoryStore())
+		mw := limiter.Middleware("api", limit, ratelimit.ByAPIKey(echo.HeaderAuthorization))
+
+		for i := range 5 {
+			req := request("10.0.0.1")
+			req.Header.Set(echo.HeaderAuthorization, "random-"+strconv.Itoa(i))
+
+			rec := serve(mw, req)
+			if i < limit.Rate {
+				assert.Equal(t, http.StatusOK, rec.Code)
+			} else {
+				assert.Equal(t, http.StatusTooManyRequests, rec.Code, "a new key does not escape the limit of the ip")
+				assert.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+			}
+		}
+	})
+
+	t.Run("limit by user", func(t *testing.T) {
+		t.Parallel()
+
+		limiter, _ := ratelimit.New(alog.NewNoopLogger(), metric.NewMeterProvider(), ratelimit.NewMemoryStore())
+		mw := limiter.Middleware("web", ratelimit.Limit{Rate: 1, Period: time.Minute, Burst: 0},
+			ratelimit.ByUser(func(ctx context.Context) string {
+				id, _ := ctx.Value(ctxUser{}).(string)
+
+				return id
+			}),
+		)
+
+		withUser := func(id string) *http.Request {
+			req := request("10.0.0.1")
+
+			return req.WithContext(context.WithValue(req.Context(), ctxUser{}, id))
+		}
+
+		assert.Equal(t, http.StatusOK, serve(mw, withUser("1")).Code)
+		assert.Equal(t, http.StatusTooManyRequests, serve(mw, withUser("1")).Code)
+		assert.Equal(t, http.StatusOK, serve(mw, withUser("2")).Code)
+		assert.Equal(t, http.StatusOK, serve(mw, withUser("")).Code, "anonymous by ip")
+	})
+
+	t.Run("disabled limit", func(t *testing.T) {
+		t.Parallel()
+
+		limiter, _ := ratelimit.New(alog.NewNoopLogger(), metric.NewMeterProvider(), failingStore{})
+		mw := limiter.Middleware("auth", ratelimit.Limit{}, ratelimit.ByIP) //nolint:exhaustruct // no limit
+
+		rec := serve(mw, request("10.0.0.1"))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Header().Get("RateLimit-Limit"))
+	})
+
+	t.Run("failing store serves the request", func(t *testing.T) {
+		t.Parallel()
+
+		limiter, _ := ratelimit.New(alog.NewNoopLogger(), metric.NewMeterProvider(), failingStore{})
+		mw := limiter.Middleware("auth", limit, ratelimit.ByIP)
+
+		assert.Equal(t, http.StatusOK, serve(mw, request("10.0.0.1")).Code)
+	})
+}
+
+type ctxUser struct{}
+
+var errStore = errors.New("store failed")
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errStore //nolint:exhaustruct // failed
+}
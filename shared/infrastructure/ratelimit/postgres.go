@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// NewPostgresStore returns a Store, that shares the buckets with all instances.
+// Schedule the PruneJob, so the buckets, that are full again, are dropped.
+func NewPostgresStore(pg *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{pg: pg}
+}
+
+type PostgresStore struct {
+	pg *pgxpool.Pool
+}
+
+// PruneJob drops the buckets of the PostgresStore, that are full again, see PostgresStore.Prune.
+type PruneJob struct{}
+
+func (PruneJob) JobType() string { return "ratelimit.prune" }
+
+// Take locks the bucket of the key, so the requests to all instances take their tokens one after the other.
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	tx, err := s.pg.Begin(ctx)
+	if err != nil {
+		return Result{}, fmt.Errorf("could not begin transaction: %w", err)
+	}
+
+	defer tx.Rollback(ctx) //nolint:errcheck // a committed transaction can not be rolled back
+
+	var (
+		b   bucket
+		now time.Time
+	)
+
+	// a new bucket is full, an existing one is locked until the transaction ends
+	err = tx.QueryRow(ctx, `
+		INSERT INTO arrower.rate_limits (key, tokens, updated_at, full_at) VALUES ($1, $2, NOW(), NOW())
+		ON CONFLICT (key) DO UPDATE SET key = EXCLUDED.key
+		RETURNING tokens, updated_at, NOW()`,
+		key, limit.capacity(),
+	).Scan(&b.tokens, &b.updatedAt, &now)
+	if err != nil {
+		return Result{}, fmt.Errorf("could not get bucket: %w", err)
+	}
+
+	updated, res := b.take(limit, now)
+
+	_, err = tx.Exec(ctx, `UPDATE arrower.rate_limits SET tokens = $2, updated_at = $3, full_at = $4 WHERE key = $1`,
+		key, updated.tokens, updated.updatedAt, now.Add(res.Reset),
+	)
+	if err != nil {
+		return Result{}, fmt.Errorf("could not update bucket: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Result{}, fmt.Errorf("could not commit bucket: %w", err)
+	}
+
+	return res, nil
+}
+
+// Prune drops the buckets, that are full again, as they are the same as new ones.
+// It is not run by Take, so the requests do not wait for it.
+func (s *PostgresStore) Prune(ctx context.Context) error {
+	_, err := s.pg.Exec(ctx, `DELETE FROM arrower.rate_limits WHERE full_at < NOW()`)
+	if err != nil {
+		return fmt.Errorf("could not prune rate limits: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+//go:build integration
+
+package ratelimit_test
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-arrower/arrower/tests"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/migrations"
+	"github.com/go-arrower/skeleton/shared/infrastructure/ratelimit"
+)
+
+var pgHandler *tests.PostgresDocker
+
+func TestMain(m *testing.M) {
+	pgHandler = tests.GetPostgresDockerForIntegrationTestingInstance()
+
+	//
+	// Run tests
+	code := m.Run()
+
+	pgHandler.Cleanup()
+	os.Exit(code)
+}
+
+func TestPostgresStore_Take(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("new bucket", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		_ = migrations.Up(ctx, pg)
+		store := ratelimit.NewPostgresStore(pg)
+
+		limit := ratelimit.Limit{Rate: 2, Period: time.Minute, Burst: 3}
+
+		res, err := store.Take(ctx, "a", limit)
+		assert.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 3, res.Limit)
+		assert.Equal(t, 2, res.Remaining, "a new bucket is full")
+
+		_, _ = store.Take(ctx, "a", limit)
+		_, _ = store.Take(ctx, "a", limit)
+
+		res, err = store.Take(ctx, "a", limit)
+		assert.NoError(t, err)
+		assert.False(t, res.Allowed)
+		assert.Positive(t, res.RetryAfter)
+
+		res, _ = store.Take(ctx, "b", limit)
+		assert.True(t, res.Allowed, "each key has its own bucket")
+	})
+
+	t.Run("refill", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		_ = migrations.Up(ctx, pg)
+		store := ratelimit.NewPostgresStore(pg)
+
+		limit := ratelimit.Limit{Rate: 10, Period: time.Second, Burst: 1}
+
+		res, _ := store.Take(ctx, "a", limit)
+		assert.True(t, res.Allowed)
+
+		res, _ = store.Take(ctx, "a", limit)
+		assert.False(t, res.Allowed)
+
+		time.Sleep(150 * time.Millisecond)
+
+		res, err := store.Take(ctx, "a", limit)
+		assert.NoError(t, err)
+		assert.True(t, res.Allowed, "a token is refilled every 100ms")
+	})
+
+	t.Run("concurrent takes", func(t *testing.T) {
+		t.Parallel()
+
+		pg := pgHandler.NewTestDatabase()
+		_ = migrations.Up(ctx, pg)
+		store := ratelimit.NewPostgresStore(pg)
+
+		limit := ratelimit.Limit{Rate: 5, Period: time.Hour, Burst: 0}
+
+		var (
+			wg      sync.WaitGroup
+			allowed atomic.Int64
+		)
+
+		for range 20 {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				res, err := store.Take(ctx, "a", limit)
+				if err == nil && res.Allowed {
+					allowed.Add(1)
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		assert.Equal(t, int64(5), allowed.Load(), "the instances share the bucket and do not over-allow")
+	})
+}
+
+func TestPostgresStore_Prune(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	pg := pgHandler.NewTestDatabase()
+	_ = migrations.Up(ctx, pg)
+	store := ratelimit.NewPostgresStore(pg)
+
+	_, _ = store.Take(ctx, "full", ratelimit.Limit{Rate: 1000, Period: time.Second, Burst: 1})
+	_, _ = store.Take(ctx, "empty", ratelimit.Limit{Rate: 1, Period: time.Hour, Burst: 0})
+
+	time.Sleep(10 * time.Millisecond)
+
+	err := store.Prune(ctx)
+	assert.NoError(t, err)
+
+	var keys []string
+
+	rows, _ := pg.Query(ctx, `SELECT key FROM arrower.rate_limits`)
+	for rows.Next() {
+		var key string
+		_ = rows.Scan(&key)
+		keys = append(keys, key)
+	}
+
+	assert.Equal(t, []string{"empty"}, keys, "only the buckets, that are full again, are dropped")
+}
@@ -2,47 +2,262 @@ package infrastructure
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"strings"
 	"time"
+
+	prometheus2 "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-func getSystemStatus(di *Container, serverStartedAt time.Time) interface{} {
-	uptime := time.Since(serverStartedAt).Round(time.Second)
+const (
+	metricPath = "/metrics"
+	statusPath = "/status"
+	livePath   = "/livez"
+	readyPath  = "/readyz"
+	pprofPath  = "/debug/pprof/"
+)
 
-	dbOnline := "online"
+func newStatusServer(ctx context.Context, di *Container) *http.Server {
+	addr := fmt.Sprintf(":%d", di.Config.Web.StatusEndpointPort)
+
+	di.Logger.InfoContext(ctx, "serving status endpoint",
+		slog.String("addr", addr),
+		slog.String("metric_path", metricPath),
+		slog.String("status_path", statusPath),
+		slog.String("live_path", livePath),
+		slog.String("ready_path", readyPath),
+		slog.Bool("pprof", di.Config.Status.Pprof),
+		slog.Bool("protected", isProtected(di.Config.Status)),
+	)
+
+	return &http.Server{ //nolint:exhaustruct
+		Addr:              addr,
+		Handler:           NewStatusHandler(di),
+		ReadHeaderTimeout: 10 * time.Second, //nolint:gomnd
+		ReadTimeout:       10 * time.Second, //nolint:gomnd
+		WriteTimeout:      time.Minute,      // longer than the default of 30s of the cpu profile of pprof
+		IdleTimeout:       2 * time.Minute,  //nolint:gomnd
+	}
+}
+
+// NewStatusHandler returns the handler of the status endpoint.
+// All paths, except the probes, are protected as configured in Config.Status.
+func NewStatusHandler(di *Container) http.Handler {
+	protected := http.NewServeMux()
+
+	// protected.Handle("/metrics", promhttp.Handler())
+	protected.Handle(metricPath, promhttp.HandlerFor(
+		prometheus2.DefaultGatherer,
+		promhttp.HandlerOpts{ //nolint:exhaustruct
+			EnableOpenMetrics: true, // to enable Examplars in the export format
+		},
+	))
+
+	protected.HandleFunc(statusPath, func(w http.ResponseWriter, r *http.Request) {
+		report := di.Health.Check(r.Context())
+
+		writeHealth(w, report.StatusCode(), getSystemStatus(di, report))
+	})
+
+	// the profiles expose the internals of the application, so they are never served without protection
+	if di.Config.Status.Pprof && isProtected(di.Config.Status) {
+		protected.HandleFunc(pprofPath, pprof.Index)
+		protected.HandleFunc(pprofPath+"cmdline", pprof.Cmdline)
+		protected.HandleFunc(pprofPath+"profile", pprof.Profile)
+		protected.HandleFunc(pprofPath+"symbol", pprof.Symbol)
+		protected.HandleFunc(pprofPath+"trace", pprof.Trace)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(livePath, di.Health.LivenessHandler())
+	mux.Handle(readyPath, di.Health.ReadinessHandler())
+	mux.Handle("/", ProtectStatus(di.Config.Status, protected))
+
+	return mux
+}
+
+// ProtectStatus only serves requests from the allowed IPs, that authenticate with basic or bearer auth.
+// Without any protection configured, all requests are served.
+//
+// The IP is the remote address of the connection, as the status endpoint is not expected behind a proxy
+// and headers like X-Forwarded-For can be set by anyone.
+func ProtectStatus(conf Status, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(conf.AllowedIPs) > 0 && !isAllowedIP(remoteIP(r), conf.AllowedIPs) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
 
-	err := di.PGx.Ping(context.Background())
+			return
+		}
+
+		if requiresAuth(conf) && !isAuthenticated(conf, r) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="status"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func isProtected(conf Status) bool {
+	return len(conf.AllowedIPs) > 0 || requiresAuth(conf)
+}
+
+func requiresAuth(conf Status) bool {
+	return conf.User != "" || conf.Token.Secret() != ""
+}
+
+func isAuthenticated(conf Status, r *http.Request) bool {
+	if token := conf.Token.Secret(); token != "" {
+		if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+			return subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) == 1
+		}
+	}
+
+	if conf.User != "" {
+		if user, password, ok := r.BasicAuth(); ok {
+			validUser := subtle.ConstantTimeCompare([]byte(user), []byte(conf.User)) == 1
+			validPassword := subtle.ConstantTimeCompare([]byte(password), []byte(conf.Password.Secret())) == 1
+
+			return validUser && validPassword
+		}
+	}
+
+	return false
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
 	if err != nil {
-		dbOnline = fmt.Errorf("err: %w", err).Error()
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+// isAllowedIP returns true, if ip matches one of the allowed IPs or CIDRs.
+func isAllowedIP(ip string, allowed []string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+
+	for _, a := range allowed {
+		if _, network, err := net.ParseCIDR(a); err == nil {
+			if network.Contains(addr) {
+				return true
+			}
+
+			continue
+		}
+
+		if allowedAddr := net.ParseIP(a); allowedAddr != nil && allowedAddr.Equal(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func getSystemStatus(di *Container, report HealthReport) interface{} {
+	dbOnline := "online"
+	if check, ok := report.Checks["postgres"]; ok && check.Status != HealthOK {
+		dbOnline = "err: " + check.Error
 	}
 
 	statusData := map[string]any{
-		"status":           "online", // later: maintenance mode, degraded etc.
-		"time":             time.Now(),
-		"uptime":           uptime.String(),
-		"gitCommit":        "", // todo what is the difference to the hash? Does it mean git tag instead?
-		"gitHash":          "", // todo
+		"status":           report.Status,
+		"time":             report.Time,
+		"uptime":           report.Uptime,
+		"gitCommit":        shortHash(report.GitHash),
+		"gitHash":          report.GitHash,
 		"organisationName": di.Config.OrganisationName,
 		"applicationName":  di.Config.ApplicationName,
 		"instanceName":     di.Config.InstanceName,
 		"debug":            di.Config.Debug,
 
-		"web":      di.Config.Web,
-		"database": dbStatus{Postgres: di.Config.Postgres, Status: dbOnline},
+		"database": dbStatus{Status: dbOnline},
+		"checks":   report.Checks,
+		"runtime":  report.Runtime,
 		// s3
 		// REST API
 		// feature flags
-		// queues
-		// memory consumption
 
-		"failures": map[string]any{},
+		"failures": failures(report),
+	}
+
+	if di.Config.Status.ShowConfig {
+		statusData["config"] = statusConfig(di.Config)
 	}
 
 	return statusData
 }
 
 type dbStatus struct {
-	Postgres
 	Status string `json:"status"`
 	// average response time (?)
 }
+
+// statusConfig returns the config shown in the status payload.
+// Everything, that helps to attack the infrastructure, is redacted: hosts, users, and secrets.
+func statusConfig(conf *Config) map[string]any {
+	web := conf.Web
+	web.Hostname = redacted
+
+	pg := conf.Postgres
+	pg.User = redacted
+	pg.Host = redacted
+	pg.Database = redacted
+
+	otel := conf.OTEL
+	otel.Host = redacted
+	otel.TracesFile = redactPath(otel.TracesFile)
+	otel.MetricsFile = redactPath(otel.MetricsFile)
+
+	return map[string]any{
+		"role":       conf.Role,
+		"web":        web,
+		"postgres":   pg,
+		"otel":       otel,
+		"mail":       map[string]any{"enabled": conf.Mail.Host != ""},
+		"rateLimits": conf.RateLimits,
+	}
+}
+
+func redactPath(path string) string {
+	if path == "" {
+		return ""
+	}
+
+	return redacted
+}
+
+// failures returns the errors of all failed checks by their name.
+func failures(report HealthReport) map[string]any {
+	failed := map[string]any{}
+
+	for name, check := range report.Checks {
+		if check.Status != HealthOK {
+			failed[name] = check.Error
+		}
+	}
+
+	return failed
+}
+
+func shortHash(hash string) string {
+	const length = 7
+
+	if len(hash) < length {
+		return hash
+	}
+
+	return hash[:length]
+}
@@ -0,0 +1,147 @@
+package infrastructure_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-arrower/arrower/secret"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure"
+)
+
+func TestProtectStatus(t *testing.T) {
+	t.Parallel()
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	tests := map[string]struct {
+		conf   infrastructure.Status
+		req    func(r *http.Request)
+		expect int
+	}{
+		"unprotected": {
+			conf:   infrastructure.Status{},
+			req:    func(_ *http.Request) {},
+			expect: http.StatusOK,
+		},
+		"basic auth": {
+			conf:   infrastructure.Status{User: "prometheus", Password: secret.New("secret")},
+			req:    func(r *http.Request) { r.SetBasicAuth("prometheus", "secret") },
+			expect: http.StatusOK,
+		},
+		"wrong password": {
+			conf:   infrastructure.Status{User: "prometheus", Password: secret.New("secret")},
+			req:    func(r *http.Request) { r.SetBasicAuth("prometheus", "wrong") },
+			expect: http.StatusUnauthorized,
+		},
+		"bearer token": {
+			conf:   infrastructure.Status{Token: secret.New("token")},
+			req:    func(r *http.Request) { r.Header.Set("Authorization", "Bearer token") },
+			expect: http.StatusOK,
+		},
+		"missing auth": {
+			conf:   infrastructure.Status{Token: secret.New("token")},
+			req:    func(_ *http.Request) {},
+			expect: http.StatusUnauthorized,
+		},
+		"allowed ip": {
+			conf:   infrastructure.Status{AllowedIPs: []string{"10.0.0.0/8"}},
+			req:    func(r *http.Request) { r.RemoteAddr = "10.1.2.3:1234" },
+			expect: http.StatusOK,
+		},
+		"forwarded ip is ignored": {
+			conf: infrastructure.Status{AllowedIPs: []string{"10.1.2.3"}},
+			req: func(r *http.Request) {
+				r.RemoteAddr = "192.168.0.1:1234"
+				r.Header.Set("X-Forwarded-For", "10.1.2.3")
+			},
+			expect: http.StatusForbidden,
+		},
+		"allowed ip without auth": {
+			conf:   infrastructure.Status{AllowedIPs: []string{"10.1.2.3"}, Token: secret.New("token")},
+			req:    func(r *http.Request) { r.RemoteAddr = "10.1.2.3:1234" },
+			expect: http.StatusUnauthorized,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(http.MethodGet, "/status", nil)
+			tt.req(req)
+
+			rec := httptest.NewRecorder()
+			infrastructure.ProtectStatus(tt.conf, ok).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expect, rec.Code)
+		})
+	}
+}
+
+func TestNewStatusHandler(t *testing.T) {
+	t.Parallel()
+
+	newContainer := func(status infrastructure.Status) *infrastructure.Container {
+		conf := infrastructure.DefaultConfig()
+		conf.Status = status
+
+		return &infrastructure.Container{Config: conf, Health: infrastructure.NewHealth()} //nolint:exhaustruct
+	}
+
+	serve := func(handler http.Handler, path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		return rec
+	}
+
+	t.Run("probes are not protected", func(t *testing.T) {
+		t.Parallel()
+
+		handler := infrastructure.NewStatusHandler(newContainer(infrastructure.Status{Token: secret.New("token")}))
+
+		assert.Equal(t, http.StatusOK, serve(handler, "/livez").Code)
+		assert.Equal(t, http.StatusOK, serve(handler, "/readyz").Code)
+		assert.Equal(t, http.StatusUnauthorized, serve(handler, "/status").Code)
+		assert.Equal(t, http.StatusUnauthorized, serve(handler, "/metrics").Code)
+	})
+
+	t.Run("pprof", func(t *testing.T) {
+		t.Parallel()
+
+		handler := infrastructure.NewStatusHandler(newContainer(infrastructure.Status{AllowedIPs: []string{"192.0.2.1"}}))
+		assert.Equal(t, http.StatusNotFound, serve(handler, "/debug/pprof/").Code)
+
+		handler = infrastructure.NewStatusHandler(newContainer(infrastructure.Status{Pprof: true, AllowedIPs: []string{"192.0.2.1"}}))
+		assert.Equal(t, http.StatusOK, serve(handler, "/debug/pprof/").Code)
+
+		handler = infrastructure.NewStatusHandler(newContainer(infrastructure.Status{Pprof: true}))
+		assert.Equal(t, http.StatusNotFound, serve(handler, "/debug/pprof/").Code, "never served without protection")
+	})
+
+	t.Run("redact config", func(t *testing.T) {
+		t.Parallel()
+
+		rec := serve(infrastructure.NewStatusHandler(newContainer(infrastructure.Status{})), "/status")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "config")
+		assert.NotContains(t, rec.Body.String(), "localhost")
+
+		di := newContainer(infrastructure.Status{ShowConfig: true})
+		di.Config.Web.Hostname = "www.example.com"
+
+		rec = serve(infrastructure.NewStatusHandler(di), "/status")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "localhost", "postgres and otel hosts are redacted")
+		assert.NotContains(t, rec.Body.String(), "www.example.com", "the web hostname is redacted")
+		assert.NotContains(t, rec.Body.String(), `"user":"arrower"`)
+
+		var status map[string]any
+		_ = json.Unmarshal(rec.Body.Bytes(), &status)
+		assert.Contains(t, status, "config")
+	})
+}
@@ -0,0 +1,201 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// The exporters of the traces and metrics, see OTEL.
+const (
+	ExporterNone       = "none"
+	ExporterStdout     = "stdout"
+	ExporterFile       = "file"
+	ExporterOTLPGRPC   = "otlp-grpc"
+	ExporterOTLPHTTP   = "otlp-http"
+	ExporterPrometheus = "prometheus"
+)
+
+// debugBatchTimeout exports the spans quickly in debug mode, so they show up while developing.
+const debugBatchTimeout = time.Second
+
+// NewTracerProvider returns a TracerProvider, that exports the spans with the exporter configured in conf.
+// It does not wait for the collector to be reachable, so the application starts without one.
+// In debug mode, all traces are sampled.
+func NewTracerProvider(ctx context.Context, conf OTEL, res *resource.Resource, debug bool) (*trace.TracerProvider, error) {
+	sampler := trace.ParentBased(trace.TraceIDRatioBased(conf.SampleRatio))
+	if debug {
+		sampler = trace.AlwaysSample()
+	}
+
+	opts := []trace.TracerProviderOption{
+		trace.WithResource(res),
+		trace.WithSampler(sampler),
+	}
+
+	exporter, err := newSpanExporter(ctx, conf, debug)
+	if err != nil {
+		return nil, err
+	}
+
+	if exporter != nil {
+		var batchOpts []trace.BatchSpanProcessorOption
+		if debug {
+			batchOpts = append(batchOpts, trace.WithBatchTimeout(debugBatchTimeout))
+		}
+
+		opts = append(opts, trace.WithBatcher(exporter, batchOpts...))
+	}
+
+	return trace.NewTracerProvider(opts...), nil
+}
+
+// newSpanExporter returns the exporter configured in conf, or nil if no spans are exported.
+func newSpanExporter(ctx context.Context, conf OTEL, debug bool) (trace.SpanExporter, error) { //nolint:ireturn,lll // the exporter is chosen by the config
+	endpoint := conf.Endpoint()
+	insecure := conf.Insecure || debug
+
+	switch conf.Traces {
+	case ExporterNone:
+		return nil, nil //nolint:nilnil // no exporter is valid
+	case ExporterStdout, ExporterFile:
+		w, err := openTelemetryOutput(conf.Traces, conf.TracesFile)
+		if err != nil {
+			return nil, err
+		}
+
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(w))
+		if err != nil {
+			return nil, fmt.Errorf("could not create trace exporter: %w", err)
+		}
+
+		return fileSpanExporter{Exporter: exporter, close: closeOnShutdown(w)}, nil
+	case ExporterOTLPGRPC:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint)}
+		if insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+
+		exporter, err := otlptracegrpc.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("could not create trace exporter: %w", err)
+		}
+
+		return exporter, nil
+	case ExporterOTLPHTTP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint)}
+		if insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+
+		exporter, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("could not create trace exporter: %w", err)
+		}
+
+		return exporter, nil
+	}
+
+	return nil, fmt.Errorf("%w: unknown trace exporter: %s", ErrInvalidConfig, conf.Traces)
+}
+
+// NewMeterProvider returns a MeterProvider, that exports the metrics with the exporter configured in conf.
+// The prometheus metrics are served by the status endpoint.
+func NewMeterProvider(conf OTEL, res *resource.Resource) (*metric.MeterProvider, error) {
+	opts := []metric.Option{metric.WithResource(res)}
+
+	switch conf.Metrics {
+	case ExporterNone:
+	case ExporterPrometheus:
+		exporter, err := prometheus.New()
+		if err != nil {
+			return nil, fmt.Errorf("could not create prometheus exporter: %w", err)
+		}
+
+		opts = append(opts, metric.WithReader(exporter))
+	case ExporterStdout, ExporterFile:
+		w, err := openTelemetryOutput(conf.Metrics, conf.MetricsFile)
+		if err != nil {
+			return nil, err
+		}
+
+		exporter, err := stdoutmetric.New(stdoutmetric.WithWriter(w))
+		if err != nil {
+			return nil, fmt.Errorf("could not create metric exporter: %w", err)
+		}
+
+		opts = append(opts, metric.WithReader(metric.NewPeriodicReader(fileMetricExporter{Exporter: exporter, close: closeOnShutdown(w)})))
+	default:
+		return nil, fmt.Errorf("%w: unknown metric exporter: %s", ErrInvalidConfig, conf.Metrics)
+	}
+
+	return metric.NewMeterProvider(opts...), nil
+}
+
+// resourceLogAttrs returns the attributes of the resource, so the logs can be correlated with the traces and metrics.
+func resourceLogAttrs(res *resource.Resource) []any {
+	attrs := make([]any, 0, res.Len())
+
+	for _, kv := range res.Attributes() {
+		attrs = append(attrs, slog.String(string(kv.Key), kv.Value.Emit()))
+	}
+
+	return attrs
+}
+
+func openTelemetryOutput(exporter string, path string) (io.Writer, error) {
+	if exporter == ExporterStdout {
+		return os.Stdout, nil
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600) //nolint:gomnd // only the app reads it
+	if err != nil {
+		return nil, fmt.Errorf("could not open telemetry file: %w", err)
+	}
+
+	return f, nil
+}
+
+// closeOnShutdown closes the file of an exporter, when it is shut down. Stdout is kept open.
+func closeOnShutdown(w io.Writer) func() error {
+	return func() error {
+		if f, ok := w.(*os.File); ok && f != os.Stdout {
+			return f.Close() //nolint:wrapcheck // reported by the caller
+		}
+
+		return nil
+	}
+}
+
+// fileSpanExporter closes the file of the stdout exporter on shutdown.
+type fileSpanExporter struct {
+	*stdouttrace.Exporter
+	close func() error
+}
+
+func (e fileSpanExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.Exporter.Shutdown(ctx), e.close())
+}
+
+// fileMetricExporter closes the file of the stdout exporter on shutdown.
+type fileMetricExporter struct {
+	metric.Exporter
+	close func() error
+}
+
+func (e fileMetricExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.Exporter.Shutdown(ctx), e.close())
+}
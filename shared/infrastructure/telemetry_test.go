@@ -0,0 +1,129 @@
+package infrastructure_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/sdk/resource"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure"
+)
+
+func TestNewTracerProvider(t *testing.T) {
+	t.Parallel()
+
+	t.Run("none", func(t *testing.T) {
+		t.Parallel()
+
+		conf := infrastructure.DefaultConfig().OTEL
+		conf.Traces = infrastructure.ExporterNone
+		conf.Host = ""
+
+		tp, err := infrastructure.NewTracerProvider(context.Background(), conf, resource.Empty(), false)
+		assert.NoError(t, err)
+
+		_, span := tp.Tracer("test").Start(context.Background(), "span")
+		span.End()
+
+		assert.NoError(t, tp.Shutdown(context.Background()))
+	})
+
+	t.Run("file", func(t *testing.T) {
+		t.Parallel()
+
+		conf := infrastructure.DefaultConfig().OTEL
+		conf.Traces = infrastructure.ExporterFile
+		conf.TracesFile = filepath.Join(t.TempDir(), "traces.json")
+
+		tp, err := infrastructure.NewTracerProvider(context.Background(), conf, resource.Empty(), true)
+		assert.NoError(t, err)
+
+		_, span := tp.Tracer("test").Start(context.Background(), "some-span")
+		span.End()
+
+		err = tp.Shutdown(context.Background()) // flushes the spans
+		assert.NoError(t, err)
+
+		b, err := os.ReadFile(conf.TracesFile)
+		assert.NoError(t, err)
+		assert.Contains(t, string(b), `"Name":"some-span"`)
+	})
+
+	t.Run("otlp does not block without a collector", func(t *testing.T) {
+		t.Parallel()
+
+		conf := infrastructure.DefaultConfig().OTEL
+		conf.Traces = infrastructure.ExporterOTLPHTTP
+		conf.Port = 1
+
+		tp, err := infrastructure.NewTracerProvider(context.Background(), conf, resource.Empty(), false)
+		assert.NoError(t, err)
+		assert.NotNil(t, tp)
+	})
+}
+
+func TestOTEL_Endpoint(t *testing.T) {
+	t.Parallel()
+
+	conf := infrastructure.DefaultConfig().OTEL
+	conf.Host = "collector"
+
+	conf.Traces = infrastructure.ExporterOTLPGRPC
+	assert.Equal(t, "collector:4317", conf.Endpoint())
+
+	conf.Traces = infrastructure.ExporterOTLPHTTP
+	assert.Equal(t, "collector:4318", conf.Endpoint())
+
+	conf.Port = 1234
+	assert.Equal(t, "collector:1234", conf.Endpoint(), "the port is configured")
+}
+
+func TestNewMeterProvider(t *testing.T) {
+	t.Parallel()
+
+	t.Run("none", func(t *testing.T) {
+		t.Parallel()
+
+		conf := infrastructure.DefaultConfig().OTEL
+		conf.Metrics = infrastructure.ExporterNone
+
+		mp, err := infrastructure.NewMeterProvider(conf, resource.Empty())
+		assert.NoError(t, err)
+		assert.NoError(t, mp.Shutdown(context.Background()))
+	})
+
+	t.Run("file", func(t *testing.T) {
+		t.Parallel()
+
+		conf := infrastructure.DefaultConfig().OTEL
+		conf.Metrics = infrastructure.ExporterFile
+		conf.MetricsFile = filepath.Join(t.TempDir(), "metrics.json")
+
+		mp, err := infrastructure.NewMeterProvider(conf, resource.Empty())
+		assert.NoError(t, err)
+
+		counter, err := mp.Meter("test").Int64Counter("some_counter")
+		assert.NoError(t, err)
+		counter.Add(context.Background(), 1)
+
+		err = mp.Shutdown(context.Background()) // exports the metrics a last time
+		assert.NoError(t, err)
+
+		b, err := os.ReadFile(conf.MetricsFile)
+		assert.NoError(t, err)
+		assert.Contains(t, string(b), "some_counter")
+	})
+
+	t.Run("unknown exporter", func(t *testing.T) {
+		t.Parallel()
+
+		conf := infrastructure.DefaultConfig().OTEL
+		conf.Metrics = "otlp"
+
+		_, err := infrastructure.NewMeterProvider(conf, resource.Empty())
+		assert.ErrorIs(t, err, infrastructure.ErrInvalidConfig)
+	})
+}
@@ -0,0 +1,351 @@
+package web
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/labstack/echo/v4"
+)
+
+var ErrCreateAssetsFailed = errors.New("create assets failed")
+
+const (
+	hashLength = 8 // bytes of the sha256 hash used in the fingerprint
+
+	cacheForever = "public, max-age=31536000, immutable"
+	revalidate   = "no-cache"
+	noStore      = "no-store"
+
+	brotliSuffix = ".br"
+	gzipSuffix   = ".gz"
+)
+
+// NewAssets fingerprints all files in assetFS, so they can be cached forever by browsers.
+// A file css/main.css is served under the URL /css/main.3f2a9c1b5e7d0a4f.css,
+// which changes as soon as the content of the file changes.
+//
+// Compressed variants of a file are served to clients accepting them.
+// They are created on startup, unless the build provides them, e.g. css/main.css.br and css/main.css.gz.
+//
+// Without cache the files are read and fingerprinted on every request and are not stored by browsers,
+// so changes are visible with the next render of a page, e.g. in development.
+func NewAssets(assetFS fs.FS, cache bool) (*Assets, error) {
+	if assetFS == nil {
+		return nil, fmt.Errorf("%w: missing assets", ErrCreateAssetsFailed)
+	}
+
+	assets := &Assets{
+		fs:     assetFS,
+		byName: map[string]*asset{},
+		byURL:  map[string]*asset{},
+		cache:  cache,
+	}
+
+	if !cache {
+		return assets, nil
+	}
+
+	err := fs.WalkDir(assetFS, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || isVariant(name) {
+			return nil
+		}
+
+		a, err := loadAsset(assetFS, name, true)
+		if err != nil {
+			return err
+		}
+
+		assets.byName[a.name] = a
+		assets.byURL[a.url] = a
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCreateAssetsFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	return assets, nil
+}
+
+// Assets serves the static files of the application.
+type Assets struct {
+	fs fs.FS
+
+	// byName are the assets by their path in the fs, byURL by their fingerprinted path.
+	byName map[string]*asset
+	byURL  map[string]*asset
+
+	cache bool
+}
+
+type asset struct {
+	name        string
+	url         string
+	etag        string
+	contentType string
+
+	data   []byte
+	gzip   []byte
+	brotli []byte
+}
+
+// URL returns the fingerprinted URL of the asset with the given path, e.g. "css/main.css".
+// Use it in templates as {{ asset "css/main.css" }}.
+// Unknown assets return their unchanged path, so they fail the same way a missing file would.
+func (a *Assets) URL(name string) string {
+	name = strings.TrimPrefix(name, "/")
+
+	if asset, ok := a.byName[name]; ok {
+		return "/" + asset.url
+	}
+
+	if !a.cache {
+		if asset, err := loadAsset(a.fs, name, false); err == nil {
+			return "/" + asset.url
+		}
+	}
+
+	return "/" + name
+}
+
+// FuncMap returns the template functions to reference assets from views.
+func (a *Assets) FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"asset": a.URL,
+	}
+}
+
+// Middleware serves the assets.
+// Fingerprinted URLs are cached forever, the plain paths are still served, but have to be revalidated by the client.
+func (a *Assets) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		method := c.Request().Method
+		if method != http.MethodGet && method != http.MethodHead {
+			return next(c)
+		}
+
+		name := strings.TrimPrefix(c.Request().URL.Path, "/")
+
+		if asset, ok := a.byURL[name]; ok {
+			return a.serve(c, asset, cacheForever)
+		}
+
+		if asset, ok := a.byName[name]; ok {
+			return a.serve(c, asset, revalidate)
+		}
+
+		if !a.cache && name != "" && !isVariant(name) {
+			asset, err := loadAsset(a.fs, unfingerprint(name), false)
+			if err == nil {
+				return a.serve(c, asset, noStore)
+			}
+		}
+
+		return next(c)
+	}
+}
+
+func (a *Assets) serve(c echo.Context, asset *asset, cacheControl string) error {
+	header := c.Response().Header()
+
+	body, encoding := asset.data, ""
+
+	accepted := c.Request().Header.Get(echo.HeaderAcceptEncoding)
+	if asset.brotli != nil && acceptsEncoding(accepted, "br") {
+		body, encoding = asset.brotli, "br"
+	} else if asset.gzip != nil && acceptsEncoding(accepted, "gzip") {
+		body, encoding = asset.gzip, "gzip"
+	}
+
+	etag := asset.etag
+	if encoding != "" {
+		etag = strings.TrimSuffix(etag, `"`) + "-" + encoding + `"`
+		header.Set(echo.HeaderContentEncoding, encoding)
+	}
+
+	header.Set(echo.HeaderVary, echo.HeaderAcceptEncoding)
+	header.Set("Cache-Control", cacheControl)
+	header.Set("ETag", etag)
+
+	if matchesETag(c.Request().Header.Get("If-None-Match"), etag) {
+		header.Del(echo.HeaderContentEncoding)
+
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return c.Blob(http.StatusOK, asset.contentType, body)
+}
+
+func loadAsset(assetFS fs.FS, name string, compress bool) (*asset, error) {
+	data, err := fs.ReadFile(assetFS, name)
+	if err != nil {
+		return nil, fmt.Errorf("could not read asset: %s: %w", name, err)
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:hashLength])
+
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+
+	a := &asset{
+		name:        name,
+		url:         fingerprint(name, hash),
+		etag:        `"` + hash + `"`,
+		contentType: contentType,
+		data:        data,
+		gzip:        nil,
+		brotli:      nil,
+	}
+
+	if !compress {
+		return a, nil
+	}
+
+	if br, err := fs.ReadFile(assetFS, name+brotliSuffix); err == nil {
+		a.brotli = br
+	} else {
+		a.brotli = compressBrotli(data)
+	}
+
+	if gz, err := fs.ReadFile(assetFS, name+gzipSuffix); err == nil {
+		a.gzip = gz
+	} else {
+		a.gzip = compressGzip(data)
+	}
+
+	return a, nil
+}
+
+// fingerprint adds the hash to the name of a file, before its extension.
+func fingerprint(name string, hash string) string {
+	ext := path.Ext(name)
+
+	return strings.TrimSuffix(name, ext) + "." + hash + ext
+}
+
+// unfingerprint returns the name of the file, the fingerprinted name is created from.
+// Names without a fingerprint are returned unchanged.
+func unfingerprint(name string) string {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+
+	i := strings.LastIndex(base, ".")
+	if i < 0 {
+		return name
+	}
+
+	if hash := base[i+1:]; len(hash) != 2*hashLength || strings.Trim(hash, "0123456789abcdef") != "" {
+		return name
+	}
+
+	return base[:i] + ext
+}
+
+// matchesETag returns true, if the If-None-Match header contains the etag or "*".
+// The tags are compared weakly, as the header can list several tags and mark them as weak with W/.
+func matchesETag(header string, etag string) bool {
+	if header == "" {
+		return false
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// acceptsEncoding returns true, if the Accept-Encoding header accepts the encoding with a quality above zero,
+// either by its name or by "*".
+func acceptsEncoding(header string, encoding string) bool {
+	wildcard := false
+
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		quality := 1.0
+
+		for _, param := range strings.Split(params, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || !strings.EqualFold(key, "q") {
+				continue
+			}
+
+			q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				q = 0
+			}
+
+			quality = q
+		}
+
+		switch name {
+		case encoding:
+			return quality > 0
+		case "*":
+			wildcard = quality > 0
+		}
+	}
+
+	return wildcard
+}
+
+// compressGzip returns the compressed data, or nil if compressing does not make it smaller.
+func compressGzip(data []byte) []byte {
+	var buf bytes.Buffer
+
+	w, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	_, _ = w.Write(data)
+	_ = w.Close()
+
+	if buf.Len() >= len(data) {
+		return nil
+	}
+
+	return buf.Bytes()
+}
+
+// compressBrotli returns the compressed data, or nil if compressing does not make it smaller.
+// The best compression takes seconds for large assets, so the default level keeps the startup fast.
+func compressBrotli(data []byte) []byte {
+	var buf bytes.Buffer
+
+	w := brotli.NewWriterLevel(&buf, brotli.DefaultCompression)
+	_, _ = w.Write(data)
+	_ = w.Close()
+
+	if buf.Len() >= len(data) {
+		return nil
+	}
+
+	return buf.Bytes()
+}
+
+// isVariant returns true for precompressed files, as they are served in place of the original.
+func isVariant(name string) bool {
+	return strings.HasSuffix(name, brotliSuffix) || strings.HasSuffix(name, gzipSuffix)
+}
@@ -0,0 +1,188 @@
+package web_test
+
+import (
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/andybalholm/brotli"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/public"
+	"github.com/go-arrower/skeleton/shared/infrastructure/web"
+)
+
+var assetFiles = fstest.MapFS{
+	"css/main.css":    {Data: []byte(`body { color: black; } body { color: black; } body { color: black; }`)},
+	"css/main.css.br": {Data: []byte(`brotli`)},
+	"js/app.js":       {Data: []byte(`console.log("hello")`)},
+}
+
+func TestNewAssets(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing fs", func(t *testing.T) {
+		t.Parallel()
+
+		assets, err := web.NewAssets(nil, true)
+		assert.ErrorIs(t, err, web.ErrCreateAssetsFailed)
+		assert.Nil(t, assets)
+	})
+}
+
+func TestAssets_URL(t *testing.T) {
+	t.Parallel()
+
+	assets, _ := web.NewAssets(assetFiles, true)
+
+	assert.Regexp(t, regexp.MustCompile(`^/css/main\.[0-9a-f]{16}\.css$`), assets.URL("css/main.css"))
+	assert.Regexp(t, regexp.MustCompile(`^/js/app\.[0-9a-f]{16}\.js$`), assets.URL("/js/app.js"))
+	assert.Equal(t, "/css/non-existing.css", assets.URL("css/non-existing.css"))
+
+	t.Run("without cache", func(t *testing.T) {
+		t.Parallel()
+
+		files := fstest.MapFS{"css/main.css": {Data: []byte(`body { color: black; }`)}}
+		assets, _ := web.NewAssets(files, false)
+
+		url := assets.URL("css/main.css")
+		assert.Regexp(t, regexp.MustCompile(`^/css/main\.[0-9a-f]{16}\.css$`), url)
+
+		files["css/main.css"] = &fstest.MapFile{Data: []byte(`body { color: red; }`)}
+		assert.NotEqual(t, url, assets.URL("css/main.css"), "changes are fingerprinted on the next render")
+
+		rec := serveAsset(assets, assets.URL("css/main.css"), nil)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+		assert.Contains(t, rec.Body.String(), "color: red")
+	})
+}
+
+func TestAssets_Middleware(t *testing.T) {
+	t.Parallel()
+
+	assets, _ := web.NewAssets(assetFiles, true)
+	url := assets.URL("css/main.css")
+
+	t.Run("fingerprinted url", func(t *testing.T) {
+		t.Parallel()
+
+		rec := serveAsset(assets, url, nil)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "public, max-age=31536000, immutable", rec.Header().Get("Cache-Control"))
+		assert.NotEmpty(t, rec.Header().Get("ETag"))
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentType), "text/css")
+		assert.Contains(t, rec.Body.String(), "color: black")
+	})
+
+	t.Run("plain path", func(t *testing.T) {
+		t.Parallel()
+
+		rec := serveAsset(assets, "/css/main.css", nil)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		t.Parallel()
+
+		etag := serveAsset(assets, url, nil).Header().Get("ETag")
+
+		rec := serveAsset(assets, url, map[string]string{"If-None-Match": etag})
+
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Body.String())
+
+		for _, header := range []string{`"other", ` + etag, "W/" + etag, "*"} {
+			rec = serveAsset(assets, url, map[string]string{"If-None-Match": header})
+			assert.Equal(t, http.StatusNotModified, rec.Code, header)
+		}
+
+		rec = serveAsset(assets, url, map[string]string{"If-None-Match": `"other"`})
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("precompressed brotli", func(t *testing.T) {
+		t.Parallel()
+
+		rec := serveAsset(assets, url, map[string]string{echo.HeaderAcceptEncoding: "gzip, br"})
+
+		assert.Equal(t, "br", rec.Header().Get(echo.HeaderContentEncoding))
+		assert.Equal(t, "brotli", rec.Body.String())
+	})
+
+	t.Run("brotli not acceptable", func(t *testing.T) {
+		t.Parallel()
+
+		rec := serveAsset(assets, url, map[string]string{echo.HeaderAcceptEncoding: "gzip;q=0.8, br;q=0"})
+		assert.Equal(t, "gzip", rec.Header().Get(echo.HeaderContentEncoding))
+
+		rec = serveAsset(assets, url, map[string]string{echo.HeaderAcceptEncoding: "brotli-like"})
+		assert.Empty(t, rec.Header().Get(echo.HeaderContentEncoding), "br is not part of another name")
+
+		rec = serveAsset(assets, url, map[string]string{echo.HeaderAcceptEncoding: "*"})
+		assert.Equal(t, "br", rec.Header().Get(echo.HeaderContentEncoding))
+	})
+
+	t.Run("gzip", func(t *testing.T) {
+		t.Parallel()
+
+		rec := serveAsset(assets, url, map[string]string{echo.HeaderAcceptEncoding: "gzip"})
+
+		assert.Equal(t, "gzip", rec.Header().Get(echo.HeaderContentEncoding))
+		assert.Equal(t, echo.HeaderAcceptEncoding, rec.Header().Get(echo.HeaderVary))
+	})
+
+	t.Run("compressed on startup", func(t *testing.T) {
+		t.Parallel()
+
+		assets, err := web.NewAssets(public.Assets, true)
+		assert.NoError(t, err)
+
+		original, _ := fs.ReadFile(public.Assets, "css/main.css")
+
+		rec := serveAsset(assets, assets.URL("css/main.css"), map[string]string{echo.HeaderAcceptEncoding: "gzip, br"})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "br", rec.Header().Get(echo.HeaderContentEncoding))
+		assert.Less(t, rec.Body.Len(), len(original))
+
+		body, err := io.ReadAll(brotli.NewReader(rec.Body))
+		assert.NoError(t, err)
+		assert.Equal(t, original, body)
+	})
+
+	t.Run("unknown asset", func(t *testing.T) {
+		t.Parallel()
+
+		rec := serveAsset(assets, "/css/non-existing.css", nil)
+
+		assert.Equal(t, http.StatusTeapot, rec.Code)
+	})
+}
+
+// serveAsset requests the url through the middleware. Requests not served by it return http.StatusTeapot.
+func serveAsset(assets *web.Assets, url string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, url, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	rec := httptest.NewRecorder()
+
+	e := echo.New()
+	c := e.NewContext(req, rec)
+
+	_ = assets.Middleware(func(c echo.Context) error {
+		return c.NoContent(http.StatusTeapot)
+	})(c)
+
+	return rec
+}
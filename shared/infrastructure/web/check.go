@@ -0,0 +1,479 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template/parse"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/go-arrower/arrower/alog"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+var (
+	ErrCheckFailed = errors.New("check views failed")
+	ErrUntypedPage = errors.New("page without typed view")
+)
+
+// checkedContext is the name the views of a context are added under, while they are checked.
+const checkedContext = "checked"
+
+// CheckViews parses all templates of the shared views and of the views of a context
+// and validates the field references of the typed views against the type of data they are declared with.
+// A template referencing a field that does not exist renders empty output at runtime,
+// run CheckViews in a test, so it fails `go test` instead:
+//
+//	func TestViews(t *testing.T) {
+//		t.Parallel()
+//
+//		err := web.CheckViews(sharedviews.SharedViews, views.AdminViews, pages.Views...)
+//		assert.NoError(t, err)
+//	}
+//
+// Set contextFS to nil to check the shared views only.
+// Each page of the checked views needs a typed view, otherwise the pages without one are returned as error,
+// so a new page can not be left unchecked.
+// Values of unknown type, e.g. of an interface or returned by a template function, are not checked any further.
+func CheckViews(sharedFS fs.FS, contextFS fs.FS, views ...TypedView) error {
+	funcMap := templateFuncs(echo.New(), &Assets{}) //nolint:exhaustruct // the functions are never called
+
+	renderer, err := NewProductionRenderer(alog.NewNoopLogger(), noop.NewTracerProvider(), sharedFS, funcMap)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCheckFailed, err)
+	}
+
+	contextName := SharedViews
+
+	if contextFS != nil {
+		contextName = checkedContext
+
+		err = renderer.AddContext(contextName, contextFS)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrCheckFailed, err)
+		}
+	}
+
+	funcs := sprig.FuncMap()
+	for name, f := range renderer.funcMap {
+		funcs[name] = f
+	}
+
+	var errs []error
+
+	typed := map[string]bool{}
+
+	for _, view := range views {
+		err = renderer.checkView(contextName, view, funcs)
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		// a view of a fragment does not declare the data of its page
+		if parsedTempl, err := parseTemplateName(view.Name()); err == nil && parsedTempl.fragment == "" {
+			typed[parsedTempl.page] = true
+		}
+	}
+
+	if untyped := untypedPages(renderer.loadViews()[contextName], typed); len(untyped) > 0 {
+		errs = append(errs, fmt.Errorf("%w: %s", ErrUntypedPage, strings.Join(untyped, ", ")))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrCheckFailed, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+func (r *Renderer) checkView(contextName string, view TypedView, funcs template.FuncMap) error {
+	parsedTempl, err := parseTemplateName(view.Name())
+	if err != nil {
+		return fmt.Errorf("%s: %w", view.Name(), err)
+	}
+
+	parsedTempl.context = contextName
+	parsedTempl.partial = true // the data of the layouts is not typed, so only the page itself is checked
+
+	templ, err := r.buildPageTemplate(contextName != SharedViews, parsedTempl)
+	if err != nil {
+		return fmt.Errorf("%s: %w", view.Name(), err)
+	}
+
+	if templ.Lookup(parsedTempl.templateName()) == nil {
+		return fmt.Errorf("%s: %w", view.Name(), ErrNotExistsFragment)
+	}
+
+	root := view.DataType()
+	if parsedTempl.fragment == "" {
+		root = viewDataType(root)
+	}
+
+	checker := &typeChecker{
+		templ:    templ,
+		funcs:    funcs,
+		view:     view,
+		root:     root,
+		checked:  map[string]bool{},
+		tree:     nil,
+		problems: nil,
+	}
+	checker.checkTemplate(parsedTempl.templateName(), root)
+
+	return errors.Join(checker.problems...)
+}
+
+// untypedPages returns the sorted names of the pages, that have no typed view.
+func untypedPages(views viewTemplates, typed map[string]bool) []string {
+	var untyped []string
+
+	for page := range views.rawPages {
+		if !typed[page] {
+			untyped = append(untyped, page)
+		}
+	}
+
+	sort.Strings(untyped)
+
+	return untyped
+}
+
+// viewDataType mirrors viewData: the top level of the data of a page has the exported fields of a struct,
+// but none of its methods.
+func viewDataType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return t
+	}
+
+	fields := []reflect.StructField{}
+	seen := map[string]bool{}
+
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || seen[field.Name] {
+			continue
+		}
+
+		seen[field.Name] = true
+
+		fields = append(fields, reflect.StructField{ //nolint:exhaustruct // only the name and type are relevant
+			Name: field.Name,
+			Type: field.Type,
+		})
+	}
+
+	return reflect.StructOf(fields)
+}
+
+// typeChecker walks the parse trees of a template and follows the type of the data through them.
+// A nil reflect.Type is a value of unknown type.
+type typeChecker struct {
+	templ *template.Template
+	funcs template.FuncMap
+
+	view TypedView
+	root reflect.Type
+
+	// checked are the templates already checked with the type of their data, so recursive templates terminate.
+	checked map[string]bool
+	tree    *parse.Tree
+
+	problems []error
+}
+
+type scope struct {
+	dot  reflect.Type
+	vars map[string]reflect.Type
+}
+
+func (s scope) inner() scope {
+	vars := make(map[string]reflect.Type, len(s.vars))
+	for k, v := range s.vars {
+		vars[k] = v
+	}
+
+	return scope{dot: s.dot, vars: vars}
+}
+
+func (c *typeChecker) checkTemplate(name string, dot reflect.Type) {
+	key := name + "|" + c.typeName(dot)
+	if c.checked[key] {
+		return
+	}
+
+	c.checked[key] = true
+
+	templ := c.templ.Lookup(name)
+	if templ == nil || templ.Tree == nil {
+		return // html/template reports missing templates itself
+	}
+
+	previous := c.tree
+	c.tree = templ.Tree
+
+	c.walk(templ.Tree.Root, scope{dot: dot, vars: map[string]reflect.Type{"$": dot}})
+
+	c.tree = previous
+}
+
+func (c *typeChecker) walk(node parse.Node, s scope) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+
+		for _, child := range n.Nodes {
+			c.walk(child, s)
+		}
+	case *parse.ActionNode:
+		typ := c.pipe(n.Pipe, s)
+		c.declare(n.Pipe, s, typ)
+	case *parse.IfNode:
+		inner := s.inner()
+		c.declare(n.Pipe, inner, c.pipe(n.Pipe, inner))
+
+		c.walk(n.List, inner)
+		c.walk(n.ElseList, s.inner())
+	case *parse.WithNode:
+		inner := s.inner()
+		inner.dot = c.pipe(n.Pipe, inner)
+		c.declare(n.Pipe, inner, inner.dot)
+
+		c.walk(n.List, inner)
+		c.walk(n.ElseList, s.inner())
+	case *parse.RangeNode:
+		inner := s.inner()
+		key, elem := c.rangeTypes(n, c.pipe(n.Pipe, inner))
+
+		inner.dot = elem
+		if len(n.Pipe.Decl) == 1 {
+			c.declare(n.Pipe, inner, elem)
+		} else {
+			c.declare(n.Pipe, inner, key, elem)
+		}
+
+		c.walk(n.List, inner)
+		c.walk(n.ElseList, s.inner())
+	case *parse.TemplateNode:
+		var dot reflect.Type
+		if n.Pipe != nil {
+			dot = c.pipe(n.Pipe, s)
+		}
+
+		c.checkTemplate(n.Name, dot)
+	}
+}
+
+// declare sets the types of the variables declared in a pipeline, e.g. {{ $name := .Name }}.
+func (c *typeChecker) declare(pipe *parse.PipeNode, s scope, types ...reflect.Type) {
+	if pipe == nil || pipe.IsAssign {
+		return
+	}
+
+	for i, v := range pipe.Decl {
+		var typ reflect.Type
+		if i < len(types) {
+			typ = types[i]
+		}
+
+		s.vars[v.Ident[0]] = typ
+	}
+}
+
+func (c *typeChecker) pipe(pipe *parse.PipeNode, s scope) reflect.Type {
+	if pipe == nil {
+		return nil
+	}
+
+	var typ reflect.Type
+
+	for _, cmd := range pipe.Cmds {
+		typ = c.command(cmd, s)
+	}
+
+	return typ
+}
+
+func (c *typeChecker) command(cmd *parse.CommandNode, s scope) reflect.Type {
+	for _, arg := range cmd.Args[1:] {
+		c.arg(arg, s)
+	}
+
+	return c.arg(cmd.Args[0], s)
+}
+
+func (c *typeChecker) arg(node parse.Node, s scope) reflect.Type {
+	switch n := node.(type) {
+	case *parse.DotNode:
+		return s.dot
+	case *parse.FieldNode:
+		return c.fields(node, s.dot, n.Ident)
+	case *parse.VariableNode:
+		return c.fields(node, s.vars[n.Ident[0]], n.Ident[1:])
+	case *parse.ChainNode:
+		return c.fields(node, c.arg(n.Node, s), n.Field)
+	case *parse.PipeNode:
+		return c.pipe(n, s)
+	case *parse.IdentifierNode:
+		return c.function(n.Ident)
+	case *parse.StringNode:
+		return reflect.TypeFor[string]()
+	case *parse.BoolNode:
+		return reflect.TypeFor[bool]()
+	case *parse.NumberNode:
+		if n.IsInt {
+			return reflect.TypeFor[int]()
+		}
+
+		return reflect.TypeFor[float64]()
+	}
+
+	return nil
+}
+
+// function returns the type of the result of a template function.
+func (c *typeChecker) function(name string) reflect.Type {
+	switch name {
+	case "len":
+		return reflect.TypeFor[int]()
+	case "eq", "ne", "lt", "le", "gt", "ge", "not":
+		return reflect.TypeFor[bool]()
+	case "print", "printf", "println", "html", "js", "urlquery":
+		return reflect.TypeFor[string]()
+	}
+
+	f, ok := c.funcs[name]
+	if !ok {
+		return nil
+	}
+
+	return result(reflect.TypeOf(f))
+}
+
+// fields follows a chain of fields, e.g. .Stats.PendingJobs, and returns the type of the last one.
+func (c *typeChecker) fields(node parse.Node, typ reflect.Type, names []string) reflect.Type {
+	for _, name := range names {
+		if typ == nil {
+			return nil
+		}
+
+		next, ok := field(typ, name)
+		if !ok {
+			c.errorf(node, "can't evaluate field %s in type %s", name, c.typeName(typ))
+
+			return nil
+		}
+
+		typ = next
+	}
+
+	return typ
+}
+
+// field returns the type of a field or method called name, the same way text/template resolves it.
+func field(typ reflect.Type, name string) (reflect.Type, bool) {
+	if method, ok := typ.MethodByName(name); ok {
+		return result(method.Type), true
+	}
+
+	if typ.Kind() != reflect.Pointer && typ.Kind() != reflect.Interface {
+		if method, ok := reflect.PointerTo(typ).MethodByName(name); ok {
+			return result(method.Type), true
+		}
+	}
+
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch typ.Kind() { //nolint:exhaustive // all other kinds have no fields
+	case reflect.Interface:
+		return nil, true
+	case reflect.Struct:
+		if f, ok := typ.FieldByName(name); ok && f.IsExported() {
+			return known(f.Type), true
+		}
+	case reflect.Map:
+		if typ.Key().Kind() == reflect.String {
+			return known(typ.Elem()), true
+		}
+	}
+
+	return nil, false
+}
+
+// result returns the type of the first value returned by a function.
+func result(fn reflect.Type) reflect.Type {
+	if fn == nil || fn.Kind() != reflect.Func || fn.NumOut() == 0 {
+		return nil
+	}
+
+	return known(fn.Out(0))
+}
+
+func (c *typeChecker) rangeTypes(node parse.Node, typ reflect.Type) (reflect.Type, reflect.Type) {
+	if typ == nil {
+		return nil, nil
+	}
+
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch typ.Kind() { //nolint:exhaustive // all other kinds can not be ranged over
+	case reflect.Slice, reflect.Array:
+		return reflect.TypeFor[int](), known(typ.Elem())
+	case reflect.Map:
+		return known(typ.Key()), known(typ.Elem())
+	case reflect.Chan:
+		return known(typ.Elem()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return typ, typ
+	case reflect.Interface, reflect.Func:
+		return nil, nil
+	}
+
+	c.errorf(node, "range can't iterate over type %s", c.typeName(typ))
+
+	return nil, nil
+}
+
+// known returns nil for interfaces, as the type of their value is only known at runtime.
+func known(typ reflect.Type) reflect.Type {
+	if typ == nil || typ.Kind() == reflect.Interface {
+		return nil
+	}
+
+	return typ
+}
+
+func (c *typeChecker) typeName(typ reflect.Type) string {
+	if typ == nil {
+		return "<unknown>"
+	}
+
+	if typ == c.root {
+		return c.view.DataType().String()
+	}
+
+	return typ.String()
+}
+
+func (c *typeChecker) errorf(node parse.Node, format string, args ...any) {
+	location, context := c.tree.ErrorContext(node)
+
+	msg := fmt.Sprintf(format, args...)
+	msg = strings.Join([]string{c.view.Name(), location, context, msg}, ": ")
+
+	c.problems = append(c.problems, errors.New(msg)) //nolint:goerr113 // the problems are wrapped by ErrCheckFailed
+}
@@ -0,0 +1,135 @@
+package web_test
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/web"
+)
+
+type (
+	checkedPage struct {
+		Title   string
+		Items   []checkedItem
+		Labels  map[string]string
+		Details any
+	}
+
+	checkedItem struct {
+		Name    string
+		Created time.Time
+	}
+)
+
+func (i checkedItem) IsNew() bool { return false }
+
+var checkedViews = fstest.MapFS{
+	"components/c0.html": {Data: []byte(`{{ .Name }}`)},
+	"pages/valid.html": {Data: []byte(`
+		<h1>{{ .Title }}</h1>
+		{{ range $i, $item := .Items }}
+			{{ block "item" . }}{{ .Name }} {{ .Created.Year }}{{ if .IsNew }}new{{ end }}{{ end }}
+			{{ $item.Name }} {{ $.Title | upper }}
+		{{ end }}
+		{{ with .Labels }}{{ .anyKey }}{{ end }}
+		{{ .Details.Anything.Goes }}
+		{{ template "c0" (index .Items 0) }}
+	`)},
+	"pages/invalid.html": {Data: []byte(`
+		{{ .Titel }}
+		{{ range .Items }}{{ .Nmae }}{{ end }}
+		{{ define "fragment" }}{{ .Created.Yaer }}{{ end }}
+	`)},
+	"pages/method.html": {Data: []byte(`{{ range .Items }}{{ .IsNew }}{{ end }}{{ .Title.Len }}`)},
+}
+
+// checkedPages returns the component and the given pages of checkedViews,
+// so the pages, that are not checked by a test, are not reported as untyped.
+func checkedPages(pages ...string) fstest.MapFS {
+	views := fstest.MapFS{"components/c0.html": checkedViews["components/c0.html"]}
+
+	for _, page := range pages {
+		views["pages/"+page+".html"] = checkedViews["pages/"+page+".html"]
+	}
+
+	return views
+}
+
+func TestCheckViews(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid views", func(t *testing.T) {
+		t.Parallel()
+
+		err := web.CheckViews(checkedPages("valid"), nil,
+			web.NewView[checkedPage]("valid"),
+			web.NewView[checkedItem]("valid#item"),
+			web.NewView[checkedItem]("#c0"),
+		)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown fields", func(t *testing.T) {
+		t.Parallel()
+
+		err := web.CheckViews(checkedPages("invalid"), nil,
+			web.NewView[checkedPage]("invalid"),
+			web.NewView[checkedItem]("invalid#fragment"),
+		)
+		assert.ErrorIs(t, err, web.ErrCheckFailed)
+		assert.ErrorContains(t, err, "can't evaluate field Titel in type web_test.checkedPage")
+		assert.ErrorContains(t, err, "can't evaluate field Nmae in type web_test.checkedItem")
+		assert.ErrorContains(t, err, "can't evaluate field Yaer in type time.Time")
+	})
+
+	t.Run("methods", func(t *testing.T) {
+		t.Parallel()
+
+		err := web.CheckViews(checkedPages("method"), nil, web.NewView[checkedPage]("method"))
+		assert.ErrorIs(t, err, web.ErrCheckFailed)
+		assert.ErrorContains(t, err, "can't evaluate field Len in type string")
+		assert.NotContains(t, err.Error(), "IsNew")
+	})
+
+	t.Run("non existing fragment", func(t *testing.T) {
+		t.Parallel()
+
+		err := web.CheckViews(checkedPages("valid"), nil,
+			web.NewView[checkedPage]("valid"),
+			web.NewView[checkedItem]("valid#non-existing"),
+		)
+		assert.ErrorIs(t, err, web.ErrCheckFailed)
+		assert.ErrorIs(t, err, web.ErrNotExistsFragment)
+	})
+
+	t.Run("broken template", func(t *testing.T) {
+		t.Parallel()
+
+		err := web.CheckViews(fstest.MapFS{"pages/broken.html": {Data: []byte(`{{ if }}`)}}, nil)
+		assert.ErrorIs(t, err, web.ErrCheckFailed)
+	})
+
+	t.Run("context views", func(t *testing.T) {
+		t.Parallel()
+
+		err := web.CheckViews(checkedViews, fstest.MapFS{
+			"pages/context.html": {Data: []byte(`{{ range .Items }}{{ template "c0" . }}{{ end }}`)},
+		}, web.NewView[checkedPage]("context"))
+		assert.NoError(t, err, "the shared pages are checked on their own")
+	})
+
+	t.Run("untyped pages", func(t *testing.T) {
+		t.Parallel()
+
+		err := web.CheckViews(checkedPages("valid", "method"), nil, web.NewView[checkedItem]("valid#item"))
+		assert.ErrorIs(t, err, web.ErrCheckFailed)
+		assert.ErrorIs(t, err, web.ErrUntypedPage)
+		assert.ErrorContains(t, err, "method, valid", "a fragment view does not declare its page")
+
+		err = web.CheckViews(checkedPages("valid"), nil, web.NewView[checkedPage]("valid"))
+		assert.NoError(t, err, "every page has a typed view")
+	})
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/go-arrower/arrower/alog"
 	"github.com/labstack/echo/v4"
 	"go.opentelemetry.io/otel/trace"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/web/htmx"
 )
 
 func NewEchoRenderer(
@@ -18,15 +20,36 @@ func NewEchoRenderer(
 	traceProvider trace.TracerProvider,
 	echo *echo.Echo,
 	viewFS fs.FS,
+	assets *Assets,
 	hotReload bool,
 ) (*EchoRenderer, error) {
-	r, _ := NewRenderer(logger, traceProvider, viewFS, template.FuncMap{
-		"route": echo.Reverse, // todo test case for reverse func
-	}, hotReload)
+	funcMap := templateFuncs(echo, assets)
+
+	if hotReload {
+		r, err := NewRenderer(logger, traceProvider, viewFS, funcMap, true)
+		if err != nil {
+			return nil, err
+		}
+
+		return &EchoRenderer{Renderer: r}, nil
+	}
+
+	r, err := NewProductionRenderer(logger, traceProvider, viewFS, funcMap)
+	if err != nil {
+		return nil, err
+	}
 
 	return &EchoRenderer{Renderer: r}, nil
 }
 
+// templateFuncs are the functions available in all views.
+func templateFuncs(echo *echo.Echo, assets *Assets) template.FuncMap {
+	funcMap := assets.FuncMap()
+	funcMap["route"] = echo.Reverse // todo test case for reverse func
+
+	return funcMap
+}
+
 type EchoRenderer struct {
 	*Renderer
 }
@@ -34,6 +57,10 @@ type EchoRenderer struct {
 func (r *EchoRenderer) Render(w io.Writer, templateName string, data interface{}, c echo.Context) error {
 	_, _, context := r.isRegisteredContext(c) // todo test how it is split
 
+	if htmx.IsRequest(c) && !htmx.IsBoosted(c) {
+		return r.Renderer.RenderPartial(c.Request().Context(), w, context, templateName, htmx.Target(c), data)
+	}
+
 	return r.Renderer.Render(c.Request().Context(), w, context, templateName, data)
 }
 
@@ -55,7 +82,7 @@ func (r *EchoRenderer) isRegisteredContext(c echo.Context) (bool, bool, string)
 			continue
 		}
 
-		_, exists := r.views[path]
+		_, exists := r.loadViews()[path]
 		if exists {
 			if isAdmin {
 				return true, true, "/admin/" + path
@@ -83,11 +110,11 @@ func (r *EchoRenderer) isRegisteredContext(c echo.Context) (bool, bool, string)
 
 // layout returns the default layout of this renderer.
 func (r *Renderer) layout() string {
-	return r.views[SharedViews].defaultLayout
+	return r.loadViews()[SharedViews].defaultLayout
 }
 
 func (r *Renderer) viewsForContext(name string) viewTemplates {
-	return r.views[name]
+	return r.loadViews()[name]
 }
 
 func (r *Renderer) totalCachedTemplates() int {
@@ -0,0 +1,287 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/i18n"
+)
+
+// ViewError is the page shown for a failed request. If a context has no page "error", the shared one is used.
+var ViewError = NewView[ErrorPage]("error") //nolint:gochecknoglobals // typed views are declared once
+
+// ErrorPage is the data of ViewError.
+type ErrorPage struct {
+	Title  string
+	Status int
+	// Message is the key of the translated message for the user.
+	Message string
+	// Errors are the translated validation errors by field.
+	Errors  map[string]string
+	TraceID string
+	// Debug is only set, if the application runs in debug mode.
+	Debug *ErrorDebug
+}
+
+// ErrorDebug has the details of an error, so a developer can find its cause.
+type ErrorDebug struct {
+	Method string
+	Path   string
+	// Chain are the messages of the error and all the errors it wraps.
+	Chain []string
+	// Stack is the stack of a recovered panic, for other errors it is empty.
+	Stack string
+}
+
+// Problem is the response of a failed API request, see RFC 7807.
+type Problem struct {
+	Type     string            `json:"type"`
+	Title    string            `json:"title"`
+	Status   int               `json:"status"`
+	Detail   string            `json:"detail,omitempty"`
+	Instance string            `json:"instance,omitempty"`
+	Errors   map[string]string `json:"errors,omitempty"`
+	TraceID  string            `json:"trace_id,omitempty"`
+}
+
+// NewErrorHandler returns the central handler for all errors returned by controllers.
+// Contexts Register their domain errors, so they are answered with a fitting status code.
+// Set debug to show the details of an error, otherwise they are only logged.
+func NewErrorHandler(logger alog.Logger, validate *i18n.Validator, debug bool) *ErrorHandler {
+	handler := &ErrorHandler{
+		logger:   logger,
+		validate: validate,
+		debug:    debug,
+		mu:       sync.RWMutex{},
+		statuses: nil,
+	}
+
+	handler.Register(http.StatusInternalServerError, ErrRenderFailed)
+
+	return handler
+}
+
+// ErrorHandler maps errors to status codes and answers with an error page for HTML
+// and a Problem for requests to the API.
+type ErrorHandler struct {
+	logger   alog.Logger
+	validate *i18n.Validator
+	debug    bool
+
+	mu       sync.RWMutex
+	statuses []errorStatus
+}
+
+type errorStatus struct {
+	err  error
+	code int
+}
+
+// Register answers requests failing with one of the errors with the status code, e.g.:
+//
+//	errorHandler.Register(http.StatusNotFound, domain.ErrNotFound)
+func (h *ErrorHandler) Register(code int, errs ...error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, err := range errs {
+		h.statuses = append(h.statuses, errorStatus{err: err, code: code})
+	}
+}
+
+// StatusCode returns the status code of the error.
+// An echo.HTTPError keeps its code, validation errors are http.StatusUnprocessableEntity,
+// and errors that are not registered are http.StatusInternalServerError.
+func (h *ErrorHandler) StatusCode(err error) int {
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		return http.StatusUnprocessableEntity
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, s := range h.statuses {
+		if errors.Is(err, s.err) {
+			return s.code
+		}
+	}
+
+	return http.StatusInternalServerError
+}
+
+// Handle is the echo.HTTPErrorHandler. The message of an error is never shown to a user,
+// as it can contain internal details. In debug mode, they are shown on the error page instead.
+func (h *ErrorHandler) Handle(err error, c echo.Context) {
+	ctx := c.Request().Context()
+	code := h.StatusCode(err)
+	traceID := traceIDOf(c)
+
+	level := slog.LevelInfo
+	if code >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+
+	h.logger.Log(ctx, level, "request failed",
+		slog.Int("status", code),
+		slog.String("method", c.Request().Method),
+		slog.String("path", c.Request().URL.Path),
+		slog.String("err", err.Error()),
+		slog.String("trace_id", traceID),
+	)
+
+	if c.Response().Committed {
+		return
+	}
+
+	if c.Request().Method == http.MethodHead {
+		_ = c.NoContent(code)
+
+		return
+	}
+
+	var validationErrors map[string]string
+	if h.validate != nil {
+		validationErrors = h.validate.Errors(ctx, err)
+	}
+
+	if IsAPIRequest(c) {
+		problem := Problem{
+			Type:     "about:blank",
+			Title:    http.StatusText(code),
+			Status:   code,
+			Detail:   "",
+			Instance: c.Request().URL.Path,
+			Errors:   validationErrors,
+			TraceID:  traceID,
+		}
+
+		if h.debug {
+			problem.Detail = err.Error()
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, "application/problem+json")
+		_ = c.JSON(code, problem)
+
+		return
+	}
+
+	page := ErrorPage{
+		Title:   http.StatusText(code),
+		Status:  code,
+		Message: errorMessage(code),
+		Errors:  validationErrors,
+		TraceID: traceID,
+		Debug:   nil,
+	}
+
+	if h.debug {
+		page.Debug = newErrorDebug(c, err)
+	}
+
+	if errors.Is(err, ErrRenderFailed) && !h.debug {
+		// the views can be the cause of the error, so do not render them again
+		_ = c.String(code, http.StatusText(code))
+
+		return
+	}
+
+	if rErr := Render(c, code, ViewError, page); rErr != nil {
+		h.logger.ErrorContext(ctx, "could not render error page", slog.String("err", rErr.Error()))
+
+		_ = c.String(code, fmt.Sprintf("%d %s", code, http.StatusText(code)))
+	}
+}
+
+// Recover turns panics into a PanicError, so they are handled by the ErrorHandler, including their stack.
+func Recover() echo.MiddlewareFunc {
+	return middleware.RecoverWithConfig(middleware.RecoverConfig{ //nolint:exhaustruct // use the defaults of echo
+		LogErrorFunc: func(_ echo.Context, err error, stack []byte) error {
+			return &PanicError{Err: err, Stack: stack}
+		},
+	})
+}
+
+// PanicError is a recovered panic.
+type PanicError struct {
+	Err   error
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return "panic: " + e.Err.Error()
+}
+
+func (e *PanicError) Unwrap() error {
+	return e.Err
+}
+
+func newErrorDebug(c echo.Context, err error) *ErrorDebug {
+	debug := &ErrorDebug{
+		Method: c.Request().Method,
+		Path:   c.Request().URL.String(),
+		Chain:  nil,
+		Stack:  "",
+	}
+
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		debug.Stack = string(panicErr.Stack)
+	}
+
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		debug.Chain = append(debug.Chain, e.Error())
+	}
+
+	return debug
+}
+
+func errorMessage(code int) string {
+	switch {
+	case code == http.StatusNotFound:
+		return "error.not_found"
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return "error.forbidden"
+	case code == http.StatusUnprocessableEntity:
+		return "error.invalid"
+	case code >= http.StatusInternalServerError:
+		return "error.server"
+	default:
+		return "error.client"
+	}
+}
+
+// IsAPIRequest returns true for requests to the API or requests accepting JSON but not HTML.
+func IsAPIRequest(c echo.Context) bool {
+	if strings.HasPrefix(c.Request().URL.Path, "/api") {
+		return true
+	}
+
+	accept := c.Request().Header.Get(echo.HeaderAccept)
+
+	return strings.Contains(accept, "json") && !strings.Contains(accept, "html")
+}
+
+func traceIDOf(c echo.Context) string {
+	spanCtx := trace.SpanFromContext(c.Request().Context()).SpanContext()
+	if !spanCtx.HasTraceID() {
+		return ""
+	}
+
+	return spanCtx.TraceID().String()
+}
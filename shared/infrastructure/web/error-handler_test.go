@@ -0,0 +1,186 @@
+package web_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/i18n"
+	"github.com/go-arrower/skeleton/shared/infrastructure/web"
+)
+
+var errNotFound = errors.New("not found")
+
+func TestErrorHandler_StatusCode(t *testing.T) {
+	t.Parallel()
+
+	validate, err := i18n.NewValidator()
+	assert.NoError(t, err)
+
+	handler := web.NewErrorHandler(alog.NewNoopLogger(), validate, false)
+	handler.Register(http.StatusNotFound, errNotFound)
+
+	type user struct {
+		Name string `validate:"required"`
+	}
+
+	tests := map[string]struct {
+		err  error
+		code int
+	}{
+		"registered": {
+			fmt.Errorf("%w: user", errNotFound),
+			http.StatusNotFound,
+		},
+		"http error": {
+			echo.NewHTTPError(http.StatusForbidden),
+			http.StatusForbidden,
+		},
+		"validation": {
+			validate.Validate.Struct(user{}),
+			http.StatusUnprocessableEntity,
+		},
+		"render failed": {
+			fmt.Errorf("%w: missing page", web.ErrRenderFailed),
+			http.StatusInternalServerError,
+		},
+		"unknown": {
+			errSomeError,
+			http.StatusInternalServerError,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.code, handler.StatusCode(tt.err))
+		})
+	}
+}
+
+func TestErrorHandler_Handle(t *testing.T) {
+	t.Parallel()
+
+	newEcho := func(t *testing.T, debug bool) *echo.Echo {
+		t.Helper()
+
+		e := echo.New()
+
+		assets, _ := web.NewAssets(fstest.MapFS{}, true)
+
+		renderer, err := web.NewEchoRenderer(alog.NewNoopLogger(), noop.NewTracerProvider(), e, fstest.MapFS{
+			"pages/error.html": {Data: []byte(`{{ .Status }} {{ .Message }}{{ if .Debug }}{{ range .Debug.Chain }} [{{ . }}]{{ end }}{{ end }}`)},
+		}, assets, false)
+		assert.NoError(t, err)
+
+		e.Renderer = renderer
+
+		handler := web.NewErrorHandler(alog.NewNoopLogger(), nil, debug)
+		handler.Register(http.StatusNotFound, errNotFound)
+		e.HTTPErrorHandler = handler.Handle
+
+		return e
+	}
+
+	t.Run("error page", func(t *testing.T) {
+		t.Parallel()
+
+		e := newEcho(t, false)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/users/1", nil), rec)
+
+		e.HTTPErrorHandler(fmt.Errorf("%w: user 1", errNotFound), c)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Body.String(), "404 error.not_found")
+		assert.NotContains(t, rec.Body.String(), "user 1", "do not leak the error to the user")
+	})
+
+	t.Run("debug page", func(t *testing.T) {
+		t.Parallel()
+
+		e := newEcho(t, true)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+		e.HTTPErrorHandler(fmt.Errorf("%w: user 1", errNotFound), c)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Body.String(), "[not found: user 1] [not found]")
+	})
+
+	t.Run("problem for api", func(t *testing.T) {
+		t.Parallel()
+
+		e := newEcho(t, false)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/users/1", nil), rec)
+
+		e.HTTPErrorHandler(fmt.Errorf("%w: user 1", errNotFound), c)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "application/problem+json", rec.Header().Get(echo.HeaderContentType))
+
+		var problem web.Problem
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+		assert.Equal(t, http.StatusNotFound, problem.Status)
+		assert.Equal(t, "Not Found", problem.Title)
+		assert.Equal(t, "/api/users/1", problem.Instance)
+		assert.Empty(t, problem.Detail)
+	})
+
+	t.Run("render failed", func(t *testing.T) {
+		t.Parallel()
+
+		e := newEcho(t, false)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+		e.HTTPErrorHandler(fmt.Errorf("%w: missing page", web.ErrRenderFailed), c)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, "Internal Server Error", rec.Body.String())
+	})
+
+	t.Run("head", func(t *testing.T) {
+		t.Parallel()
+
+		e := newEcho(t, false)
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodHead, "/", nil), rec)
+
+		e.HTTPErrorHandler(errNotFound, c)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		t.Parallel()
+
+		e := newEcho(t, true)
+		e.Use(web.Recover())
+		e.GET("/", func(echo.Context) error { panic("something broke") })
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Contains(t, rec.Body.String(), "[panic: something broke]")
+	})
+}
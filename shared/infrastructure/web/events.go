@@ -0,0 +1,333 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/labstack/echo/v4"
+)
+
+var ErrPublishFailed = errors.New("could not publish event")
+
+const (
+	// EventsChannel is the Postgres channel events are sent over, so all instances receive them.
+	// The triggers in the migrations notify the same channel, keep them in sync.
+	EventsChannel = "arrower_events"
+
+	defaultCoalesce  = 500 * time.Millisecond
+	defaultHeartbeat = 15 * time.Second
+	reconnectDelay   = time.Second
+	subscriberBuffer = 16
+)
+
+// Event is sent to all browsers subscribed to its Topic.
+// Pages subscribe with the htmx sse extension, e.g. to reload a fragment:
+//
+//	<div hx-ext="sse" sse-connect="/admin/events">
+//	  <table hx-get="/admin/jobs/workers" hx-trigger="sse:jobs.workers">...</table>
+//	</div>
+//
+// or to swap the Data into an element with sse-swap="topic".
+type Event struct {
+	Topic string `json:"topic"`
+	Data  string `json:"data,omitempty"`
+}
+
+type EventsOption func(*Events)
+
+// WithCoalesce sets how long events of the same topic are collected, before they are sent to a browser.
+// Only the last event of a topic is sent, so a busy topic does not flood the browser with requests.
+func WithCoalesce(interval time.Duration) EventsOption {
+	return func(e *Events) {
+		e.coalesce = interval
+	}
+}
+
+// WithHeartbeat sets how often an idle stream sends a comment, so proxies do not close the connection.
+func WithHeartbeat(interval time.Duration) EventsOption {
+	return func(e *Events) {
+		e.heartbeat = interval
+	}
+}
+
+// NewEvents returns Events, that are published to all instances via Postgres LISTEN/NOTIFY.
+// If pg is nil, events are only sent to the subscribers of this instance.
+func NewEvents(logger alog.Logger, pg *pgxpool.Pool, opts ...EventsOption) *Events {
+	events := &Events{
+		logger:      logger.WithGroup("arrower.events"),
+		pg:          pg,
+		coalesce:    defaultCoalesce,
+		heartbeat:   defaultHeartbeat,
+		mu:          sync.RWMutex{},
+		subscribers: map[chan Event]map[string]bool{},
+		cancel:      func() {},
+		done:        make(chan struct{}),
+		wg:          sync.WaitGroup{},
+	}
+
+	for _, opt := range opts {
+		opt(events)
+	}
+
+	return events
+}
+
+// Events is a server-sent events broker. Contexts Publish to topics, and browsers
+// receive them via a Stream, no matter which instance they are connected to.
+type Events struct {
+	logger alog.Logger
+	pg     *pgxpool.Pool
+
+	coalesce  time.Duration
+	heartbeat time.Duration
+
+	mu          sync.RWMutex
+	subscribers map[chan Event]map[string]bool
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// Publish sends the event to all subscribers of the topic on all instances.
+// The data has to fit into a Postgres notification (less than 8000 bytes),
+// for larger data publish the topic only and let the page load the data.
+func (e *Events) Publish(ctx context.Context, topic string, data string) error {
+	event := Event{Topic: topic, Data: data}
+
+	if e.pg == nil {
+		e.dispatch(event)
+
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPublishFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	_, err = e.pg.Exec(ctx, `SELECT pg_notify($1, $2)`, EventsChannel, string(payload))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrPublishFailed, err) //nolint:errorlint // prevent err in api
+	}
+
+	return nil
+}
+
+// Subscribe returns a channel receiving the events of the topics.
+// Call the returned function to unsubscribe, the channel is not closed.
+// Events are dropped, if the subscriber does not keep up.
+func (e *Events) Subscribe(topics ...string) (<-chan Event, func()) {
+	ch := make(chan Event, subscriberBuffer)
+
+	filter := make(map[string]bool, len(topics))
+	for _, t := range topics {
+		filter[t] = true
+	}
+
+	e.mu.Lock()
+	e.subscribers[ch] = filter
+	e.mu.Unlock()
+
+	return ch, func() {
+		e.mu.Lock()
+		delete(e.subscribers, ch)
+		e.mu.Unlock()
+	}
+}
+
+// Stream returns a handler, that streams the events of the topics to the browser.
+// A request can narrow the topics down with the query parameter topic, e.g. ?topic=logs.
+func (e *Events) Stream(topics ...string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		subscribed := topics
+		if requested := c.QueryParams()["topic"]; len(requested) > 0 {
+			subscribed = intersect(topics, requested)
+		}
+
+		events, unsubscribe := e.Subscribe(subscribed...)
+		defer unsubscribe()
+
+		res := c.Response()
+		res.Header().Set(echo.HeaderContentType, "text/event-stream")
+		res.Header().Set(echo.HeaderCacheControl, "no-cache")
+		res.Header().Set(echo.HeaderConnection, "keep-alive")
+		res.Header().Set("X-Accel-Buffering", "no") // disable buffering in nginx
+		res.WriteHeader(http.StatusOK)
+		res.Flush()
+
+		coalesce := time.NewTicker(e.coalesce)
+		defer coalesce.Stop()
+
+		heartbeat := time.NewTicker(e.heartbeat)
+		defer heartbeat.Stop()
+
+		pending := map[string]Event{}
+
+		for {
+			select {
+			case event := <-events:
+				pending[event.Topic] = event
+			case <-coalesce.C:
+				if len(pending) == 0 {
+					continue
+				}
+
+				for topic, event := range pending {
+					if _, err := res.Write(encodeEvent(event)); err != nil {
+						return nil //nolint:nilerr // the browser is gone, there is nobody to answer
+					}
+
+					delete(pending, topic)
+				}
+
+				res.Flush()
+				heartbeat.Reset(e.heartbeat)
+			case <-heartbeat.C:
+				if _, err := res.Write([]byte(": heartbeat\n\n")); err != nil {
+					return nil //nolint:nilerr // the browser is gone, there is nobody to answer
+				}
+
+				res.Flush()
+			case <-c.Request().Context().Done():
+				return nil
+			case <-e.done:
+				return nil
+			}
+		}
+	}
+}
+
+// Start listens for the events of all instances in the background until Shutdown is called or the ctx is cancelled.
+// If the connection to Postgres is lost, it reconnects.
+func (e *Events) Start(ctx context.Context) {
+	if e.pg == nil {
+		return
+	}
+
+	ctx, e.cancel = context.WithCancel(ctx)
+
+	e.wg.Add(1)
+
+	go func() {
+		defer e.wg.Done()
+
+		for {
+			err := e.listen(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+
+			e.logger.LogAttrs(ctx, slog.LevelWarn, "lost connection, reconnecting", slog.String("err", err.Error()))
+
+			select {
+			case <-time.After(reconnectDelay):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	e.logger.LogAttrs(ctx, alog.LevelInfo, "events started", slog.String("channel", EventsChannel))
+}
+
+// Shutdown stops listening and ends all open streams, so the web server can shut down.
+func (e *Events) Shutdown(_ context.Context) error {
+	select {
+	case <-e.done:
+	default:
+		close(e.done)
+	}
+
+	e.cancel()
+	e.wg.Wait()
+
+	return nil
+}
+
+func (e *Events) listen(ctx context.Context) error {
+	conn, err := e.pg.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("could not acquire connection: %v", err) //nolint:errorlint,goerr113 // prevent err in api
+	}
+
+	// the connection is listening, so it is not given back to the pool but closed
+	pgConn := conn.Hijack()
+	defer pgConn.Close(context.Background()) //nolint:contextcheck // close even if ctx is cancelled
+
+	_, err = pgConn.Exec(ctx, "LISTEN "+EventsChannel)
+	if err != nil {
+		return fmt.Errorf("could not listen: %v", err) //nolint:errorlint,goerr113 // prevent err in api
+	}
+
+	for {
+		notification, err := pgConn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("could not receive notification: %v", err) //nolint:errorlint,goerr113 // prevent err in api
+		}
+
+		var event Event
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			e.logger.LogAttrs(ctx, slog.LevelWarn, "invalid event", slog.String("payload", notification.Payload))
+
+			continue
+		}
+
+		e.dispatch(event)
+	}
+}
+
+func (e *Events) dispatch(event Event) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	for ch, topics := range e.subscribers {
+		if !topics[event.Topic] {
+			continue
+		}
+
+		select {
+		case ch <- event:
+		default: // the subscriber is slow, the next event of the topic updates the page anyway
+		}
+	}
+}
+
+// encodeEvent returns the event in the format of server-sent events.
+// The topic is the name of the event, so pages can trigger on it with sse:topic.
+func encodeEvent(event Event) []byte {
+	var b strings.Builder
+
+	b.WriteString("event: " + event.Topic + "\n")
+
+	for _, line := range strings.Split(event.Data, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+
+	b.WriteString("\n")
+
+	return []byte(b.String())
+}
+
+func intersect(allowed []string, requested []string) []string {
+	var topics []string
+
+	for _, r := range requested {
+		for _, a := range allowed {
+			if r == a {
+				topics = append(topics, r)
+			}
+		}
+	}
+
+	return topics
+}
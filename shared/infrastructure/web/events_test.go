@@ -0,0 +1,140 @@
+package web_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/web"
+)
+
+func TestEvents_Subscribe(t *testing.T) {
+	t.Parallel()
+
+	t.Run("receive subscribed topics only", func(t *testing.T) {
+		t.Parallel()
+
+		events := web.NewEvents(alog.NewNoopLogger(), nil)
+
+		ch, unsubscribe := events.Subscribe("jobs.queues")
+		defer unsubscribe()
+
+		_ = events.Publish(context.Background(), "logs", "")
+		_ = events.Publish(context.Background(), "jobs.queues", "12")
+
+		assert.Equal(t, web.Event{Topic: "jobs.queues", Data: "12"}, <-ch)
+		assert.Empty(t, ch)
+	})
+
+	t.Run("unsubscribe", func(t *testing.T) {
+		t.Parallel()
+
+		events := web.NewEvents(alog.NewNoopLogger(), nil)
+
+		ch, unsubscribe := events.Subscribe("logs")
+		unsubscribe()
+
+		_ = events.Publish(context.Background(), "logs", "")
+
+		assert.Empty(t, ch)
+	})
+}
+
+func TestEvents_Stream(t *testing.T) {
+	t.Parallel()
+
+	t.Run("stream events", func(t *testing.T) {
+		t.Parallel()
+
+		events := web.NewEvents(alog.NewNoopLogger(), nil, web.WithCoalesce(10*time.Millisecond))
+		rec, wait := stream(events, "/events", "jobs.queues", "logs")
+
+		time.Sleep(50 * time.Millisecond) // wait for the stream to subscribe
+		_ = events.Publish(context.Background(), "jobs.queues", "first\nsecond")
+
+		time.Sleep(50 * time.Millisecond)
+		assert.NoError(t, events.Shutdown(context.Background()))
+		wait()
+
+		assert.Equal(t, "text/event-stream", rec.Header().Get(echo.HeaderContentType))
+		assert.Contains(t, rec.Body.String(), "event: jobs.queues\ndata: first\ndata: second\n\n")
+	})
+
+	t.Run("narrow topics by query", func(t *testing.T) {
+		t.Parallel()
+
+		events := web.NewEvents(alog.NewNoopLogger(), nil, web.WithCoalesce(10*time.Millisecond))
+		rec, wait := stream(events, "/events?topic=logs&topic=unknown", "jobs.queues", "logs")
+
+		time.Sleep(50 * time.Millisecond) // wait for the stream to subscribe
+		_ = events.Publish(context.Background(), "logs", "")
+		_ = events.Publish(context.Background(), "jobs.queues", "")
+		_ = events.Publish(context.Background(), "unknown", "")
+
+		time.Sleep(50 * time.Millisecond)
+		assert.NoError(t, events.Shutdown(context.Background()))
+		wait()
+
+		assert.Contains(t, rec.Body.String(), "event: logs\n")
+		assert.NotContains(t, rec.Body.String(), "event: jobs.queues\n")
+		assert.NotContains(t, rec.Body.String(), "event: unknown\n")
+	})
+
+	t.Run("coalesce events of a topic", func(t *testing.T) {
+		t.Parallel()
+
+		events := web.NewEvents(alog.NewNoopLogger(), nil, web.WithCoalesce(100*time.Millisecond))
+		rec, wait := stream(events, "/events", "logs")
+
+		time.Sleep(50 * time.Millisecond) // wait for the stream to subscribe
+
+		for range 10 {
+			_ = events.Publish(context.Background(), "logs", "")
+		}
+
+		time.Sleep(150 * time.Millisecond)
+		assert.NoError(t, events.Shutdown(context.Background()))
+		wait()
+
+		assert.Equal(t, 1, strings.Count(rec.Body.String(), "event: logs\n"))
+	})
+
+	t.Run("heartbeat", func(t *testing.T) {
+		t.Parallel()
+
+		events := web.NewEvents(alog.NewNoopLogger(), nil, web.WithHeartbeat(10*time.Millisecond))
+		rec, wait := stream(events, "/events", "logs")
+
+		time.Sleep(50 * time.Millisecond)
+		assert.NoError(t, events.Shutdown(context.Background()))
+		wait()
+
+		assert.Contains(t, rec.Body.String(), ": heartbeat\n\n")
+	})
+}
+
+// stream serves the events in the background, until the Events are shut down.
+func stream(events *web.Events, url string, topics ...string) (*httptest.ResponseRecorder, func()) {
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, url, nil), rec)
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		_ = events.Stream(topics...)(c)
+	}()
+
+	return rec, wg.Wait
+}
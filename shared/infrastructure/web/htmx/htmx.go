@@ -0,0 +1,114 @@
+// Package htmx contains helpers for controllers answering requests made by htmx.
+//
+// See: https://htmx.org/reference/#headers
+package htmx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+var ErrRenderFailed = errors.New("render oob failed")
+
+// Request headers.
+const (
+	HeaderRequest = "HX-Request"
+	HeaderBoosted = "HX-Boosted"
+	HeaderTarget  = "HX-Target"
+)
+
+// Response headers.
+const (
+	HeaderTrigger  = "HX-Trigger"
+	HeaderRedirect = "HX-Redirect"
+	HeaderPushURL  = "HX-Push-Url"
+	HeaderReswap   = "HX-Reswap"
+)
+
+// IsRequest returns true, if the request is made by htmx.
+func IsRequest(c echo.Context) bool {
+	return c.Request().Header.Get(HeaderRequest) == "true"
+}
+
+// IsBoosted returns true, if the request is made by an element using hx-boost.
+// Boosted requests swap the whole body, so they expect a full page.
+func IsBoosted(c echo.Context) bool {
+	return c.Request().Header.Get(HeaderBoosted) == "true"
+}
+
+// Target returns the id of the element the response is swapped into, if it has one.
+func Target(c echo.Context) string {
+	return c.Request().Header.Get(HeaderTarget)
+}
+
+// Trigger triggers the events on the client, once the response is received.
+func Trigger(c echo.Context, events ...string) {
+	c.Response().Header().Set(HeaderTrigger, strings.Join(events, ", "))
+}
+
+// Redirect makes the client do a full page redirect to the url.
+func Redirect(c echo.Context, url string) {
+	c.Response().Header().Set(HeaderRedirect, url)
+}
+
+// PushURL pushes the url into the history of the browser.
+func PushURL(c echo.Context, url string) {
+	c.Response().Header().Set(HeaderPushURL, url)
+}
+
+// Reswap overwrites how the response is swapped, e.g. "outerHTML", see: https://htmx.org/attributes/hx-swap/
+func Reswap(c echo.Context, swap string) {
+	c.Response().Header().Set(HeaderReswap, swap)
+}
+
+// OOB is a fragment rendered as an out-of-band swap, in addition to the main content of a response.
+// See: https://htmx.org/attributes/hx-swap-oob/
+type OOB struct {
+	Data any
+	// Name is the name of the template, e.g. "jobs.cron#schedule".
+	Name string
+	// Target is the css selector of the element to swap, e.g. "#schedule-list".
+	Target string
+	// Swap is the swap strategy, it defaults to innerHTML.
+	// The content of the fragment is swapped, so outerHTML is not supported, use innerHTML on the parent instead.
+	Swap string
+}
+
+// Render renders the template name like c.Render and appends all oob fragments to it,
+// so several parts of a page are updated with one response.
+func Render(c echo.Context, code int, name string, data any, oob ...OOB) error {
+	renderer := c.Echo().Renderer
+	if renderer == nil {
+		return fmt.Errorf("%w: no renderer", ErrRenderFailed)
+	}
+
+	buf := &bytes.Buffer{}
+
+	err := renderer.Render(buf, name, data, c)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	for _, fragment := range oob {
+		swap := fragment.Swap
+		if swap == "" {
+			swap = "innerHTML"
+		}
+
+		fmt.Fprintf(buf, `<div hx-swap-oob="%s">`, template.HTMLEscapeString(swap+":"+fragment.Target))
+
+		err = renderer.Render(buf, fragment.Name, fragment.Data, c)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrRenderFailed, fragment.Name, err)
+		}
+
+		buf.WriteString(`</div>`)
+	}
+
+	return c.HTMLBlob(code, buf.Bytes())
+}
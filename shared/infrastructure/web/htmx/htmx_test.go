@@ -0,0 +1,97 @@
+package htmx_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/web/htmx"
+)
+
+func TestIsRequest(t *testing.T) {
+	t.Parallel()
+
+	c, _ := newContext(map[string]string{htmx.HeaderRequest: "true", htmx.HeaderTarget: "list"})
+
+	assert.True(t, htmx.IsRequest(c))
+	assert.False(t, htmx.IsBoosted(c))
+	assert.Equal(t, "list", htmx.Target(c))
+
+	c, _ = newContext(nil)
+	assert.False(t, htmx.IsRequest(c))
+}
+
+func TestResponseHeaders(t *testing.T) {
+	t.Parallel()
+
+	c, rec := newContext(nil)
+
+	htmx.Trigger(c, "first", "second")
+	htmx.Redirect(c, "/login")
+	htmx.PushURL(c, "/users")
+	htmx.Reswap(c, "outerHTML")
+
+	assert.Equal(t, "first, second", rec.Header().Get(htmx.HeaderTrigger))
+	assert.Equal(t, "/login", rec.Header().Get(htmx.HeaderRedirect))
+	assert.Equal(t, "/users", rec.Header().Get(htmx.HeaderPushURL))
+	assert.Equal(t, "outerHTML", rec.Header().Get(htmx.HeaderReswap))
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	t.Run("oob fragments", func(t *testing.T) {
+		t.Parallel()
+
+		c, rec := newContext(nil)
+
+		err := htmx.Render(c, http.StatusOK, "main", "m",
+			htmx.OOB{Name: "count", Data: 3, Target: "#count"},
+			htmx.OOB{Name: "row", Data: "r", Target: "#list", Swap: "beforeend"},
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t,
+			`main:m<div hx-swap-oob="innerHTML:#count">count:3</div><div hx-swap-oob="beforeend:#list">row:r</div>`,
+			rec.Body.String(),
+		)
+	})
+
+	t.Run("no renderer", func(t *testing.T) {
+		t.Parallel()
+
+		c, _ := newContext(nil)
+		c.Echo().Renderer = nil
+
+		err := htmx.Render(c, http.StatusOK, "main", nil)
+		assert.ErrorIs(t, err, htmx.ErrRenderFailed)
+	})
+}
+
+func newContext(headers map[string]string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	rec := httptest.NewRecorder()
+
+	e := echo.New()
+	e.Renderer = nameRenderer{}
+
+	return e.NewContext(req, rec), rec
+}
+
+// nameRenderer renders the name of the template and its data.
+type nameRenderer struct{}
+
+func (nameRenderer) Render(w io.Writer, name string, data any, _ echo.Context) error {
+	_, err := fmt.Fprintf(w, "%s:%v", name, data)
+
+	return err //nolint:wrapcheck // this is a test helper
+}
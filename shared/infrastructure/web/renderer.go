@@ -12,10 +12,14 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/go-arrower/arrower/alog"
 	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/text/language"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/i18n"
 )
 
 var (
@@ -32,6 +36,9 @@ var (
 const (
 	SharedViews = ""
 
+	// adminContext renders the pages of the other contexts inside its layouts, see isContext.
+	adminContext = "admin"
+
 	templateSeparator = "=>"
 	fragmentSeparator = "#"
 )
@@ -39,6 +46,8 @@ const (
 type (
 	Map      map[string]any
 	DataFunc func(ctx context.Context) (map[string]any, error)
+	// FlagFunc returns true, if the feature flag is on for the request of ctx.
+	FlagFunc func(ctx context.Context, key string) bool
 )
 
 // NewRenderer prepares a renderer for HTML web views.
@@ -55,6 +64,8 @@ func NewRenderer(
 
 	ctx := context.Background()
 
+	funcMap = withFlagFunc(withLocaleFuncs(funcMap))
+
 	logger = logger.WithGroup("arrower.renderer")
 	tracer := traceProvider.Tracer("arrower.renderer")
 
@@ -73,17 +84,46 @@ func NewRenderer(
 		slog.String("default_layout", views[SharedViews].defaultLayout),
 	)
 
-	return &Renderer{
+	r := &Renderer{
 		logger:      logger,
 		tracer:      tracer,
 		cache:       sync.Map{},
 		mu:          sync.Mutex{},
-		views:       views,
+		views:       atomic.Pointer[map[string]viewTemplates]{},
 		baseData:    map[string][]DataFunc{},
 		contextData: map[string]map[string][]DataFunc{},
 		funcMap:     funcMap,
+		flags:       nil,
 		hotReload:   hotReload,
-	}, nil
+		production:  false,
+	}
+	r.views.Store(&views)
+
+	return r, nil
+}
+
+// NewProductionRenderer prepares a renderer for HTML web views, that never reloads them.
+// All combinations of layouts and pages are parsed when the renderer is created or a context is added,
+// so a broken template fails on startup instead of on the first request that renders it.
+func NewProductionRenderer(
+	logger alog.Logger,
+	traceProvider trace.TracerProvider,
+	viewFS fs.FS,
+	funcMap template.FuncMap,
+) (*Renderer, error) {
+	r, err := NewRenderer(logger, traceProvider, viewFS, funcMap, false)
+	if err != nil {
+		return nil, err
+	}
+
+	r.production = true
+
+	err = r.compile(context.Background(), SharedViews)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCreateRendererFailed, err)
+	}
+
+	return r, nil
 }
 
 type Renderer struct {
@@ -92,13 +132,17 @@ type Renderer struct {
 
 	cache sync.Map
 
+	// mu serialises changes to the views and the building of new templates.
+	// The views are replaced as a whole, so rendering a cached template reads them without locking.
 	mu          sync.Mutex
-	views       map[string]viewTemplates
+	views       atomic.Pointer[map[string]viewTemplates]
 	baseData    map[string][]DataFunc
 	contextData map[string]map[string][]DataFunc
 
-	funcMap   template.FuncMap
-	hotReload bool
+	funcMap    template.FuncMap
+	flags      FlagFunc
+	hotReload  bool
+	production bool
 }
 
 type viewTemplates struct {
@@ -109,16 +153,43 @@ type viewTemplates struct {
 	defaultLayout string
 
 	components *template.Template
+	catalog    *i18n.Catalog
 }
 
 func (r *Renderer) Render(ctx context.Context, w io.Writer, contextName string, templateName string, data interface{}) error {
+	return r.render(ctx, w, contextName, templateName, data, false, "")
+}
+
+// RenderPartial renders a page without its base and layout, e.g. for a request by htmx.
+// If the page defines a fragment named like the target, only that fragment is rendered.
+// Names of fragments or components are rendered as by Render.
+func (r *Renderer) RenderPartial(
+	ctx context.Context,
+	w io.Writer,
+	contextName string,
+	templateName string,
+	target string,
+	data interface{},
+) error {
+	return r.render(ctx, w, contextName, templateName, data, true, target)
+}
+
+func (r *Renderer) render(
+	ctx context.Context,
+	w io.Writer,
+	contextName string,
+	templateName string,
+	data interface{},
+	partial bool,
+	target string,
+) error {
 	span := trace.SpanFromContext(ctx)
 
 	_, innerSpan := span.TracerProvider().Tracer("arrower.renderer").Start(ctx, "render")
 	defer innerSpan.End()
 
 	if r.hotReload {
-		r.mu.Lock() // todo is this lock still reqired, as the cache is delted via range now. Instead of = sync.Map{} of previous implementation => for the r.views
+		r.mu.Lock()
 
 		// delete all keys
 		r.cache.Range(func(key interface{}, _ interface{}) bool {
@@ -126,11 +197,14 @@ func (r *Renderer) Render(ctx context.Context, w io.Writer, contextName string,
 			return true
 		})
 
-		for k, v := range r.views {
+		views := map[string]viewTemplates{}
+		for k, v := range r.loadViews() {
 			isCont, _, _ := isContext(k)
-			r.views[k], _ = prepareViewTemplates(context.Background(), r.logger, v.viewFS, r.funcMap, isCont)
+			views[k], _ = prepareViewTemplates(context.Background(), r.logger, v.viewFS, r.funcMap, isCont)
 		}
 
+		r.views.Store(&views)
+
 		r.mu.Unlock()
 	}
 
@@ -139,6 +213,9 @@ func (r *Renderer) Render(ctx context.Context, w io.Writer, contextName string,
 		return fmt.Errorf("%w", err)
 	}
 
+	parsedTempl.partial = partial && !parsedTempl.isComponent && parsedTempl.fragment == ""
+	parsedTempl.locale = i18n.Locale(ctx)
+
 	r.logger.LogAttrs(ctx, alog.LevelInfo,
 		"render template",
 		slog.String("original_name", templateName),
@@ -169,17 +246,21 @@ func (r *Renderer) Render(ctx context.Context, w io.Writer, contextName string,
 		)
 	}
 
-	/*
-		(?) htmx support for partial rendering
-	*/
+	if parsedTempl.partial && target != "" && templ.Lookup(target) != nil {
+		parsedTempl.fragment = target
+	}
 
 	if nil == templ.Lookup(parsedTempl.templateName()) {
 		return ErrNotExistsFragment
 	}
 
-	data, err = r.getMergedData(ctx, parsedTempl, data)
-	if err != nil {
-		return fmt.Errorf("%w: could not build data: %w", ErrRenderFailed, err)
+	if fragment, ok := data.(fragmentData); ok && parsedTempl.fragment != "" {
+		data = fragment.data
+	} else {
+		data, err = r.getMergedData(ctx, parsedTempl, data)
+		if err != nil {
+			return fmt.Errorf("%w: could not build data: %w", ErrRenderFailed, err)
+		}
 	}
 
 	err = templ.ExecuteTemplate(w, parsedTempl.templateName(), data)
@@ -203,8 +284,7 @@ func isContext(context string) (bool, bool, string) {
 }
 
 func (r *Renderer) getParsedTemplate(context string, templateName string) (parsedTemplate, error) {
-	r.mu.Lock() // todo this is in the "hot path" could this be removed?
-	defer r.mu.Unlock()
+	views := r.loadViews()
 
 	parsedTempl, err := parseTemplateName(templateName)
 	if err != nil {
@@ -217,7 +297,7 @@ func (r *Renderer) getParsedTemplate(context string, templateName string) (parse
 	parsedTempl.renderAsAdminPage = isAdmin
 
 	if isContext {
-		parsedTempl.contextLayout = r.views[contextName].defaultLayout
+		parsedTempl.contextLayout = views[contextName].defaultLayout
 	}
 
 	isSharedView := parsedTempl.context == SharedViews
@@ -227,18 +307,26 @@ func (r *Renderer) getParsedTemplate(context string, templateName string) (parse
 	}
 
 	if !parsedTempl.isComponent && parsedTempl.baseLayout == "" {
-		parsedTempl.baseLayout = r.views[SharedViews].defaultLayout
+		parsedTempl.baseLayout = views[SharedViews].defaultLayout
 	}
 
 	return parsedTempl, nil
 }
 
+// loadViews returns the views of all contexts.
+// The map is never changed, so it is safe to read without holding the lock.
+func (r *Renderer) loadViews() map[string]viewTemplates {
+	return *r.views.Load()
+}
+
 func (r *Renderer) buildPageTemplate(isContext bool, parsedTempl parsedTemplate) (*template.Template, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	views := r.loadViews()
+
 	if parsedTempl.isComponent {
-		newTemplate := r.views[parsedTempl.context].components.Lookup(parsedTempl.fragment)
+		newTemplate := views[parsedTempl.context].components.Lookup(parsedTempl.fragment)
 		if newTemplate == nil {
 			return nil, ErrNotExistsComponent
 		}
@@ -253,52 +341,52 @@ func (r *Renderer) buildPageTemplate(isContext bool, parsedTempl parsedTemplate)
 			return nil, fmt.Errorf("%w: %v", ErrRenderFailed, err) //nolint:errorlint // prevent err in api
 		}
 
-		return newTemplate, nil
+		return newTemplate.Funcs(localeFuncs(views, parsedTempl)), nil
 	}
 
-	newTemplate, err := r.views[parsedTempl.context].components.Clone()
+	newTemplate, err := views[parsedTempl.context].components.Clone()
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrRenderFailed, err) //nolint:errorlint // prevent err in api
 	}
 
-	isPageWithoutLayout := parsedTempl.baseLayout == "" && parsedTempl.contextLayout == ""
+	isPageWithoutLayout := parsedTempl.partial || (parsedTempl.baseLayout == "" && parsedTempl.contextLayout == "")
 	if isPageWithoutLayout {
 		newTemplate, _ = newTemplate.New(parsedTempl.key()).Parse(`{{block "content" .}}{{end}}`)
 	} else {
-		if _, found := r.views[SharedViews].rawLayouts[parsedTempl.baseLayout]; !found {
+		if _, found := views[SharedViews].rawLayouts[parsedTempl.baseLayout]; !found {
 			return nil, fmt.Errorf("%w: default", ErrNotExistsLayout)
 		}
 
-		newTemplate, err = newTemplate.New(parsedTempl.key()).Parse(r.views[SharedViews].rawLayouts[parsedTempl.baseLayout])
+		newTemplate, err = newTemplate.New(parsedTempl.key()).Parse(views[SharedViews].rawLayouts[parsedTempl.baseLayout])
 		if err != nil {
 			return nil, fmt.Errorf("%w: could not parse base: %v", ErrRenderFailed, err) //nolint:errorlint,lll // prevent err in api
 		}
 
 		if isContext {
 			newTemplate, err = newTemplate.New("layout").
-				Parse(r.views[parsedTempl.context].rawLayouts[parsedTempl.contextLayout])
+				Parse(views[parsedTempl.context].rawLayouts[parsedTempl.contextLayout])
 			if err != nil {
 				return nil, fmt.Errorf("%w: could not parse layout: %v", ErrRenderFailed, err) //nolint:errorlint,lll // prevent err in api
 			}
 		}
 
 		if parsedTempl.renderAsAdminPage {
-			if r.views["admin"].rawLayouts[parsedTempl.baseLayout] == "" {
+			if views[adminContext].rawLayouts[parsedTempl.baseLayout] == "" {
 				return nil, ErrNotExistsLayout
 			}
 
 			newTemplate, err = newTemplate.New("layout").
-				Parse(r.views["admin"].rawLayouts[parsedTempl.contextLayout])
+				Parse(views[adminContext].rawLayouts[parsedTempl.contextLayout])
 			if err != nil {
 				return nil, fmt.Errorf("%w: could not parse admin layout: %v", ErrRenderFailed, err) //nolint:errorlint,lll // prevent err in api
 			}
 		}
 	}
 
-	page, contextPageExists := r.views[parsedTempl.context].rawPages[parsedTempl.page]
+	page, contextPageExists := views[parsedTempl.context].rawPages[parsedTempl.page]
 
 	if !contextPageExists {
-		p, sharedPageExists := r.views[SharedViews].rawPages[parsedTempl.page]
+		p, sharedPageExists := views[SharedViews].rawPages[parsedTempl.page]
 		if !sharedPageExists {
 			return nil, ErrNotExistsPage
 		}
@@ -311,7 +399,92 @@ func (r *Renderer) buildPageTemplate(isContext bool, parsedTempl parsedTemplate)
 		return nil, fmt.Errorf("%w: could not parse page: %v", ErrRenderFailed, err) //nolint:errorlint // prevent err in api
 	}
 
-	return newTemplate, nil
+	return newTemplate.Funcs(localeFuncs(views, parsedTempl)), nil
+}
+
+// withLocaleFuncs adds the functions to translate messages, so the views can be parsed.
+// They are replaced by localeFuncs, whenever a template is built for a locale.
+func withLocaleFuncs(funcMap template.FuncMap) template.FuncMap {
+	funcs := template.FuncMap{
+		"t":      func(key string, _ ...any) string { return key },
+		"locale": func() string { return i18n.DefaultLocale.String() },
+	}
+
+	for name, f := range funcMap {
+		funcs[name] = f
+	}
+
+	return funcs
+}
+
+// flagsKey is the key of the merged data holding the feature flags of the request.
+// It can not be reached with a field, e.g. {{ .Name }}, so only the function flag reads it.
+const flagsKey = "arrower.flags"
+
+// requestFlags checks the feature flags for the request, the data was merged for.
+type requestFlags func(key string) bool
+
+// withFlagFunc adds the function flag, that checks a feature flag for the request of the page data:
+//
+//	{{ if flag $ "new-checkout" }}...{{ end }}
+//
+// The flags are bound to the request by getMergedData, so the cached templates are the same for all requests.
+// A fragment or component rendered with its own data has all flags off.
+func withFlagFunc(funcMap template.FuncMap) template.FuncMap {
+	funcs := template.FuncMap{
+		"flag": func(data any, key string) bool {
+			m, ok := data.(Map)
+			if !ok {
+				return false
+			}
+
+			flags, ok := m[flagsKey].(requestFlags)
+
+			return ok && flags(key)
+		},
+	}
+
+	for name, f := range funcMap {
+		funcs[name] = f
+	}
+
+	return funcs
+}
+
+// SetFlags sets the feature flags checked by the template function flag.
+// Until it is called, all feature flags are off. Call it before the first render.
+func (r *Renderer) SetFlags(flags FlagFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.flags = flags
+}
+
+// localeFuncs returns the functions to translate messages into the locale of the template.
+// Messages are looked up in the catalog of the context first and then in the shared one.
+//
+//	{{ t "nav.home" }}
+//	{{ t "users.count" .Total }}
+//	<html lang="{{ locale }}">
+func localeFuncs(views map[string]viewTemplates, parsedTempl parsedTemplate) template.FuncMap {
+	locale := parsedTempl.locale
+	if locale == language.Und {
+		locale = i18n.DefaultLocale
+	}
+
+	contextCatalog := views[parsedTempl.context].catalog
+	sharedCatalog := views[SharedViews].catalog
+
+	return template.FuncMap{
+		"t": func(key string, args ...any) string {
+			if msg, ok := contextCatalog.Lookup(locale, key, args...); ok {
+				return msg
+			}
+
+			return sharedCatalog.Translate(locale, key, args...)
+		},
+		"locale": locale.String,
+	}
 }
 
 func prepareViewTemplates(ctx context.Context, logger alog.Logger, viewFS fs.FS, funcMap template.FuncMap, isContext bool) (viewTemplates, error) {
@@ -401,12 +574,23 @@ func prepareViewTemplates(ctx context.Context, logger alog.Logger, viewFS fs.FS,
 		slog.Any("layout_templates", rawTemplateNames(rawLayouts)),
 	)
 
+	catalog, err := i18n.NewCatalog(viewFS)
+	if err != nil {
+		return viewTemplates{}, fmt.Errorf("could not load messages: %w", err)
+	}
+
+	logger.LogAttrs(ctx, alog.LevelDebug,
+		"loaded messages",
+		slog.Any("locales", catalog.Locales()),
+	)
+
 	return viewTemplates{
 		viewFS:        viewFS,
 		rawLayouts:    rawLayouts,
 		rawPages:      rawPages,
 		defaultLayout: defaultLayout,
 		components:    componentTemplates,
+		catalog:       catalog,
 	}, nil
 }
 
@@ -467,14 +651,38 @@ type parsedTemplate struct {
 	fragment          string
 	renderAsAdminPage bool
 	isComponent       bool
+	// partial pages are rendered without their base and layout, the data of the layouts is still merged.
+	partial bool
+	// locale the messages of the template are translated into.
+	locale language.Tag
 }
 
 func (t parsedTemplate) key() string {
+	key := t.pageKey()
+
+	if t.locale != language.Und && t.locale != i18n.DefaultLocale { // each locale has its own translation functions
+		return t.locale.String() + ":" + key
+	}
+
+	return key
+}
+
+func (t parsedTemplate) pageKey() string {
 	if t.isComponent {
 		return fmt.Sprintf("%s/%s", t.context, t.fragment)
 	}
 
-	return fmt.Sprintf("%s/%s%s%s%s%s", t.context, t.baseLayout, templateSeparator, t.contextLayout, templateSeparator, t.page)
+	key := fmt.Sprintf("%s/%s%s%s%s%s", t.context, t.baseLayout, templateSeparator, t.contextLayout, templateSeparator, t.page)
+
+	if t.partial {
+		return "partial/" + key
+	}
+
+	if t.renderAsAdminPage { // the same page is built differently inside the admin, so it needs its own cache entry
+		return "admin/" + key
+	}
+
+	return key
 }
 
 func (t parsedTemplate) templateName() string {
@@ -556,6 +764,22 @@ func parseTemplateName(name string) (parsedTemplate, error) {
 }
 
 func (r *Renderer) AddContext(name string, viewFS fs.FS) error {
+	err := r.addContext(name, viewFS)
+	if err != nil {
+		return err
+	}
+
+	if r.production {
+		err = r.compile(context.Background(), name)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrContextNotAdded, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Renderer) addContext(name string, viewFS fs.FS) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
@@ -567,7 +791,9 @@ func (r *Renderer) AddContext(name string, viewFS fs.FS) error {
 		return fmt.Errorf("%w: no view files", ErrContextNotAdded)
 	}
 
-	if _, exists := r.views[name]; exists {
+	views := r.loadViews()
+
+	if _, exists := views[name]; exists {
 		return fmt.Errorf("%w: already added", ErrContextNotAdded)
 	}
 
@@ -577,31 +803,168 @@ func (r *Renderer) AddContext(name string, viewFS fs.FS) error {
 		return fmt.Errorf("%w", err)
 	}
 
-	r.views[name] = view
-	tmp := r.views[name]
-
-	cc, err := r.views[SharedViews].components.Clone()
+	cc, err := views[SharedViews].components.Clone()
 	if err != nil {
 		return fmt.Errorf("%w", err)
 	}
 
-	for _, t := range tmp.components.Templates() {
+	for _, t := range view.components.Templates() {
 		c, _ := cc.AddParseTree(t.Name(), t.Tree)
 		cc = c
 	}
 
-	tmp.components = cc
-	r.views[name] = tmp
+	view.components = cc
+
+	newViews := make(map[string]viewTemplates, len(views)+1)
+	for k, v := range views {
+		newViews[k] = v
+	}
+
+	newViews[name] = view
+	r.views.Store(&newViews)
 
 	return nil
 }
 
+// compile builds the templates of all combinations of base layouts, context layouts and pages of a context
+// and caches them, so they are validated once and are not parsed while rendering.
+// This includes the partial pages, as rendered for htmx, and the pages of the context rendered inside the admin.
+func (r *Renderer) compile(ctx context.Context, contextName string) error {
+	views := r.loadViews()
+	isContext := contextName != SharedViews
+
+	bases := layoutNames(views[SharedViews])
+	layouts := []string{""}
+
+	if isContext {
+		layouts = layoutNames(views[contextName])
+	}
+
+	count := 0
+
+	for _, base := range bases {
+		for _, layout := range layouts {
+			if base == "" && layout != "" { // a context layout can only be rendered inside a base layout
+				continue
+			}
+
+			for page := range views[contextName].rawPages {
+				for _, partial := range []bool{false, true} {
+					parsedTempl := parsedTemplate{ //nolint:exhaustruct // pages without fragments are compiled
+						context:       contextName,
+						baseLayout:    base,
+						contextLayout: layout,
+						page:          page,
+						partial:       partial,
+					}
+
+					templ, err := r.buildPageTemplate(isContext, parsedTempl)
+					if err != nil {
+						return fmt.Errorf("could not compile page: %s: %w", parsedTempl.key(), err)
+					}
+
+					r.cache.Store(parsedTempl.key(), templ)
+					count++
+				}
+			}
+		}
+	}
+
+	adminCount, err := r.compileAdmin(ctx, contextName)
+	if err != nil {
+		return err
+	}
+
+	r.logger.LogAttrs(ctx, alog.LevelDebug,
+		"templates compiled",
+		slog.String("context", contextName),
+		slog.Int("template_count", count+adminCount),
+	)
+
+	return nil
+}
+
+// compileAdmin builds the pages of the context as they are rendered inside the admin.
+// If the admin is added after other contexts, their pages are built for the admin then.
+func (r *Renderer) compileAdmin(ctx context.Context, contextName string) (int, error) {
+	views := r.loadViews()
+
+	if _, exists := views[adminContext]; !exists || contextName == SharedViews {
+		return 0, nil
+	}
+
+	if contextName == adminContext {
+		count := 0
+
+		for name := range views {
+			if name == SharedViews || name == adminContext {
+				continue
+			}
+
+			n, err := r.compileAdmin(ctx, name)
+			if err != nil {
+				return 0, err
+			}
+
+			count += n
+		}
+
+		return count, nil
+	}
+
+	count := 0
+
+	for _, base := range layoutNames(views[SharedViews]) {
+		for _, layout := range layoutNames(views[contextName]) {
+			for page := range views[contextName].rawPages {
+				parsedTempl := parsedTemplate{ //nolint:exhaustruct // pages without fragments are compiled
+					context:           contextName,
+					baseLayout:        base,
+					contextLayout:     layout,
+					page:              page,
+					renderAsAdminPage: true,
+				}
+
+				templ, err := r.buildPageTemplate(true, parsedTempl)
+				if errors.Is(err, ErrNotExistsLayout) { // the admin does not render the page inside this layout
+					continue
+				}
+
+				if err != nil {
+					return 0, fmt.Errorf("could not compile admin page: %s: %w", parsedTempl.key(), err)
+				}
+
+				r.cache.Store(parsedTempl.key(), templ)
+				count++
+			}
+		}
+	}
+
+	return count, nil
+}
+
+// layoutNames returns all layouts of the views.
+// If the views have no default layout, the empty name is included, as pages are rendered without a layout then.
+func layoutNames(views viewTemplates) []string {
+	names := make([]string, 0, len(views.rawLayouts)+1)
+
+	for name := range views.rawLayouts {
+		names = append(names, name)
+	}
+
+	if views.defaultLayout == "" {
+		names = append(names, "")
+	}
+
+	return names
+}
+
 func (r *Renderer) AddBaseData(baseName string, dataFunc DataFunc) error {
 	if baseName == "" {
 		baseName = "default"
 	}
 
-	if _, exists := r.views[SharedViews].rawLayouts[baseName]; !exists {
+	if _, exists := r.loadViews()[SharedViews].rawLayouts[baseName]; !exists {
 		return fmt.Errorf("%w: could not add base data", ErrCreateRendererFailed)
 	}
 
@@ -617,7 +980,7 @@ func (r *Renderer) AddLayoutData(context string, layoutName string, dataFunc Dat
 		layoutName = "default"
 	}
 
-	if _, exists := r.views[context].rawLayouts[layoutName]; !exists {
+	if _, exists := r.loadViews()[context].rawLayouts[layoutName]; !exists {
 		return fmt.Errorf("%w: could not add layout data", ErrCreateRendererFailed)
 	}
 
@@ -637,6 +1000,10 @@ func (r *Renderer) AddLayoutData(context string, layoutName string, dataFunc Dat
 func (r *Renderer) getMergedData(ctx context.Context, parsedTemplate parsedTemplate, pageData any) (Map, error) {
 	data := Map{}
 
+	if flags := r.flags; flags != nil {
+		data[flagsKey] = requestFlags(func(key string) bool { return flags(ctx, key) })
+	}
+
 	for _, df := range r.baseData[parsedTemplate.baseLayout] {
 		res, err := df(ctx)
 		if err != nil {
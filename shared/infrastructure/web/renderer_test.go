@@ -8,11 +8,14 @@ import (
 	"math/rand"
 	"sync"
 	"testing"
+	"testing/fstest"
 
 	"github.com/go-arrower/arrower/alog"
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/otel/trace/noop"
+	"golang.org/x/text/language"
 
+	"github.com/go-arrower/skeleton/shared/infrastructure/i18n"
 	"github.com/go-arrower/skeleton/shared/infrastructure/web"
 	"github.com/go-arrower/skeleton/shared/infrastructure/web/testdata"
 )
@@ -76,6 +79,73 @@ func TestNewRenderer(t *testing.T) {
 	})
 }
 
+func TestNewProductionRenderer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("render compiled page", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := web.NewProductionRenderer(alog.NewNoopLogger(), noop.NewTracerProvider(), testdata.FilesSharedViews(), template.FuncMap{})
+		assert.NoError(t, err)
+
+		buf := &bytes.Buffer{}
+		err = r.Render(ctx, buf, web.SharedViews, "p1", nil)
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), testdata.P1Content)
+		assert.Contains(t, buf.String(), testdata.C0Content)
+	})
+
+	t.Run("broken page", func(t *testing.T) {
+		t.Parallel()
+
+		views := testdata.FilesSharedViews()
+		views["pages/broken.html"] = &fstest.MapFile{Data: []byte(`{{ if }}`)}
+
+		r, err := web.NewProductionRenderer(alog.NewNoopLogger(), noop.NewTracerProvider(), views, template.FuncMap{})
+		assert.ErrorIs(t, err, web.ErrCreateRendererFailed)
+		assert.Nil(t, r)
+	})
+
+	t.Run("broken context layout", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := web.NewProductionRenderer(alog.NewNoopLogger(), noop.NewTracerProvider(), testdata.FilesSharedViewsWithDefaultBase(), template.FuncMap{})
+		assert.NoError(t, err)
+
+		err = r.AddContext(testdata.ExampleContext, fstest.MapFS{
+			"default.layout.html": {Data: []byte(`{{ block "layout" . }}{{ end`)},
+			"pages/p0.html":       {Data: []byte(testdata.P0Content)},
+		})
+		assert.ErrorIs(t, err, web.ErrContextNotAdded)
+	})
+
+	t.Run("render compiled admin and partial page", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := web.NewProductionRenderer(alog.NewNoopLogger(), noop.NewTracerProvider(), testdata.FilesSharedViewsWithDefaultBase(), template.FuncMap{})
+		assert.NoError(t, err)
+
+		err = r.AddContext(testdata.ExampleContext, testdata.ContextViews)
+		assert.NoError(t, err)
+		err = r.AddContext("admin", testdata.ContextAdmin)
+		assert.NoError(t, err, "the pages of the other contexts are compiled for the admin")
+
+		buf := &bytes.Buffer{}
+		err = r.Render(ctx, buf, "/admin/"+testdata.ExampleContext, "p0", nil)
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), testdata.P0ContextContent)
+		assert.Contains(t, buf.String(), "adminLayout")
+
+		buf.Reset()
+		err = r.RenderPartial(ctx, buf, testdata.ExampleContext, "p0", "", nil)
+		assert.NoError(t, err)
+		assert.Contains(t, buf.String(), testdata.P0ContextContent)
+		assert.NotContains(t, buf.String(), testdata.BaseDefaultLayoutContent)
+	})
+}
+
 func TestRenderer_Render(t *testing.T) {
 	t.Parallel()
 
@@ -516,6 +586,171 @@ func TestRenderer_Render(t *testing.T) {
 	})
 }
 
+func TestRenderer_RenderPartial(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	renderer, _ := web.NewRenderer(alog.NewNoopLogger(), noop.NewTracerProvider(), testdata.FilesSharedViewsWithDefaultBase(), template.FuncMap{}, false)
+
+	t.Run("page without base", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		err := renderer.RenderPartial(ctx, buf, web.SharedViews, "p1", "", nil)
+		assert.NoError(t, err)
+
+		assert.Contains(t, buf.String(), testdata.P1Content)
+		assert.Contains(t, buf.String(), testdata.C0Content)
+		assert.NotContains(t, buf.String(), testdata.BaseDefaultLayoutContent)
+	})
+
+	t.Run("fragment of target", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		err := renderer.RenderPartial(ctx, buf, web.SharedViews, "p2", "f1", nil)
+		assert.NoError(t, err)
+
+		assert.Equal(t, testdata.F1Content, buf.String())
+	})
+
+	t.Run("unknown target", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		err := renderer.RenderPartial(ctx, buf, web.SharedViews, "p2", "non-existing", nil)
+		assert.NoError(t, err)
+
+		assert.Contains(t, buf.String(), testdata.P2Content)
+		assert.NotContains(t, buf.String(), testdata.BaseDefaultLayoutContent)
+	})
+
+	t.Run("explicit fragment", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		err := renderer.RenderPartial(ctx, buf, web.SharedViews, "p2#f0", "f1", nil)
+		assert.NoError(t, err)
+
+		assert.Equal(t, testdata.F0Content, buf.String())
+	})
+
+	t.Run("full page is still rendered", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		err := renderer.Render(ctx, buf, web.SharedViews, "p1", nil)
+		assert.NoError(t, err)
+
+		assert.Contains(t, buf.String(), testdata.BaseDefaultLayoutContent)
+	})
+}
+
+func TestRenderer_RenderTranslated(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	german := i18n.WithLocale(ctx, language.German)
+
+	renderer, err := web.NewRenderer(alog.NewNoopLogger(), noop.NewTracerProvider(), fstest.MapFS{
+		"components/c0.html": {Data: []byte(`{{ t "hello" }}`)},
+		"pages/p0.html":      {Data: []byte(`<p lang="{{ locale }}">{{ t "hello" }} {{ t "users" .Count }}</p>`)},
+		"locales/en.json":    {Data: []byte(`{"hello": "Hello", "users": {"one": "%d user", "other": "%d users"}}`)},
+		"locales/de.json":    {Data: []byte(`{"hello": "Hallo", "users": {"one": "%d Nutzer", "other": "%d Nutzer"}}`)},
+	}, template.FuncMap{}, false)
+	assert.NoError(t, err)
+
+	err = renderer.AddContext(testdata.ExampleContext, fstest.MapFS{
+		"pages/p1.html":   {Data: []byte(`{{ t "hello" }} {{ t "bye" }}`)},
+		"locales/de.json": {Data: []byte(`{"bye": "Tschüss"}`)},
+	})
+	assert.NoError(t, err)
+
+	t.Run("default locale", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		err := renderer.Render(ctx, buf, web.SharedViews, "p0", map[string]any{"Count": 2})
+		assert.NoError(t, err)
+		assert.Equal(t, `<p lang="en">Hello 2 users</p>`, buf.String())
+	})
+
+	t.Run("locale of the context", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		err := renderer.Render(german, buf, web.SharedViews, "p0", map[string]any{"Count": 1})
+		assert.NoError(t, err)
+		assert.Equal(t, `<p lang="de">Hallo 1 Nutzer</p>`, buf.String())
+	})
+
+	t.Run("component", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		err := renderer.Render(german, buf, web.SharedViews, "#c0", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "Hallo", buf.String())
+	})
+
+	t.Run("context messages", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		err := renderer.Render(german, buf, testdata.ExampleContext, "p1", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "Hallo Tschüss", buf.String())
+	})
+}
+
+func TestRenderer_SetFlags(t *testing.T) {
+	t.Parallel()
+
+	type ctxKey struct{}
+
+	fs := fstest.MapFS{
+		"pages/p0.html": {Data: []byte(`{{ if flag $ "new" }}new{{ else }}old{{ end }}{{ range .Items }}{{ if flag $ "new" }}!{{ end }}{{ end }}`)},
+	}
+
+	t.Run("flags are off by default", func(t *testing.T) {
+		t.Parallel()
+
+		renderer, err := web.NewRenderer(alog.NewNoopLogger(), noop.NewTracerProvider(), fs, template.FuncMap{}, false)
+		assert.NoError(t, err)
+
+		buf := &bytes.Buffer{}
+		err = renderer.Render(context.Background(), buf, web.SharedViews, "p0", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "old", buf.String())
+	})
+
+	t.Run("flags of the request", func(t *testing.T) {
+		t.Parallel()
+
+		renderer, err := web.NewRenderer(alog.NewNoopLogger(), noop.NewTracerProvider(), fs, template.FuncMap{}, false)
+		assert.NoError(t, err)
+
+		renderer.SetFlags(func(ctx context.Context, key string) bool {
+			return key == "new" && ctx.Value(ctxKey{}) != nil
+		})
+
+		data := web.Map{"Items": []int{1, 2}}
+
+		for range 2 { // the cached template is used again
+			buf := &bytes.Buffer{}
+			err = renderer.Render(context.WithValue(context.Background(), ctxKey{}, true), buf, web.SharedViews, "p0", data)
+			assert.NoError(t, err)
+			assert.Equal(t, "new!!", buf.String())
+
+			buf.Reset()
+			err = renderer.Render(context.Background(), buf, web.SharedViews, "p0", data)
+			assert.NoError(t, err)
+			assert.Equal(t, "old", buf.String())
+		}
+	})
+}
+
 func TestRenderer_AddContext(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,91 @@
+package web
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// View is a page, a fragment, or a component that is rendered with data of type T.
+// Declare views next to the presenters building their data, so the controllers
+// and the type checker of the templates agree on what a template gets to see:
+//
+//	var CronSchedule = web.NewView[Schedule]("jobs.cron#schedule")
+//
+// The name follows the same rules as any other template name given to the Renderer.
+type View[T any] struct {
+	name string
+}
+
+func NewView[T any](name string) View[T] {
+	return View[T]{name: name}
+}
+
+func (v View[T]) Name() string {
+	return v.name
+}
+
+// DataType returns the type of the data the view is rendered with.
+func (v View[T]) DataType() reflect.Type {
+	return reflect.TypeFor[T]()
+}
+
+// TypedView is any View, independent of its type of data. See CheckViews.
+type TypedView interface {
+	Name() string
+	DataType() reflect.Type
+}
+
+// Render renders the view with its data.
+//
+// A fragment or component gets data as its dot, the same as when it is called inside its page.
+// For a page, the exported fields of a struct become the top level of the template's data, e.g. {{ .Name }},
+// next to the base and layout data. If a field has the same name as a key of the layout data, the field wins.
+// Methods of the struct are not available at the top level of a page, use a field holding the value instead.
+func Render[T any](c echo.Context, code int, view View[T], data T) error {
+	if strings.Contains(view.name, fragmentSeparator) {
+		return c.Render(code, view.name, fragmentData{data: data}) //nolint:wrapcheck // return the error of the renderer as is
+	}
+
+	return c.Render(code, view.name, viewData(data)) //nolint:wrapcheck // return the error of the renderer as is
+}
+
+// fragmentData is rendered as the dot of a fragment, instead of being merged with the base and layout data.
+type fragmentData struct {
+	data any
+}
+
+// viewData flattens the exported fields of a struct into a Map,
+// so they are merged with the base and layout data. All other data is returned unchanged.
+func viewData(data any) any {
+	val := reflect.ValueOf(data)
+	for val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			return nil
+		}
+
+		val = val.Elem()
+	}
+
+	if val.Kind() != reflect.Struct {
+		return data
+	}
+
+	m := Map{}
+
+	for _, field := range reflect.VisibleFields(val.Type()) {
+		if !field.IsExported() {
+			continue
+		}
+
+		v, err := val.FieldByIndexErr(field.Index)
+		if err != nil { // promoted through a nil embedded pointer
+			continue
+		}
+
+		m[field.Name] = v.Interface()
+	}
+
+	return m
+}
@@ -0,0 +1,53 @@
+package web_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-arrower/arrower/alog"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/web"
+)
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	e := echo.New()
+
+	assets, _ := web.NewAssets(fstest.MapFS{}, true)
+
+	renderer, err := web.NewEchoRenderer(alog.NewNoopLogger(), noop.NewTracerProvider(), e, fstest.MapFS{
+		"pages/item.html": {Data: []byte(`{{ define "item" }}<p>{{ .Name }}</p>{{ end }}`)},
+	}, assets, false)
+	assert.NoError(t, err)
+
+	e.Renderer = renderer
+
+	t.Run("struct data", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+		err := web.Render(c, http.StatusOK, web.NewView[checkedItem]("item#item"), checkedItem{Name: "arrower"})
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "<p>arrower</p>", rec.Body.String())
+	})
+
+	t.Run("pointer data", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+		err := web.Render(c, http.StatusOK, web.NewView[*checkedItem]("item#item"), &checkedItem{Name: "arrower"})
+		assert.NoError(t, err)
+		assert.Equal(t, "<p>arrower</p>", rec.Body.String())
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/go-arrower/skeleton/shared/application"
+	sharedweb "github.com/go-arrower/skeleton/shared/infrastructure/web"
 	"github.com/go-arrower/skeleton/shared/views/pages"
 )
 
@@ -29,8 +30,8 @@ func (hc *HelloController) SayHello() func(c echo.Context) error {
 			return c.String(http.StatusBadRequest, "ERROR")
 		}
 
-		return c.Render(http.StatusOK, "hello", echo.Map{
-			"Member": pages.PresentHello(res),
+		return sharedweb.Render(c, http.StatusOK, pages.ViewHello, pages.Hello{
+			Member: pages.PresentHello(res),
 		})
 	}
 }
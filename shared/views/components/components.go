@@ -0,0 +1,181 @@
+// Package components has the data of the UI components shared by all Contexts.
+//
+// The templates of the components are in shared/views/components and can be called from any view,
+// either with the data types of this package or with a dict built in the template:
+//
+//	{{ template "ui.table" .Users }}
+//	{{ template "ui.field" .Email }}
+//	{{ template "ui.confirm" .DeleteUser }}
+//	{{ template "ui.flashes" .Flashes }}
+//	{{ template "ui.kpi" dict "Title" "Jobs" "Value" .Jobs "Description" "pending" }}
+//
+// The admin Context shows all components with sample data under /admin/components.
+package components
+
+import (
+	"net/url"
+	"strconv"
+)
+
+const (
+	// QuerySort, QueryDesc and QueryPage are the query parameters a Table is sorted and paginated with.
+	QuerySort = "sort"
+	QueryDesc = "desc"
+	QueryPage = "page"
+
+	DefaultPageSize = 20
+)
+
+// NewTable returns a Table, that is sorted and paginated by the query of the request.
+// Sorting is only possible by a sortable column, other values in the query are ignored.
+// The caller sorts and paginates the Rows by Sort, Desc and Offset and sets Total.
+func NewTable(id string, baseURL string, query url.Values, columns ...Column) Table {
+	table := Table{
+		ID:       id,
+		URL:      baseURL,
+		Columns:  columns,
+		Rows:     nil,
+		Sort:     "",
+		Desc:     query.Get(QueryDesc) == "true",
+		Page:     1,
+		PageSize: DefaultPageSize,
+		Total:    0,
+		Empty:    "-",
+	}
+
+	for _, c := range columns {
+		if c.Sortable && c.Key == query.Get(QuerySort) {
+			table.Sort = c.Key
+		}
+	}
+
+	if page, err := strconv.Atoi(query.Get(QueryPage)); err == nil && page > 1 {
+		table.Page = page
+	}
+
+	return table
+}
+
+// Table is a data table, that is sorted and paginated via htmx, without reloading the page.
+type Table struct {
+	// ID of the table in the page, it is replaced on sorting and paginating.
+	ID string
+	// URL the table is loaded from, with the query parameters to sort and paginate.
+	URL string
+
+	Columns []Column
+	Rows    [][]string
+
+	Sort string
+	Desc bool
+
+	Page     int
+	PageSize int
+	Total    int
+
+	// Empty is shown, if the table has no rows.
+	Empty string
+}
+
+type Column struct {
+	Key      string
+	Label    string
+	Sortable bool
+}
+
+// Offset is the number of rows before the current page.
+func (t Table) Offset() int {
+	return (t.Page - 1) * t.PageSize
+}
+
+// Pages returns the number of pages, a table has at least one.
+func (t Table) Pages() int {
+	if t.PageSize <= 0 || t.Total <= t.PageSize {
+		return 1
+	}
+
+	return (t.Total + t.PageSize - 1) / t.PageSize
+}
+
+func (t Table) HasPrev() bool {
+	return t.Page > 1
+}
+
+func (t Table) HasNext() bool {
+	return t.Page < t.Pages()
+}
+
+// SortURL returns the URL to sort the table by the column with key.
+// If the table is already sorted by it, the order is reversed.
+// Sorting starts again on the first page.
+func (t Table) SortURL(key string) string {
+	desc := t.Sort == key && !t.Desc
+
+	return t.url(key, desc, 1)
+}
+
+// PageURL returns the URL of the page in the current order.
+func (t Table) PageURL(page int) string {
+	return t.url(t.Sort, t.Desc, page)
+}
+
+func (t Table) PrevURL() string {
+	return t.PageURL(t.Page - 1)
+}
+
+func (t Table) NextURL() string {
+	return t.PageURL(t.Page + 1)
+}
+
+func (t Table) url(sort string, desc bool, page int) string {
+	query := url.Values{}
+
+	if sort != "" {
+		query.Set(QuerySort, sort)
+	}
+
+	if desc {
+		query.Set(QueryDesc, "true")
+	}
+
+	if page > 1 {
+		query.Set(QueryPage, strconv.Itoa(page))
+	}
+
+	if len(query) == 0 {
+		return t.URL
+	}
+
+	return t.URL + "?" + query.Encode()
+}
+
+// Field is an input of a form with its label and validation error.
+type Field struct {
+	Label       string
+	Name        string
+	Type        string // e.g. text, email or password; text if empty
+	Value       string
+	Placeholder string
+	Error       string
+	Required    bool
+}
+
+// Confirm is a button that opens a modal dialog. Only if the user confirms, the Action is posted via htmx.
+type Confirm struct {
+	// ID of the dialog in the page.
+	ID      string
+	Button  string
+	Title   string
+	Message string
+	// Action is the URL posted to, if the user confirms.
+	Action string
+	// Target is the element replaced with the response of the Action, e.g. #users.
+	Target string
+}
+
+// KPI is a card with a single key figure.
+type KPI struct {
+	Title       string
+	Value       string
+	Description string
+}
@@ -0,0 +1,67 @@
+package components_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/shared/views/components"
+)
+
+var columns = []components.Column{
+	{Key: "name", Label: "Name", Sortable: true},
+	{Key: "email", Label: "Email", Sortable: false},
+}
+
+func TestNewTable(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults", func(t *testing.T) {
+		t.Parallel()
+
+		table := components.NewTable("users", "/users", url.Values{}, columns...)
+		assert.Equal(t, "", table.Sort)
+		assert.False(t, table.Desc)
+		assert.Equal(t, 1, table.Page)
+		assert.Equal(t, 0, table.Offset())
+	})
+
+	t.Run("from query", func(t *testing.T) {
+		t.Parallel()
+
+		table := components.NewTable("users", "/users", url.Values{"sort": {"name"}, "desc": {"true"}, "page": {"3"}}, columns...)
+		assert.Equal(t, "name", table.Sort)
+		assert.True(t, table.Desc)
+		assert.Equal(t, 3, table.Page)
+		assert.Equal(t, 2*components.DefaultPageSize, table.Offset())
+	})
+
+	t.Run("ignore invalid query", func(t *testing.T) {
+		t.Parallel()
+
+		table := components.NewTable("users", "/users", url.Values{"sort": {"email"}, "page": {"-1"}}, columns...)
+		assert.Equal(t, "", table.Sort, "not sortable")
+		assert.Equal(t, 1, table.Page)
+	})
+}
+
+func TestTable_URLs(t *testing.T) {
+	t.Parallel()
+
+	table := components.NewTable("users", "/users", url.Values{"sort": {"name"}, "page": {"2"}}, columns...)
+	table.PageSize = 10
+	table.Total = 25
+
+	assert.Equal(t, 3, table.Pages())
+	assert.True(t, table.HasPrev())
+	assert.True(t, table.HasNext())
+
+	assert.Equal(t, "/users?desc=true&sort=name", table.SortURL("name"), "reverse the order")
+	assert.Equal(t, "/users?sort=email", table.SortURL("email"))
+	assert.Equal(t, "/users?sort=name", table.PrevURL())
+	assert.Equal(t, "/users?page=3&sort=name", table.NextURL())
+
+	table.Page = 3
+	assert.False(t, table.HasNext())
+}
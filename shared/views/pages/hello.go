@@ -2,6 +2,10 @@ package pages
 
 import "github.com/go-arrower/skeleton/shared/domain"
 
+type Hello struct {
+	Member helloPage
+}
+
 type helloPage struct {
 	domain.TeamMember
 	TeamTimeFmt string
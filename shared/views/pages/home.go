@@ -0,0 +1,7 @@
+package pages
+
+type Home struct {
+	Title   string
+	UserID  string
+	Flashes []any
+}
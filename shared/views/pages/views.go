@@ -0,0 +1,19 @@
+package pages
+
+import (
+	"github.com/go-arrower/skeleton/shared/infrastructure/maintenance"
+	"github.com/go-arrower/skeleton/shared/infrastructure/web"
+)
+
+var (
+	ViewHello = web.NewView[Hello]("hello")
+	ViewHome  = web.NewView[Home]("=>home")
+)
+
+// Views are all typed views of the shared pages, so they can be checked by web.CheckViews.
+var Views = []web.TypedView{
+	ViewHello,
+	ViewHome,
+	web.ViewError,
+	maintenance.ViewMaintenance,
+}
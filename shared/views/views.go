@@ -4,5 +4,5 @@ import (
 	"embed"
 )
 
-//go:embed **/*.html
+//go:embed *.html **/*.html locales/*.json
 var SharedViews embed.FS
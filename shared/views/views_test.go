@@ -0,0 +1,18 @@
+package views_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/go-arrower/skeleton/shared/infrastructure/web"
+	"github.com/go-arrower/skeleton/shared/views"
+	"github.com/go-arrower/skeleton/shared/views/pages"
+)
+
+func TestSharedViews(t *testing.T) {
+	t.Parallel()
+
+	err := web.CheckViews(views.SharedViews, nil, pages.Views...)
+	assert.NoError(t, err)
+}